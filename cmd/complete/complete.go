@@ -0,0 +1,227 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package complete provides dynamic shell completion of token paths.
+package complete
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bennypowers.dev/asimonim/config"
+	"bennypowers.dev/asimonim/fs"
+	"bennypowers.dev/asimonim/internal/logger"
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/specifier"
+)
+
+// Limit is the most completions returned at once. Shells get slow to
+// display long lists, and a user with thousands of tokens will type a
+// few more characters before asking again.
+const Limit = 200
+
+// FileArgs picks the positional arguments that name token files.
+type FileArgs func(args []string) []string
+
+// AllArgs treats every positional argument as a token file, as list does.
+func AllArgs(args []string) []string {
+	return args
+}
+
+// ArgsAfterQuery skips the leading query argument, as search does.
+func ArgsAfterQuery(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	return args[1:]
+}
+
+// Tokens returns a completion function offering the dot-paths of tokens
+// in the files named on the command line, or in the config's files when
+// none are named.
+func Tokens(files FileArgs) cobra.CompletionFunc {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return complete(files(args), toComplete, func(paths []string) []string { return paths })
+	}
+}
+
+// Groups is like Tokens, but offers the dot-paths of groups.
+func Groups(files FileArgs) cobra.CompletionFunc {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return complete(files(args), toComplete, GroupPaths)
+	}
+}
+
+func complete(files []string, toComplete string, choose func([]string) []string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	// Warnings on stderr would garble the shell's prompt
+	logger.SetOutput(io.Discard)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	// Without a user cache directory, completions are parsed every time
+	cacheDir, err := os.UserCacheDir()
+	if err == nil {
+		cacheDir = filepath.Join(cacheDir, "asimonim")
+	}
+	paths, err := TokenPaths(fs.NewOSFileSystem(), cwd, cacheDir, files)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return Filter(choose(paths), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// Filter returns the candidates starting with prefix, up to Limit.
+func Filter(candidates []string, prefix string) []string {
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			matches = append(matches, c)
+			if len(matches) == Limit {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// GroupPaths returns the sorted, unique dot-paths of the groups that
+// contain the given token paths.
+func GroupPaths(tokenPaths []string) []string {
+	var groups []string
+	for _, p := range tokenPaths {
+		for i := range len(p) {
+			if p[i] == '.' {
+				groups = append(groups, p[:i])
+			}
+		}
+	}
+	slices.Sort(groups)
+	return slices.Compact(groups)
+}
+
+// TokenPaths returns the sorted dot-paths of the tokens in files, or in
+// the files listed by the config in root when files is empty. Aliases are
+// not resolved, since only the names are needed.
+//
+// When cacheDir is not empty, the paths are cached there in one file per
+// project, which is overwritten whenever the token files or the config
+// change, so repeated completions skip parsing unchanged files.
+func TokenPaths(filesystem fs.FileSystem, root, cacheDir string, files []string) ([]string, error) {
+	specResolver, err := specifier.NewDefaultResolver(filesystem, root)
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.LoadOrDefault(filesystem, root)
+
+	var resolvedFiles []*specifier.ResolvedFile
+	if len(files) == 0 {
+		resolvedFiles, err = cfg.ResolveFiles(specResolver, filesystem, root)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for _, f := range files {
+			rf, err := specResolver.Resolve(f)
+			if err != nil {
+				return nil, err
+			}
+			resolvedFiles = append(resolvedFiles, rf)
+		}
+	}
+
+	var cachePath, key string
+	cacheable := false
+	if cacheDir != "" {
+		cachePath = filepath.Join(cacheDir, "completion-"+hash(root)+".json")
+		key, cacheable = cacheKey(filesystem, root, resolvedFiles)
+	}
+	if cacheable {
+		if data, err := filesystem.ReadFile(cachePath); err == nil {
+			var c cache
+			if json.Unmarshal(data, &c) == nil && c.Key == key {
+				return c.Paths, nil
+			}
+		}
+	}
+
+	jsonParser := parser.NewJSONParser()
+	var paths []string
+	for _, rf := range resolvedFiles {
+		data, err := filesystem.ReadFile(rf.Path)
+		if err != nil {
+			continue
+		}
+		opts := cfg.OptionsForFile(rf.Specifier)
+		opts.SkipPositions = true
+		tokens, err := jsonParser.Parse(data, opts)
+		if err != nil {
+			continue
+		}
+		for _, tok := range tokens {
+			paths = append(paths, tok.DotPath())
+		}
+	}
+	slices.Sort(paths)
+	paths = slices.Compact(paths)
+
+	if cacheable {
+		if data, err := json.Marshal(cache{Key: key, Paths: paths}); err == nil {
+			if err := filesystem.MkdirAll(cacheDir, 0o700); err == nil {
+				_ = filesystem.WriteFile(cachePath, data, 0o600)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// cache is the content of a project's completion cache file.
+type cache struct {
+	// Key identifies the token files the paths were read from.
+	Key   string   `json:"key"`
+	Paths []string `json:"paths"`
+}
+
+// cacheKey identifies a set of token files by the contents of the config
+// in root, which decides how they parse, and each file's path, size, and
+// modification time. It returns false if the config can't be read or any
+// file can't be stat'd, as with remote files.
+func cacheKey(filesystem fs.FileSystem, root string, files []*specifier.ResolvedFile) (string, bool) {
+	if len(files) == 0 {
+		return "", false
+	}
+	var sb strings.Builder
+	if configPath := config.FindFile(filesystem, root); configPath != "" {
+		data, err := filesystem.ReadFile(configPath)
+		if err != nil {
+			return "", false
+		}
+		fmt.Fprintf(&sb, "%s\x00%s\n", configPath, data)
+	}
+	for _, rf := range files {
+		info, err := filesystem.Stat(rf.Path)
+		if err != nil {
+			return "", false
+		}
+		fmt.Fprintf(&sb, "%s\x00%d\x00%d\n", rf.Path, info.Size(), info.ModTime().UnixNano())
+	}
+	return hash(sb.String()), true
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:16]
+}
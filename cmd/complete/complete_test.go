@@ -0,0 +1,140 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package complete_test
+
+import (
+	"fmt"
+	iofs "io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/cmd/complete"
+	"bennypowers.dev/asimonim/internal/mapfs"
+	"bennypowers.dev/asimonim/testutil"
+)
+
+func TestTokenPaths_Config(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/config/simple", "/project")
+
+	paths, err := complete.TokenPaths(mfs, "/project", "", nil)
+	if err != nil {
+		t.Fatalf("TokenPaths() error = %v", err)
+	}
+	want := []string{"color.primary", "color.secondary"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("TokenPaths() = %v, want %v", paths, want)
+	}
+}
+
+func TestTokenPaths_Files(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/a.json", `{"space": {"$type": "dimension", "sm": {"$value": "4px"}}}`, 0644)
+	mfs.AddFile("/b.json", `{"color": {"$type": "color", "red": {"$value": "#f00"}}}`, 0644)
+
+	paths, err := complete.TokenPaths(mfs, "/", "", []string{"/a.json", "/b.json"})
+	if err != nil {
+		t.Fatalf("TokenPaths() error = %v", err)
+	}
+	want := []string{"color.red", "space.sm"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("TokenPaths() = %v, want %v", paths, want)
+	}
+}
+
+func TestTokenPaths_Cache(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.json", `{"color": {"$type": "color", "red": {"$value": "#f00"}}}`, 0644)
+	cacheDir := "/cache/asimonim"
+
+	cacheFile := func() string {
+		t.Helper()
+		entries, err := mfs.ReadDir(cacheDir)
+		entries = slices.DeleteFunc(entries, func(e iofs.DirEntry) bool { return !strings.HasPrefix(e.Name(), "completion-") })
+		if err != nil || len(entries) != 1 {
+			t.Fatalf("expected one cache file in %s, got %v (%v)", cacheDir, entries, err)
+		}
+		info, err := entries[0].Info()
+		if err != nil {
+			t.Fatalf("failed to stat cache file: %v", err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("cache file mode = %v, want 0600", perm)
+		}
+		return filepath.Join(cacheDir, entries[0].Name())
+	}
+
+	if _, err := complete.TokenPaths(mfs, "/", cacheDir, []string{"/tokens.json"}); err != nil {
+		t.Fatalf("TokenPaths() error = %v", err)
+	}
+	cachePath := cacheFile()
+
+	// A second call reads the cache instead of parsing the file again
+	data, err := mfs.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	mfs.AddFile(cachePath, strings.Replace(string(data), "color.red", "from.cache", 1), 0o600)
+	paths, err := complete.TokenPaths(mfs, "/", cacheDir, []string{"/tokens.json"})
+	if err != nil {
+		t.Fatalf("TokenPaths() error = %v", err)
+	}
+	if !slices.Equal(paths, []string{"from.cache"}) {
+		t.Errorf("expected cached paths, got %v", paths)
+	}
+
+	// Changing the tokens overwrites the project's cache file
+	mfs.AddFile("/tokens.json", `{"color": {"$type": "color", "blue": {"$value": "#00f"}}}`, 0644)
+	paths, err = complete.TokenPaths(mfs, "/", cacheDir, []string{"/tokens.json"})
+	if err != nil {
+		t.Fatalf("TokenPaths() error = %v", err)
+	}
+	if !slices.Equal(paths, []string{"color.blue"}) {
+		t.Errorf("expected stale cache to be ignored, got %v", paths)
+	}
+	if got := cacheFile(); got != cachePath {
+		t.Errorf("expected %s to be overwritten, got %s", cachePath, got)
+	}
+
+	// So does changing the config, which decides how the tokens parse
+	data, err = mfs.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("failed to read cache: %v", err)
+	}
+	mfs.AddFile(cachePath, strings.Replace(string(data), "color.blue", "from.cache", 1), 0o600)
+	mfs.AddFile("/.config/design-tokens.yaml", "prefix: ds\n", 0644)
+	paths, err = complete.TokenPaths(mfs, "/", cacheDir, []string{"/tokens.json"})
+	if err != nil {
+		t.Fatalf("TokenPaths() error = %v", err)
+	}
+	if !slices.Equal(paths, []string{"color.blue"}) {
+		t.Errorf("expected the cache to be ignored after a config change, got %v", paths)
+	}
+}
+
+func TestGroupPaths(t *testing.T) {
+	got := complete.GroupPaths([]string{"color.brand.primary", "color.brand.secondary", "space.sm", "flat"})
+	want := []string{"color", "color.brand", "space"}
+	if !slices.Equal(got, want) {
+		t.Errorf("GroupPaths() = %v, want %v", got, want)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	if got := complete.Filter([]string{"color.red", "color.blue", "space.sm"}, "color."); !slices.Equal(got, []string{"color.red", "color.blue"}) {
+		t.Errorf("Filter() = %v", got)
+	}
+
+	many := make([]string, complete.Limit+10)
+	for i := range many {
+		many[i] = fmt.Sprintf("color.c%d", i)
+	}
+	if got := complete.Filter(many, "color"); len(got) != complete.Limit {
+		t.Errorf("expected Filter() to stop at %d, got %d", complete.Limit, len(got))
+	}
+}
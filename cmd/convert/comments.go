@@ -0,0 +1,322 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"bennypowers.dev/asimonim/parser"
+)
+
+// commentMap holds the comment lines written above keys in a token file,
+// keyed by the dot-separated path of the key. The empty path holds the
+// comment at the top of the file.
+type commentMap map[string][]string
+
+// arrayItem stands in for the key of array elements in comment paths.
+const arrayItem = "[]"
+
+// collectComments returns the leading comments on keys in a YAML or JSONC
+// token file, or nil when the file has none. Trailing comments on the
+// same line as a value are not kept.
+func collectComments(data []byte, format parser.Format) commentMap {
+	var comments commentMap
+	if format == parser.FormatYAML {
+		comments = collectYAMLComments(data)
+	} else {
+		comments = collectJSONCComments(data)
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+	return comments
+}
+
+// marshalInPlace serializes an in-place conversion result in the file's
+// own format: YAML for YAML files, indented JSON otherwise. Comments are
+// written above the key they preceded; comments on keys that no longer
+// exist are dropped.
+func marshalInPlace(result map[string]any, format parser.Format, comments commentMap) ([]byte, error) {
+	if format == parser.FormatYAML {
+		return marshalYAMLWithComments(result, comments)
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil || comments == nil {
+		return data, err
+	}
+	return insertJSONComments(data, comments), nil
+}
+
+func collectYAMLComments(data []byte) commentMap {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	comments := commentMap{}
+	if doc.HeadComment != "" {
+		comments[""] = yamlCommentLines(doc.HeadComment)
+	}
+	var walk func(n *yaml.Node, path []string)
+	walk = func(n *yaml.Node, path []string) {
+		switch n.Kind {
+		case yaml.DocumentNode:
+			for _, c := range n.Content {
+				walk(c, path)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key := n.Content[i]
+				keyPath := append(path[:len(path):len(path)], key.Value)
+				if key.HeadComment != "" {
+					comments[strings.Join(keyPath, ".")] = yamlCommentLines(key.HeadComment)
+				}
+				walk(n.Content[i+1], keyPath)
+			}
+		case yaml.SequenceNode:
+			for _, c := range n.Content {
+				walk(c, append(path[:len(path):len(path)], arrayItem))
+			}
+		}
+	}
+	walk(&doc, nil)
+	return comments
+}
+
+// yamlCommentLines strips the # markers from a yaml.v3 comment.
+func yamlCommentLines(comment string) []string {
+	var lines []string
+	for line := range strings.SplitSeq(comment, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func marshalYAMLWithComments(result map[string]any, comments commentMap) ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(result); err != nil {
+		return nil, err
+	}
+	var walk func(n *yaml.Node, path []string)
+	walk = func(n *yaml.Node, path []string) {
+		switch n.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key := n.Content[i]
+				keyPath := append(path[:len(path):len(path)], key.Value)
+				if lines, ok := comments[strings.Join(keyPath, ".")]; ok {
+					key.HeadComment = yamlComment(lines)
+				}
+				walk(n.Content[i+1], keyPath)
+			}
+		case yaml.SequenceNode:
+			for _, c := range n.Content {
+				walk(c, append(path[:len(path):len(path)], arrayItem))
+			}
+		}
+	}
+	walk(&root, nil)
+
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&root}}
+	if lines, ok := comments[""]; ok {
+		doc.HeadComment = yamlComment(lines)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlComment formats comment lines for a yaml.v3 node.
+func yamlComment(lines []string) string {
+	prefixed := make([]string, len(lines))
+	for i, line := range lines {
+		prefixed[i] = strings.TrimRight("# "+line, " ")
+	}
+	return strings.Join(prefixed, "\n")
+}
+
+// jsonFrame is an object or array being scanned, named by the key (or
+// arrayItem) it is the value of.
+type jsonFrame struct {
+	name  string
+	array bool
+	key   string
+}
+
+// framePath joins the names of the open frames below the root with key.
+func framePath(frames []jsonFrame, key string) string {
+	parts := make([]string, 0, len(frames))
+	for _, f := range frames[1:] {
+		parts = append(parts, f.name)
+	}
+	return strings.Join(append(parts, key), ".")
+}
+
+// childName returns the name for a value opened inside frames.
+func childName(frames []jsonFrame) string {
+	if len(frames) == 0 {
+		return ""
+	}
+	if top := frames[len(frames)-1]; !top.array {
+		return top.key
+	}
+	return arrayItem
+}
+
+func collectJSONCComments(data []byte) commentMap {
+	comments := commentMap{}
+	var frames []jsonFrame
+	var pending []string
+	var lastString string
+	// trailing is set after a value or key until the next newline, so a
+	// comment on the same line is not taken as leading the next key.
+	trailing := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '\n':
+			trailing = false
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			end := bytes.IndexByte(data[i:], '\n')
+			if end < 0 {
+				end = len(data) - i
+			}
+			if !trailing {
+				pending = append(pending, strings.TrimSpace(string(data[i+2:i+end])))
+			}
+			i += end - 1
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				end = len(data) - i - 2
+			}
+			if !trailing {
+				for line := range strings.SplitSeq(string(data[i+2:i+2+end]), "\n") {
+					line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+					if line != "" {
+						pending = append(pending, line)
+					}
+				}
+			}
+			i += end + 3
+		case c == '"':
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			lastString = ""
+			if i < len(data) {
+				_ = json.Unmarshal(data[start:i+1], &lastString)
+			}
+			trailing = true
+		case c == ':':
+			if n := len(frames); n > 0 && !frames[n-1].array {
+				frames[n-1].key = lastString
+				if len(pending) > 0 {
+					comments[framePath(frames, lastString)] = pending
+				}
+			}
+			pending = nil
+		case c == '{' || c == '[':
+			if len(frames) == 0 && len(pending) > 0 {
+				comments[""] = pending
+			}
+			frames = append(frames, jsonFrame{name: childName(frames), array: c == '['})
+			pending = nil
+			trailing = true
+		case c == '}' || c == ']':
+			if len(frames) > 0 {
+				frames = frames[:len(frames)-1]
+			}
+			pending = nil
+			trailing = true
+		case c == ',' || c == ' ' || c == '\t' || c == '\r':
+		default:
+			pending = nil
+			trailing = true
+		}
+	}
+	return comments
+}
+
+// insertJSONComments writes comments as // lines above their keys in
+// two-space indented JSON from json.MarshalIndent.
+func insertJSONComments(data []byte, comments commentMap) []byte {
+	var buf bytes.Buffer
+	var frames []jsonFrame
+	for i, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+
+		if i == 0 {
+			for _, c := range comments[""] {
+				buf.WriteString(strings.TrimRight("// "+c, " ") + "\n")
+			}
+		} else {
+			buf.WriteByte('\n')
+		}
+
+		opened := ""
+		if strings.HasPrefix(trimmed, `"`) && len(frames) > 0 && !frames[len(frames)-1].array {
+			if key, ok := leadingJSONString(trimmed); ok {
+				frames[len(frames)-1].key = key
+				for _, c := range comments[framePath(frames, key)] {
+					buf.WriteString(strings.TrimRight(indent+"// "+c, " ") + "\n")
+				}
+			}
+		}
+		switch {
+		case strings.HasSuffix(trimmed, "{"):
+			opened = "{"
+		case strings.HasSuffix(trimmed, "["):
+			opened = "["
+		}
+		if strings.HasPrefix(trimmed, "}") || strings.HasPrefix(trimmed, "]") {
+			if len(frames) > 0 {
+				frames = frames[:len(frames)-1]
+			}
+		}
+		if opened != "" {
+			frames = append(frames, jsonFrame{name: childName(frames), array: opened == "["})
+		}
+
+		buf.WriteString(line)
+	}
+	return buf.Bytes()
+}
+
+// leadingJSONString decodes the JSON string that starts s.
+func leadingJSONString(s string) (string, bool) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			var key string
+			if err := json.Unmarshal([]byte(s[:i+1]), &key); err != nil {
+				return "", false
+			}
+			return key, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/config"
+	convertlib "bennypowers.dev/asimonim/convert"
+	"bennypowers.dev/asimonim/internal/mapfs"
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/specifier"
+)
+
+func TestCollectJSONCComments(t *testing.T) {
+	data := []byte(`// Brand tokens
+{
+  // Colors
+  "color": {
+    /*
+     * Primary brand color,
+     * used for buttons
+     */
+    "primary": { "$type": "color", "$value": "#ff0000" }, // trailing
+    "secondary": { "$type": "color", "$value": "#00ff00" }
+  }
+}`)
+
+	got := collectComments(data, parser.FormatJSON)
+	want := map[string][]string{
+		"":              {"Brand tokens"},
+		"color":         {"Colors"},
+		"color.primary": {"Primary brand color,", "used for buttons"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d comments, got %v", len(want), got)
+	}
+	for path, lines := range want {
+		if !slices.Equal(got[path], lines) {
+			t.Errorf("comment on %q = %q, want %q", path, got[path], lines)
+		}
+	}
+}
+
+func TestCollectComments_None(t *testing.T) {
+	if got := collectComments([]byte(`{"a": {"$value": "// not a comment"}}`), parser.FormatJSON); got != nil {
+		t.Errorf("expected nil for a file without comments, got %v", got)
+	}
+}
+
+func TestRunInPlace_PreservesJSONCComments(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.jsonc", `// Brand tokens
+{
+  // Colors
+  "color": {
+    // Primary brand color
+    "primary": {"$type": "color", "$value": "#ff0000"}
+  }
+}
+`, 0644)
+	files := []*specifier.ResolvedFile{{Specifier: "tokens.jsonc", Path: "/tokens.jsonc"}}
+
+	if err := runInPlace(mfs, parser.NewJSONParser(), config.Default(), files, schema.Unknown, convertlib.RefStyleDefault, false, false, defaultFileModes); err != nil {
+		t.Fatalf("runInPlace error: %v", err)
+	}
+
+	got, _ := mfs.ReadFile("/tokens.jsonc")
+	want := `// Brand tokens
+{
+  // Colors
+  "color": {
+    // Primary brand color
+    "primary": {
+      "$type": "color",
+      "$value": "#ff0000"
+    }
+  }
+}`
+	if string(got) != want {
+		t.Errorf("expected comments to be preserved, got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunInPlace_PreservesYAMLComments(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.yaml", `# Brand tokens
+
+# Colors
+color:
+  # Primary brand color
+  primary: {$type: color, $value: "#ff0000"}
+`, 0644)
+	files := []*specifier.ResolvedFile{{Specifier: "tokens.yaml", Path: "/tokens.yaml"}}
+
+	if err := runInPlace(mfs, parser.NewJSONParser(), config.Default(), files, schema.Unknown, convertlib.RefStyleDefault, false, false, defaultFileModes); err != nil {
+		t.Fatalf("runInPlace error: %v", err)
+	}
+
+	got, _ := mfs.ReadFile("/tokens.yaml")
+	want := `# Brand tokens
+
+# Colors
+color:
+  # Primary brand color
+  primary:
+    $type: color
+    $value: '#ff0000'
+`
+	if string(got) != want {
+		t.Errorf("expected comments to be preserved, got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunInPlace_YAMLWithoutComments(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.yaml", "color: {red: {$type: color, $value: \"#ff0000\"}}\n", 0644)
+	files := []*specifier.ResolvedFile{{Specifier: "tokens.yaml", Path: "/tokens.yaml"}}
+
+	if err := runInPlace(mfs, parser.NewJSONParser(), config.Default(), files, schema.Unknown, convertlib.RefStyleDefault, false, false, defaultFileModes); err != nil {
+		t.Fatalf("runInPlace error: %v", err)
+	}
+
+	got, _ := mfs.ReadFile("/tokens.yaml")
+	want := `color:
+  red:
+    $type: color
+    $value: '#ff0000'
+`
+	if string(got) != want {
+		t.Fatalf("expected YAML output, got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// The canonical file is left alone and passes --check
+	if err := runInPlace(mfs, parser.NewJSONParser(), config.Default(), files, schema.Unknown, convertlib.RefStyleDefault, false, true, defaultFileModes); err != nil {
+		t.Errorf("expected canonical YAML to pass the check, got %v", err)
+	}
+}
+
+func TestRunInPlace_DropsCommentsAcrossSchemas(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.jsonc", `{
+  // Primary brand color
+  "color": {"primary": {"$type": "color", "$value": "#ff0000"}}
+}`, 0644)
+	files := []*specifier.ResolvedFile{{Specifier: "tokens.jsonc", Path: "/tokens.jsonc"}}
+
+	if err := runInPlace(mfs, parser.NewJSONParser(), config.Default(), files, schema.V2025_10, convertlib.RefStyleDefault, false, false, defaultFileModes); err != nil {
+		t.Fatalf("runInPlace error: %v", err)
+	}
+
+	got, _ := mfs.ReadFile("/tokens.jsonc")
+	if strings.Contains(string(got), "// Primary") {
+		t.Errorf("expected no comments after a schema change, got:\n%s", got)
+	}
+}
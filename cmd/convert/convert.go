@@ -8,13 +8,17 @@ license that can be found in the LICENSE file.
 package convert
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,6 +27,7 @@ import (
 	convertlib "bennypowers.dev/asimonim/convert"
 	"bennypowers.dev/asimonim/convert/formatter"
 	"bennypowers.dev/asimonim/fs"
+	"bennypowers.dev/asimonim/internal/logger"
 	"bennypowers.dev/asimonim/parser"
 	"bennypowers.dev/asimonim/resolver"
 	"bennypowers.dev/asimonim/schema"
@@ -42,18 +47,30 @@ func NewCmd() *cobra.Command {
 
 Output Formats:
   dtcg       DTCG-compliant JSON (default)
+  yaml       DTCG structure as YAML
   json       Flat key-value JSON
   android    Android-style XML resources
   swift      iOS Swift constants with native SwiftUI Color
   js         JavaScript/TypeScript (use --js-module, --js-types, --js-export for options)
   scss       SCSS variables with kebab-case names
   css        CSS custom properties (use --css-selector and --css-module for options)
-  snippets   Editor snippets (use --snippet-type for vscode, textmate, or zed)
+  css-custom-media  @custom-media rules for dimension tokens (use --custom-media-group)
+  snippets   Editor snippets (use --snippet-type for vscode, textmate, zed, or sublime)
+  tokens-studio  Tokens Studio for Figma JSON
+  html       Self-contained HTML page with color swatches
+  ios-assets Xcode asset catalog of color sets (--output is the .xcassets directory)
+  template   Custom Go text/template output (use --template-file)
 
 Examples:
   # Flatten to shallow structure
   asimonim convert --flatten tokens/*.yaml
 
+  # Flatten only the innermost group level
+  asimonim convert --flatten-depth 1 tokens/*.yaml
+
+  # Round-trip DTCG JSON to YAML
+  asimonim convert --format yaml -o tokens.yaml tokens.json
+
   # Convert to TypeScript module (default JS output)
   asimonim convert --format js -o tokens.ts tokens/*.yaml
 
@@ -66,12 +83,18 @@ Examples:
   # Convert to SCSS variables
   asimonim convert --format scss -o _tokens.scss tokens/*.yaml
 
+  # Convert to a nested SCSS map with a token() accessor
+  asimonim convert --format scss --scss-map -o _tokens.scss tokens/*.yaml
+
   # Convert to CSS custom properties
   asimonim convert --format css -o tokens.css tokens/*.yaml
 
   # Convert to CSS with :host selector (for shadow DOM)
   asimonim convert --format css --css-selector :host -o tokens.css tokens/*.yaml
 
+  # Generate one CSS file with a [data-theme] block per theme file
+  asimonim convert --format css --theme-attribute data-theme --theme-names light,dark -o themes.css light.json dark.json
+
   # Convert to Lit CSS module
   asimonim convert --format css --css-module lit -o tokens.css.ts tokens/*.yaml
 
@@ -84,6 +107,12 @@ Examples:
   # In-place schema conversion
   asimonim convert --in-place --schema v2025.10 tokens/*.yaml
 
+  # In CI, list files that aren't in canonical form without rewriting them
+  asimonim convert --in-place --check tokens/*.yaml
+
+  # In CI, check inputs for schema consistency before generating anything
+  asimonim convert --validate --format css -o tokens.css tokens/*.yaml
+
   # Multi-output mode: generate multiple formats at once
   asimonim convert --outputs scss:tokens.scss --outputs js:tokens.ts tokens/*.yaml
 
@@ -107,24 +136,61 @@ Examples:
   asimonim convert --format snippets --snippet-type textmate -o tokens.tmSnippet tokens/*.yaml
 
   # Generate Zed editor snippets
-  asimonim convert --format snippets --snippet-type zed -o css.json tokens/*.yaml`,
+  asimonim convert --format snippets --snippet-type zed -o css.json tokens/*.yaml
+
+  # Generate Sublime Text completions
+  asimonim convert --format snippets --snippet-type sublime -o tokens.sublime-completions tokens/*.yaml
+
+  # Stamp generated files with the commit they were built from
+  asimonim convert --format css --banner-from-git -o tokens.css tokens/*.yaml
+
+  # Render a custom Go template
+  asimonim convert --format template --template-file tokens.tmpl -o tokens.txt tokens/*.yaml`,
 		Args: cobra.ArbitraryArgs,
 		RunE: run,
 	}
 	cmd.Flags().StringP("output", "o", "", "Output file (default: stdout)")
 	cmd.Flags().StringP("format", "f", "dtcg", "Output format: "+strings.Join(convertlib.ValidFormats(), ", "))
 	cmd.Flags().Bool("flatten", false, "Flatten to shallow structure (dtcg/json formats only)")
+	cmd.Flags().Int("flatten-depth", 0, "Flatten only the innermost N group levels, keeping outer groups nested (dtcg/yaml formats only)")
 	cmd.Flags().StringP("delimiter", "d", "-", "Delimiter for flattened keys")
 	cmd.Flags().BoolP("in-place", "i", false, "Overwrite input files with converted output")
+	cmd.Flags().Bool("force", false, "With --in-place, rewrite files even when the output is unchanged")
+	cmd.Flags().Bool("check", false, "With --in-place, list files that would change and exit non-zero, without writing them")
 	cmd.Flags().StringArray("outputs", nil, "Multiple outputs as format:path pairs (repeatable, supports {group} template)")
+	cmd.Flags().String("manifest", "", "With multiple outputs, write a JSON manifest of the generated files to this path")
+	cmd.Flags().Bool("skip-unchanged", false, "Leave output files alone when their content would not change")
+	cmd.Flags().String("file-mode", "", "Octal permissions for written files, e.g. 0600 (default: config fileMode, or 0644)")
+	cmd.Flags().String("dir-mode", "", "Octal permissions for created directories, e.g. 0700 (default: config dirMode, or 0755)")
+	cmd.Flags().String("ref-style", "", "Reference syntax in dtcg/yaml output: curly, slash, or json-ref (default: per schema)")
+	cmd.Flags().Bool("hoist-types", false, "Write a $type shared by a whole group once on the group (dtcg/yaml formats only)")
+	cmd.Flags().StringSlice("strip-meta", nil, "Metadata to omit from dtcg/yaml/tokens-studio output: extensions, descriptions")
 	cmd.Flags().String("split-by", "topLevel", "Split strategy: topLevel (default), type, or path[N]")
 	cmd.Flags().String("header", "", "Header to prepend to output (use @path to read from file)")
+	cmd.Flags().Bool("banner-from-git", false, "Add the git commit, date, and remote to the header")
 	cmd.Flags().String("css-selector", ":root", "CSS selector for custom properties: :root (default), :host")
+	cmd.Flags().Bool("css-references", false, "Write aliases in css output as var() references instead of resolved values")
+	cmd.Flags().Bool("include-placeholders", false, "Write tokens with a null $value in css and scss output")
+	cmd.Flags().String("theme-attribute", "", "Write each input file as a theme in one css file, selected by this attribute, e.g. data-theme")
+	cmd.Flags().StringSlice("theme-names", nil, "Theme names for --theme-attribute, one per input file (default: file names)")
 	cmd.Flags().String("css-module", "", "JavaScript module wrapper for CSS: lit (Lit css tagged template), or empty for plain CSS")
-	cmd.Flags().String("snippet-type", "vscode", "Snippet output format: vscode (default), textmate, zed")
+	cmd.Flags().String("custom-media-group", "breakpoint", "Token group for css-custom-media format (dot-separated path)")
+	cmd.Flags().Bool("group-comments", true, "Write group comments in style output (scss)")
+	cmd.Flags().Bool("scss-map", false, "Write scss output as a nested $tokens map with a token() accessor function")
+	cmd.Flags().Bool("hex8", false, "Write translucent sRGB colors as #RRGGBBAA in css and scss output")
+	cmd.Flags().String("color-syntax", "modern", "CSS color functions in css and scss output: modern (default), legacy (rgb(), hsl())")
+	cmd.Flags().String("snippet-type", "vscode", "Snippet output format: vscode (default), textmate, zed, sublime")
 	cmd.Flags().String("js-module", "esm", "JS module format: esm (default), cjs")
 	cmd.Flags().String("js-types", "ts", "JS type system: ts (default), jsdoc")
 	cmd.Flags().String("js-export", "values", "JS export form: values (default), map")
+	cmd.Flags().Bool("no-jsdoc", false, "Leave doc comments out of TypeScript output (js format)")
+	cmd.Flags().Float64("root-font-size", formatter.DefaultRootPx, "Size of 1rem in px, for converting rem and em dimensions in swift output")
+	cmd.Flags().String("template-file", "", "Go text/template file for template format")
+	cmd.Flags().Bool("strip-deprecated", false, "Exclude deprecated tokens from output")
+	cmd.Flags().String("transform-color", "none", "Colors outside sRGB: none (default), srgb (gamut-map to sRGB), srgb-only (drop them)")
+	cmd.Flags().Int("concurrency", runtime.GOMAXPROCS(0), "Number of files to parse in parallel (1 parses serially)")
+	cmd.Flags().Bool("validate", false, "Check input files for schema consistency and abort before writing output if any fail")
+	cmd.Flags().Bool("include-private", false, "Include private tokens (names starting with \"_\" or the configured privatePrefix)")
 	return cmd
 }
 
@@ -132,24 +198,60 @@ func run(cmd *cobra.Command, args []string) error {
 	output, _ := cmd.Flags().GetString("output")
 	formatFlag, _ := cmd.Flags().GetString("format")
 	flatten, _ := cmd.Flags().GetBool("flatten")
+	flattenDepth, _ := cmd.Flags().GetInt("flatten-depth")
 	delimiter, _ := cmd.Flags().GetString("delimiter")
 	inPlace, _ := cmd.Flags().GetBool("in-place")
+	force, _ := cmd.Flags().GetBool("force")
+	check, _ := cmd.Flags().GetBool("check")
 	schemaFlag, _ := cmd.Flags().GetString("schema")
 	outputsFlag, _ := cmd.Flags().GetStringArray("outputs")
+	manifestPath, _ := cmd.Flags().GetString("manifest")
+	skipUnchanged, _ := cmd.Flags().GetBool("skip-unchanged")
+	fileModeFlag, _ := cmd.Flags().GetString("file-mode")
+	dirModeFlag, _ := cmd.Flags().GetString("dir-mode")
 	splitByFlag, _ := cmd.Flags().GetString("split-by")
 	headerFlag, _ := cmd.Flags().GetString("header")
+	bannerFromGit, _ := cmd.Flags().GetBool("banner-from-git")
 	cssSelector, _ := cmd.Flags().GetString("css-selector")
 	cssModule, _ := cmd.Flags().GetString("css-module")
+	customMediaGroup, _ := cmd.Flags().GetString("custom-media-group")
+	groupComments, _ := cmd.Flags().GetBool("group-comments")
+	scssMap, _ := cmd.Flags().GetBool("scss-map")
+	hex8, _ := cmd.Flags().GetBool("hex8")
+	colorSyntaxFlag, _ := cmd.Flags().GetString("color-syntax")
+	cssReferences, _ := cmd.Flags().GetBool("css-references")
+	includePlaceholders, _ := cmd.Flags().GetBool("include-placeholders")
+	themeAttribute, _ := cmd.Flags().GetString("theme-attribute")
+	themeNamesFlag, _ := cmd.Flags().GetStringSlice("theme-names")
+	refStyleFlag, _ := cmd.Flags().GetString("ref-style")
+	stripMetaFlag, _ := cmd.Flags().GetStringSlice("strip-meta")
+	hoistTypes, _ := cmd.Flags().GetBool("hoist-types")
 	snippetType, _ := cmd.Flags().GetString("snippet-type")
 	jsModule, _ := cmd.Flags().GetString("js-module")
 	jsTypes, _ := cmd.Flags().GetString("js-types")
 	jsExport, _ := cmd.Flags().GetString("js-export")
+	noJSDoc, _ := cmd.Flags().GetBool("no-jsdoc")
+	rootFontSize, _ := cmd.Flags().GetFloat64("root-font-size")
+	templateFile, _ := cmd.Flags().GetString("template-file")
+	stripDeprecatedFlag, _ := cmd.Flags().GetBool("strip-deprecated")
+	includePrivate, _ := cmd.Flags().GetBool("include-private")
+	transformColorFlag, _ := cmd.Flags().GetString("transform-color")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	validate, _ := cmd.Flags().GetBool("validate")
 
 	// Parse format
 	format, err := convertlib.ParseFormat(formatFlag)
 	if err != nil {
 		return err
 	}
+	colorTransform, err := convertlib.ParseColorTransform(transformColorFlag)
+	if err != nil {
+		return err
+	}
+	colorSyntax, err := convertlib.ParseColorSyntax(colorSyntaxFlag)
+	if err != nil {
+		return err
+	}
 
 	// Parse CLI outputs flag into OutputSpecs
 	var cliOutputs []config.OutputSpec
@@ -172,6 +274,30 @@ func run(cmd *cobra.Command, args []string) error {
 	if inPlace && flatten {
 		return fmt.Errorf("--in-place and --flatten are mutually exclusive")
 	}
+	if flattenDepth < 0 {
+		return fmt.Errorf("--flatten-depth must not be negative")
+	}
+	if rootFontSize <= 0 {
+		return fmt.Errorf("--root-font-size must be positive")
+	}
+	if concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if flatten && flattenDepth > 0 {
+		return fmt.Errorf("--flatten and --flatten-depth are mutually exclusive")
+	}
+	if inPlace && flattenDepth > 0 {
+		return fmt.Errorf("--in-place and --flatten-depth are mutually exclusive")
+	}
+	if force && !inPlace {
+		return fmt.Errorf("--force requires --in-place")
+	}
+	if check && !inPlace {
+		return fmt.Errorf("--check requires --in-place")
+	}
+	if check && force {
+		return fmt.Errorf("--check and --force are mutually exclusive")
+	}
 	if inPlace && format != convertlib.FormatDTCG {
 		return fmt.Errorf("--in-place only supports dtcg format")
 	}
@@ -181,8 +307,58 @@ func run(cmd *cobra.Command, args []string) error {
 	if len(cliOutputs) > 0 && inPlace {
 		return fmt.Errorf("--outputs and --in-place are mutually exclusive")
 	}
+	if inPlace && stripDeprecatedFlag {
+		return fmt.Errorf("--in-place and --strip-deprecated are mutually exclusive")
+	}
+	if inPlace && colorTransform != convertlib.ColorTransformNone {
+		return fmt.Errorf("--in-place and --transform-color are mutually exclusive")
+	}
+	if inPlace && len(stripMetaFlag) > 0 {
+		return fmt.Errorf("--in-place and --strip-meta are mutually exclusive")
+	}
+	if inPlace && hoistTypes {
+		return fmt.Errorf("--in-place and --hoist-types are mutually exclusive")
+	}
+	if flatten && hoistTypes {
+		return fmt.Errorf("--flatten and --hoist-types are mutually exclusive")
+	}
+	if len(themeNamesFlag) > 0 && themeAttribute == "" {
+		return fmt.Errorf("--theme-names requires --theme-attribute")
+	}
+	if themeAttribute != "" {
+		if format != convertlib.FormatCSS {
+			return fmt.Errorf("--theme-attribute only supports css format")
+		}
+		if !attributeNamePattern.MatchString(themeAttribute) {
+			return fmt.Errorf("invalid --theme-attribute %q: expected an attribute name, e.g. data-theme", themeAttribute)
+		}
+		if inPlace || len(cliOutputs) > 0 {
+			return fmt.Errorf("--theme-attribute cannot be combined with --in-place or --outputs")
+		}
+	}
+
+	var stripExtensions, stripDescriptions bool
+	for _, key := range stripMetaFlag {
+		switch key {
+		case "extensions":
+			stripExtensions = true
+		case "descriptions":
+			stripDescriptions = true
+		default:
+			return fmt.Errorf("invalid --strip-meta key %q: expected extensions or descriptions", key)
+		}
+	}
+	if format == convertlib.FormatIOSAssets && output == "" && len(cliOutputs) == 0 {
+		return fmt.Errorf("--format ios-assets requires --output, the .xcassets directory to write")
+	}
+	if noJSDoc && jsTypes == "jsdoc" {
+		return fmt.Errorf("--no-jsdoc cannot be combined with --js-types jsdoc, which writes types as JSDoc")
+	}
+	if format == convertlib.FormatTemplate && templateFile == "" && len(cliOutputs) == 0 {
+		return fmt.Errorf("--format template requires --template-file")
+	}
 
-	filesystem := fs.NewOSFileSystem()
+	filesystem := fs.WithURLs(fs.NewOSFileSystem(), nil)
 	jsonParser := parser.NewJSONParser()
 
 	cwd, err := os.Getwd()
@@ -240,8 +416,24 @@ func run(cmd *cobra.Command, args []string) error {
 		targetSchema = cfg.SchemaVersion()
 	}
 
+	refStyle, err := convertlib.ParseRefStyle(refStyleFlag)
+	if err != nil {
+		return err
+	}
+
+	modes, err := resolveFileModes(fileModeFlag, dirModeFlag, cfg)
+	if err != nil {
+		return err
+	}
+
+	if validate {
+		if err := validateInputs(filesystem, resolvedFiles); err != nil {
+			return err
+		}
+	}
+
 	if inPlace {
-		return runInPlace(filesystem, jsonParser, cfg, resolvedFiles, targetSchema)
+		return runInPlace(filesystem, jsonParser, cfg, resolvedFiles, targetSchema, refStyle, force, check, modes)
 	}
 
 	// Resolve header content
@@ -249,19 +441,141 @@ func run(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("error resolving header: %w", err)
 	}
+	header = applyGitHeader(header, bannerFromGit, cwd)
+
+	var tmpl string
+	if templateFile != "" {
+		data, err := filesystem.ReadFile(templateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read template file %s: %w", templateFile, err)
+		}
+		tmpl = string(data)
+	}
+
+	// Get prefix from viper (CLI flag or config file)
+	prefix := viper.GetString("prefix")
+	if prefix == "" {
+		prefix = cfg.Prefix
+	}
+
+	// InputSchema is filled in once the files are parsed, as is
+	// OutputSchema when no target schema was given.
+	opts := convertlib.Options{
+		OutputSchema:        targetSchema,
+		RefStyle:            refStyle,
+		StripExtensions:     stripExtensions,
+		StripDescriptions:   stripDescriptions,
+		HoistTypes:          hoistTypes,
+		Flatten:             flatten,
+		FlattenDepth:        flattenDepth,
+		Delimiter:           delimiter,
+		Format:              format,
+		Prefix:              prefix,
+		Header:              header,
+		CSSSelector:         cssSelector,
+		CSSModule:           cssModule,
+		CustomMediaGroup:    customMediaGroup,
+		OmitGroupComments:   !groupComments,
+		SCSSMap:             scssMap,
+		Hex8:                hex8,
+		ColorSyntax:         colorSyntax,
+		CSSReferences:       cssReferences,
+		IncludePlaceholders: includePlaceholders,
+		SnippetType:         snippetType,
+		JSModule:            jsModule,
+		JSTypes:             jsTypes,
+		JSExport:            jsExport,
+		OmitJSDoc:           noJSDoc,
+		RootFontSize:        rootFontSize,
+		Template:            tmpl,
+		OnWarning:           func(w formatter.Warning) { logger.Warn("%s", w) },
+	}
+	settings := runSettings{
+		filesystem:     filesystem,
+		jsonParser:     jsonParser,
+		cfg:            cfg,
+		files:          resolvedFiles,
+		opts:           opts,
+		concurrency:    concurrency,
+		skipUnchanged:  skipUnchanged,
+		modes:          modes,
+		strip:          stripDeprecatedFlag,
+		includePrivate: includePrivate,
+		colorTransform: colorTransform,
+	}
+
+	if themeAttribute != "" {
+		return runThemes(settings, themeAttribute, themeNamesFlag, output)
+	}
 
 	outputs := cliOutputs
 	if len(outputs) == 0 && len(cfg.Outputs) > 0 && output == "" {
 		// Use config outputs only if no single output is specified
 		outputs = cfg.Outputs
 	}
+	if manifestPath != "" && len(outputs) == 0 {
+		return fmt.Errorf("--manifest requires --outputs or outputs in config")
+	}
 
 	// Multi-output mode
 	if len(outputs) > 0 {
-		return runMultiOutput(filesystem, jsonParser, cfg, resolvedFiles, targetSchema, outputs, header, cssSelector, cssModule, snippetType, jsModule, jsTypes, jsExport)
+		return runMultiOutput(settings, outputs, manifestPath)
+	}
+
+	return runCombined(settings, output)
+}
+
+// runSettings are the settings of a convert run: the files it reads, how
+// it filters their tokens, and how it formats and writes them.
+type runSettings struct {
+	filesystem     fs.FileSystem
+	jsonParser     *parser.JSONParser
+	cfg            *config.Config
+	files          []*specifier.ResolvedFile
+	opts           convertlib.Options
+	concurrency    int
+	skipUnchanged  bool
+	modes          fileModes
+	strip          bool
+	includePrivate bool
+	colorTransform convertlib.ColorTransform
+}
+
+// filter applies the deprecated, private, and color filters to tokens
+// whose aliases are resolved.
+func (s runSettings) filter(tokens []*token.Token) []*token.Token {
+	if s.strip {
+		tokens = stripDeprecated(tokens)
+	}
+	if !s.includePrivate {
+		tokens = stripPrivate(tokens, s.cfg)
+	}
+	return transformColors(tokens, s.colorTransform)
+}
+
+// parse parses the run's files, resolves aliases, and filters the
+// tokens, returning them with the detected schema version.
+func (s runSettings) parse() ([]*token.Token, schema.Version, error) {
+	tokens, version, err := parseAndResolveTokens(s.filesystem, s.jsonParser, s.cfg, s.files, s.concurrency)
+	if err != nil {
+		return nil, version, err
 	}
+	return s.filter(tokens), version, nil
+}
 
-	return runCombined(filesystem, jsonParser, cfg, resolvedFiles, targetSchema, output, format, flatten, delimiter, header, cssSelector, cssModule, snippetType, jsModule, jsTypes, jsExport)
+// write writes data to path, skipping unchanged files if asked to.
+func (s runSettings) write(path string, data []byte) (bool, error) {
+	return writeOutput(s.filesystem, path, data, s.skipUnchanged, s.modes)
+}
+
+// withSchemas returns opts for tokens detected as inputSchema, which is
+// also the output schema unless opts names one.
+func withSchemas(opts convertlib.Options, inputSchema schema.Version) convertlib.Options {
+	opts.InputSchema = inputSchema
+	if opts.OutputSchema == schema.Unknown {
+		opts.OutputSchema = inputSchema
+	}
+	return opts
 }
 
 // resolveHeader resolves the header content from a flag value or config.
@@ -288,28 +602,36 @@ func resolveHeader(filesystem fs.FileSystem, headerFlag, cfgHeader string) (stri
 	return header, nil
 }
 
+// runInPlace rewrites each file in canonical form. With check, files
+// that would change are listed on stdout instead of being written, and
+// an error is returned if there are any.
 func runInPlace(
 	filesystem fs.FileSystem,
 	jsonParser *parser.JSONParser,
 	cfg *config.Config,
 	resolvedFiles []*specifier.ResolvedFile,
 	targetSchema schema.Version,
+	refStyle convertlib.RefStyle,
+	force bool,
+	check bool,
+	modes fileModes,
 ) error {
-	var failures int
+	var failures, converted, unchanged, changed int
 	for _, rf := range resolvedFiles {
 		data, err := filesystem.ReadFile(rf.Path)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", rf.Specifier, err)
+			logger.Error("Error reading %s: %v", rf.Specifier, err)
 			failures++
 			continue
 		}
 
 		detectedVersion, err := schema.DetectVersion(data, nil)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error detecting schema for %s: %v\n", rf.Specifier, err)
+			logger.Error("Error detecting schema for %s: %v", rf.Specifier, err)
 			failures++
 			continue
 		}
+		logger.Debug("Detected schema %s for %s", detectedVersion, rf.Specifier)
 
 		outputSchema := targetSchema
 		if outputSchema == schema.Unknown {
@@ -324,13 +646,13 @@ func runInPlace(
 
 		tokens, err := jsonParser.ParseFile(filesystem, rf.Path, opts)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", rf.Specifier, err)
+			logger.Error("Error parsing %s: %v", rf.Specifier, err)
 			failures++
 			continue
 		}
 
 		if err := resolver.ResolveAliases(tokens, detectedVersion); err != nil {
-			fmt.Fprintf(os.Stderr, "Resolution error in %s: %v\n", rf.Specifier, err)
+			logger.Error("Resolution error in %s: %v", rf.Specifier, err)
 			failures++
 			continue
 		}
@@ -338,83 +660,83 @@ func runInPlace(
 		result := convertlib.Serialize(tokens, convertlib.Options{
 			InputSchema:  detectedVersion,
 			OutputSchema: outputSchema,
+			RefStyle:     refStyle,
 			Flatten:      false,
 			Delimiter:    "-",
 		})
-		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		// Comments only survive a same-schema rewrite, where token
+		// paths are unchanged.
+		format := parser.FormatFromPath(rf.Path)
+		var comments commentMap
+		if outputSchema == detectedVersion {
+			comments = collectComments(data, format)
+		}
+		out, err := marshalInPlace(result, format, comments)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error serializing %s: %v\n", rf.Specifier, err)
+			logger.Error("Error serializing %s: %v", rf.Specifier, err)
 			failures++
 			continue
 		}
 
-		if err := filesystem.WriteFile(rf.Path, jsonBytes, 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", rf.Specifier, err)
+		// Skip identical output to avoid touching mtimes; a trailing
+		// newline in the input doesn't count as a difference.
+		if !force && bytes.Equal(bytes.TrimSuffix(out, []byte("\n")), bytes.TrimSuffix(data, []byte("\n"))) {
+			unchanged++
+			continue
+		}
+
+		if check {
+			fmt.Println(rf.Specifier)
+			changed++
+			continue
+		}
+
+		// Source files keep their permissions unless a mode was asked for.
+		if modes.fileSet {
+			err = writeFile(filesystem, rf.Path, out, modes.file)
+		} else {
+			err = filesystem.WriteFile(rf.Path, out, modes.file)
+		}
+		if err != nil {
+			logger.Error("Error writing %s: %v", rf.Specifier, err)
 			failures++
 			continue
 		}
+		converted++
+	}
+
+	if check {
+		logger.Info("Checked %d files, %d would change", changed+unchanged, changed)
+	} else {
+		logger.Info("Converted %d files, %d unchanged", converted, unchanged)
 	}
 
 	if failures > 0 {
 		return fmt.Errorf("failed to convert %d file(s)", failures)
 	}
+	if changed > 0 {
+		return fmt.Errorf("%d file(s) not in canonical form", changed)
+	}
 	return nil
 }
 
-func runCombined(
-	filesystem fs.FileSystem,
-	jsonParser *parser.JSONParser,
-	cfg *config.Config,
-	resolvedFiles []*specifier.ResolvedFile,
-	targetSchema schema.Version,
-	output string,
-	format convertlib.Format,
-	flatten bool,
-	delimiter string,
-	header string,
-	cssSelector string,
-	cssModule string,
-	snippetType string,
-	jsModule string,
-	jsTypes string,
-	jsExport string,
-) error {
+func runCombined(s runSettings, output string) error {
 	// Parse all files and resolve aliases
-	allTokens, detectedVersion, err := parseAndResolveTokens(filesystem, jsonParser, cfg, resolvedFiles)
+	allTokens, detectedVersion, err := s.parse()
 	if err != nil {
 		return err
 	}
+	s.opts = withSchemas(s.opts, detectedVersion)
+	format := s.opts.Format
 
-	// Determine output schema
-	outputSchema := targetSchema
-	if outputSchema == schema.Unknown {
-		outputSchema = detectedVersion
-	}
-
-	// Get prefix from viper (CLI flag or config file)
-	prefix := viper.GetString("prefix")
-	if prefix == "" {
-		prefix = cfg.Prefix
+	// An asset catalog is a directory, so it can't go to stdout
+	if format == convertlib.FormatIOSAssets {
+		_, err := writeAssetCatalog(s, output, allTokens)
+		return err
 	}
 
 	// Phase 3: Serialize tokens to requested format
-	opts := convertlib.Options{
-		InputSchema:  detectedVersion,
-		OutputSchema: outputSchema,
-		Flatten:      flatten,
-		Delimiter:    delimiter,
-		Format:       format,
-		Prefix:       prefix,
-		Header:       header,
-		CSSSelector:  cssSelector,
-		CSSModule:    cssModule,
-		SnippetType:  snippetType,
-		JSModule:     jsModule,
-		JSTypes:      jsTypes,
-		JSExport:      jsExport,
-	}
-
-	outputBytes, err := convertlib.FormatTokens(allTokens, format, opts)
+	outputBytes, err := convertlib.FormatTokens(allTokens, format, s.opts)
 	if err != nil {
 		return fmt.Errorf("error formatting output: %w", err)
 	}
@@ -426,7 +748,7 @@ func runCombined(
 
 	// Phase 4: Write output
 	if output != "" {
-		if err := filesystem.WriteFile(output, outputBytes, 0644); err != nil {
+		if _, err := s.write(output, outputBytes); err != nil {
 			return fmt.Errorf("error writing to %s: %w", output, err)
 		}
 		return nil
@@ -440,113 +762,126 @@ func runCombined(
 // pathIndexPattern matches path[N] split-by values.
 var pathIndexPattern = regexp.MustCompile(`^path\[(\d+)\]$`)
 
-func runMultiOutput(
-	filesystem fs.FileSystem,
-	jsonParser *parser.JSONParser,
-	cfg *config.Config,
-	resolvedFiles []*specifier.ResolvedFile,
-	targetSchema schema.Version,
-	outputs []config.OutputSpec,
-	header string,
-	cssSelector string,
-	cssModule string,
-	snippetType string,
-	jsModule string,
-	jsTypes string,
-	jsExport string,
-) error {
+func runMultiOutput(s runSettings, outputs []config.OutputSpec, manifestPath string) error {
 	// Parse all files and resolve aliases
-	allTokens, detectedVersion, err := parseAndResolveTokens(filesystem, jsonParser, cfg, resolvedFiles)
+	allTokens, detectedVersion, err := s.parse()
 	if err != nil {
 		return err
 	}
-
-	// Determine output schema
-	outputSchema := targetSchema
-	if outputSchema == schema.Unknown {
-		outputSchema = detectedVersion
-	}
-
-	// Get global prefix
-	prefix := viper.GetString("prefix")
-	if prefix == "" {
-		prefix = cfg.Prefix
-	}
+	s.opts = withSchemas(s.opts, detectedVersion)
 
 	// Phase 3: Generate each output
 	var failures int
+	var written []manifestEntry
 	for _, out := range outputs {
 		format, err := convertlib.ParseFormat(out.Format)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing format for %s: %v\n", out.Path, err)
+			logger.Error("Error parsing format for %s: %v", out.Path, err)
 			failures++
 			continue
 		}
 
-		// Use output-specific prefix if set, otherwise global
-		outPrefix := out.Prefix
-		if outPrefix == "" {
-			outPrefix = prefix
+		tokens := allTokens
+		if out.Type != "" {
+			tokens = filterByType(allTokens, out.Type)
+		}
+
+		// Each output sets its own format, flattening, and delimiter,
+		// and may override the global prefix
+		outOpts := s.opts
+		outOpts.Format = format
+		outOpts.Flatten = out.Flatten
+		outOpts.FlattenDepth = 0
+		outOpts.Delimiter = out.Delimiter
+		if outOpts.Delimiter == "" {
+			outOpts.Delimiter = "-"
 		}
+		if out.Prefix != "" {
+			outOpts.Prefix = out.Prefix
+		}
+		outSettings := s
+		outSettings.opts = outOpts
 
-		// Use output-specific delimiter if set
-		delimiter := out.Delimiter
-		if delimiter == "" {
-			delimiter = "-"
+		if format == convertlib.FormatIOSAssets && strings.Contains(out.Path, "{group}") {
+			logger.Error("Error generating %s: ios-assets writes one catalog and can't be split by {group}", out.Path)
+			failures++
+			continue
 		}
 
 		// Check if this is a split output (path contains {group})
 		if strings.Contains(out.Path, "{group}") {
-			if err := generateSplitOutput(filesystem, allTokens, out, format, outPrefix, delimiter, detectedVersion, outputSchema, header, cssSelector, cssModule, snippetType, jsModule, jsTypes, jsExport); err != nil {
-				fmt.Fprintf(os.Stderr, "Error generating split output %s: %v\n", out.Path, err)
+			entries, err := generateSplitOutput(outSettings, tokens, out)
+			written = append(written, entries...)
+			if err != nil {
+				logger.Error("Error generating split output %s: %v", out.Path, err)
 				failures++
 			}
 			continue
 		}
 
-		// Regular single-file output
-		opts := convertlib.Options{
-			InputSchema:  detectedVersion,
-			OutputSchema: outputSchema,
-			Flatten:      out.Flatten,
-			Delimiter:    delimiter,
-			Format:       format,
-			Prefix:       outPrefix,
-			Header:       header,
-			CSSSelector:  cssSelector,
-			CSSModule:    cssModule,
-			SnippetType:  snippetType,
-			JSModule:     jsModule,
-			JSTypes:      jsTypes,
-			JSExport:     jsExport,
-		}
-
-		outputBytes, err := convertlib.FormatTokens(allTokens, format, opts)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", out.Path, err)
+		if out.EmitDTS && (format != convertlib.FormatJS || s.opts.JSExport != "map") {
+			logger.Error("Error generating %s: emitDts requires the js format with --js-export map", out.Path)
 			failures++
 			continue
 		}
 
-		// Append newline for proper file formatting (if not already present)
-		if len(outputBytes) > 0 && outputBytes[len(outputBytes)-1] != '\n' {
-			outputBytes = append(outputBytes, '\n')
+		if format == convertlib.FormatIOSAssets {
+			paths, err := writeAssetCatalog(outSettings, out.Path, tokens)
+			for _, path := range paths {
+				written = append(written, manifestEntry{Path: path, Format: string(format), Tokens: len(tokens)})
+			}
+			if err != nil {
+				logger.Error("Error generating %s: %v", out.Path, err)
+				failures++
+			}
+			continue
 		}
 
-		// Ensure parent directory exists
-		if err := ensureDir(filesystem, out.Path); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating directory for %s: %v\n", out.Path, err)
-			failures++
-			continue
+		// With emitDts, the map is written as plain JavaScript plus a
+		// declaration file describing it.
+		files := []struct{ path, mapMode string }{{out.Path, ""}}
+		if out.EmitDTS {
+			files = []struct{ path, mapMode string }{
+				{out.Path, "js"},
+				{declarationPath(out.Path), "dts"},
+			}
 		}
 
-		if err := filesystem.WriteFile(out.Path, outputBytes, 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing to %s: %v\n", out.Path, err)
-			failures++
-			continue
+		for _, file := range files {
+			outOpts.JSMapMode = file.mapMode
+			outputBytes, err := convertlib.FormatTokens(tokens, format, outOpts)
+			if err != nil {
+				logger.Error("Error formatting %s: %v", file.path, err)
+				failures++
+				break
+			}
+
+			// Append newline for proper file formatting (if not already present)
+			if len(outputBytes) > 0 && outputBytes[len(outputBytes)-1] != '\n' {
+				outputBytes = append(outputBytes, '\n')
+			}
+
+			wrote, err := s.write(file.path, outputBytes)
+			if err != nil {
+				logger.Error("Error writing to %s: %v", file.path, err)
+				failures++
+				break
+			}
+			if wrote {
+				logger.Info("Wrote %s", file.path)
+			}
+			written = append(written, manifestEntry{Path: file.path, Format: string(format), Tokens: len(tokens)})
 		}
+	}
 
-		fmt.Fprintf(os.Stderr, "Wrote %s\n", out.Path)
+	// The manifest lists whatever was written, even when some outputs failed
+	if manifestPath != "" {
+		if err := writeManifest(s.filesystem, manifestPath, written, s.modes); err != nil {
+			logger.Error("Error writing manifest %s: %v", manifestPath, err)
+			failures++
+		} else {
+			logger.Info("Wrote %s", manifestPath)
+		}
 	}
 
 	if failures > 0 {
@@ -555,63 +890,87 @@ func runMultiOutput(
 	return nil
 }
 
+// declarationPath returns the .d.ts path for a JavaScript output, keeping
+// the module flavor: tokens.mjs declares as tokens.d.mts.
+func declarationPath(path string) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	switch ext {
+	case ".mjs":
+		return base + ".d.mts"
+	case ".cjs":
+		return base + ".d.cts"
+	case ".js":
+		return base + ".d.ts"
+	}
+	return path + ".d.ts"
+}
+
+// manifestEntry describes one file written by a multi-output build.
+type manifestEntry struct {
+	Path   string `json:"path"`
+	Format string `json:"format"`
+	Group  string `json:"group,omitempty"`
+	Tokens int    `json:"tokens"`
+}
+
+// writeManifest writes the entries as a JSON manifest, sorted by path so
+// the file is stable across runs.
+func writeManifest(filesystem fs.FileSystem, path string, entries []manifestEntry, modes fileModes) error {
+	slices.SortFunc(entries, func(a, b manifestEntry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	if entries == nil {
+		entries = []manifestEntry{}
+	}
+	data, err := json.MarshalIndent(struct {
+		Files []manifestEntry `json:"files"`
+	}{entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ensureDir(filesystem, path, modes); err != nil {
+		return err
+	}
+	return writeFile(filesystem, path, append(data, '\n'), modes.file)
+}
+
 // generateSplitOutput generates multiple files by splitting tokens based on the splitBy strategy.
-func generateSplitOutput(
-	filesystem fs.FileSystem,
-	allTokens []*token.Token,
-	out config.OutputSpec,
-	format convertlib.Format,
-	prefix string,
-	delimiter string,
-	inputSchema schema.Version,
-	outputSchema schema.Version,
-	header string,
-	cssSelector string,
-	cssModule string,
-	snippetType string,
-	jsModule string,
-	jsTypes string,
-	jsExport string,
-) error {
+// It returns an entry for each file written, including when some files fail.
+func generateSplitOutput(s runSettings, allTokens []*token.Token, out config.OutputSpec) ([]manifestEntry, error) {
+	opts := s.opts
+	format := opts.Format
+	mapExport := format == convertlib.FormatJS && opts.JSExport == "map"
+
 	// Group tokens by split key
 	groups := groupTokens(allTokens, out.SplitBy)
 
 	var failures int
+	var written []manifestEntry
 
 	// For JS with map style, generate shared types file first
-	if format == convertlib.FormatJS && jsExport == "map" {
+	if mapExport {
 		typesPath := computeTypesPath(out.Path)
 
-		opts := convertlib.Options{
-			InputSchema:  inputSchema,
-			OutputSchema: outputSchema,
-			Flatten:      out.Flatten,
-			Delimiter:    delimiter,
-			Format:       format,
-			Prefix:       prefix,
-			Header:       header,
-			JSModule:     jsModule,
-			JSTypes:      jsTypes,
-			JSExport:      jsExport,
-			JSMapMode:    "types",
-		}
-
-		outputBytes, err := convertlib.FormatTokens(nil, format, opts)
+		typesOpts := opts
+		typesOpts.JSMapMode = "types"
+
+		outputBytes, err := convertlib.FormatTokens(nil, format, typesOpts)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", typesPath, err)
+			logger.Error("Error formatting %s: %v", typesPath, err)
 			failures++
 		} else {
 			if len(outputBytes) > 0 && outputBytes[len(outputBytes)-1] != '\n' {
 				outputBytes = append(outputBytes, '\n')
 			}
-			if err := ensureDir(filesystem, typesPath); err != nil {
-				fmt.Fprintf(os.Stderr, "Error creating directory for %s: %v\n", typesPath, err)
-				failures++
-			} else if err := filesystem.WriteFile(typesPath, outputBytes, 0644); err != nil {
-				fmt.Fprintf(os.Stderr, "Error writing to %s: %v\n", typesPath, err)
+			if wrote, err := s.write(typesPath, outputBytes); err != nil {
+				logger.Error("Error writing to %s: %v", typesPath, err)
 				failures++
 			} else {
-				fmt.Fprintf(os.Stderr, "Wrote %s\n", typesPath)
+				if wrote {
+					logger.Info("Wrote %s", typesPath)
+				}
+				written = append(written, manifestEntry{Path: typesPath, Format: string(format)})
 			}
 		}
 	}
@@ -623,32 +982,17 @@ func generateSplitOutput(
 		// Expand path template with sanitized name
 		path := strings.ReplaceAll(out.Path, "{group}", safeName)
 
-		opts := convertlib.Options{
-			InputSchema:  inputSchema,
-			OutputSchema: outputSchema,
-			Flatten:      out.Flatten,
-			Delimiter:    delimiter,
-			Format:       format,
-			Prefix:       prefix,
-			Header:       header,
-			CSSSelector:  cssSelector,
-			CSSModule:    cssModule,
-			SnippetType:  snippetType,
-			JSModule:     jsModule,
-			JSTypes:      jsTypes,
-			JSExport:      jsExport,
-		}
-
+		groupOpts := opts
 		// For JS with map style, use module mode with imports
-		if format == convertlib.FormatJS && jsExport == "map" {
-			opts.JSMapMode = "module"
-			opts.JSMapTypesPath = computeSharedTypesImport(path, out.Path)
-			opts.JSMapClassName = formatter.ToPascalCase(groupName) + "TokenMap"
+		if mapExport {
+			groupOpts.JSMapMode = "module"
+			groupOpts.JSMapTypesPath = computeSharedTypesImport(path, out.Path)
+			groupOpts.JSMapClassName = formatter.ToPascalCase(groupName) + "TokenMap"
 		}
 
-		outputBytes, err := convertlib.FormatTokens(tokens, format, opts)
+		outputBytes, err := convertlib.FormatTokens(tokens, format, groupOpts)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", path, err)
+			logger.Error("Error formatting %s: %v", path, err)
 			failures++
 			continue
 		}
@@ -658,26 +1002,22 @@ func generateSplitOutput(
 			outputBytes = append(outputBytes, '\n')
 		}
 
-		// Ensure parent directory exists
-		if err := ensureDir(filesystem, path); err != nil {
-			fmt.Fprintf(os.Stderr, "Error creating directory for %s: %v\n", path, err)
+		wrote, err := s.write(path, outputBytes)
+		if err != nil {
+			logger.Error("Error writing to %s: %v", path, err)
 			failures++
 			continue
 		}
-
-		if err := filesystem.WriteFile(path, outputBytes, 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Error writing to %s: %v\n", path, err)
-			failures++
-			continue
+		if wrote {
+			logger.Info("Wrote %s", path)
 		}
-
-		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+		written = append(written, manifestEntry{Path: path, Format: string(format), Group: groupName, Tokens: len(tokens)})
 	}
 
 	if failures > 0 {
-		return fmt.Errorf("failed to generate %d split file(s)", failures)
+		return written, fmt.Errorf("failed to generate %d split file(s)", failures)
 	}
-	return nil
+	return written, nil
 }
 
 // computeTypesPath computes the path for the shared types file.
@@ -795,58 +1135,220 @@ func sanitizeGroupName(name string) string {
 	return sb.String()
 }
 
-// ensureDir creates the parent directory for a file path if it doesn't exist.
-func ensureDir(filesystem fs.FileSystem, path string) error {
+// writeOutput writes data to path, creating its parent directory, and
+// reports whether it wrote the file. With skipUnchanged, a file that
+// already holds data is left untouched, so watchers and version control
+// don't see a change, and is reported on stderr as unchanged.
+func writeOutput(filesystem fs.FileSystem, path string, data []byte, skipUnchanged bool, modes fileModes) (bool, error) {
+	if skipUnchanged {
+		if existing, err := filesystem.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+			logger.Info("Unchanged: %s", path)
+			return false, nil
+		}
+	}
+	if err := ensureDir(filesystem, path, modes); err != nil {
+		return false, fmt.Errorf("creating directory: %w", err)
+	}
+	if err := writeFile(filesystem, path, data, modes.file); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeFile writes data to path with mode. WriteFile only applies its mode
+// to new files, and the umask applies on top of it, so the mode is set
+// again afterwards.
+func writeFile(filesystem fs.FileSystem, path string, data []byte, mode os.FileMode) error {
+	if err := filesystem.WriteFile(path, data, mode); err != nil {
+		return err
+	}
+	return filesystem.Chmod(path, mode)
+}
+
+// ensureDir creates the parent directory for a file path if it doesn't
+// exist, and sets modes.dir on every directory it creates. When the
+// directory mode was set explicitly, an existing parent directory gets it
+// too.
+func ensureDir(filesystem fs.FileSystem, path string, modes fileModes) error {
 	dir := filepath.Dir(path)
 	if dir == "" || dir == "." {
 		return nil
 	}
-	return filesystem.MkdirAll(dir, 0755)
+	var created []string
+	for d := dir; filepath.Dir(d) != d && !filesystem.Exists(d); d = filepath.Dir(d) {
+		created = append(created, d)
+	}
+	if err := filesystem.MkdirAll(dir, modes.dir); err != nil {
+		return err
+	}
+	if len(created) == 0 && modes.dirSet {
+		created = append(created, dir)
+	}
+	for _, d := range created {
+		if err := filesystem.Chmod(d, modes.dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileModes are the permissions for written files and directories.
+// fileSet and dirSet report whether each mode came from a flag or the
+// config rather than defaultFileModes.
+type fileModes struct {
+	file, dir       os.FileMode
+	fileSet, dirSet bool
+}
+
+// defaultFileModes are the permissions used without --file-mode,
+// --dir-mode, or their config equivalents.
+var defaultFileModes = fileModes{file: 0644, dir: 0755}
+
+// resolveFileModes returns the file and directory permissions from the
+// --file-mode and --dir-mode flags, falling back to config, then to
+// defaultFileModes.
+func resolveFileModes(fileModeFlag, dirModeFlag string, cfg *config.Config) (fileModes, error) {
+	modes := defaultFileModes
+	if fileModeFlag == "" {
+		fileModeFlag = cfg.FileMode
+	}
+	if dirModeFlag == "" {
+		dirModeFlag = cfg.DirMode
+	}
+	if fileModeFlag != "" {
+		mode, err := config.ParseFileMode(fileModeFlag)
+		if err != nil {
+			return modes, fmt.Errorf("--file-mode: %w", err)
+		}
+		modes.file = mode
+		modes.fileSet = true
+	}
+	if dirModeFlag != "" {
+		mode, err := config.ParseFileMode(dirModeFlag)
+		if err != nil {
+			return modes, fmt.Errorf("--dir-mode: %w", err)
+		}
+		modes.dir = mode
+		modes.dirSet = true
+	}
+	return modes, nil
+}
+
+// parsedFile is the outcome of reading and parsing one input file.
+type parsedFile struct {
+	version schema.Version
+	tokens  []*token.Token
+	// problem reports why the file was skipped; version is still set
+	// when only parsing failed.
+	problem string
+}
+
+// parseFile reads, detects the schema of, and parses a single file.
+func parseFile(
+	filesystem fs.FileSystem,
+	jsonParser *parser.JSONParser,
+	cfg *config.Config,
+	rf *specifier.ResolvedFile,
+) parsedFile {
+	data, err := filesystem.ReadFile(rf.Path)
+	if err != nil {
+		return parsedFile{problem: fmt.Sprintf("Error reading %s: %v", rf.Specifier, err)}
+	}
+
+	version, err := schema.DetectVersion(data, nil)
+	if err != nil {
+		return parsedFile{problem: fmt.Sprintf("Error detecting schema for %s: %v", rf.Specifier, err)}
+	}
+
+	opts := cfg.OptionsForFile(rf.Specifier)
+	opts.SkipPositions = true
+	if version != schema.Unknown {
+		opts.SchemaVersion = version
+	}
+
+	tokens, err := jsonParser.ParseFile(filesystem, rf.Path, opts)
+	if err != nil {
+		return parsedFile{version: version, problem: fmt.Sprintf("Error parsing %s: %v", rf.Specifier, err)}
+	}
+	return parsedFile{version: version, tokens: tokens}
+}
+
+// parseFiles parses resolvedFiles using up to concurrency workers.
+// Results keep the order of resolvedFiles, so output does not depend on
+// scheduling. A concurrency of 1 or less parses serially.
+func parseFiles(
+	filesystem fs.FileSystem,
+	jsonParser *parser.JSONParser,
+	cfg *config.Config,
+	resolvedFiles []*specifier.ResolvedFile,
+	concurrency int,
+) []parsedFile {
+	results := make([]parsedFile, len(resolvedFiles))
+	if concurrency <= 1 || len(resolvedFiles) <= 1 {
+		for i, rf := range resolvedFiles {
+			results[i] = parseFile(filesystem, jsonParser, cfg, rf)
+		}
+		return results
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(resolvedFiles)) {
+		wg.Go(func() {
+			for i := range jobs {
+				results[i] = parseFile(filesystem, jsonParser, cfg, resolvedFiles[i])
+			}
+		})
+	}
+	for i := range resolvedFiles {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
 }
 
 // parseAndResolveTokens parses all files and resolves aliases.
+// Files are parsed by up to concurrency workers; see parseFiles.
+// Returns an error wrapping schema.ErrMixedSchemas if the files were
+// written against different schema versions, since aliases cannot be
+// resolved consistently across them.
 func parseAndResolveTokens(
 	filesystem fs.FileSystem,
 	jsonParser *parser.JSONParser,
 	cfg *config.Config,
 	resolvedFiles []*specifier.ResolvedFile,
+	concurrency int,
 ) ([]*token.Token, schema.Version, error) {
 	var allTokens []*token.Token
 	var detectedVersion schema.Version
+	var detectedFrom string
 	var failures int
 
-	for _, rf := range resolvedFiles {
-		data, err := filesystem.ReadFile(rf.Path)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", rf.Specifier, err)
-			failures++
-			continue
-		}
-
-		version, err := schema.DetectVersion(data, nil)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error detecting schema for %s: %v\n", rf.Specifier, err)
-			failures++
-			continue
-		}
+	for i, parsed := range parseFiles(filesystem, jsonParser, cfg, resolvedFiles, concurrency) {
+		rf := resolvedFiles[i]
+		version := parsed.version
 		if detectedVersion == schema.Unknown {
 			detectedVersion = version
+			detectedFrom = rf.Specifier
+		} else if version != schema.Unknown && version != detectedVersion {
+			return nil, schema.Unknown, fmt.Errorf(
+				"%w: %s is %s but %s is %s; convert them to a common schema first (e.g. asimonim convert --in-place --schema %s %s)",
+				schema.ErrMixedSchemas,
+				detectedFrom, detectedVersion,
+				rf.Specifier, version,
+				detectedVersion, rf.Specifier,
+			)
 		}
 
-		opts := cfg.OptionsForFile(rf.Specifier)
-		opts.SkipPositions = true
-		if version != schema.Unknown {
-			opts.SchemaVersion = version
-		}
-
-		tokens, err := jsonParser.ParseFile(filesystem, rf.Path, opts)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", rf.Specifier, err)
+		if parsed.problem != "" {
+			logger.Error("%s", parsed.problem)
 			failures++
 			continue
 		}
+		logger.Debug("Detected schema %s for %s", version, rf.Specifier)
 
-		allTokens = append(allTokens, tokens...)
+		allTokens = append(allTokens, parsed.tokens...)
 	}
 
 	if len(allTokens) == 0 && failures > 0 {
@@ -860,5 +1362,128 @@ func parseAndResolveTokens(
 		return nil, schema.Unknown, fmt.Errorf("error resolving aliases: %w", err)
 	}
 
-	return allTokens, detectedVersion, nil
+	return dedupTokens(allTokens), detectedVersion, nil
+}
+
+// dedupTokens drops tokens that repeat an earlier token with the same
+// path, as when input files overlap. Tokens that share a path but aren't
+// Equal are all kept, with a warning, so the last definition wins as
+// before.
+func dedupTokens(tokens []*token.Token) []*token.Token {
+	first := make(map[string]*token.Token, len(tokens))
+	kept := make([]*token.Token, 0, len(tokens))
+	for _, tok := range tokens {
+		path := tok.DotPath()
+		prev, ok := first[path]
+		if !ok {
+			first[path] = tok
+			kept = append(kept, tok)
+			continue
+		}
+		if prev.Equal(tok) {
+			logger.Debug("%s: dropping duplicate from %s", path, tok.FilePath)
+			continue
+		}
+		logger.Warn("%s: defined differently in %s and %s", path, prev.FilePath, tok.FilePath)
+		kept = append(kept, tok)
+	}
+	return kept
+}
+
+// stripPrivate returns tokens with private tokens removed. Aliases are
+// already resolved, so a public token that references a private one is
+// kept with the resolved value in place of the reference, which would
+// dangle in formats that write references.
+func stripPrivate(tokens []*token.Token, cfg *config.Config) []*token.Token {
+	graph := resolver.BuildDependencyGraph(tokens)
+
+	kept := make([]*token.Token, 0, len(tokens))
+	stripped := make(map[string]bool)
+	for _, tok := range tokens {
+		if cfg.IsPrivate(tok.Path) {
+			stripped[tok.Name] = true
+			continue
+		}
+		kept = append(kept, tok)
+	}
+
+	for i, tok := range kept {
+		if slices.ContainsFunc(graph.Dependencies(tok.Name), func(dep string) bool { return stripped[dep] }) {
+			logger.Debug("%s references a private token, writing its resolved value", tok.DotPath())
+			kept[i] = inlineAlias(tok)
+		}
+	}
+	return kept
+}
+
+// inlineAlias returns a copy of an alias token whose value is the value
+// it resolves to rather than the reference.
+func inlineAlias(tok *token.Token) *token.Token {
+	inlined := *tok
+	inlined.RawValue = tok.ResolvedValue
+	inlined.ResolutionChain = nil
+	switch v := tok.ResolvedValue.(type) {
+	case string:
+		inlined.Value = v
+	case float64:
+		inlined.Value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		inlined.Value = ""
+	}
+	return &inlined
+}
+
+// transformColors applies a color transform, warning about each color
+// token it converts or drops.
+func transformColors(tokens []*token.Token, transform convertlib.ColorTransform) []*token.Token {
+	kept, changes := convertlib.TransformColors(tokens, transform)
+	for _, c := range changes {
+		switch {
+		case !c.Dropped:
+			logger.Warn("%s: converted %s color to sRGB", c.Token.DotPath(), c.ColorSpace)
+		case transform == convertlib.ColorTransformSRGBOnly:
+			logger.Warn("%s: skipped %s color, which is not sRGB", c.Token.DotPath(), c.ColorSpace)
+		default:
+			logger.Warn("%s: skipped %s color, which has no sRGB conversion", c.Token.DotPath(), c.ColorSpace)
+		}
+	}
+	return kept
+}
+
+// filterByType returns the tokens whose $type is typ.
+func filterByType(tokens []*token.Token, typ string) []*token.Token {
+	kept := make([]*token.Token, 0, len(tokens))
+	for _, tok := range tokens {
+		if tok.Type == typ {
+			kept = append(kept, tok)
+		}
+	}
+	return kept
+}
+
+// stripDeprecated returns tokens with deprecated tokens removed.
+// It warns about each remaining token that references a stripped one,
+// since reference-preserving formats would emit a dangling reference.
+func stripDeprecated(tokens []*token.Token) []*token.Token {
+	graph := resolver.BuildDependencyGraph(tokens)
+
+	kept := make([]*token.Token, 0, len(tokens))
+	stripped := make(map[string]string) // name -> dot path
+	for _, tok := range tokens {
+		if tok.Deprecated {
+			stripped[tok.Name] = tok.DotPath()
+			continue
+		}
+		kept = append(kept, tok)
+	}
+
+	for _, tok := range kept {
+		for _, dep := range graph.Dependencies(tok.Name) {
+			if path, ok := stripped[dep]; ok {
+				logger.Warn("%s references deprecated token %s, which was stripped", tok.DotPath(), path)
+			}
+		}
+	}
+
+	return kept
 }
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"bennypowers.dev/asimonim/config"
+	convertlib "bennypowers.dev/asimonim/convert"
+	"bennypowers.dev/asimonim/internal/mapfs"
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/specifier"
+)
+
+// generateTokenFiles spreads numTokens color and dimension tokens over
+// numFiles draft files. Every other color is an alias, so resolution has
+// work to do.
+func generateTokenFiles(numFiles, numTokens int) (*mapfs.MapFileSystem, []*specifier.ResolvedFile) {
+	mfs := mapfs.New()
+	files := make([]*specifier.ResolvedFile, 0, numFiles)
+	perFile := numTokens / numFiles
+	for f := range numFiles {
+		group := fmt.Sprintf("set%d", f)
+		colors := map[string]any{"$type": "color"}
+		spacing := map[string]any{"$type": "dimension"}
+		for i := range perFile / 2 {
+			value := fmt.Sprintf("#%06x", (f*perFile+i)%0xffffff)
+			if i%2 == 1 {
+				value = fmt.Sprintf("{%s.color.c%d}", group, i-1)
+			}
+			colors[fmt.Sprintf("c%d", i)] = map[string]any{"$value": value}
+			spacing[fmt.Sprintf("s%d", i)] = map[string]any{"$value": fmt.Sprintf("%dpx", i*4)}
+		}
+		data, _ := json.Marshal(map[string]any{
+			group: map[string]any{"color": colors, "space": spacing},
+		})
+		path := fmt.Sprintf("/tokens/%s.json", group)
+		mfs.AddFile(path, string(data), 0644)
+		files = append(files, &specifier.ResolvedFile{Specifier: path, Path: path})
+	}
+	return mfs, files
+}
+
+// BenchmarkParseResolveFormat measures a 10k token CSS conversion at
+// serial and default concurrency (at least 2). Compare the two to see what parallel
+// parsing buys, and against earlier runs to catch regressions.
+func BenchmarkParseResolveFormat(b *testing.B) {
+	const numTokens = 10000
+	mfs, files := generateTokenFiles(20, numTokens)
+	cfg := config.Default()
+
+	for _, concurrency := range []int{1, max(2, runtime.GOMAXPROCS(0))} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				tokens, _, err := parseAndResolveTokens(mfs, parser.NewJSONParser(), cfg, files, concurrency)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(tokens) != numTokens {
+					b.Fatalf("expected %d tokens, got %d", numTokens, len(tokens))
+				}
+				if _, err := convertlib.FormatTokens(tokens, convertlib.FormatCSS, convertlib.Options{
+					InputSchema:  schema.Draft,
+					OutputSchema: schema.Draft,
+				}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
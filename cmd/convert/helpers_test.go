@@ -7,9 +7,20 @@ license that can be found in the LICENSE file.
 package convert
 
 import (
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 
+	"bennypowers.dev/asimonim/config"
+	convertlib "bennypowers.dev/asimonim/convert"
+	"bennypowers.dev/asimonim/fs"
 	"bennypowers.dev/asimonim/internal/mapfs"
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/specifier"
 	"bennypowers.dev/asimonim/token"
 )
 
@@ -152,13 +163,13 @@ func TestEnsureDir(t *testing.T) {
 	mfs := mapfs.New()
 
 	// Current dir should be a no-op
-	err := ensureDir(mfs, "file.txt")
+	err := ensureDir(mfs, "file.txt", defaultFileModes)
 	if err != nil {
 		t.Errorf("ensureDir for current dir failed: %v", err)
 	}
 
 	// Nested path should create parent dirs
-	err = ensureDir(mfs, "/output/subdir/file.txt")
+	err = ensureDir(mfs, "/output/subdir/file.txt", defaultFileModes)
 	if err != nil {
 		t.Errorf("ensureDir for nested path failed: %v", err)
 	}
@@ -228,3 +239,460 @@ func TestComputeSharedTypesImport(t *testing.T) {
 		t.Errorf("computeSharedTypesImport() = %q, want %q", imp, "./types.ts")
 	}
 }
+
+func TestParseAndResolveTokens_MixedSchemas(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/draft.json", `{"color": {"red": {"$type": "color", "$value": "#ff0000"}}}`, 0644)
+	mfs.AddFile("/stable.json", `{
+  "$schema": "https://www.designtokens.org/schemas/2025.10/format.json",
+  "size": {"small": {"$type": "dimension", "$value": {"value": 4, "unit": "px"}}}
+}`, 0644)
+
+	files := []*specifier.ResolvedFile{
+		{Specifier: "draft.json", Path: "/draft.json"},
+		{Specifier: "stable.json", Path: "/stable.json"},
+	}
+
+	_, _, err := parseAndResolveTokens(mfs, parser.NewJSONParser(), config.Default(), files, 1)
+	if err == nil {
+		t.Fatal("expected error for mixed schema versions")
+	}
+	if !errors.Is(err, schema.ErrMixedSchemas) {
+		t.Errorf("expected ErrMixedSchemas, got %v", err)
+	}
+	for _, name := range []string{"draft.json", "stable.json"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("expected error to name %s, got %q", name, err.Error())
+		}
+	}
+}
+
+func TestParseAndResolveTokens_SameSchema(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/a.json", `{"color": {"red": {"$type": "color", "$value": "#ff0000"}}}`, 0644)
+	mfs.AddFile("/b.json", `{"color": {"primary": {"$type": "color", "$value": "{color.red}"}}}`, 0644)
+
+	files := []*specifier.ResolvedFile{
+		{Specifier: "a.json", Path: "/a.json"},
+		{Specifier: "b.json", Path: "/b.json"},
+	}
+
+	tokens, version, err := parseAndResolveTokens(mfs, parser.NewJSONParser(), config.Default(), files, 1)
+	if err != nil {
+		t.Fatalf("parseAndResolveTokens error: %v", err)
+	}
+	if version != schema.Draft {
+		t.Errorf("version = %v, want %v", version, schema.Draft)
+	}
+	if len(tokens) != 2 {
+		t.Errorf("expected 2 tokens, got %d", len(tokens))
+	}
+}
+
+func TestParseAndResolveTokens_Duplicates(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/a.json", `{"color": {"red": {"$type": "color", "$value": "#ff0000"}, "blue": {"$type": "color", "$value": "#0000ff"}}}`, 0644)
+	mfs.AddFile("/b.json", `{
+  "color": {
+    "red": {"$type": "color", "$value": "#ff0000"},
+    "blue": {"$type": "color", "$value": "#0000cc"}
+  }
+}`, 0644)
+
+	files := []*specifier.ResolvedFile{
+		{Specifier: "a.json", Path: "/a.json"},
+		{Specifier: "b.json", Path: "/b.json"},
+	}
+
+	tokens, _, err := parseAndResolveTokens(mfs, parser.NewJSONParser(), config.Default(), files, 1)
+	if err != nil {
+		t.Fatalf("parseAndResolveTokens error: %v", err)
+	}
+	var red, blue int
+	for _, tok := range tokens {
+		switch tok.DotPath() {
+		case "color.red":
+			red++
+		case "color.blue":
+			blue++
+		}
+	}
+	// The identical red is written once; both blues are kept
+	if red != 1 || blue != 2 {
+		t.Errorf("got %d color.red and %d color.blue, want 1 and 2", red, blue)
+	}
+}
+
+func TestParseAndResolveTokens_ConcurrencyKeepsOrder(t *testing.T) {
+	mfs := mapfs.New()
+	var files []*specifier.ResolvedFile
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		mfs.AddFile("/"+name+".json", `{"`+name+`": {"$type": "color", "$value": "#000000"}}`, 0644)
+		files = append(files, &specifier.ResolvedFile{Specifier: name + ".json", Path: "/" + name + ".json"})
+	}
+
+	serial, _, err := parseAndResolveTokens(mfs, parser.NewJSONParser(), config.Default(), files, 1)
+	if err != nil {
+		t.Fatalf("serial parse error: %v", err)
+	}
+	parallel, _, err := parseAndResolveTokens(mfs, parser.NewJSONParser(), config.Default(), files, 4)
+	if err != nil {
+		t.Fatalf("parallel parse error: %v", err)
+	}
+	if len(parallel) != len(serial) {
+		t.Fatalf("parallel parse got %d tokens, serial got %d", len(parallel), len(serial))
+	}
+	for i := range serial {
+		if parallel[i].Name != serial[i].Name {
+			t.Errorf("token %d = %s, want %s", i, parallel[i].Name, serial[i].Name)
+		}
+	}
+}
+
+func TestStripDeprecated(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-red", Path: []string{"color", "red"}, Value: "#f00"},
+		{Name: "color-old", Path: []string{"color", "old"}, Value: "#a00", Deprecated: true},
+		{Name: "color-alias", Path: []string{"color", "alias"}, Value: "{color.old}"},
+	}
+
+	got := stripDeprecated(tokens)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 tokens, got %d", len(got))
+	}
+	for _, tok := range got {
+		if tok.Deprecated {
+			t.Errorf("deprecated token %s was not stripped", tok.Name)
+		}
+	}
+}
+
+func TestStripPrivate(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-base", Path: []string{"color", "_base"}, Value: "#f00"},
+		{Name: "color-primary", Path: []string{"color", "primary"}, Value: "{color._base}", ResolvedValue: "#f00", IsResolved: true},
+	}
+
+	got := stripPrivate(tokens, &config.Config{})
+	if len(got) != 1 || got[0].Name != "color-primary" {
+		t.Fatalf("expected only color-primary, got %v", got)
+	}
+	if got[0].ResolvedValue != "#f00" {
+		t.Errorf("expected public token to keep its resolved value, got %v", got[0].ResolvedValue)
+	}
+}
+
+func TestRunInPlace_SkipsUnchanged(t *testing.T) {
+	canonical := "{\n  \"color\": {\n    \"red\": {\n      \"$type\": \"color\",\n      \"$value\": \"#ff0000\"\n    }\n  }\n}"
+
+	mfs := mapfs.New()
+	mfs.AddFile("/compact.json", `{"color": {"red": {"$type": "color", "$value": "#ff0000"}}}`, 0644)
+	mfs.AddFile("/canonical.json", canonical+"\n", 0644)
+
+	files := []*specifier.ResolvedFile{
+		{Specifier: "compact.json", Path: "/compact.json"},
+		{Specifier: "canonical.json", Path: "/canonical.json"},
+	}
+
+	if err := runInPlace(mfs, parser.NewJSONParser(), config.Default(), files, schema.Unknown, convertlib.RefStyleDefault, false, false, defaultFileModes); err != nil {
+		t.Fatalf("runInPlace error: %v", err)
+	}
+
+	compact, _ := mfs.ReadFile("/compact.json")
+	if string(compact) != canonical {
+		t.Errorf("expected compact file to be rewritten, got:\n%s", compact)
+	}
+	unchanged, _ := mfs.ReadFile("/canonical.json")
+	if string(unchanged) != canonical+"\n" {
+		t.Errorf("expected canonical file to be left alone, got:\n%q", unchanged)
+	}
+
+	if err := runInPlace(mfs, parser.NewJSONParser(), config.Default(), files[1:], schema.Unknown, convertlib.RefStyleDefault, true, false, defaultFileModes); err != nil {
+		t.Fatalf("runInPlace --force error: %v", err)
+	}
+	forced, _ := mfs.ReadFile("/canonical.json")
+	if string(forced) != canonical {
+		t.Errorf("expected --force to rewrite the file, got:\n%q", forced)
+	}
+}
+
+// writeCountingFS records the paths written through it.
+type writeCountingFS struct {
+	*mapfs.MapFileSystem
+	written []string
+}
+
+func (w *writeCountingFS) WriteFile(path string, data []byte, perm os.FileMode) error {
+	w.written = append(w.written, path)
+	return w.MapFileSystem.WriteFile(path, data, perm)
+}
+
+func TestRunInPlace_Check(t *testing.T) {
+	compact := `{"color": {"red": {"$type": "color", "$value": "#ff0000"}}}`
+	canonical := "{\n  \"color\": {\n    \"red\": {\n      \"$type\": \"color\",\n      \"$value\": \"#ff0000\"\n    }\n  }\n}\n"
+
+	mfs := &writeCountingFS{MapFileSystem: mapfs.New()}
+	mfs.AddFile("/compact.json", compact, 0644)
+	mfs.AddFile("/canonical.json", canonical, 0644)
+
+	files := []*specifier.ResolvedFile{
+		{Specifier: "compact.json", Path: "/compact.json"},
+		{Specifier: "canonical.json", Path: "/canonical.json"},
+	}
+
+	err := runInPlace(mfs, parser.NewJSONParser(), config.Default(), files, schema.Unknown, convertlib.RefStyleDefault, false, true, defaultFileModes)
+	if err == nil || !strings.Contains(err.Error(), "1 file(s) not in canonical form") {
+		t.Errorf("expected an error for the compact file, got %v", err)
+	}
+	if len(mfs.written) != 0 {
+		t.Errorf("expected --check not to write, wrote %v", mfs.written)
+	}
+	if data, _ := mfs.ReadFile("/compact.json"); string(data) != compact {
+		t.Errorf("expected compact file to be left alone, got:\n%s", data)
+	}
+
+	if err := runInPlace(mfs, parser.NewJSONParser(), config.Default(), files[1:], schema.Unknown, convertlib.RefStyleDefault, false, true, defaultFileModes); err != nil {
+		t.Errorf("expected canonical file to pass, got %v", err)
+	}
+}
+
+// testSettings returns the settings of a serial run over files.
+func testSettings(filesystem fs.FileSystem, files []*specifier.ResolvedFile) runSettings {
+	return runSettings{
+		filesystem:  filesystem,
+		jsonParser:  parser.NewJSONParser(),
+		cfg:         config.Default(),
+		files:       files,
+		concurrency: 1,
+		modes:       defaultFileModes,
+	}
+}
+
+func TestRunMultiOutput_SkipUnchanged(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.json", `{
+  "color": {"primary": {"$type": "color", "$value": "#ff0000"}},
+  "space": {"sm": {"$type": "dimension", "$value": "4px"}}
+}`, 0644)
+	files := []*specifier.ResolvedFile{{Specifier: "tokens.json", Path: "/tokens.json"}}
+	outputs := []config.OutputSpec{
+		{Format: "scss", Path: "/out/tokens.scss"},
+		{Format: "css", Path: "/out/{group}.css"},
+	}
+	build := func(filesystem *writeCountingFS) {
+		t.Helper()
+		s := testSettings(filesystem, files)
+		s.skipUnchanged = true
+		err := runMultiOutput(s, outputs, "")
+		if err != nil {
+			t.Fatalf("runMultiOutput error: %v", err)
+		}
+	}
+
+	first := &writeCountingFS{MapFileSystem: mfs}
+	build(first)
+	if len(first.written) != 3 {
+		t.Fatalf("expected first build to write 3 files, wrote %v", first.written)
+	}
+
+	mfs.AddFile("/out/space.css", "stale\n", 0644)
+	second := &writeCountingFS{MapFileSystem: mfs}
+	build(second)
+	if !slices.Equal(second.written, []string{"/out/space.css"}) {
+		t.Errorf("expected only the stale file to be rewritten, wrote %v", second.written)
+	}
+}
+
+func TestRunMultiOutput_TypeFilter(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.json", `{
+  "color": {"primary": {"$type": "color", "$value": "#ff0000"}},
+  "space": {"sm": {"$type": "dimension", "$value": "4px"}}
+}`, 0644)
+	files := []*specifier.ResolvedFile{{Specifier: "tokens.json", Path: "/tokens.json"}}
+	outputs := []config.OutputSpec{
+		{Format: "scss", Path: "/out/colors.scss", Type: "color"},
+		{Format: "css", Path: "/out/{group}.css", Type: "dimension"},
+	}
+
+	err := runMultiOutput(testSettings(mfs, files), outputs, "")
+	if err != nil {
+		t.Fatalf("runMultiOutput error: %v", err)
+	}
+
+	colors, err := mfs.ReadFile("/out/colors.scss")
+	if err != nil {
+		t.Fatalf("expected colors.scss: %v", err)
+	}
+	if !strings.Contains(string(colors), "$color-primary: #ff0000;") || strings.Contains(string(colors), "space") {
+		t.Errorf("expected only color tokens in colors.scss, got:\n%s", colors)
+	}
+
+	space, err := mfs.ReadFile("/out/space.css")
+	if err != nil {
+		t.Fatalf("expected space.css: %v", err)
+	}
+	if !strings.Contains(string(space), "--space-sm: 4px;") {
+		t.Errorf("expected dimension tokens in space.css, got:\n%s", space)
+	}
+	if _, err := mfs.ReadFile("/out/color.css"); err == nil {
+		t.Error("expected no color.css split output for a dimension-only output")
+	}
+}
+
+func TestRunMultiOutput_Manifest(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.json", `{
+  "color": {"primary": {"$type": "color", "$value": "#ff0000"}, "secondary": {"$type": "color", "$value": "#00ff00"}},
+  "space": {"sm": {"$type": "dimension", "$value": "4px"}}
+}`, 0644)
+	files := []*specifier.ResolvedFile{{Specifier: "tokens.json", Path: "/tokens.json"}}
+	outputs := []config.OutputSpec{
+		{Format: "scss", Path: "/out/tokens.scss"},
+		{Format: "css", Path: "/out/{group}.css"},
+	}
+
+	err := runMultiOutput(testSettings(mfs, files), outputs, "/out/manifest.json")
+	if err != nil {
+		t.Fatalf("runMultiOutput error: %v", err)
+	}
+
+	data, err := mfs.ReadFile("/out/manifest.json")
+	if err != nil {
+		t.Fatalf("expected manifest: %v", err)
+	}
+	var manifest struct {
+		Files []manifestEntry `json:"files"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("invalid manifest JSON: %v\n%s", err, data)
+	}
+
+	want := []manifestEntry{
+		{Path: "/out/color.css", Format: "css", Group: "color", Tokens: 2},
+		{Path: "/out/space.css", Format: "css", Group: "space", Tokens: 1},
+		{Path: "/out/tokens.scss", Format: "scss", Tokens: 3},
+	}
+	if !slices.Equal(manifest.Files, want) {
+		t.Errorf("manifest files = %+v, want %+v", manifest.Files, want)
+	}
+}
+
+func TestRunMultiOutput_EmitDTS(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.json", `{
+  "color": {"primary": {"$type": "color", "$value": "#ff0000"}}
+}`, 0644)
+	files := []*specifier.ResolvedFile{{Specifier: "tokens.json", Path: "/tokens.json"}}
+	outputs := []config.OutputSpec{{Format: "js", Path: "/out/tokens.js", EmitDTS: true}}
+
+	s := testSettings(mfs, files)
+	s.opts.JSExport = "map"
+	err := runMultiOutput(s, outputs, "/out/manifest.json")
+	if err != nil {
+		t.Fatalf("runMultiOutput error: %v", err)
+	}
+
+	impl, err := mfs.ReadFile("/out/tokens.js")
+	if err != nil {
+		t.Fatalf("expected tokens.js: %v", err)
+	}
+	if !strings.Contains(string(impl), `"--color-primary":`) || strings.Contains(string(impl), "DesignToken<") {
+		t.Errorf("expected plain JavaScript in tokens.js, got:\n%s", impl)
+	}
+	decl, err := mfs.ReadFile("/out/tokens.d.ts")
+	if err != nil {
+		t.Fatalf("expected tokens.d.ts: %v", err)
+	}
+	if !strings.Contains(string(decl), `| "--color-primary"`) || !strings.Contains(string(decl), "export declare const tokens") {
+		t.Errorf("expected declarations in tokens.d.ts, got:\n%s", decl)
+	}
+
+	data, _ := mfs.ReadFile("/out/manifest.json")
+	if !strings.Contains(string(data), `"/out/tokens.d.ts"`) {
+		t.Errorf("expected the declaration file in the manifest, got:\n%s", data)
+	}
+}
+
+func TestRunMultiOutput_EmitDTSRequiresMap(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.json", `{"color": {"primary": {"$type": "color", "$value": "#ff0000"}}}`, 0644)
+	files := []*specifier.ResolvedFile{{Specifier: "tokens.json", Path: "/tokens.json"}}
+	outputs := []config.OutputSpec{{Format: "js", Path: "/out/tokens.js", EmitDTS: true}}
+
+	err := runMultiOutput(testSettings(mfs, files), outputs, "")
+	if err == nil {
+		t.Fatal("expected an error for emitDts without the map export")
+	}
+	if mfs.Exists("/out/tokens.js") {
+		t.Error("expected no output to be written")
+	}
+}
+
+func TestDeclarationPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"dist/tokens.js":  "dist/tokens.d.ts",
+		"dist/tokens.mjs": "dist/tokens.d.mts",
+		"dist/tokens.cjs": "dist/tokens.d.cts",
+		"dist/tokens":     "dist/tokens.d.ts",
+	} {
+		if got := declarationPath(path); got != want {
+			t.Errorf("declarationPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestThemeNames(t *testing.T) {
+	files := []*specifier.ResolvedFile{
+		{Specifier: "themes/light.tokens.json", Path: "/themes/light.tokens.json"},
+		{Specifier: "themes/dark.json", Path: "/themes/dark.json"},
+	}
+
+	names, err := themeNames(files, nil)
+	if err != nil || !slices.Equal(names, []string{"light", "dark"}) {
+		t.Errorf("themeNames() = %v, %v; want [light dark]", names, err)
+	}
+	names, err = themeNames(files, []string{"day", "night"})
+	if err != nil || !slices.Equal(names, []string{"day", "night"}) {
+		t.Errorf("themeNames() = %v, %v; want [day night]", names, err)
+	}
+	if _, err := themeNames(files, []string{"day"}); err == nil {
+		t.Error("expected an error when the names don't match the files")
+	}
+	if _, err := themeNames(files, []string{"day", "day"}); err == nil {
+		t.Error("expected an error for duplicate theme names")
+	}
+}
+
+func TestRunThemes(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/light.json", `{"color": {"$type": "color", "bg": {"$value": "#ffffff"}, "_base": {"$value": "#eeeeee"}}}`, 0644)
+	mfs.AddFile("/dark.json", `{"color": {"$type": "color", "bg": {"$value": "#000000"}, "_base": {"$value": "#111111"}}}`, 0644)
+	files := []*specifier.ResolvedFile{
+		{Specifier: "light.json", Path: "/light.json"},
+		{Specifier: "dark.json", Path: "/dark.json"},
+	}
+
+	s := testSettings(mfs, files)
+	s.opts.Prefix = "ds"
+	err := runThemes(s, "data-theme", nil, "/out/themes.css")
+	if err != nil {
+		t.Fatalf("runThemes error: %v", err)
+	}
+
+	data, err := mfs.ReadFile("/out/themes.css")
+	if err != nil {
+		t.Fatalf("expected themes.css: %v", err)
+	}
+	want := `[data-theme="light"] {
+  --ds-color-bg: #ffffff;
+}
+
+[data-theme="dark"] {
+  --ds-color-bg: #000000;
+}
+`
+	if !strings.HasSuffix(string(data), want) {
+		t.Errorf("expected theme blocks without private tokens, got:\n%s", data)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	convertlib "bennypowers.dev/asimonim/convert"
+	"bennypowers.dev/asimonim/convert/formatter/css"
+	"bennypowers.dev/asimonim/specifier"
+)
+
+// attributeNamePattern matches the HTML attribute names allowed in
+// --theme-attribute, e.g. data-theme.
+var attributeNamePattern = regexp.MustCompile(`^[A-Za-z_][-A-Za-z0-9_.:]*$`)
+
+// themeNames returns the theme name for each file: the given names, or
+// each file's base name up to its first dot when none are given, so
+// dark.tokens.json is the dark theme.
+func themeNames(resolvedFiles []*specifier.ResolvedFile, names []string) ([]string, error) {
+	if len(names) == 0 {
+		for _, rf := range resolvedFiles {
+			name, _, _ := strings.Cut(filepath.Base(rf.Path), ".")
+			names = append(names, name)
+		}
+	} else if len(names) != len(resolvedFiles) {
+		return nil, fmt.Errorf("--theme-names has %d names for %d files", len(names), len(resolvedFiles))
+	}
+
+	seen := make(map[string]string, len(names))
+	for i, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("theme name for %s is empty", resolvedFiles[i].Specifier)
+		}
+		if other, ok := seen[name]; ok {
+			return nil, fmt.Errorf("%s and %s are both named theme %q; set --theme-names", other, resolvedFiles[i].Specifier, name)
+		}
+		seen[name] = resolvedFiles[i].Specifier
+	}
+	return names, nil
+}
+
+// runThemes parses each file as its own theme and writes a single CSS
+// file with a block per theme, selected by the attribute. Aliases are
+// resolved within each theme's file.
+func runThemes(s runSettings, attribute string, names []string, output string) error {
+	names, err := themeNames(s.files, names)
+	if err != nil {
+		return err
+	}
+
+	themes := make([]css.Theme, 0, len(s.files))
+	for i, rf := range s.files {
+		theme := s
+		theme.files = []*specifier.ResolvedFile{rf}
+		theme.concurrency = 1
+		tokens, _, err := theme.parse()
+		if err != nil {
+			return fmt.Errorf("theme %s: %w", names[i], err)
+		}
+		themes = append(themes, css.Theme{Name: names[i], Tokens: tokens})
+	}
+
+	outputBytes, err := convertlib.FormatThemes(themes, attribute, s.opts)
+	if err != nil {
+		return fmt.Errorf("error formatting output: %w", err)
+	}
+
+	if output != "" {
+		if _, err := s.write(output, outputBytes); err != nil {
+			return fmt.Errorf("error writing to %s: %w", output, err)
+		}
+		return nil
+	}
+
+	fmt.Print(string(outputBytes))
+	return nil
+}
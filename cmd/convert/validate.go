@@ -0,0 +1,50 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert
+
+import (
+	"fmt"
+
+	"github.com/tidwall/jsonc"
+
+	"bennypowers.dev/asimonim/fs"
+	"bennypowers.dev/asimonim/internal/logger"
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/specifier"
+	"bennypowers.dev/asimonim/validator"
+)
+
+// validateInputs checks each file for consistency with its detected
+// schema version, e.g. $ref in a draft file, logging every problem
+// found. It returns an error if any file is inconsistent, so that no
+// output is generated from it.
+func validateInputs(filesystem fs.FileSystem, resolvedFiles []*specifier.ResolvedFile) error {
+	var problems int
+	for _, rf := range resolvedFiles {
+		data, err := filesystem.ReadFile(rf.Path)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", rf.Specifier, err)
+		}
+		// The validator reads YAML, which JSONC comments aren't
+		if parser.FormatFromPath(rf.Path).IsJSON(data) {
+			data = jsonc.ToJSON(data)
+		}
+		version, err := schema.DetectVersion(data, nil)
+		if err != nil {
+			return fmt.Errorf("error detecting schema for %s: %w", rf.Specifier, err)
+		}
+		for _, verr := range validator.ValidateConsistencyWithPath(data, version, rf.Specifier) {
+			logger.Error("%s", verr.Error())
+			problems++
+		}
+	}
+	if problems > 0 {
+		return fmt.Errorf("validation failed with %d error(s), no output written", problems)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	convertlib "bennypowers.dev/asimonim/convert"
+	"bennypowers.dev/asimonim/internal/logger"
+	"bennypowers.dev/asimonim/token"
+)
+
+// writeAssetCatalog writes tokens as an Xcode asset catalog in dir,
+// e.g. Colors.xcassets, returning the paths of its files, including
+// those skipped as unchanged. Color sets already in dir for tokens that
+// no longer exist are left in place.
+func writeAssetCatalog(s runSettings, dir string, tokens []*token.Token) ([]string, error) {
+	files, err := convertlib.FormatAssetCatalog(tokens, s.opts)
+	if err != nil {
+		return nil, fmt.Errorf("error formatting output: %w", err)
+	}
+
+	var paths []string
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		wrote, err := s.write(path, files[name])
+		if err != nil {
+			return paths, fmt.Errorf("error writing to %s: %w", path, err)
+		}
+		if wrote {
+			logger.Info("Wrote %s", path)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package filter evaluates token filter expressions, such as
+//
+//	type == "color" && !deprecated && group startswith "color.brand"
+//
+// Expressions compare token fields with string or boolean literals, and
+// combine comparisons with &&, ||, ! and parentheses. There are no
+// function calls or variables, so an expression can only read the token
+// it is matched against.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"bennypowers.dev/asimonim/token"
+)
+
+// Expr is a parsed filter expression.
+type Expr struct {
+	src   string
+	match func(tok *token.Token) bool
+}
+
+// Parse parses a filter expression. Errors give the offset of the
+// problem in src.
+func Parse(src string) (*Expr, error) {
+	lexemes, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{lexemes: lexemes}
+	match, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != lexEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", next, next.pos)
+	}
+	return &Expr{src: src, match: match}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Match reports whether tok satisfies the expression.
+func (e *Expr) Match(tok *token.Token) bool {
+	return e.match(tok)
+}
+
+// Filter returns the tokens that satisfy the expression, in order.
+func (e *Expr) Filter(tokens []*token.Token) []*token.Token {
+	filtered := make([]*token.Token, 0, len(tokens))
+	for _, tok := range tokens {
+		if e.match(tok) {
+			filtered = append(filtered, tok)
+		}
+	}
+	return filtered
+}
+
+// And returns an expression that matches tokens matching every one of
+// exprs. Nil expressions are skipped; if all are nil, And returns nil.
+func And(exprs ...*Expr) *Expr {
+	var parts []*Expr
+	for _, e := range exprs {
+		if e != nil {
+			parts = append(parts, e)
+		}
+	}
+	switch len(parts) {
+	case 0:
+		return nil
+	case 1:
+		return parts[0]
+	}
+	srcs := make([]string, len(parts))
+	for i, e := range parts {
+		srcs[i] = "(" + e.src + ")"
+	}
+	return &Expr{
+		src: strings.Join(srcs, " && "),
+		match: func(tok *token.Token) bool {
+			for _, e := range parts {
+				if !e.match(tok) {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// FlagExpr returns the expression for the --type, --exclude-type,
+// --group, --deprecated and --no-deprecated flags shared by list and
+// search, or nil when none of them is set. group matches by dot path
+// prefix, as in path startswith "color.brand".
+func FlagExpr(typ string, excludeTypes []string, group string, deprecated, noDeprecated bool) *Expr {
+	var terms []string
+	if typ != "" {
+		terms = append(terms, "type == "+strconv.Quote(typ))
+	}
+	for _, t := range excludeTypes {
+		terms = append(terms, "type != "+strconv.Quote(t))
+	}
+	if group != "" {
+		terms = append(terms, "path startswith "+strconv.Quote(group))
+	}
+	if deprecated {
+		terms = append(terms, "deprecated")
+	} else if noDeprecated {
+		terms = append(terms, "!deprecated")
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	expr, err := Parse(strings.Join(terms, " && "))
+	if err != nil {
+		// Quoted literals and known fields always parse.
+		panic(err)
+	}
+	return expr
+}
+
+// stringFields are the string-valued token fields an expression can read.
+var stringFields = map[string]func(tok *token.Token) string{
+	"name":        func(tok *token.Token) string { return tok.CSSVariableName() },
+	"path":        (*token.Token).DotPath,
+	"group":       groupPath,
+	"type":        func(tok *token.Token) string { return tok.Type },
+	"value":       (*token.Token).DisplayValue,
+	"description": func(tok *token.Token) string { return tok.Description },
+}
+
+// boolFields are the boolean token fields an expression can read.
+var boolFields = map[string]func(tok *token.Token) bool{
+	"deprecated": func(tok *token.Token) bool { return tok.Deprecated },
+	"alias":      (*token.Token).IsAlias,
+}
+
+// Fields returns the names of the fields an expression can read, sorted.
+func Fields() []string {
+	var names []string
+	for name := range stringFields {
+		names = append(names, name)
+	}
+	for name := range boolFields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// groupPath returns the dot path of the group that holds tok.
+func groupPath(tok *token.Token) string {
+	if len(tok.Path) == 0 {
+		return ""
+	}
+	return strings.Join(tok.Path[:len(tok.Path)-1], ".")
+}
+
+// stringOps are the operators that compare a string field with a string.
+var stringOps = map[string]func(field, operand string) bool{
+	"==":         func(field, operand string) bool { return field == operand },
+	"!=":         func(field, operand string) bool { return field != operand },
+	"contains":   strings.Contains,
+	"startswith": strings.HasPrefix,
+	"endswith":   strings.HasSuffix,
+}
+
+type parser struct {
+	lexemes []lexeme
+	pos     int
+}
+
+func (p *parser) peek() lexeme {
+	return p.lexemes[p.pos]
+}
+
+func (p *parser) next() lexeme {
+	l := p.lexemes[p.pos]
+	if l.kind != lexEOF {
+		p.pos++
+	}
+	return l
+}
+
+// or parses a sequence of && expressions joined by ||.
+func (p *parser) or() (func(*token.Token) bool, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == lexOr {
+		p.next()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(tok *token.Token) bool { return l(tok) || right(tok) }
+	}
+	return left, nil
+}
+
+// and parses a sequence of unary expressions joined by &&.
+func (p *parser) and() (func(*token.Token) bool, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == lexAnd {
+		p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(tok *token.Token) bool { return l(tok) && right(tok) }
+	}
+	return left, nil
+}
+
+// unary parses a negation, a parenthesized expression, or a comparison.
+func (p *parser) unary() (func(*token.Token) bool, error) {
+	switch l := p.peek(); l.kind {
+	case lexNot:
+		p.next()
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(tok *token.Token) bool { return !operand(tok) }, nil
+	case lexLParen:
+		p.next()
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != lexRParen {
+			return nil, fmt.Errorf("expected ) at offset %d, got %s", closing.pos, closing)
+		}
+		return inner, nil
+	case lexIdent:
+		return p.comparison()
+	default:
+		return nil, fmt.Errorf("expected a field name at offset %d, got %s", l.pos, l)
+	}
+}
+
+// comparison parses a field, optionally followed by an operator and a
+// literal. A boolean field on its own tests that the field is true.
+func (p *parser) comparison() (func(*token.Token) bool, error) {
+	field := p.next()
+
+	if get, ok := boolFields[field.text]; ok {
+		if p.peek().kind != lexOp {
+			return get, nil
+		}
+		op := p.next()
+		if op.text != "==" && op.text != "!=" {
+			return nil, fmt.Errorf("operator %s at offset %d needs a string field, but %s is true or false", op.text, op.pos, field.text)
+		}
+		operand := p.next()
+		if operand.kind != lexIdent || (operand.text != "true" && operand.text != "false") {
+			return nil, fmt.Errorf("expected true or false at offset %d, got %s", operand.pos, operand)
+		}
+		want := (operand.text == "true") == (op.text == "==")
+		return func(tok *token.Token) bool { return get(tok) == want }, nil
+	}
+
+	get, ok := stringFields[field.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q at offset %d (valid: %s)", field.text, field.pos, strings.Join(Fields(), ", "))
+	}
+	op := p.next()
+	if op.kind != lexOp {
+		return nil, fmt.Errorf("expected an operator after %s at offset %d, got %s", field.text, op.pos, op)
+	}
+	operand := p.next()
+	if operand.kind != lexString {
+		return nil, fmt.Errorf("expected a quoted string at offset %d, got %s", operand.pos, operand)
+	}
+
+	if op.text == "matches" {
+		pattern, err := regexp.Compile(operand.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern at offset %d: %w", operand.pos, err)
+		}
+		return func(tok *token.Token) bool { return pattern.MatchString(get(tok)) }, nil
+	}
+	compare := stringOps[op.text]
+	return func(tok *token.Token) bool { return compare(get(tok), operand.text) }, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package filter_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/cmd/filter"
+	"bennypowers.dev/asimonim/token"
+)
+
+var tokens = []*token.Token{
+	{Name: "color-brand-primary", Type: "color", Path: []string{"color", "brand", "primary"}, Value: "#ff0000", Description: "Main brand color"},
+	{Name: "color-brand-old", Type: "color", Path: []string{"color", "brand", "old"}, Value: "#00ff00", Deprecated: true},
+	{Name: "color-text", Type: "color", Path: []string{"color", "text"}, Value: "{color.brand.primary}"},
+	{Name: "space-sm", Type: "dimension", Path: []string{"space", "sm"}, Value: "4px", Prefix: "ds"},
+}
+
+func TestExpr_Filter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{`type == "color" && deprecated == false && group startswith "color.brand"`, []string{"color-brand-primary"}},
+		{`type == "color" && !deprecated`, []string{"color-brand-primary", "color-text"}},
+		{`deprecated`, []string{"color-brand-old"}},
+		{`deprecated != true && type != "color"`, []string{"space-sm"}},
+		{`alias`, []string{"color-text"}},
+		{`path == "color.text" || value endswith "px"`, []string{"color-text", "space-sm"}},
+		{`name == "--ds-space-sm"`, []string{"space-sm"}},
+		{`group == "color"`, []string{"color-text"}},
+		{`description contains "brand"`, []string{"color-brand-primary"}},
+		{`value matches "^#[0-9a-f]{6}$"`, []string{"color-brand-primary", "color-brand-old"}},
+		{`!(type == "color" || type == "dimension")`, nil},
+		{`type == "color" && (deprecated || alias)`, []string{"color-brand-old", "color-text"}},
+		{`type == "color" || type == "dimension" && deprecated`, []string{"color-brand-primary", "color-brand-old", "color-text"}},
+		{`path == "with \"quotes\""`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := filter.Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			var got []string
+			for _, tok := range expr.Filter(tokens) {
+				got = append(got, tok.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagExpr(t *testing.T) {
+	tests := []struct {
+		name         string
+		typ          string
+		excludeTypes []string
+		group        string
+		deprecated   bool
+		noDeprecated bool
+		want         []string
+	}{
+		{name: "type", typ: "color", want: []string{"color-brand-primary", "color-brand-old", "color-text"}},
+		{name: "exclude types", excludeTypes: []string{"color"}, want: []string{"space-sm"}},
+		{name: "type minus excluded type", typ: "color", excludeTypes: []string{"color"}},
+		{name: "group prefix", group: "color.brand", want: []string{"color-brand-primary", "color-brand-old"}},
+		{name: "deprecated", deprecated: true, want: []string{"color-brand-old"}},
+		{name: "no deprecated", typ: "color", noDeprecated: true, want: []string{"color-brand-primary", "color-text"}},
+		{name: "quoted group", group: `color."brand"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := filter.FlagExpr(tt.typ, tt.excludeTypes, tt.group, tt.deprecated, tt.noDeprecated)
+			var got []string
+			for _, tok := range expr.Filter(tokens) {
+				got = append(got, tok.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Filter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if expr := filter.FlagExpr("", nil, "", false, false); expr != nil {
+		t.Errorf("FlagExpr() with no flags = %q, want nil", expr)
+	}
+}
+
+func TestAnd(t *testing.T) {
+	flags := filter.FlagExpr("color", nil, "", false, false)
+	user, err := filter.Parse(`alias || deprecated`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	expr := filter.And(flags, nil, user)
+	if want := `(type == "color") && (alias || deprecated)`; expr.String() != want {
+		t.Errorf("String() = %q, want %q", expr.String(), want)
+	}
+	var got []string
+	for _, tok := range expr.Filter(tokens) {
+		got = append(got, tok.Name)
+	}
+	if want := []string{"color-brand-old", "color-text"}; !slices.Equal(got, want) {
+		t.Errorf("Filter() = %v, want %v", got, want)
+	}
+
+	if expr := filter.And(nil, user); expr != user {
+		t.Errorf("And() of one expression = %q, want %q", expr, user)
+	}
+	if expr := filter.And(nil, nil); expr != nil {
+		t.Errorf("And() of nils = %q, want nil", expr)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{``, "expected a field name at offset 0, got end of expression"},
+		{`typ == "color"`, `unknown field "typ" at offset 0`},
+		{`type == color`, "expected a quoted string at offset 8, got color"},
+		{`type`, "expected an operator after type at offset 4"},
+		{`deprecated == "yes"`, "expected true or false at offset 14"},
+		{`deprecated contains "x"`, "operator contains at offset 11 needs a string field"},
+		{`(type == "color"`, "expected ) at offset 16"},
+		{`type == "color")`, "unexpected ) at offset 15"},
+		{`type == "color`, "unterminated string at offset 8"},
+		{`type = "color"`, "unexpected '=' at offset 5"},
+		{`value matches "("`, "invalid pattern at offset 14"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := filter.Parse(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
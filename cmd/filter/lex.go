@@ -0,0 +1,126 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type lexKind int
+
+const (
+	lexEOF lexKind = iota
+	lexIdent
+	lexString
+	lexOp
+	lexAnd
+	lexOr
+	lexNot
+	lexLParen
+	lexRParen
+)
+
+// lexeme is one unit of a filter expression.
+type lexeme struct {
+	kind lexKind
+	text string
+	pos  int
+}
+
+func (l lexeme) String() string {
+	switch l.kind {
+	case lexEOF:
+		return "end of expression"
+	case lexString:
+		return strconv.Quote(l.text)
+	default:
+		return l.text
+	}
+}
+
+// wordOps are the operators spelled as words.
+var wordOps = map[string]bool{
+	"contains":   true,
+	"startswith": true,
+	"endswith":   true,
+	"matches":    true,
+}
+
+// lex splits src into lexemes, ending with lexEOF.
+func lex(src string) ([]lexeme, error) {
+	var lexemes []lexeme
+	for i := 0; i < len(src); {
+		ch := src[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '(':
+			lexemes = append(lexemes, lexeme{lexLParen, "(", i})
+			i++
+		case ch == ')':
+			lexemes = append(lexemes, lexeme{lexRParen, ")", i})
+			i++
+		case strings.HasPrefix(src[i:], "&&"):
+			lexemes = append(lexemes, lexeme{lexAnd, "&&", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "||"):
+			lexemes = append(lexemes, lexeme{lexOr, "||", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "=="), strings.HasPrefix(src[i:], "!="):
+			lexemes = append(lexemes, lexeme{lexOp, src[i : i+2], i})
+			i += 2
+		case ch == '!':
+			lexemes = append(lexemes, lexeme{lexNot, "!", i})
+			i++
+		case ch == '"':
+			end, text, err := scanString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			lexemes = append(lexemes, lexeme{lexString, text, i})
+			i = end
+		case isIdentByte(ch):
+			start := i
+			for i < len(src) && isIdentByte(src[i]) {
+				i++
+			}
+			word := src[start:i]
+			kind := lexIdent
+			if wordOps[word] {
+				kind = lexOp
+			}
+			lexemes = append(lexemes, lexeme{kind, word, start})
+		default:
+			return nil, fmt.Errorf("unexpected %q at offset %d", ch, i)
+		}
+	}
+	return append(lexemes, lexeme{lexEOF, "", len(src)}), nil
+}
+
+// scanString reads the double-quoted string starting at src[start],
+// returning the offset after it and its unescaped text.
+func scanString(src string, start int) (int, string, error) {
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '"':
+			text, err := strconv.Unquote(src[start : i+1])
+			if err != nil {
+				return 0, "", fmt.Errorf("invalid string at offset %d: %w", start, err)
+			}
+			return i + 1, text, nil
+		}
+	}
+	return 0, "", fmt.Errorf("unterminated string at offset %d", start)
+}
+
+func isIdentByte(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package initcmd provides the init command for asimonim.
+package initcmd
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"bennypowers.dev/asimonim/config"
+	"bennypowers.dev/asimonim/fs"
+	"bennypowers.dev/asimonim/internal/logger"
+	"bennypowers.dev/asimonim/schema"
+)
+
+//go:embed starter/*.json
+var starterFS embed.FS
+
+// TokensFileName is the name of the starter tokens file.
+const TokensFileName = "tokens.json"
+
+// defaultPrefix is the example prefix written when --prefix isn't given.
+const defaultPrefix = "ds"
+
+// configHeader introduces the generated config file.
+const configHeader = `asimonim configuration
+See https://bennypowers.dev/asimonim/docs/reference/configuration/`
+
+// configComments documents each key written to the generated config.
+// The prefix comment is a format string for the prefix.
+var configComments = map[string]string{
+	"prefix":  "Prefix for CSS variables and other generated names, e.g. --%s-color-brand",
+	"files":   "Token files to load when none are given on the command line.\nPaths may be globs or npm:/jsr: specifiers.",
+	"schema":  "DTCG schema version of the token files: draft or v2025.10",
+	"outputs": "Files `asimonim convert` writes in a single pass.\nEach output has a format and path, and may set prefix, type, and splitBy.",
+}
+
+// NewCmd creates a fresh init command with its own flags.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Create a config file and starter tokens",
+		Long: `Create .config/design-tokens.yaml with commented examples, and a starter
+tokens.json with a few example tokens, in the current directory.
+
+Existing files are not overwritten unless --force is given.`,
+		Example: `  # Scaffold a project using the stable schema
+  asimonim init
+
+  # Use the Editor's Draft schema and a custom prefix
+  asimonim init --schema draft --prefix acme`,
+		Args: cobra.NoArgs,
+		RunE: run,
+	}
+	cmd.Flags().Bool("force", false, "Overwrite existing config and tokens files")
+	return cmd
+}
+
+func run(cmd *cobra.Command, _ []string) error {
+	force, _ := cmd.Flags().GetBool("force")
+	schemaFlag, _ := cmd.Flags().GetString("schema")
+	prefix, _ := cmd.Flags().GetString("prefix")
+
+	version := schema.V2025_10
+	if schemaFlag != "" {
+		var err error
+		if version, err = schema.FromString(schemaFlag); err != nil {
+			return fmt.Errorf("invalid schema version: %w", err)
+		}
+	}
+
+	written, err := scaffold(fs.NewOSFileSystem(), ".", version, prefix, force)
+	if err != nil {
+		return err
+	}
+	for _, path := range written {
+		logger.Info("Wrote %s", path)
+	}
+	return nil
+}
+
+// scaffold writes the config and starter tokens under rootDir, returning
+// the paths written. Unless force is set, it writes nothing if either
+// file, or a config file in another format, already exists.
+func scaffold(filesystem fs.FileSystem, rootDir string, version schema.Version, prefix string, force bool) ([]string, error) {
+	configPath := filepath.Join(rootDir, config.ConfigDir, config.ConfigFileName+".yaml")
+	tokensPath := filepath.Join(rootDir, TokensFileName)
+
+	if !force {
+		var existing []string
+		if path := config.FindFile(filesystem, rootDir); path != "" {
+			existing = append(existing, path)
+		}
+		if filesystem.Exists(tokensPath) {
+			existing = append(existing, tokensPath)
+		}
+		if len(existing) > 0 {
+			return nil, fmt.Errorf("refusing to overwrite %s; use --force to replace it", strings.Join(existing, " and "))
+		}
+	}
+
+	configData, err := configYAML(version, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("error generating config: %w", err)
+	}
+	tokensData, err := starterFS.ReadFile("starter/tokens." + version.String() + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("no starter tokens for schema %s", version)
+	}
+
+	if err := filesystem.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return nil, fmt.Errorf("error creating %s: %w", filepath.Dir(configPath), err)
+	}
+	if err := filesystem.WriteFile(configPath, configData, 0644); err != nil {
+		return nil, fmt.Errorf("error writing %s: %w", configPath, err)
+	}
+	if err := filesystem.WriteFile(tokensPath, tokensData, 0644); err != nil {
+		return []string{configPath}, fmt.Errorf("error writing %s: %w", tokensPath, err)
+	}
+	return []string{configPath, tokensPath}, nil
+}
+
+// configYAML marshals a starter config, leaving out empty fields and
+// commenting each key that remains.
+func configYAML(version schema.Version, prefix string) ([]byte, error) {
+	if prefix == "" {
+		prefix = defaultPrefix
+	}
+	cfg := &config.Config{
+		Prefix: prefix,
+		Files:  []config.FileSpec{{Path: "./" + TokensFileName}},
+		Schema: version.String(),
+		Outputs: []config.OutputSpec{
+			{Format: "css", Path: "dist/tokens.css"},
+			{Format: "scss", Path: "dist/_tokens.scss"},
+		},
+	}
+
+	var node yaml.Node
+	if err := node.Encode(cfg); err != nil {
+		return nil, err
+	}
+	pruneEmpty(&node)
+	node.HeadComment = configHeader
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		key.HeadComment = configComments[key.Value]
+		if key.Value == "prefix" {
+			key.HeadComment = fmt.Sprintf(key.HeadComment, prefix)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// pruneEmpty removes mapping entries whose values are empty, false, or
+// null, so the config only shows the settings it uses.
+func pruneEmpty(node *yaml.Node) {
+	for _, child := range node.Content {
+		pruneEmpty(child)
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	kept := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if !isEmpty(node.Content[i+1]) {
+			kept = append(kept, node.Content[i], node.Content[i+1])
+		}
+	}
+	node.Content = kept
+}
+
+// isEmpty reports whether node holds a zero value.
+func isEmpty(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		return len(node.Content) == 0
+	case yaml.ScalarNode:
+		return node.Tag == "!!null" ||
+			(node.Tag == "!!str" && node.Value == "") ||
+			(node.Tag == "!!bool" && node.Value == "false")
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package initcmd
+
+import (
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/config"
+	"bennypowers.dev/asimonim/internal/mapfs"
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/resolver"
+	"bennypowers.dev/asimonim/schema"
+)
+
+func TestScaffold(t *testing.T) {
+	for _, version := range []schema.Version{schema.Draft, schema.V2025_10} {
+		t.Run(version.String(), func(t *testing.T) {
+			mfs := mapfs.New()
+			written, err := scaffold(mfs, "/project", version, "", false)
+			if err != nil {
+				t.Fatalf("scaffold error: %v", err)
+			}
+			if len(written) != 2 {
+				t.Fatalf("expected 2 files written, got %v", written)
+			}
+
+			cfg, err := config.LoadStrict(mfs, "/project")
+			if err != nil {
+				t.Fatalf("expected a valid config, got %v", err)
+			}
+			if cfg.Prefix != defaultPrefix || cfg.SchemaVersion() != version {
+				t.Errorf("unexpected config: prefix %q, schema %q", cfg.Prefix, cfg.Schema)
+			}
+			if len(cfg.Files) != 1 || cfg.Files[0].Path != "./tokens.json" {
+				t.Errorf("unexpected files: %+v", cfg.Files)
+			}
+			if len(cfg.Outputs) != 2 {
+				t.Errorf("expected 2 outputs, got %+v", cfg.Outputs)
+			}
+
+			data, _ := mfs.ReadFile("/project/.config/design-tokens.yaml")
+			for _, want := range []string{"# asimonim configuration", "# Prefix for CSS variables", "--ds-color-brand", "# DTCG schema version"} {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("expected config to contain %q, got:\n%s", want, data)
+				}
+			}
+			if strings.Contains(string(data), "groupMarkers") || strings.Contains(string(data), "flatten") {
+				t.Errorf("expected empty fields to be left out, got:\n%s", data)
+			}
+
+			tokens, err := parser.NewJSONParser().ParseFile(mfs, "/project/tokens.json", parser.Options{})
+			if err != nil {
+				t.Fatalf("failed to parse starter tokens: %v", err)
+			}
+			if err := resolver.ResolveAliases(tokens, version); err != nil {
+				t.Fatalf("failed to resolve starter tokens: %v", err)
+			}
+			if len(tokens) != 5 || tokens[0].SchemaVersion != version {
+				t.Errorf("expected 5 %s tokens, got %d", version, len(tokens))
+			}
+		})
+	}
+}
+
+func TestScaffold_Prefix(t *testing.T) {
+	mfs := mapfs.New()
+	if _, err := scaffold(mfs, "/project", schema.V2025_10, "acme", false); err != nil {
+		t.Fatalf("scaffold error: %v", err)
+	}
+	data, _ := mfs.ReadFile("/project/.config/design-tokens.yaml")
+	if !strings.Contains(string(data), "prefix: acme") || !strings.Contains(string(data), "--acme-color-brand") {
+		t.Errorf("expected the acme prefix, got:\n%s", data)
+	}
+}
+
+func TestScaffold_RefusesToOverwrite(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/project/.config/design-tokens.json", `{"prefix": "mine"}`, 0644)
+	mfs.AddFile("/project/tokens.json", `{}`, 0644)
+
+	_, err := scaffold(mfs, "/project", schema.V2025_10, "", false)
+	if err == nil || !strings.Contains(err.Error(), "design-tokens.json and /project/tokens.json") {
+		t.Fatalf("expected an error naming both files, got %v", err)
+	}
+	if mfs.Exists("/project/.config/design-tokens.yaml") {
+		t.Error("expected no config to be written")
+	}
+	if data, _ := mfs.ReadFile("/project/tokens.json"); string(data) != "{}" {
+		t.Errorf("expected tokens.json to be left alone, got %s", data)
+	}
+
+	if _, err := scaffold(mfs, "/project", schema.V2025_10, "", true); err != nil {
+		t.Fatalf("scaffold --force error: %v", err)
+	}
+	if data, _ := mfs.ReadFile("/project/tokens.json"); string(data) == "{}" {
+		t.Error("expected --force to overwrite tokens.json")
+	}
+}
@@ -8,12 +8,16 @@ package cmd_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"bennypowers.dev/asimonim/cmd"
+	"bennypowers.dev/asimonim/internal/logger"
 )
 
 // testdataDir finds the testdata directory relative to this test file.
@@ -55,6 +59,34 @@ func captureAndExecute(t *testing.T, args ...string) (string, error) {
 	return buf.String(), err
 }
 
+// captureStderr runs the command like captureAndExecute, also returning
+// what it wrote to stderr.
+func captureStderr(t *testing.T, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+	oldStderr := os.Stderr
+	r, w, pipeErr := os.Pipe()
+	if pipeErr != nil {
+		t.Fatalf("failed to create pipe: %v", pipeErr)
+	}
+	os.Stderr = w
+	defer func() { os.Stderr = oldStderr }()
+
+	stdout, err = captureAndExecute(t, args...)
+
+	if closeErr := w.Close(); closeErr != nil {
+		t.Fatalf("failed to close pipe: %v", closeErr)
+	}
+	var buf bytes.Buffer
+	if _, readErr := buf.ReadFrom(r); readErr != nil {
+		t.Fatalf("failed to read captured output: %v", readErr)
+	}
+	if closeErr := r.Close(); closeErr != nil {
+		t.Fatalf("failed to close read pipe: %v", closeErr)
+	}
+
+	return stdout, buf.String(), err
+}
+
 func TestValidateCommand(t *testing.T) {
 	td := testdataDir(t)
 	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
@@ -82,6 +114,128 @@ func TestValidateCommand_NonexistentFile(t *testing.T) {
 	}
 }
 
+func TestValidateCommand_SelfReference(t *testing.T) {
+	td := testdataDir(t)
+
+	_, stderr, err := captureStderr(t, "validate", filepath.Join(td, "fixtures/validate/self-reference/tokens.json"))
+	if err == nil {
+		t.Error("expected validate to fail for a token that references itself")
+	}
+	if want := "token references itself: color.accent"; !strings.Contains(stderr, want) {
+		t.Errorf("expected stderr to contain %q, got:\n%s", want, stderr)
+	}
+}
+
+func TestValidateCommand_Types(t *testing.T) {
+	td := testdataDir(t)
+
+	output, err := captureAndExecute(t, "validate", "--types", filepath.Join(td, "fixtures/validate/bad-types/tokens.json"))
+	if err == nil {
+		t.Error("expected validate --types to fail for implausible values")
+	}
+	if !strings.Contains(output, "2 tokens, schema:") {
+		t.Errorf("expected the file summary after type errors, got:\n%s", output)
+	}
+
+	_, err = captureAndExecute(t, "validate", "--types", filepath.Join(td, "fixtures/v2025_10/all-color-spaces/tokens.json"))
+	if err != nil {
+		t.Errorf("validate --types failed on valid tokens: %v", err)
+	}
+}
+
+func TestValidateCommand_StrictTypes(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/validate/unknown-types/tokens.json")
+
+	if _, err := captureAndExecute(t, "validate", "--strict-types", fixture); err != nil {
+		t.Errorf("expected unknown types to be warnings, got %v", err)
+	}
+	if _, err := captureAndExecute(t, "validate", "--strict-types", "--strict", fixture); err == nil {
+		t.Error("expected validate --strict-types --strict to fail for an unknown $type")
+	}
+	if _, err := captureAndExecute(t, "validate", "--strict", fixture); err != nil {
+		t.Errorf("expected unknown types to be ignored without --strict-types, got %v", err)
+	}
+}
+
+func TestValidateCommand_TypeMismatch(t *testing.T) {
+	td := testdataDir(t)
+
+	fixture := filepath.Join(td, "fixtures/validate/mistyped/tokens.json")
+
+	output, err := captureAndExecute(t, "validate", fixture)
+	if err != nil {
+		t.Errorf("expected type mismatches to be warnings, got %v", err)
+	}
+	if !strings.Contains(output, "All files valid.") {
+		t.Errorf("expected the remaining checks to run, got:\n%s", output)
+	}
+
+	if _, err := captureAndExecute(t, "validate", "--strict", fixture); err == nil {
+		t.Error("expected validate --strict to fail for values of the wrong type")
+	}
+
+	_, err = captureAndExecute(t, "validate", "--strict", filepath.Join(td, "fixtures/draft/simple/tokens.json"))
+	if err != nil {
+		t.Errorf("validate failed on well-typed tokens: %v", err)
+	}
+}
+
+func TestValidateCommand_RequireDescriptions(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/validate/descriptions/tokens.json")
+
+	_, err := captureAndExecute(t, "validate", fixture)
+	if err != nil {
+		t.Errorf("validate without --require-descriptions failed: %v", err)
+	}
+
+	output, stderr, err := captureStderr(t, "validate", "--require-descriptions", fixture)
+	if err == nil {
+		t.Error("expected validate --require-descriptions to fail for undocumented tokens")
+	}
+	if !strings.Contains(stderr, "deprecated token(s)") || !strings.Contains(output, "tokens, schema:") {
+		t.Errorf("expected the remaining checks to run after description errors, got:\n%s%s", output, stderr)
+	}
+
+	_, err = captureAndExecute(t, "validate", "--require-descriptions", "--description-types", "color", fixture)
+	if err == nil {
+		t.Error("expected deprecated color token without description to fail")
+	}
+
+	_, err = captureAndExecute(t, "validate", "--require-descriptions", "--description-types", "color", "--allow-undocumented-deprecated", fixture)
+	if err != nil {
+		t.Errorf("expected documented colors to pass when deprecated tokens are exempt: %v", err)
+	}
+}
+
+func TestValidateCommand_Baseline(t *testing.T) {
+	td := testdataDir(t)
+	dir := filepath.Join(td, "fixtures/validate/baseline")
+	baseline := filepath.Join(dir, "baseline.json")
+
+	_, err := captureAndExecute(t, "validate", "--baseline", baseline, filepath.Join(dir, "compatible.json"))
+	if err != nil {
+		t.Errorf("expected additions and value changes to pass: %v", err)
+	}
+
+	_, err = captureAndExecute(t, "validate", "--baseline", baseline, filepath.Join(dir, "breaking.json"))
+	if err == nil {
+		t.Error("expected removed and retyped tokens to fail against the baseline")
+	}
+
+	_, stderr, err := captureStderr(t, "validate", "--baseline", baseline, filepath.Join(dir, "unresolved.json"))
+	if err == nil {
+		t.Error("expected the resolution error to fail validation")
+	}
+	if strings.Contains(stderr, "Breaking change") {
+		t.Errorf("expected no breaking changes for a file that failed to resolve, got:\n%s", stderr)
+	}
+	if !strings.Contains(stderr, "Skipping baseline comparison") {
+		t.Errorf("expected the baseline comparison to be skipped, got:\n%s", stderr)
+	}
+}
+
 func TestListCommand(t *testing.T) {
 	td := testdataDir(t)
 	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
@@ -95,6 +249,20 @@ func TestListCommand(t *testing.T) {
 	}
 }
 
+func TestListCommand_URL(t *testing.T) {
+	td := testdataDir(t)
+	srv := httptest.NewServer(http.FileServer(http.Dir(filepath.Join(td, "fixtures/draft/simple"))))
+	defer srv.Close()
+
+	output, err := captureAndExecute(t, "list", srv.URL+"/tokens.json")
+	if err != nil {
+		t.Fatalf("list over HTTP failed: %v", err)
+	}
+	if !strings.Contains(output, "color-primary") {
+		t.Errorf("expected output to contain 'color-primary', got:\n%s", output)
+	}
+}
+
 func TestListCommand_TypeFilter(t *testing.T) {
 	td := testdataDir(t)
 	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
@@ -108,6 +276,69 @@ func TestListCommand_TypeFilter(t *testing.T) {
 	}
 }
 
+func TestListCommand_FilterExpression(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "list", "--filter", `type == "color" && path endswith "primary"`, fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if !strings.Contains(output, "color-primary") || strings.Contains(output, "color-secondary") || strings.Contains(output, "dimension") {
+		t.Errorf("expected only color-primary, got:\n%s", output)
+	}
+
+	if _, err := captureAndExecute(t, "list", "--filter", `type = "color"`, fixture); err == nil {
+		t.Error("expected an invalid filter expression to fail")
+	}
+}
+
+func TestListCommand_Frontmatter(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "list", "--format", "markdown", "--frontmatter", "title=Tokens", "--frontmatter", "weight=10", fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if !strings.HasPrefix(output, "---\ntitle: Tokens\nweight: \"10\"\n---\n\n") {
+		t.Errorf("expected frontmatter at the top of markdown output, got:\n%s", output)
+	}
+
+	if _, err := captureAndExecute(t, "search", "--format", "markdown", "--frontmatter", "title", "primary", fixture); err == nil {
+		t.Error("expected --frontmatter without a value to fail")
+	}
+}
+
+func TestListCommand_ShowSource(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "list", "--show-source", "--no-color", fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if !strings.Contains(output, "tokens.json") {
+		t.Errorf("expected source column in table output, got:\n%s", output)
+	}
+
+	output, err = captureAndExecute(t, "list", "--show-source", "--format", "markdown", fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if !strings.Contains(output, "| Source") || !strings.Contains(output, "tokens.json |") {
+		t.Errorf("expected source column in markdown output, got:\n%s", output)
+	}
+
+	output, err = captureAndExecute(t, "list", "--no-color", fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if strings.Contains(output, "tokens.json") {
+		t.Errorf("expected no source column without --show-source, got:\n%s", output)
+	}
+}
+
 func TestListCommand_CSSFormat(t *testing.T) {
 	td := testdataDir(t)
 	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
@@ -121,6 +352,27 @@ func TestListCommand_CSSFormat(t *testing.T) {
 	}
 }
 
+func TestListCommand_RootSelector(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "list", "--format", "css", "--root-selector", ".theme-dark", fixture)
+	if err != nil {
+		t.Errorf("list command failed: %v", err)
+	}
+	if !strings.HasPrefix(output, ".theme-dark {\n") || strings.Contains(output, ":root") {
+		t.Errorf("expected .theme-dark rule, got:\n%s", output)
+	}
+
+	output, err = captureAndExecute(t, "list", "--css", "--root-selector", "none", fixture)
+	if err != nil {
+		t.Errorf("list command failed: %v", err)
+	}
+	if strings.Contains(output, "{") || !strings.Contains(output, "\n--spacing-small: 4px;\n") {
+		t.Errorf("expected bare declarations, got:\n%s", output)
+	}
+}
+
 func TestListCommand_MarkdownFormat(t *testing.T) {
 	td := testdataDir(t)
 	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
@@ -134,6 +386,137 @@ func TestListCommand_MarkdownFormat(t *testing.T) {
 	}
 }
 
+func TestListCommand_MarkdownSwatches(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "list", "--format", "markdown", "--swatches", fixture)
+	if err != nil {
+		t.Errorf("list command failed: %v", err)
+	}
+	if !strings.Contains(output, "background:#ff6b35") {
+		t.Errorf("expected inline swatch for color-primary, got:\n%s", output)
+	}
+	if strings.Count(output, "<span") != 2 {
+		t.Errorf("expected swatches only for the two color tokens, got:\n%s", output)
+	}
+}
+
+func TestListCommand_NameStyle(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "list", "--format", "names", "--name-style", "dot", fixture)
+	if err != nil {
+		t.Errorf("list command failed: %v", err)
+	}
+	if !strings.Contains(output, "color.primary\n") || strings.Contains(output, "--color-primary") {
+		t.Errorf("expected dot-path names, got:\n%s", output)
+	}
+
+	if _, err := captureAndExecute(t, "list", "--format", "names", "--name-style", "camel", fixture); err == nil {
+		t.Error("expected error for unknown --name-style")
+	}
+}
+
+func TestListCommand_TreeFormat(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "list", "--format", "tree", fixture)
+	if err != nil {
+		t.Errorf("list command failed: %v", err)
+	}
+	if !strings.Contains(output, "└─ ") {
+		t.Errorf("expected tree branches, got:\n%s", output)
+	}
+}
+
+func TestListCommand_Unused(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/list/unused/tokens.json")
+
+	output, err := captureAndExecute(t, "list", "--unused", "--format", "css", fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if !strings.Contains(output, "--color-_legacy") {
+		t.Errorf("expected unreferenced private token, got:\n%s", output)
+	}
+	for _, used := range []string{"--color-_brand", "--color-primary", "--button-bg"} {
+		if strings.Contains(output, used+":") {
+			t.Errorf("expected %s to be reachable, got:\n%s", used, output)
+		}
+	}
+
+	output, err = captureAndExecute(t, "list", "--unused", "--entry", "button", "--format", "css", fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if strings.Contains(output, "--color-primary:") || !strings.Contains(output, "--color-_legacy") {
+		t.Errorf("expected only tokens unreachable from button, got:\n%s", output)
+	}
+}
+
+func TestListCommand_NoResolve(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/list/unused/tokens.json")
+
+	output, err := captureAndExecute(t, "list", "--no-resolve", "--no-color", fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if !strings.Contains(output, "{color._brand}") || !strings.Contains(output, "{color.primary}") {
+		t.Errorf("expected references as authored, got:\n%s", output)
+	}
+	if strings.Contains(output, "→") {
+		t.Errorf("expected no resolution chains, got:\n%s", output)
+	}
+
+	fixture = filepath.Join(td, "fixtures/v2025_10/json-pointer-refs/tokens.json")
+	output, err = captureAndExecute(t, "list", "--no-resolve", "--no-color", fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if !strings.Contains(output, "{color.primary}") || strings.Contains(output, "#/") {
+		t.Errorf("expected $ref pointers as curly references, got:\n%s", output)
+	}
+
+	if _, err := captureAndExecute(t, "list", "--no-resolve", "--resolved", fixture); err == nil {
+		t.Error("expected --no-resolve with --resolved to fail")
+	}
+
+	// --resolved is deprecated, since values are resolved by default
+	resolved, err := captureAndExecute(t, "list", "--resolved", "--no-color", fixture)
+	if err != nil {
+		t.Fatalf("list --resolved failed: %v", err)
+	}
+	if plain, _ := captureAndExecute(t, "list", "--no-color", fixture); resolved != plain {
+		t.Errorf("expected --resolved to match the default output, got:\n%s\nwant:\n%s", resolved, plain)
+	}
+}
+
+func TestListCommand_Count(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/list/unused/tokens.json")
+
+	output, err := captureAndExecute(t, "list", "--count", fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if output != "4\n" {
+		t.Errorf("expected count of 4, got %q", output)
+	}
+
+	output, err = captureAndExecute(t, "list", "--count", "--group", "button", fixture)
+	if err != nil {
+		t.Fatalf("list command failed: %v", err)
+	}
+	if output != "1\n" {
+		t.Errorf("expected filtered count of 1, got %q", output)
+	}
+}
+
 func TestSearchCommand(t *testing.T) {
 	td := testdataDir(t)
 	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
@@ -147,6 +530,27 @@ func TestSearchCommand(t *testing.T) {
 	}
 }
 
+func TestSearchCommand_Count(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "search", "primary", fixture, "--count")
+	if err != nil {
+		t.Fatalf("search command failed: %v", err)
+	}
+	if output != "2\n" {
+		t.Errorf("expected count of 2, got %q", output)
+	}
+
+	output, err = captureAndExecute(t, "search", "no-such-token", fixture, "--count")
+	if err != nil {
+		t.Fatalf("search command failed: %v", err)
+	}
+	if output != "0\n" {
+		t.Errorf("expected count of 0, got %q", output)
+	}
+}
+
 func TestSearchCommand_Regex(t *testing.T) {
 	td := testdataDir(t)
 	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
@@ -170,6 +574,25 @@ func TestSearchCommand_NameOnly(t *testing.T) {
 	}
 }
 
+func TestSearchCommand_ShowMatch(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "search", "--show-match", "primary", fixture)
+	if err != nil {
+		t.Fatalf("search command failed: %v", err)
+	}
+	for _, want := range []string{
+		"#FF6B35  [name, description]",
+		"--color-secondary",
+		"[value]",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
 func TestVersionCommand(t *testing.T) {
 	output, err := captureAndExecute(t, "version")
 	if err != nil {
@@ -185,8 +608,55 @@ func TestVersionCommand_JSON(t *testing.T) {
 	if err != nil {
 		t.Errorf("version --format json failed: %v", err)
 	}
-	if !strings.Contains(output, "{") {
-		t.Errorf("expected JSON output, got:\n%s", output)
+	if !strings.Contains(output, "{") {
+		t.Errorf("expected JSON output, got:\n%s", output)
+	}
+}
+
+func TestSchemaListCommand(t *testing.T) {
+	output, err := captureAndExecute(t, "schema", "list")
+	if err != nil {
+		t.Fatalf("schema list failed: %v", err)
+	}
+	for _, want := range []string{"draft", "v2025.10", "https://www.designtokens.org/schemas/2025.10.json", "$extends"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	output, err = captureAndExecute(t, "schema", "list", "--format", "json")
+	if err != nil {
+		t.Fatalf("schema list --format json failed: %v", err)
+	}
+	var versions []struct {
+		Version  string   `json:"version"`
+		URL      string   `json:"url"`
+		Features []string `json:"features"`
+	}
+	if err := json.Unmarshal([]byte(output), &versions); err != nil {
+		t.Fatalf("expected JSON output, got %v:\n%s", err, output)
+	}
+	if len(versions) != 2 || versions[0].Version != "draft" || versions[1].URL == "" {
+		t.Errorf("unexpected versions: %+v", versions)
+	}
+}
+
+func TestConvertCommand_LegacyColorSyntax(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/v2025_10/all-color-spaces/tokens.json")
+
+	output, err := captureAndExecute(t, "convert", "--format", "css", "--color-syntax", "legacy", fixture)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	for _, want := range []string{"--color-hsl: hsl(210, 50%, 60%);", "--color-srgb-alpha: rgba(255, 128, 64, 0.5);"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q, got:\n%s", want, output)
+		}
+	}
+
+	if _, err := captureAndExecute(t, "convert", "--format", "css", "--color-syntax", "rgb", fixture); err == nil {
+		t.Error("expected an unknown color syntax to fail")
 	}
 }
 
@@ -284,6 +754,160 @@ func TestConvertCommand_Swift(t *testing.T) {
 	}
 }
 
+func TestConvertCommand_SwiftRootFontSize(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/v2025_10/all-color-spaces/tokens.json")
+
+	output, err := captureAndExecute(t, "convert", "--format", "swift", "--root-font-size", "10", fixture)
+	if err != nil {
+		t.Fatalf("convert to swift failed: %v", err)
+	}
+	// spacing.medium is 1.5rem
+	if !strings.Contains(output, "CGFloat(15) /* 1.5rem */") {
+		t.Errorf("expected rem scaled by --root-font-size, got:\n%s", output)
+	}
+
+	if _, err := captureAndExecute(t, "convert", "--format", "swift", "--root-font-size", "0", fixture); err == nil {
+		t.Error("expected an error for a zero --root-font-size")
+	}
+}
+
+func TestConvertCommand_IOSAssets(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+	catalog := filepath.Join(t.TempDir(), "Colors.xcassets")
+
+	_, err := captureAndExecute(t, "convert", "--format", "ios-assets", "--output", catalog, fixture)
+	if err != nil {
+		t.Fatalf("convert to ios-assets failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(catalog, "Contents.json")); err != nil {
+		t.Errorf("expected catalog Contents.json: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(catalog, "colorPrimary.colorset", "Contents.json"))
+	if err != nil {
+		t.Fatalf("failed to read color set: %v", err)
+	}
+	if !strings.Contains(string(data), `"color-space": "srgb"`) {
+		t.Errorf("expected sRGB color set, got:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(catalog, "spacingSmall.colorset")); err == nil {
+		t.Error("expected no color set for a dimension token")
+	}
+
+	if _, err := captureAndExecute(t, "convert", "--format", "ios-assets", fixture); err == nil {
+		t.Error("expected ios-assets without --output to fail")
+	}
+}
+
+func TestConvertCommand_Validate(t *testing.T) {
+	td := testdataDir(t)
+	outFile := filepath.Join(t.TempDir(), "tokens.css")
+
+	inconsistent := filepath.Join(td, "fixtures/validate/inconsistent/tokens.json")
+	_, err := captureAndExecute(t, "convert", "--validate", "--format", "css", "--output", outFile, inconsistent)
+	if err == nil || !strings.Contains(err.Error(), "validation failed") {
+		t.Fatalf("expected validation failure, got %v", err)
+	}
+	if _, err := os.Stat(outFile); err == nil {
+		t.Error("expected no output file after failed validation")
+	}
+
+	consistent := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+	if _, err := captureAndExecute(t, "convert", "--validate", "--format", "css", "--output", outFile, consistent); err != nil {
+		t.Fatalf("convert with --validate failed: %v", err)
+	}
+	if _, err := os.Stat(outFile); err != nil {
+		t.Errorf("expected output file: %v", err)
+	}
+
+	jsoncFile := filepath.Join(td, "fixtures/validate/jsonc/tokens.jsonc")
+	if _, err := captureAndExecute(t, "convert", "--validate", "--format", "css", "--output", outFile, jsoncFile); err != nil {
+		t.Errorf("convert with --validate failed on JSONC: %v", err)
+	}
+}
+
+func TestConvertCommand_FileMode(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+	outDir := filepath.Join(t.TempDir(), "private")
+	outFile := filepath.Join(outDir, "tokens.css")
+
+	if _, err := captureAndExecute(t, "convert", "--format", "css", "--file-mode", "0600", "--dir-mode", "0700", "-o", outFile, fixture); err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	for path, want := range map[string]os.FileMode{outDir: 0700, outFile: 0600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("failed to stat %s: %v", path, err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s mode = %o, want %o", path, got, want)
+		}
+	}
+
+	if _, err := captureAndExecute(t, "convert", "--format", "css", "--file-mode", "rw-------", "-o", outFile, fixture); err == nil {
+		t.Error("expected an error for a non-octal --file-mode")
+	}
+}
+
+func TestConvertCommand_FileModeExistingOutput(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+	outDir := filepath.Join(t.TempDir(), "dist")
+	outFile := filepath.Join(outDir, "tokens.css")
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(outFile, []byte("/* stale */\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := captureAndExecute(t, "convert", "--format", "css", "--file-mode", "0600", "--dir-mode", "0700", "-o", outFile, fixture); err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	assertMode(t, outDir, 0700)
+	assertMode(t, outFile, 0600)
+}
+
+func TestConvertCommand_FileModeInPlace(t *testing.T) {
+	td := testdataDir(t)
+	data, err := os.ReadFile(filepath.Join(td, "fixtures/draft/simple/tokens.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "tokens.json")
+	if err := os.WriteFile(file, data, 0664); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(file, 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without --file-mode, rewriting a source file keeps its permissions
+	if _, err := captureAndExecute(t, "convert", "--in-place", "--force", file); err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	assertMode(t, file, 0664)
+
+	if _, err := captureAndExecute(t, "convert", "--in-place", "--force", "--file-mode", "0600", file); err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	assertMode(t, file, 0600)
+}
+
+func assertMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat %s: %v", path, err)
+	}
+	if got := info.Mode().Perm(); got != want {
+		t.Errorf("%s mode = %o, want %o", path, got, want)
+	}
+}
+
 func TestConvertCommand_Android(t *testing.T) {
 	td := testdataDir(t)
 	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
@@ -324,6 +948,29 @@ func TestConvertCommand_JS(t *testing.T) {
 	}
 }
 
+func TestConvertCommand_NoJSDoc(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+	outFile := filepath.Join(t.TempDir(), "output.ts")
+
+	_, err := captureAndExecute(t, "convert", "--format", "js", "--no-jsdoc", "--output", outFile, fixture)
+	if err != nil {
+		t.Fatalf("convert to js failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if strings.Contains(string(data), "/**") {
+		t.Errorf("expected no JSDoc comments, got:\n%s", data)
+	}
+
+	if _, err := captureAndExecute(t, "convert", "--format", "js", "--no-jsdoc", "--js-types", "jsdoc", fixture); err == nil {
+		t.Error("expected --no-jsdoc with --js-types jsdoc to fail")
+	}
+}
+
 func TestConvertCommand_Snippets(t *testing.T) {
 	td := testdataDir(t)
 	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
@@ -364,6 +1011,138 @@ func TestConvertCommand_Flatten(t *testing.T) {
 	}
 }
 
+func TestConvertCommand_Template(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+	tmplFile := filepath.Join(t.TempDir(), "tokens.tmpl")
+	if err := os.WriteFile(tmplFile, []byte("{{range .Tokens}}{{.CSSVar}}={{.DisplayValue}}\n{{end}}"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	output, err := captureAndExecute(t, "convert", "--format", "template", "--template-file", tmplFile, fixture)
+	if err != nil {
+		t.Fatalf("convert with template failed: %v", err)
+	}
+	if !strings.Contains(output, "--color-primary=") {
+		t.Errorf("expected templated output, got:\n%s", output)
+	}
+}
+
+func TestConvertCommand_CustomMedia(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "convert", "--format", "css-custom-media", "--custom-media-group", "spacing", fixture)
+	if err != nil {
+		t.Fatalf("convert css-custom-media failed: %v", err)
+	}
+	if !strings.Contains(output, "@custom-media --spacing-medium (min-width: 8px);") {
+		t.Errorf("expected custom media rule, got:\n%s", output)
+	}
+	if strings.Contains(output, "color-primary") {
+		t.Errorf("expected tokens outside the group to be ignored, got:\n%s", output)
+	}
+}
+
+func TestConvertCommand_TemplateRequiresFile(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+
+	_, err := captureAndExecute(t, "convert", "--format", "template", fixture)
+	if err == nil {
+		t.Error("expected error when --template-file is missing")
+	}
+}
+
+func TestConvertCommand_StripDeprecated(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/convert/strip-deprecated/tokens.json")
+
+	var stderr bytes.Buffer
+	logger.SetOutput(&stderr)
+	t.Cleanup(func() { logger.SetOutput(os.Stderr) })
+
+	output, err := captureAndExecute(t, "convert", "--format", "json", "--strip-deprecated", fixture)
+	if err != nil {
+		t.Fatalf("convert --strip-deprecated failed: %v", err)
+	}
+	if strings.Contains(output, "color-legacy") {
+		t.Errorf("expected deprecated token to be stripped, got:\n%s", output)
+	}
+	if !strings.Contains(output, "color-primary") || !strings.Contains(output, "color-accent") {
+		t.Errorf("expected non-deprecated tokens to remain, got:\n%s", output)
+	}
+	want := "color.accent references deprecated token color.legacy, which was stripped"
+	if !strings.Contains(stderr.String(), want) {
+		t.Errorf("expected a warning about the dangling reference, got:\n%s", stderr.String())
+	}
+}
+
+func TestConvertCommand_StripMeta(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/convert/strip-meta/tokens.json")
+
+	output, err := captureAndExecute(t, "convert", "--strip-meta", "extensions,descriptions", fixture)
+	if err != nil {
+		t.Fatalf("convert --strip-meta failed: %v", err)
+	}
+	if strings.Contains(output, "$extensions") || strings.Contains(output, "$description") {
+		t.Errorf("expected metadata to be stripped, got:\n%s", output)
+	}
+	if !strings.Contains(output, "#FF6B35") {
+		t.Errorf("expected token values to remain, got:\n%s", output)
+	}
+
+	if _, err := captureAndExecute(t, "convert", "--strip-meta", "types", fixture); err == nil {
+		t.Error("expected error for unknown --strip-meta key")
+	}
+}
+
+func TestConvertCommand_PrivateTokens(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/convert/private/tokens.json")
+
+	output, err := captureAndExecute(t, "convert", "--format", "css", fixture)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if strings.Contains(output, "--color-brand") {
+		t.Errorf("expected private token to be excluded, got:\n%s", output)
+	}
+	if !strings.Contains(output, "--color-primary: #FF6B35;") {
+		t.Errorf("expected public alias to keep the private value, got:\n%s", output)
+	}
+
+	output, err = captureAndExecute(t, "convert", "--format", "css", "--include-private", fixture)
+	if err != nil {
+		t.Fatalf("convert --include-private failed: %v", err)
+	}
+	if !strings.Contains(output, "--color-brand: #FF6B35;") {
+		t.Errorf("expected private token with --include-private, got:\n%s", output)
+	}
+
+	// Formats that keep references write the value of a stripped private
+	// token instead of a reference to it.
+	output, err = captureAndExecute(t, "convert", "--format", "dtcg", fixture)
+	if err != nil {
+		t.Fatalf("convert --format dtcg failed: %v", err)
+	}
+	if strings.Contains(output, "_brand") {
+		t.Errorf("expected no reference to the private token, got:\n%s", output)
+	}
+	if !strings.Contains(output, `"$value": "#FF6B35"`) {
+		t.Errorf("expected public alias to be written with the private value, got:\n%s", output)
+	}
+
+	output, err = captureAndExecute(t, "convert", "--format", "css", "--css-references", fixture)
+	if err != nil {
+		t.Fatalf("convert --css-references failed: %v", err)
+	}
+	if !strings.Contains(output, "--color-primary: #FF6B35;") {
+		t.Errorf("expected public alias to keep the private value, got:\n%s", output)
+	}
+}
+
 func TestNewRootCmd_HasAllSubcommands(t *testing.T) {
 	rootCmd := cmd.NewRootCmd()
 	expectedCmds := []string{"convert", "list", "search", "validate", "version"}
@@ -438,3 +1217,321 @@ func TestExecute(t *testing.T) {
 		t.Errorf("expected 'asimonim' in output, got: %s", buf.String())
 	}
 }
+
+func TestVerbosityFlags(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+	broken := filepath.Join(t.TempDir(), "broken.json")
+	if err := os.WriteFile(broken, []byte(`{"color": {"$value": `), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	var stderr bytes.Buffer
+	logger.SetOutput(&stderr)
+	t.Cleanup(func() {
+		logger.SetOutput(os.Stderr)
+		logger.SetLevel(logger.LevelNormal)
+	})
+
+	tests := []struct {
+		name    string
+		flag    string
+		want    []string
+		notWant []string
+	}{
+		{"default", "", []string{"Error"}, []string{"Detected schema"}},
+		{"verbose", "--verbose", []string{"Error", "Detected schema draft for " + fixture}, nil},
+		{"quiet", "--quiet", nil, []string{"Error", "Detected schema"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stderr.Reset()
+			args := []string{"list"}
+			if tt.flag != "" {
+				args = append(args, tt.flag)
+			}
+			if _, err := captureAndExecute(t, append(args, fixture, broken)...); err != nil {
+				t.Fatalf("list command failed: %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(stderr.String(), s) {
+					t.Errorf("expected stderr to contain %q, got:\n%s", s, stderr.String())
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(stderr.String(), s) {
+					t.Errorf("expected stderr not to contain %q, got:\n%s", s, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func TestVerbosityFlags_MutuallyExclusive(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/draft/simple/tokens.json")
+	if _, err := captureAndExecute(t, "list", "--verbose", "--quiet", fixture); err == nil {
+		t.Error("expected an error when combining --verbose and --quiet")
+	}
+}
+
+func TestCompletion_GroupFlag(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/config/simple/tokens.json")
+
+	output, err := captureAndExecute(t, "__complete", "list", fixture, "--group", "co")
+	if err != nil {
+		t.Fatalf("completion failed: %v", err)
+	}
+	if !strings.HasPrefix(output, "color\n") {
+		t.Errorf("expected group completion for color, got:\n%s", output)
+	}
+}
+
+func TestCompletion_SearchQuery(t *testing.T) {
+	td := testdataDir(t)
+	fixture := filepath.Join(td, "fixtures/config/simple/tokens.json")
+
+	// With a query already given, the remaining arguments are files
+	output, err := captureAndExecute(t, "__complete", "search", "color", fixture, "")
+	if err != nil {
+		t.Fatalf("completion failed: %v", err)
+	}
+	if strings.Contains(output, "color.primary") {
+		t.Errorf("expected file completion after the query, got:\n%s", output)
+	}
+}
+
+func TestConvertCommand_TransformColor(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "tokens.json")
+	tokens := `{
+  "color": {
+    "$type": "color",
+    "red": {"$value": {"colorSpace": "srgb", "components": [1, 0, 0]}},
+    "vivid": {"$value": {"colorSpace": "display-p3", "components": [0, 1, 0]}}
+  }
+}`
+	if err := os.WriteFile(fixture, []byte(tokens), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	var stderr bytes.Buffer
+	logger.SetOutput(&stderr)
+	t.Cleanup(func() { logger.SetOutput(os.Stderr) })
+
+	tests := []struct {
+		transform string
+		want      []string
+		notWant   []string
+		warning   string
+	}{
+		{"none", []string{"--color-vivid: color(display-p3 0 1 0)"}, nil, ""},
+		{"srgb", []string{"--color-vivid: #00ff00"}, []string{"display-p3 0 1 0"}, "color.vivid: converted display-p3 color to sRGB"},
+		{"srgb-only", []string{"--color-red"}, []string{"--color-vivid"}, "color.vivid: skipped display-p3 color"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.transform, func(t *testing.T) {
+			stderr.Reset()
+			output, err := captureAndExecute(t, "convert", "--format", "css", "--transform-color", tt.transform, fixture)
+			if err != nil {
+				t.Fatalf("convert command failed: %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(output, s) {
+					t.Errorf("expected output to contain %q, got:\n%s", s, output)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(output, s) {
+					t.Errorf("expected output not to contain %q, got:\n%s", s, output)
+				}
+			}
+			if tt.warning == "" && stderr.Len() > 0 {
+				t.Errorf("expected no warnings, got:\n%s", stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.warning) {
+				t.Errorf("expected stderr to contain %q, got:\n%s", tt.warning, stderr.String())
+			}
+		})
+	}
+
+	if _, err := captureAndExecute(t, "convert", "--transform-color", "p3", fixture); err == nil {
+		t.Error("expected an error for an unknown color transform")
+	}
+}
+
+func TestConvertCommand_InPlaceCheck(t *testing.T) {
+	dir := t.TempDir()
+	compact := filepath.Join(dir, "compact.json")
+	if err := os.WriteFile(compact, []byte(`{"color": {"red": {"$type": "color", "$value": "#ff0000"}}}`), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	output, err := captureAndExecute(t, "convert", "--in-place", "--check", compact)
+	if err == nil {
+		t.Fatal("expected --check to fail for a file that would change")
+	}
+	if strings.TrimSpace(output) != compact {
+		t.Errorf("expected output to list %s, got:\n%s", compact, output)
+	}
+
+	if _, err := captureAndExecute(t, "convert", "--in-place", compact); err != nil {
+		t.Fatalf("in-place conversion failed: %v", err)
+	}
+	output, err = captureAndExecute(t, "convert", "--in-place", "--check", compact)
+	if err != nil {
+		t.Errorf("expected --check to pass after conversion, got %v", err)
+	}
+	if output != "" {
+		t.Errorf("expected no files listed, got:\n%s", output)
+	}
+
+	if _, err := captureAndExecute(t, "convert", "--check", compact); err == nil {
+		t.Error("expected --check without --in-place to fail")
+	}
+}
+
+func TestRenameCommand(t *testing.T) {
+	dir := t.TempDir()
+	colors := filepath.Join(dir, "colors.json")
+	aliases := filepath.Join(dir, "aliases.json")
+	write := func() {
+		t.Helper()
+		files := map[string]string{
+			colors:  `{"color": {"$type": "color", "old": {"$value": "#ff0000"}, "new": {"$value": "#00ff00"}}}`,
+			aliases: `{"button": {"bg": {"$type": "color", "$value": "{color.old}"}}}`,
+		}
+		for path, content := range files {
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("failed to write fixture: %v", err)
+			}
+		}
+	}
+	read := func(path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		return string(data)
+	}
+	write()
+
+	_, err := captureAndExecute(t, "rename", "color.old", "color.new", colors, aliases, "--in-place")
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected refusal to replace color.new, got %v", err)
+	}
+	if _, err := captureAndExecute(t, "rename", "color.missing", "color.other", colors, aliases); err == nil {
+		t.Error("expected an error renaming a missing token")
+	}
+
+	output, err := captureAndExecute(t, "rename", "color.old", "color.new", colors, aliases, "--dry-run", "--force")
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if !strings.Contains(output, colors+" (2 token(s))") || !strings.Contains(output, aliases+" (1 token(s))") {
+		t.Errorf("expected both files in dry run output, got:\n%s", output)
+	}
+	if strings.Contains(read(aliases), "color.new") {
+		t.Error("dry run modified a file")
+	}
+
+	if _, err := captureAndExecute(t, "rename", "{color.old}", "{color.new}", colors, aliases, "--in-place", "--force"); err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+	if got := read(aliases); !strings.Contains(got, `"{color.new}"`) {
+		t.Errorf("expected reference to be rewritten, got:\n%s", got)
+	}
+	got := read(colors)
+	if strings.Contains(got, `"old"`) || strings.Contains(got, "#00ff00") || !strings.Contains(got, "#ff0000") {
+		t.Errorf("expected color.old to replace color.new, got:\n%s", got)
+	}
+}
+
+func TestRenameCommand_Stdout(t *testing.T) {
+	dir := t.TempDir()
+	colors := filepath.Join(dir, "colors.json")
+	original := `{"color": {"$type": "color", "old": {"$value": "#ff0000"}}}`
+	if err := os.WriteFile(colors, []byte(original), 0644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+
+	output, err := captureAndExecute(t, "rename", "color.old", "color.new", colors)
+	if err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+	if !strings.Contains(output, `"new"`) || strings.Contains(output, "==>") {
+		t.Errorf("expected the renamed file without a header, got:\n%s", output)
+	}
+	data, err := os.ReadFile(colors)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", colors, err)
+	}
+	if string(data) != original {
+		t.Error("rename without --in-place modified the file")
+	}
+}
+
+func TestRenameCommand_PreservesFile(t *testing.T) {
+	fixtures := filepath.Join(testdataDir(t), "fixtures", "rename")
+	tests := []struct {
+		name, file, oldPath, newPath string
+		want                         func(original string) string
+	}{
+		{
+			name:    "json key",
+			file:    "tokens.json",
+			oldPath: "color.brand",
+			newPath: "color.primary",
+			want: func(original string) string {
+				return strings.NewReplacer(`"brand":`, `"primary":`, "{color.brand}", "{color.primary}").Replace(original)
+			},
+		},
+		{
+			name:    "yaml move",
+			file:    "tokens.yaml",
+			oldPath: "color.brand",
+			newPath: "palette.brand.primary",
+			want: func(string) string {
+				return `# Design tokens
+color:
+  $type: color
+  $description: Brand and surface colors
+  accent:
+    $value: '{palette.brand.primary}'
+palette:
+  $description: Named palette entries
+  gray:
+    $type: color
+    $value: "#888888"
+  brand:
+    primary:
+      $value: "#ff6b35" # primary brand color
+`
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, err := os.ReadFile(filepath.Join(fixtures, tt.file))
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, original, 0644); err != nil {
+				t.Fatalf("failed to write fixture: %v", err)
+			}
+
+			if _, err := captureAndExecute(t, "rename", tt.oldPath, tt.newPath, path, "--in-place"); err != nil {
+				t.Fatalf("rename failed: %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", path, err)
+			}
+			if want := tt.want(string(original)); string(got) != want {
+				t.Errorf("unexpected file contents\ngot:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
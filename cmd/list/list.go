@@ -12,13 +12,15 @@ import (
 	"maps"
 	"os"
 	"sort"
-	"strings"
 
 	"github.com/spf13/cobra"
 
+	"bennypowers.dev/asimonim/cmd/complete"
+	"bennypowers.dev/asimonim/cmd/filter"
 	"bennypowers.dev/asimonim/cmd/render"
 	"bennypowers.dev/asimonim/config"
 	"bennypowers.dev/asimonim/fs"
+	"bennypowers.dev/asimonim/internal/logger"
 	"bennypowers.dev/asimonim/parser"
 	"bennypowers.dev/asimonim/resolver"
 	"bennypowers.dev/asimonim/schema"
@@ -39,30 +41,78 @@ func NewCmd() *cobra.Command {
 		RunE:  run,
 	}
 	cmd.Flags().String("type", "", "Filter by token type")
+	cmd.Flags().StringSlice("exclude-type", nil, "Hide tokens of this type (repeatable)")
 	cmd.Flags().Bool("resolved", false, "Show resolved values")
+	cmd.Flags().Bool("no-resolve", false, "Show values as authored, without resolving aliases")
+	// Values are always resolved unless --no-resolve is given
+	_ = cmd.Flags().MarkDeprecated("resolved", "values are resolved by default; use --no-resolve to show references as authored")
+	cmd.Flags().Bool("count", false, "Print only the number of tokens")
 	cmd.Flags().Bool("css", false, "Output as CSS custom properties")
-	cmd.Flags().String("format", "table", "Output format: table, css, markdown")
+	cmd.Flags().String("format", "table", "Output format: table, css, markdown, tree, names")
 	cmd.Flags().String("group", "", "Filter by group/path prefix (e.g., color.brand)")
 	cmd.Flags().Bool("deprecated", false, "Show only deprecated tokens")
 	cmd.Flags().Bool("no-deprecated", false, "Hide deprecated tokens")
+	cmd.Flags().String("filter", "", `Filter expression, e.g. 'type == "color" && !deprecated'`)
 	cmd.Flags().Bool("toc", false, "Include table of contents (markdown only)")
 	cmd.Flags().Int("toc-depth", 3, "Maximum TOC depth (1-6)")
 	cmd.Flags().Bool("links", false, "Add anchor links to tokens (markdown only)")
+	cmd.Flags().Bool("no-color", false, "Disable color swatches (also disabled by NO_COLOR or non-terminal output)")
+	cmd.Flags().Bool("ascii", false, "Use ASCII arrows and tree branches, without color")
+	cmd.Flags().Bool("unused", false, "Show only tokens not reachable from the entry points")
+	cmd.Flags().StringSlice("entry", nil, "Entry point token or group for --unused (default: all public tokens)")
+	cmd.Flags().String("link-base", "", "Prefix for token links, e.g. tokens/colors# (markdown only)")
+	cmd.Flags().Bool("swatches", false, "Show inline HTML color swatches (markdown only)")
+	cmd.Flags().Bool("show-source", false, "Add a column with the file each token came from (table and markdown only)")
+	cmd.Flags().String("group-by", "hierarchy", "Markdown sections: hierarchy (nested by path) or type (markdown only)")
+	cmd.Flags().StringArray("frontmatter", nil, "YAML frontmatter key=value to put at the top of the page (repeatable, markdown only)")
+	cmd.Flags().String("name-style", "css", "Names to print with --format names: css, dot, or short")
+	cmd.Flags().String("root-selector", ":root", "Selector wrapping css output, or none for bare declarations")
+	_ = cmd.RegisterFlagCompletionFunc("group", complete.Groups(complete.AllArgs))
+	_ = cmd.RegisterFlagCompletionFunc("entry", complete.Tokens(complete.AllArgs))
 	return cmd
 }
 
 func run(cmd *cobra.Command, args []string) error {
 	typeFilter, _ := cmd.Flags().GetString("type")
+	excludeTypes, _ := cmd.Flags().GetStringSlice("exclude-type")
 	resolved, _ := cmd.Flags().GetBool("resolved")
+	noResolve, _ := cmd.Flags().GetBool("no-resolve")
+	count, _ := cmd.Flags().GetBool("count")
 	css, _ := cmd.Flags().GetBool("css")
 	format, _ := cmd.Flags().GetString("format")
 	schemaFlag, _ := cmd.Flags().GetString("schema")
 	groupFilter, _ := cmd.Flags().GetString("group")
 	onlyDeprecated, _ := cmd.Flags().GetBool("deprecated")
 	hideDeprecated, _ := cmd.Flags().GetBool("no-deprecated")
+	filterFlag, _ := cmd.Flags().GetString("filter")
 	includeTOC, _ := cmd.Flags().GetBool("toc")
 	tocDepth, _ := cmd.Flags().GetInt("toc-depth")
 	showLinks, _ := cmd.Flags().GetBool("links")
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	ascii, _ := cmd.Flags().GetBool("ascii")
+	swatches, _ := cmd.Flags().GetBool("swatches")
+	nameStyleFlag, _ := cmd.Flags().GetString("name-style")
+	groupByFlag, _ := cmd.Flags().GetString("group-by")
+	rootSelector, _ := cmd.Flags().GetString("root-selector")
+	linkBase, _ := cmd.Flags().GetString("link-base")
+	onlyUnused, _ := cmd.Flags().GetBool("unused")
+	entryPoints, _ := cmd.Flags().GetStringSlice("entry")
+	showSource, _ := cmd.Flags().GetBool("show-source")
+	frontmatterFlag, _ := cmd.Flags().GetStringArray("frontmatter")
+
+	nameStyle, err := render.ParseNameStyle(nameStyleFlag)
+	if err != nil {
+		return err
+	}
+
+	groupBy, err := render.ParseGroupBy(groupByFlag)
+	if err != nil {
+		return err
+	}
+	frontmatter, err := render.ParseFrontmatter(frontmatterFlag)
+	if err != nil {
+		return err
+	}
 
 	if tocDepth < 1 || tocDepth > 6 {
 		return fmt.Errorf("toc-depth must be between 1 and 6, got %d", tocDepth)
@@ -72,11 +122,28 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot use --deprecated and --no-deprecated together")
 	}
 
+	expr := filter.FlagExpr(typeFilter, excludeTypes, groupFilter, onlyDeprecated, hideDeprecated)
+	if filterFlag != "" {
+		userExpr, err := filter.Parse(filterFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --filter: %w", err)
+		}
+		expr = filter.And(expr, userExpr)
+	}
+
+	if resolved && noResolve {
+		return fmt.Errorf("cannot use --resolved and --no-resolve together")
+	}
+
+	if len(entryPoints) > 0 && !onlyUnused {
+		return fmt.Errorf("--entry requires --unused")
+	}
+
 	if css {
 		format = "css"
 	}
 
-	filesystem := fs.NewOSFileSystem()
+	filesystem := fs.WithURLs(fs.NewOSFileSystem(), nil)
 	jsonParser := parser.NewJSONParser()
 
 	cwd, err := os.Getwd()
@@ -141,7 +208,7 @@ func run(cmd *cobra.Command, args []string) error {
 	for _, rf := range resolvedFiles {
 		data, err := filesystem.ReadFile(rf.Path)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", rf.Specifier, err)
+			logger.Error("Error reading %s: %v", rf.Specifier, err)
 			continue
 		}
 
@@ -156,9 +223,10 @@ func run(cmd *cobra.Command, args []string) error {
 		if version == schema.Unknown {
 			version, err = schema.DetectVersion(data, nil)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error detecting schema for %s: %v\n", rf.Specifier, err)
+				logger.Error("Error detecting schema for %s: %v", rf.Specifier, err)
 				continue
 			}
+			logger.Debug("Detected schema %s for %s", version, rf.Specifier)
 		}
 		if detectedVersion == schema.Unknown {
 			detectedVersion = version
@@ -172,7 +240,7 @@ func run(cmd *cobra.Command, args []string) error {
 		}
 		tokens, err := jsonParser.ParseFile(filesystem, rf.Path, opts)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", rf.Specifier, err)
+			logger.Error("Error parsing %s: %v", rf.Specifier, err)
 			continue
 		}
 
@@ -183,76 +251,73 @@ func run(cmd *cobra.Command, args []string) error {
 	if detectedVersion == schema.Unknown {
 		detectedVersion = schema.Draft
 	}
-	if err := resolver.ResolveAliases(allTokens, detectedVersion); err != nil {
-		return fmt.Errorf("error resolving aliases: %w", err)
+	if !noResolve {
+		if err := resolver.ResolveAliases(allTokens, detectedVersion); err != nil {
+			return fmt.Errorf("error resolving aliases: %w", err)
+		}
+	}
+
+	// Unused tokens are found across the whole set, before filtering
+	if onlyUnused {
+		if len(entryPoints) == 0 {
+			entryPoints = publicTokenPaths(allTokens, cfg)
+		}
+		allTokens = resolver.FindUnused(allTokens, entryPoints)
 	}
 
 	// Apply filters
-	allTokens = filterTokens(allTokens, typeFilter, groupFilter, onlyDeprecated, hideDeprecated)
+	if expr != nil {
+		allTokens = expr.Filter(allTokens)
+	}
+
+	if count {
+		fmt.Println(len(allTokens))
+		return nil
+	}
 
 	sort.Slice(allTokens, func(i, j int) bool {
 		return allTokens[i].Name < allTokens[j].Name
 	})
 
 	// Compute display rows once
-	rows := render.ComputeRows(allTokens, resolved)
+	rows := render.ComputeRows(allTokens, noResolve)
 
 	switch format {
 	case "css":
-		return render.CSS(rows)
+		return render.CSS(rows, rootSelector)
+	case "names":
+		return render.Names(rows, nameStyle)
 	case "markdown", "md":
 		opts := render.MarkdownOptions{
-			GroupMeta:  allGroupMeta,
-			IncludeTOC: includeTOC,
-			TOCDepth:   tocDepth,
-			ShowLinks:  showLinks,
+			GroupMeta:     allGroupMeta,
+			IncludeTOC:    includeTOC,
+			TOCDepth:      tocDepth,
+			ShowLinks:     showLinks,
+			LinkBase:      linkBase,
+			ColorSwatches: swatches,
+			GroupBy:       groupBy,
+			ShowSource:    showSource,
+			Frontmatter:   frontmatter,
 		}
 		return render.MarkdownWithOptions(rows, opts)
+	case "tree":
+		return render.Tree(os.Stdout, rows, render.DetectStyle(os.Stdout, noColor, ascii))
 	default:
-		return render.Table(rows)
+		style := render.DetectStyle(os.Stdout, noColor, ascii)
+		style.Source = showSource
+		return render.Table(rows, style)
 	}
 }
 
-func filterTokens(tokens []*token.Token, typeFilter, groupFilter string, onlyDeprecated, hideDeprecated bool) []*token.Token {
-	result := tokens
-
-	if typeFilter != "" {
-		filtered := make([]*token.Token, 0, len(result))
-		for _, tok := range result {
-			if tok.Type == typeFilter {
-				filtered = append(filtered, tok)
-			}
+// publicTokenPaths returns the dot paths of all non-private tokens.
+// These are the default entry points for --unused, so that only private
+// tokens no public token refers to are reported.
+func publicTokenPaths(tokens []*token.Token, cfg *config.Config) []string {
+	var paths []string
+	for _, tok := range tokens {
+		if !cfg.IsPrivate(tok.Path) {
+			paths = append(paths, tok.DotPath())
 		}
-		result = filtered
 	}
-
-	if groupFilter != "" {
-		filtered := make([]*token.Token, 0, len(result))
-		for _, tok := range result {
-			if strings.HasPrefix(tok.DotPath(), groupFilter) {
-				filtered = append(filtered, tok)
-			}
-		}
-		result = filtered
-	}
-
-	if onlyDeprecated {
-		filtered := make([]*token.Token, 0, len(result))
-		for _, tok := range result {
-			if tok.Deprecated {
-				filtered = append(filtered, tok)
-			}
-		}
-		result = filtered
-	} else if hideDeprecated {
-		filtered := make([]*token.Token, 0, len(result))
-		for _, tok := range result {
-			if !tok.Deprecated {
-				filtered = append(filtered, tok)
-			}
-		}
-		result = filtered
-	}
-
-	return result
+	return paths
 }
@@ -0,0 +1,870 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package rename
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tidwall/jsonc"
+	"gopkg.in/yaml.v3"
+
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/token"
+)
+
+// editFile renames the token at oldPath to newPath in data, the content
+// of the token file at path, and rewrites the references to it. defines
+// reports whether the file holds the token at oldPath, and replaces
+// whether it holds a token at newPath to replace, which rename only
+// allows with --force.
+//
+// The file is edited where it stands rather than serialized again: only
+// the renamed key, the moved or removed entries, and the strings holding
+// references change, so the rest of the file keeps its metadata, key
+// order, comments, and formatting.
+func editFile(data []byte, path, oldPath, newPath string, defines, replaces bool) ([]byte, error) {
+	src, err := newSource(data, parser.FormatFromPath(path) != parser.FormatYAML)
+	if err != nil {
+		return nil, err
+	}
+	if err := src.renameReferences(oldPath, newPath); err != nil {
+		return nil, err
+	}
+
+	oldSegs := strings.Split(oldPath, ".")
+	newSegs := strings.Split(newPath, ".")
+	old, hasOld := src.find(oldSegs)
+	existing, hasExisting := src.find(newSegs)
+	// Tokens whose path isn't their place in the file, like $root
+	// tokens, can't be found by key
+	if defines && !hasOld {
+		return nil, fmt.Errorf("cannot find the key for %s", oldPath)
+	}
+	if replaces && !hasExisting {
+		return nil, fmt.Errorf("cannot find the key for %s", newPath)
+	}
+
+	switch {
+	case defines && replaces:
+		err = src.replace(old, existing, newSegs)
+	case defines && slices.Equal(oldSegs[:len(oldSegs)-1], newSegs[:len(newSegs)-1]):
+		err = src.renameKey(old.key, newSegs[len(newSegs)-1])
+	case defines:
+		err = src.move(old, newSegs)
+	case replaces:
+		err = src.remove(existing)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return src.apply()
+}
+
+// edit replaces data[start:end] with text. An edit with start == end
+// inserts text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// entry is a key and its value in a mapping.
+type entry struct {
+	key, value *yaml.Node
+	// parents are the entries of the groups holding this one, outermost
+	// first; parent is the mapping that holds it.
+	parents []*entry
+	parent  *yaml.Node
+	path    []string
+}
+
+// source is a token file being edited. Nodes come from yaml.v3, which
+// reads JSON as well as YAML; JSON is parsed with its comments blanked
+// out, which keeps every offset the same, and scanned the same way.
+type source struct {
+	data, clean []byte
+	json        bool
+	roots       []*yaml.Node
+	lines       []int
+	edits       []edit
+}
+
+func newSource(data []byte, isJSON bool) (*source, error) {
+	src := &source{data: data, clean: data, json: isJSON}
+	if isJSON {
+		src.clean = jsonc.ToJSON(data)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(src.clean))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse: %w", err)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		switch root := doc.Content[0]; root.Kind {
+		case yaml.MappingNode:
+			src.roots = append(src.roots, root)
+		case yaml.SequenceNode:
+			for _, item := range root.Content {
+				if item.Kind == yaml.MappingNode {
+					src.roots = append(src.roots, item)
+				}
+			}
+		}
+	}
+
+	src.lines = []int{0}
+	for i, c := range data {
+		if c == '\n' {
+			src.lines = append(src.lines, i+1)
+		}
+	}
+	return src, nil
+}
+
+// find returns the token entry at path, a token being a mapping with
+// $value or $ref.
+func (s *source) find(path []string) (*entry, bool) {
+	e, ok := s.findAny(path)
+	if !ok || !isToken(e.value) {
+		return nil, false
+	}
+	return e, true
+}
+
+// findAny returns the entry at path in the first root that has one.
+func (s *source) findAny(path []string) (*entry, bool) {
+	for _, root := range s.roots {
+		var parents []*entry
+		mapping := root
+		for i, seg := range path {
+			e := lookup(mapping, seg)
+			if e == nil {
+				break
+			}
+			e.parents = parents
+			e.path = path[:i+1]
+			if i == len(path)-1 {
+				return e, true
+			}
+			if e.value.Kind != yaml.MappingNode {
+				break
+			}
+			parents = append(slices.Clip(parents), e)
+			mapping = e.value
+		}
+	}
+	return nil, false
+}
+
+// lookup returns the entry for key in mapping, or nil.
+func lookup(mapping *yaml.Node, key string) *entry {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return &entry{key: mapping.Content[i], value: mapping.Content[i+1], parent: mapping}
+		}
+	}
+	return nil
+}
+
+// isToken reports whether node is a token: a mapping with $value or $ref.
+func isToken(node *yaml.Node) bool {
+	return node.Kind == yaml.MappingNode && (lookup(node, "$value") != nil || lookup(node, "$ref") != nil)
+}
+
+// tokenKeys are the token members whose strings rename rewrites,
+// matching token.Rename.
+var tokenKeys = []string{"$value", "$ref", "$extensions", "$deprecated"}
+
+// renameReferences rewrites the references to oldPath in every token.
+func (s *source) renameReferences(oldPath, newPath string) error {
+	var walk func(mapping *yaml.Node) error
+	walk = func(mapping *yaml.Node) error {
+		isTok := isToken(mapping)
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			key, value := mapping.Content[i].Value, mapping.Content[i+1]
+			switch {
+			case isTok && slices.Contains(tokenKeys, key):
+				if err := s.renameStrings(value, key == "$deprecated", oldPath, newPath); err != nil {
+					return err
+				}
+			case !strings.HasPrefix(key, "$") && value.Kind == yaml.MappingNode:
+				if err := walk(value); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for _, root := range s.roots {
+		if err := walk(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameStrings rewrites the references in the strings under node. In a
+// $deprecated object, the replacement may also name the token without
+// braces.
+func (s *source) renameStrings(node *yaml.Node, deprecated bool, oldPath, newPath string) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag != "!!str" {
+			return nil
+		}
+		value, changed := token.RenameReferences(node.Value, oldPath, newPath)
+		if !changed {
+			return nil
+		}
+		return s.replaceScalar(node, value)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if deprecated && key.Value == "replacement" && value.Kind == yaml.ScalarNode && value.Value == oldPath {
+				if err := s.replaceScalar(value, newPath); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := s.renameStrings(value, deprecated, oldPath, newPath); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if err := s.renameStrings(item, deprecated, oldPath, newPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// replaceScalar rewrites a string scalar as value, in the scalar's own
+// quoting style.
+func (s *source) replaceScalar(node *yaml.Node, value string) error {
+	start, end, err := s.scalarSpan(node)
+	if err != nil {
+		return err
+	}
+	s.edits = append(s.edits, edit{start, end, encodeScalar(node.Style, value, false)})
+	return nil
+}
+
+// renameKey renames a key in place.
+func (s *source) renameKey(key *yaml.Node, name string) error {
+	start, end, err := s.scalarSpan(key)
+	if err != nil {
+		return err
+	}
+	s.edits = append(s.edits, edit{start, end, encodeScalar(key.Style, name, true)})
+	return nil
+}
+
+// replace gives existing, the token at newSegs, the value of old, and
+// removes old.
+func (s *source) replace(old, existing *entry, newSegs []string) error {
+	from, to, err := s.valueSpan(old)
+	if err != nil {
+		return err
+	}
+	start, end, err := s.valueSpan(existing)
+	if err != nil {
+		return err
+	}
+	value := s.render(from, to, nil)
+	if s.ownLine(s.offset(old.key)) && s.ownLine(s.offset(existing.key)) {
+		value = reindent(value, s.indent(s.offset(existing.key))-s.indent(s.offset(old.key)), false)
+	}
+	s.edits = append(s.edits, edit{start, end, value})
+	return s.remove(s.collapse(old, newSegs))
+}
+
+// move moves old to newSegs in another group. When old, or the group it
+// leaves empty, is in the group that receives the token, the token takes
+// its place; otherwise it is added after the last entry of that group.
+func (s *source) move(old *entry, newSegs []string) error {
+	groups := newSegs[:len(newSegs)-1]
+	if len(groups) >= len(old.path) && slices.Equal(groups[:len(old.path)], old.path) {
+		return fmt.Errorf("cannot move %s into itself", strings.Join(old.path, "."))
+	}
+
+	// Find the deepest group of the new path that exists
+	target := old.root()
+	depth := 0
+	for _, seg := range groups {
+		e := lookup(target, seg)
+		if e == nil {
+			break
+		}
+		if e.value.Kind != yaml.MappingNode || isToken(e.value) {
+			return fmt.Errorf("cannot move a token under %s, which is not a group", strings.Join(newSegs[:depth+1], "."))
+		}
+		target = e.value
+		depth++
+	}
+	missing := groups[depth:]
+	name := newSegs[len(newSegs)-1]
+	if lookup(target, name) != nil {
+		return fmt.Errorf("%s already exists", strings.Join(newSegs, "."))
+	}
+
+	removed := s.collapse(old, newSegs)
+	if removed.parent == target {
+		start, end, err := s.entrySpan(removed)
+		if err != nil {
+			return err
+		}
+		text, err := s.movedEntry(old, missing, name, s.ownLine(s.offset(removed.key)), s.indent(s.offset(removed.key)))
+		if err != nil {
+			return err
+		}
+		s.edits = append(s.edits, edit{start, end, text})
+		return nil
+	}
+
+	// The entry's text is read before its removal is recorded
+	if err := s.append(target, old, missing, name); err != nil {
+		return err
+	}
+	return s.remove(removed)
+}
+
+// append adds old, renamed to name under the missing groups, after the
+// last entry of target.
+func (s *source) append(target *yaml.Node, old *entry, missing []string, name string) error {
+	if !s.json && target.Style&yaml.FlowStyle != 0 {
+		return fmt.Errorf("cannot move a token into the group at line %d, which is written in flow style", target.Line)
+	}
+	if len(target.Content) == 0 {
+		// Only JSON gets here: a YAML group with no entries is {}. The
+		// token goes on its own lines if it was on its own line before.
+		open := s.offset(target)
+		multiline := s.ownLine(s.offset(old.key))
+		outer := s.indent(open)
+		text, err := s.movedEntry(old, missing, name, multiline, outer+s.indentUnit())
+		if err != nil {
+			return err
+		}
+		if multiline {
+			text = "\n" + text + "\n" + strings.Repeat(" ", outer)
+		}
+		s.edits = append(s.edits, edit{open + 1, skipSpace(s.clean, open+1), text})
+		return nil
+	}
+
+	last := &entry{key: target.Content[len(target.Content)-2], value: target.Content[len(target.Content)-1], parent: target}
+	multiline := s.ownLine(s.offset(last.key))
+	text, err := s.movedEntry(old, missing, name, multiline, s.indent(s.offset(last.key)))
+	if err != nil {
+		return err
+	}
+
+	var at int
+	if s.json {
+		at = s.jsonValueEnd(s.offset(last.value))
+		if multiline {
+			text = ",\n" + text
+		} else {
+			text = ", " + text
+		}
+	} else {
+		if !multiline {
+			return fmt.Errorf("cannot move a token into the group at line %d, which is written in flow style", target.Line)
+		}
+		at = s.yamlEntryEnd(s.offset(last.key))
+		if at == len(s.data) && !bytes.HasSuffix(s.data, []byte("\n")) {
+			text = "\n" + text
+		}
+	}
+	s.edits = append(s.edits, edit{at, at, text})
+	return nil
+}
+
+// movedEntry returns the text of old renamed to name and nested in the
+// missing groups, laid out on lines indented by indent when multiline
+// and inline otherwise. Multiline YAML text ends with a newline.
+func (s *source) movedEntry(old *entry, missing []string, name string, multiline bool, indent int) (string, error) {
+	start, end, err := s.entrySpan(old)
+	if err != nil {
+		return "", err
+	}
+	keyStart, keyEnd, err := s.scalarSpan(old.key)
+	if err != nil {
+		return "", err
+	}
+	text := s.render(start, end, []edit{{keyStart, keyEnd, encodeScalar(old.key.Style, name, true)}})
+
+	unit := s.indentUnit()
+	inner := indent + len(missing)*unit
+	oldOwnLine := s.ownLine(s.offset(old.key))
+	switch {
+	case multiline && oldOwnLine:
+		text = reindent(text, inner-s.indent(s.offset(old.key)), true)
+	case multiline:
+		text = strings.Repeat(" ", inner) + text
+	default:
+		text = strings.TrimLeft(text, " \t")
+	}
+
+	var sb strings.Builder
+	for i, group := range missing {
+		if multiline {
+			sb.WriteString(strings.Repeat(" ", indent+i*unit))
+		}
+		if !s.json {
+			fmt.Fprintf(&sb, "%s:\n", encodeScalar(0, group, true))
+			continue
+		}
+		sb.WriteString(encodeScalar(yaml.DoubleQuotedStyle, group, true) + ": {")
+		if multiline {
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString(text)
+	if s.json {
+		for i := len(missing) - 1; i >= 0; i-- {
+			if multiline {
+				sb.WriteString("\n" + strings.Repeat(" ", indent+i*unit))
+			}
+			sb.WriteString("}")
+		}
+	} else if !strings.HasSuffix(text, "\n") {
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// root returns the root mapping that holds e.
+func (e *entry) root() *yaml.Node {
+	if len(e.parents) > 0 {
+		return e.parents[0].parent
+	}
+	return e.parent
+}
+
+// collapse returns the entry to remove when old moves to newSegs: old,
+// or the outermost group that holds nothing but old, short of the groups
+// the token moves into.
+func (s *source) collapse(old *entry, newSegs []string) *entry {
+	e := old
+	for i := len(old.parents) - 1; i >= 0; i-- {
+		group := old.parents[i]
+		if len(e.parent.Content) != 2 || isPrefix(group.path, newSegs) {
+			break
+		}
+		e = group
+	}
+	return e
+}
+
+// isPrefix reports whether prefix is a leading part of path.
+func isPrefix(prefix, path []string) bool {
+	return len(prefix) <= len(path) && slices.Equal(prefix, path[:len(prefix)])
+}
+
+// remove removes an entry and the separator that goes with it.
+func (s *source) remove(e *entry) error {
+	keyOff := s.offset(e.key)
+	if !s.json {
+		start, end, err := s.entrySpan(e)
+		if err != nil {
+			return err
+		}
+		s.edits = append(s.edits, edit{start, end, ""})
+		return nil
+	}
+
+	end := s.jsonValueEnd(s.offset(e.value))
+	after := skipSpace(s.clean, end)
+	if after < len(s.clean) && s.clean[after] == ',' {
+		rest := after + 1
+		for rest < len(s.clean) && (s.clean[rest] == ' ' || s.clean[rest] == '\t' || s.clean[rest] == '\r') {
+			rest++
+		}
+		if s.ownLine(keyOff) && (rest == len(s.clean) || s.clean[rest] == '\n') {
+			s.edits = append(s.edits, edit{s.commentStart(keyOff), s.lineEnd(after), ""})
+		} else {
+			s.edits = append(s.edits, edit{keyOff, rest, ""})
+		}
+		return nil
+	}
+
+	before := keyOff - 1
+	for before >= 0 && isSpace(s.clean[before]) {
+		before--
+	}
+	if before >= 0 && s.clean[before] == ',' {
+		s.edits = append(s.edits, edit{before, end, ""})
+		return nil
+	}
+	// The only entry: leave an empty {}
+	s.edits = append(s.edits, edit{before + 1, after, ""})
+	return nil
+}
+
+// entrySpan returns the extent of an entry, from the comments above its
+// key, when it starts a line, to the end of its value. YAML entries must
+// start a line, and include the rest of their last line.
+func (s *source) entrySpan(e *entry) (int, int, error) {
+	keyOff := s.offset(e.key)
+	if s.json {
+		start := keyOff
+		if s.ownLine(keyOff) {
+			start = s.commentStart(keyOff)
+		}
+		return start, s.jsonValueEnd(s.offset(e.value)), nil
+	}
+	if !s.ownLine(keyOff) {
+		return 0, 0, fmt.Errorf("cannot edit the %s entry at line %d, which is written in flow style", e.key.Value, e.key.Line)
+	}
+	return s.commentStart(keyOff), s.yamlEntryEnd(keyOff), nil
+}
+
+// valueSpan returns the extent of an entry's value. In YAML it runs from
+// just after the colon to the end of the entry.
+func (s *source) valueSpan(e *entry) (int, int, error) {
+	if s.json {
+		start := s.offset(e.value)
+		return start, s.jsonValueEnd(start), nil
+	}
+	_, end, err := s.entrySpan(e)
+	if err != nil {
+		return 0, 0, err
+	}
+	_, keyEnd, err := s.scalarSpan(e.key)
+	if err != nil {
+		return 0, 0, err
+	}
+	colon := keyEnd
+	for colon < len(s.data) && s.data[colon] == ' ' {
+		colon++
+	}
+	if colon == len(s.data) || s.data[colon] != ':' {
+		return 0, 0, fmt.Errorf("cannot edit the %s entry at line %d", e.key.Value, e.key.Line)
+	}
+	return colon + 1, end, nil
+}
+
+// offset returns the byte offset of a node. yaml.v3 counts columns in
+// characters.
+func (s *source) offset(node *yaml.Node) int {
+	off := s.lines[node.Line-1]
+	for range node.Column - 1 {
+		_, size := utf8.DecodeRune(s.data[off:])
+		off += size
+	}
+	return off
+}
+
+// lineStart returns the offset of the start of the line holding off.
+func (s *source) lineStart(off int) int {
+	return bytes.LastIndexByte(s.data[:off], '\n') + 1
+}
+
+// lineEnd returns the offset just past the end of the line holding off,
+// including its newline.
+func (s *source) lineEnd(off int) int {
+	if i := bytes.IndexByte(s.data[off:], '\n'); i >= 0 {
+		return off + i + 1
+	}
+	return len(s.data)
+}
+
+// ownLine reports whether off is the first thing on its line, comments
+// aside.
+func (s *source) ownLine(off int) bool {
+	return len(bytes.Trim(s.clean[s.lineStart(off):off], " \t")) == 0
+}
+
+// indent returns the indentation of the line holding off.
+func (s *source) indent(off int) int {
+	line := s.data[s.lineStart(off):off]
+	return len(line) - len(bytes.TrimLeft(line, " \t"))
+}
+
+// indentUnit returns the file's indentation step: how much deeper the
+// first nested key is than its group's key, or 2.
+func (s *source) indentUnit() int {
+	var find func(mapping *yaml.Node) int
+	find = func(mapping *yaml.Node) int {
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			key, value := mapping.Content[i], mapping.Content[i+1]
+			if value.Kind != yaml.MappingNode || len(value.Content) == 0 {
+				continue
+			}
+			child := value.Content[0]
+			if child.Line > key.Line && s.ownLine(s.offset(key)) && s.ownLine(s.offset(child)) && child.Column > key.Column {
+				return child.Column - key.Column
+			}
+			if unit := find(value); unit > 0 {
+				return unit
+			}
+		}
+		return 0
+	}
+	for _, root := range s.roots {
+		if unit := find(root); unit > 0 {
+			return unit
+		}
+	}
+	return 2
+}
+
+// commentStart returns the start of the line holding off, moved up over
+// the comment lines directly above it at the same indentation.
+func (s *source) commentStart(off int) int {
+	start := s.lineStart(off)
+	indent := off - start
+	for start > 0 {
+		prev := s.lineStart(start - 1)
+		line := bytes.TrimRight(s.data[prev:start], "\r\n")
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(line)-len(trimmed) != indent || len(trimmed) == 0 {
+			break
+		}
+		if s.json {
+			// A comment line is blank once comments are removed
+			if len(bytes.TrimSpace(s.clean[prev:start])) > 0 {
+				break
+			}
+		} else if trimmed[0] != '#' {
+			break
+		}
+		start = prev
+	}
+	return start
+}
+
+// yamlEntryEnd returns the offset just past the last line of the block
+// mapping entry whose key is at off: the lines below it that are
+// indented deeper, leaving out blank lines at the end.
+func (s *source) yamlEntryEnd(off int) int {
+	indent := off - s.lineStart(off)
+	end := s.lineEnd(off)
+	for next := end; next < len(s.data); {
+		lineEnd := s.lineEnd(next)
+		line := bytes.TrimRight(s.data[next:lineEnd], "\r\n")
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(trimmed) > 0 {
+			if len(line)-len(trimmed) <= indent {
+				break
+			}
+			end = lineEnd
+		}
+		next = lineEnd
+	}
+	return end
+}
+
+// jsonValueEnd returns the offset just past the JSON value at off.
+func (s *source) jsonValueEnd(off int) int {
+	depth := 0
+	for i := off; i < len(s.clean); i++ {
+		switch s.clean[i] {
+		case '"':
+			for i++; i < len(s.clean) && s.clean[i] != '"'; i++ {
+				if s.clean[i] == '\\' {
+					i++
+				}
+			}
+			if depth == 0 {
+				return i + 1
+			}
+		case '{', '[':
+			depth++
+		case '}', ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case ',', ' ', '\t', '\r', '\n':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s.clean)
+}
+
+// scalarSpan returns the extent of a scalar in the file, checking that
+// it reads back as the node's value.
+func (s *source) scalarSpan(node *yaml.Node) (int, int, error) {
+	start := s.offset(node)
+	end := start
+	var read string
+	switch {
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		for end = start + 1; end < len(s.data) && s.data[end] != '"'; end++ {
+			if s.data[end] == '\\' {
+				end++
+			}
+		}
+		end++
+		if end <= len(s.data) {
+			_ = json.Unmarshal(s.data[start:end], &read)
+		}
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		for end = start + 1; end < len(s.data); end++ {
+			if s.data[end] == '\'' {
+				if end+1 < len(s.data) && s.data[end+1] == '\'' {
+					end++
+					continue
+				}
+				break
+			}
+		}
+		end++
+		if end <= len(s.data) {
+			read = strings.ReplaceAll(string(s.data[start+1:end-1]), "''", "'")
+		}
+	case node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0:
+		end = start + len(node.Value)
+		if end <= len(s.data) {
+			read = string(s.data[start:end])
+		}
+	}
+	if end > len(s.data) || read != node.Value {
+		return 0, 0, fmt.Errorf("cannot rewrite the value at line %d, column %d", node.Line, node.Column)
+	}
+	return start, end, nil
+}
+
+// plainKeyPattern matches keys that YAML reads as strings without quotes.
+var plainKeyPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$-]*$`)
+
+// encodeScalar writes value as a scalar in style, falling back to double
+// quotes when a plain scalar would read differently.
+func encodeScalar(style yaml.Style, value string, key bool) string {
+	switch {
+	case style&yaml.DoubleQuotedStyle != 0:
+		return quote(value)
+	case style&yaml.SingleQuotedStyle != 0:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case key && !plainKeyPattern.MatchString(value):
+		return quote(value)
+	case !key && (value == "" || strings.ContainsAny(value[:1], "{}[]&*!|>'\"%@`#,?:-") ||
+		strings.Contains(value, ": ") || strings.Contains(value, " #")):
+		return quote(value)
+	}
+	return value
+}
+
+// quote returns value as a JSON string, which is also a YAML
+// double-quoted scalar.
+func quote(value string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(value)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// reindent shifts the lines of text right by delta spaces, or left by
+// up to -delta leading spaces. The first line is shifted only with first,
+// when text starts at the beginning of a line.
+func reindent(text string, delta int, first bool) string {
+	if delta == 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if (i == 0 && !first) || line == "" {
+			continue
+		}
+		if delta > 0 {
+			lines[i] = strings.Repeat(" ", delta) + line
+		} else {
+			trimmed := strings.TrimLeft(line, " ")
+			lines[i] = line[min(-delta, len(line)-len(trimmed)):]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// render returns data[start:end] with the edits inside it, and extra,
+// applied.
+func (s *source) render(start, end int, extra []edit) string {
+	var inside []edit
+	for _, e := range append(slices.Clone(s.edits), extra...) {
+		if e.start >= start && e.end <= end {
+			inside = append(inside, edit{e.start - start, e.end - start, e.text})
+		}
+	}
+	out, _ := applyEdits(s.data[start:end], inside)
+	return string(out)
+}
+
+// apply returns the file with its edits applied.
+func (s *source) apply() ([]byte, error) {
+	return applyEdits(s.data, s.edits)
+}
+
+// applyEdits applies edits to data. Edits inside a larger edit, like a
+// reference in a removed entry, are dropped; other overlaps are an error.
+func applyEdits(data []byte, edits []edit) ([]byte, error) {
+	edits = slices.Clone(edits)
+	slices.SortStableFunc(edits, func(a, b edit) int {
+		if a.start != b.start {
+			return cmp.Compare(a.start, b.start)
+		}
+		// Insertions first, then larger edits before those inside them
+		if aInsert, bInsert := a.start == a.end, b.start == b.end; aInsert != bInsert {
+			if aInsert {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(b.end, a.end)
+	})
+
+	var out bytes.Buffer
+	pos := 0
+	last := edit{start: -1, end: -1}
+	for _, e := range edits {
+		if last.end > last.start && e.start >= last.start && e.end <= last.end && e.start < last.end {
+			continue
+		}
+		if e.start < pos {
+			return nil, fmt.Errorf("conflicting edits at offset %d", e.start)
+		}
+		out.Write(data[pos:e.start])
+		out.WriteString(e.text)
+		pos = e.end
+		last = e
+	}
+	out.Write(data[pos:])
+	return out.Bytes(), nil
+}
+
+// skipSpace returns the offset of the first non-space byte at or after i.
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && isSpace(data[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
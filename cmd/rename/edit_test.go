@@ -0,0 +1,102 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package rename
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEditFile(t *testing.T) {
+	tests := []struct {
+		name              string
+		path              string
+		in                string
+		oldPath, newPath  string
+		defines, replaces bool
+		want              string
+	}{
+		{
+			name:    "json key",
+			path:    "tokens.json",
+			in:      "{\n  \"color\": {\n    \"$type\": \"color\",\n    \"a\": { \"$value\": \"#fff\" },\n    \"b\": { \"$value\": \"{color.a}\" }\n  }\n}\n",
+			oldPath: "color.a", newPath: "color.c", defines: true,
+			want: "{\n  \"color\": {\n    \"$type\": \"color\",\n    \"c\": { \"$value\": \"#fff\" },\n    \"b\": { \"$value\": \"{color.c}\" }\n  }\n}\n",
+		},
+		{
+			name:    "references only",
+			path:    "aliases.json",
+			in:      `{"button": {"bg": {"$value": "{color.a}"}, "$ref": "#/color/a"}}`,
+			oldPath: "color.a", newPath: "color.c",
+			want: `{"button": {"bg": {"$value": "{color.c}"}, "$ref": "#/color/c"}}`,
+		},
+		{
+			name:    "json move into new group",
+			path:    "tokens.json",
+			in:      "{\n  \"color\": {\n    \"a\": { \"$value\": \"#fff\" },\n    \"b\": { \"$value\": \"#000\" }\n  }\n}\n",
+			oldPath: "color.a", newPath: "palette.white", defines: true,
+			want: "{\n  \"color\": {\n    \"b\": { \"$value\": \"#000\" }\n  },\n  \"palette\": {\n    \"white\": { \"$value\": \"#fff\" }\n  }\n}\n",
+		},
+		{
+			name:    "json move collapses emptied group",
+			path:    "tokens.json",
+			in:      `{"color": {"a": {"$value": "#fff"}}, "palette": {"b": {"$value": "#000"}}}`,
+			oldPath: "color.a", newPath: "palette.a", defines: true,
+			want: `{"palette": {"b": {"$value": "#000"}, "a": {"$value": "#fff"}}}`,
+		},
+		{
+			name:    "json force",
+			path:    "tokens.json",
+			in:      `{"color": {"a": {"$value": "#fff"}, "b": {"$value": "#000"}}}`,
+			oldPath: "color.a", newPath: "color.b", defines: true, replaces: true,
+			want: `{"color": {"b": {"$value": "#fff"}}}`,
+		},
+		{
+			name:    "json remove replaced token",
+			path:    "other.json",
+			in:      `{"color": {"b": {"$value": "#000"}, "c": {"$value": "{color.a}"}}}`,
+			oldPath: "color.a", newPath: "color.b", replaces: true,
+			want: `{"color": {"c": {"$value": "{color.b}"}}}`,
+		},
+		{
+			name:    "yaml keeps comments and quoting",
+			path:    "tokens.yaml",
+			in:      "# Colors\ncolor:\n  $type: color\n  a:\n    $value: '#fff' # white\n  b:\n    $value: \"{color.a}\"\n    $deprecated:\n      replacement: color.a\n",
+			oldPath: "color.a", newPath: "color.c", defines: true,
+			want: "# Colors\ncolor:\n  $type: color\n  c:\n    $value: '#fff' # white\n  b:\n    $value: \"{color.c}\"\n    $deprecated:\n      replacement: color.c\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := editFile([]byte(tt.in), tt.path, tt.oldPath, tt.newPath, tt.defines, tt.replaces)
+			if err != nil {
+				t.Fatalf("editFile() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("editFile() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditFile_Errors(t *testing.T) {
+	tests := []struct {
+		name, path, in, newPath, want string
+	}{
+		{"into itself", "tokens.json", `{"color": {"a": {"$value": "#fff"}}}`, "color.a.b", "into itself"},
+		{"under a token", "tokens.json", `{"color": {"a": {"$value": "#fff"}}, "b": {"$value": "#000"}}`, "b.a", "not a group"},
+		{"flow style yaml", "tokens.yaml", "color:\n  a:\n    $value: '#fff'\npalette: {b: {$value: '#000'}}\n", "palette.a", "flow style"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := editFile([]byte(tt.in), tt.path, "color.a", tt.newPath, true, false)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("editFile() error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package rename provides the rename command for asimonim.
+package rename
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bennypowers.dev/asimonim/config"
+	"bennypowers.dev/asimonim/fs"
+	"bennypowers.dev/asimonim/internal/logger"
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/specifier"
+	"bennypowers.dev/asimonim/token"
+)
+
+// NewCmd creates a fresh rename command with its own flags.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename <old-path> <new-path> [files...]",
+		Short: "Rename a token and update references to it",
+		Long: `Rename a token and rewrite every reference to it across the token files.
+
+Paths are dot paths, e.g. color.brand.primary. Changed files are written to
+stdout, or back in place with --in-place. Only the renamed token and the
+references to it are edited; the rest of each file is kept as written, and
+files that don't change are left alone.
+
+Examples:
+  # Preview which files would change
+  asimonim rename color.old color.new tokens/*.yaml --dry-run
+
+  # Rename and rewrite the files
+  asimonim rename color.old color.new tokens/*.yaml --in-place
+
+  # Replace an existing color.new
+  asimonim rename color.old color.new tokens/*.yaml --in-place --force`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: run,
+	}
+	cmd.Flags().BoolP("in-place", "i", false, "Overwrite the token files instead of writing them to stdout")
+	cmd.Flags().Bool("dry-run", false, "List the files that would change without writing anything")
+	cmd.Flags().Bool("force", false, "Replace the token at the new path if it already exists")
+	return cmd
+}
+
+// tokenFile is a token file loaded for renaming.
+type tokenFile struct {
+	rf      *specifier.ResolvedFile
+	data    []byte
+	version schema.Version
+	tokens  []*token.Token
+	// changed counts the file's tokens that were renamed, removed, or
+	// had references rewritten.
+	changed int
+	// defines and replaces report whether the file holds the token being
+	// renamed and the token it replaces.
+	defines, replaces bool
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	inPlace, _ := cmd.Flags().GetBool("in-place")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	oldPath := trimBraces(args[0])
+	newPath := trimBraces(args[1])
+	if oldPath == newPath {
+		return fmt.Errorf("old and new paths are the same: %s", oldPath)
+	}
+
+	filesystem := fs.NewOSFileSystem()
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	specResolver, err := specifier.NewDefaultResolver(filesystem, cwd)
+	if err != nil {
+		return fmt.Errorf("failed to create resolver: %w", err)
+	}
+
+	// Load config from .config/design-tokens.{yaml,json}
+	cfg := config.LoadOrDefault(filesystem, ".")
+
+	// Use config files if no files are given
+	var resolvedFiles []*specifier.ResolvedFile
+	if len(args) == 2 {
+		resolvedFiles, err = cfg.ResolveFiles(specResolver, filesystem, ".")
+		if err != nil {
+			return fmt.Errorf("error resolving config files: %w", err)
+		}
+	} else {
+		for _, arg := range args[2:] {
+			rf, err := specResolver.Resolve(arg)
+			if err != nil {
+				return fmt.Errorf("error resolving %s: %w", arg, err)
+			}
+			resolvedFiles = append(resolvedFiles, rf)
+		}
+	}
+	if len(resolvedFiles) == 0 {
+		return fmt.Errorf("no files specified and no files found in config")
+	}
+
+	// Load every file before changing any, so a failure leaves them all alone
+	files, err := loadFiles(filesystem, cfg, resolvedFiles)
+	if err != nil {
+		return err
+	}
+
+	if err := rename(files, oldPath, newPath, force); err != nil {
+		return err
+	}
+
+	var outputs [][]byte
+	var changedFiles []*tokenFile
+	for _, f := range files {
+		if f.changed == 0 {
+			continue
+		}
+		out, err := editFile(f.data, f.rf.Path, oldPath, newPath, f.defines, f.replaces)
+		if err != nil {
+			return fmt.Errorf("error rewriting %s: %w", f.rf.Specifier, err)
+		}
+		outputs = append(outputs, out)
+		changedFiles = append(changedFiles, f)
+	}
+
+	switch {
+	case dryRun:
+		fmt.Printf("Would rename %s to %s:\n", oldPath, newPath)
+		for _, f := range changedFiles {
+			fmt.Printf("  %s (%d token(s))\n", f.rf.Specifier, f.changed)
+		}
+		return nil
+	case inPlace:
+		for i, f := range changedFiles {
+			if err := filesystem.WriteFile(f.rf.Path, outputs[i], 0644); err != nil {
+				return fmt.Errorf("error writing %s: %w", f.rf.Specifier, err)
+			}
+			logger.Info("Wrote %s", f.rf.Specifier)
+		}
+	default:
+		for i, f := range changedFiles {
+			// Name each file when there are several, like head(1)
+			if len(changedFiles) > 1 {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("==> %s <==\n", f.rf.Specifier)
+			}
+			os.Stdout.Write(outputs[i])
+			if !bytes.HasSuffix(outputs[i], []byte("\n")) {
+				fmt.Println()
+			}
+		}
+	}
+
+	logger.Info("Renamed %s to %s in %d file(s)", oldPath, newPath, len(changedFiles))
+	return nil
+}
+
+// trimBraces strips the braces from a {token.path} reference.
+func trimBraces(path string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+}
+
+// loadFiles reads and parses each file at its detected schema version.
+func loadFiles(filesystem fs.FileSystem, cfg *config.Config, resolvedFiles []*specifier.ResolvedFile) ([]*tokenFile, error) {
+	jsonParser := parser.NewJSONParser()
+	files := make([]*tokenFile, 0, len(resolvedFiles))
+	for _, rf := range resolvedFiles {
+		data, err := filesystem.ReadFile(rf.Path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", rf.Specifier, err)
+		}
+		version, err := schema.DetectVersion(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error detecting schema for %s: %w", rf.Specifier, err)
+		}
+		opts := cfg.OptionsForFile(rf.Specifier)
+		opts.SkipPositions = true
+		opts.SchemaVersion = version
+		tokens, err := jsonParser.ParseFile(filesystem, rf.Path, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", rf.Specifier, err)
+		}
+		files = append(files, &tokenFile{rf: rf, data: data, version: version, tokens: tokens})
+	}
+	return files, nil
+}
+
+// rename renames oldPath to newPath across files, recording how many of
+// each file's tokens changed. The token at newPath, if any, is removed
+// with force and is an error otherwise.
+func rename(files []*tokenFile, oldPath, newPath string, force bool) error {
+	found := false
+	for _, f := range files {
+		for _, tok := range f.tokens {
+			switch tok.DotPath() {
+			case oldPath:
+				found = true
+			case newPath:
+				if !force {
+					return fmt.Errorf("%s already exists in %s; use --force to replace it", newPath, f.rf.Specifier)
+				}
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("no token at %s", oldPath)
+	}
+
+	for _, f := range files {
+		kept := f.tokens[:0]
+		for _, tok := range f.tokens {
+			switch tok.DotPath() {
+			case newPath:
+				f.changed++
+				f.replaces = true
+				continue
+			case oldPath:
+				f.defines = true
+			}
+			kept = append(kept, tok)
+		}
+		f.tokens = kept
+
+		count, err := token.Rename(f.tokens, oldPath, newPath)
+		if err != nil {
+			return err
+		}
+		f.changed += count
+	}
+	return nil
+}
@@ -10,15 +10,22 @@ package render
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 	"unicode"
 
 	"github.com/mazznoer/csscolorparser"
+	"golang.org/x/term"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
 
+	"bennypowers.dev/asimonim/parser/common"
+	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/token"
 )
 
@@ -27,12 +34,17 @@ type Row struct {
 	Name               string   // CSS variable name with prefix
 	Type               string   // Token type or "-"
 	Value              string   // Display value (resolved if applicable)
+	CSSValue           string   // CSS value, empty if the token has no CSS representation
 	Description        string   // Token description
 	RefChain           []string // Resolution chain as CSS variable names
 	IsColor            bool     // Whether this is a color token with parseable value
+	Swatch             string   // sRGB hex for the color swatch when Value is not one csscolorparser reads
 	Deprecated         bool     // Whether this token is deprecated
 	DeprecationMessage string   // Optional message explaining deprecation
+	Replacement        string   // CSS variable name of the replacement for a deprecated token
 	Path               []string // Token path in the hierarchy (e.g., ["color", "brand", "primary"])
+	Matched            []string // Fields a search query matched (name, value, type, description)
+	Source             string   // Base name of the file the token was loaded from
 }
 
 // GroupMeta holds metadata extracted from group definitions.
@@ -50,33 +62,160 @@ type HierarchyNode struct {
 	Children map[string]*HierarchyNode
 }
 
+// Style controls terminal decorations in table and tree output.
+type Style struct {
+	NoColor   bool           // omit ANSI color swatches
+	ASCII     bool           // use ASCII arrows and tree branches instead of Unicode
+	Highlight *regexp.Regexp // mark matches in names, types, and values; needs color
+	Source    bool           // add a column with each token's source file
+}
+
+// DetectStyle returns the style for output written to f.
+// Color is also disabled when f is not a terminal or NO_COLOR is set
+// (see https://no-color.org), and ASCII output never has color.
+func DetectStyle(f *os.File, noColor, ascii bool) Style {
+	if os.Getenv("NO_COLOR") != "" || !term.IsTerminal(int(f.Fd())) {
+		noColor = true
+	}
+	return Style{NoColor: noColor || ascii, ASCII: ascii}
+}
+
+// highlight marks matches of s.Highlight in text in reverse video.
+func (s Style) highlight(text string) string {
+	if s.Highlight == nil || s.NoColor {
+		return text
+	}
+	return s.Highlight.ReplaceAllStringFunc(text, func(m string) string {
+		if m == "" {
+			return m
+		}
+		return "\x1b[7m" + m + "\x1b[0m"
+	})
+}
+
+// arrow returns the separator for resolution chains.
+func (s Style) arrow() string {
+	if s.ASCII {
+		return " -> "
+	}
+	return " → "
+}
+
 // MarkdownOptions configures markdown output.
 type MarkdownOptions struct {
 	GroupMeta  map[string]GroupMeta // key: dot-separated path
 	IncludeTOC bool
 	TOCDepth   int
 	ShowLinks  bool
+	LinkBase   string // prefix for token links (e.g., "tokens/colors#"); empty links within the page
+
+	// ColorSwatches draws an inline HTML swatch before color values,
+	// for markdown that allows inline styles (e.g. MkDocs, Hugo).
+	ColorSwatches bool
+
+	// GroupBy selects the sections tokens are grouped into. The default,
+	// GroupByHierarchy, nests sections by token path.
+	GroupBy GroupBy
+
+	// ShowSource adds a column with the file each token was loaded from.
+	ShowSource bool
+
+	// Frontmatter is written as a YAML frontmatter block at the top of
+	// the page, for static site generators such as Hugo or MkDocs.
+	Frontmatter map[string]any
 }
 
+// ParseFrontmatter parses --frontmatter flag values of the form
+// key=value. Values are strings; a repeated key keeps the last value.
+func ParseFrontmatter(pairs []string) (map[string]any, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	frontmatter := make(map[string]any, len(pairs))
+	for _, pair := range pairs {
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid frontmatter %q: expected key=value", pair)
+		}
+		frontmatter[key] = value
+	}
+	return frontmatter, nil
+}
+
+// writeFrontmatter prints frontmatter as a YAML block between --- lines.
+func writeFrontmatter(frontmatter map[string]any) error {
+	data, err := yaml.Marshal(frontmatter)
+	if err != nil {
+		return fmt.Errorf("failed to write frontmatter: %w", err)
+	}
+	fmt.Printf("---\n%s---\n\n", data)
+	return nil
+}
+
+// GroupBy selects how MarkdownWithOptions groups tokens into sections.
+type GroupBy string
+
+const (
+	GroupByHierarchy GroupBy = "hierarchy" // nested sections by token path
+	GroupByType      GroupBy = "type"      // one flat section per $type
+)
+
+// ParseGroupBy parses a --group-by flag value. An empty string selects
+// GroupByHierarchy.
+func ParseGroupBy(s string) (GroupBy, error) {
+	switch groupBy := GroupBy(s); groupBy {
+	case "":
+		return GroupByHierarchy, nil
+	case GroupByHierarchy, GroupByType:
+		return groupBy, nil
+	default:
+		return "", fmt.Errorf("invalid group-by %q: expected hierarchy or type", s)
+	}
+}
+
+// PlaceholderMarker is the value shown for placeholder tokens, which
+// have no value of their own.
+const PlaceholderMarker = "∅"
+
 // ComputeRows transforms tokens into display rows with all values computed.
-func ComputeRows(tokens []*token.Token, resolved bool) []Row {
+// With raw, references in values are shown as {color.brand} rather than
+// as CSS variable names, for tokens whose aliases haven't been resolved.
+// $ref JSON pointers are shown in the same form.
+func ComputeRows(tokens []*token.Token, raw bool) []Row {
 	rows := make([]Row, 0, len(tokens))
 	for _, tok := range tokens {
 		// Use DisplayValue() for type-aware formatting, then apply reference conversion
 		displayVal := tok.DisplayValue()
+		if !raw {
+			displayVal = convertReferences(displayVal, tok.Prefix)
+		} else if path, ok := token.ParseJSONPointerRef(displayVal); ok {
+			displayVal = "{" + path + "}"
+		}
 		row := Row{
 			Name:               tok.CSSVariableName(),
 			Type:               tok.Type,
-			Value:              convertReferences(displayVal, tok.Prefix),
+			Value:              displayVal,
 			Description:        tok.Description,
 			Deprecated:         tok.Deprecated,
 			DeprecationMessage: tok.DeprecationMessage,
 			Path:               tok.Path,
 		}
+		if tok.FilePath != "" {
+			row.Source = filepath.Base(tok.FilePath)
+		}
+		row.CSSValue, _ = tok.CSSValue()
+		if tok.IsPlaceholder() {
+			row.Value = PlaceholderMarker
+		}
 		if row.Type == "" {
 			row.Type = "-"
 		}
 
+		if tok.Replacement != "" {
+			row.Replacement = NameToCSSVar(strings.ReplaceAll(tok.Replacement, ".", "-"), tok.Prefix)
+		}
+
 		// Handle alias resolution chain display
 		if len(tok.ResolutionChain) > 0 {
 			row.RefChain = make([]string, len(tok.ResolutionChain))
@@ -86,7 +225,10 @@ func ComputeRows(tokens []*token.Token, resolved bool) []Row {
 		}
 
 		// Check if this is a parseable color
-		if tok.Type == "color" && !strings.HasPrefix(row.Value, "{") && !strings.HasPrefix(row.Value, "--") {
+		if hex, ok := structuredSwatch(tok); ok {
+			row.IsColor = true
+			row.Swatch = hex
+		} else if tok.Type == token.TypeColor && !strings.HasPrefix(row.Value, "{") && !strings.HasPrefix(row.Value, "--") {
 			if _, err := csscolorparser.Parse(row.Value); err == nil {
 				row.IsColor = true
 			}
@@ -97,6 +239,39 @@ func ComputeRows(tokens []*token.Token, resolved bool) []Row {
 	return rows
 }
 
+// structuredSwatch returns an sRGB hex approximating a structured color
+// token, so wide-gamut colors like color(display-p3 ...) still get a swatch.
+func structuredSwatch(tok *token.Token) (string, bool) {
+	if !tok.IsStructuredColor() {
+		return "", false
+	}
+	val := tok.RawValue
+	if tok.IsResolved && tok.ResolvedValue != nil {
+		val = tok.ResolvedValue
+	}
+	cv, err := common.ParseColorValue(val, schema.V2025_10)
+	if err != nil {
+		return "", false
+	}
+	obj, ok := cv.(*common.ObjectColorValue)
+	if !ok {
+		return "", false
+	}
+	r, g, b, ok := obj.SRGB()
+	if !ok {
+		return "", false
+	}
+	return csscolorparser.Color{R: r, G: g, B: b, A: 1}.HexString(), true
+}
+
+// swatchValue returns the color to draw the row's swatch with.
+func (r Row) swatchValue() string {
+	if r.Swatch != "" {
+		return r.Swatch
+	}
+	return r.Value
+}
+
 // convertReferences converts {ref.path} references to CSS variable names.
 func convertReferences(s, prefix string) string {
 	if !strings.Contains(s, "{") {
@@ -147,33 +322,58 @@ func ColorSwatch(value string) string {
 	return fmt.Sprintf("\x1b[48;2;%d;%d;%dm  \x1b[0m ", r, g, b)
 }
 
+// MarkdownSwatch returns an inline HTML square filled with the given
+// color value, or "" if the value is not a color. The color is written as
+// hex so no token text reaches the style attribute.
+func MarkdownSwatch(value string) string {
+	c, err := csscolorparser.Parse(value)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf(`<span style="display:inline-block;width:1em;height:1em;vertical-align:middle;border:1px solid #8888;background:%s"></span> `, c.HexString())
+}
+
 // Table renders rows as a table to stdout.
-func Table(rows []Row) error {
+func Table(rows []Row, style Style) error {
 	if len(rows) == 0 {
 		return nil
 	}
 	nameW, typeW, _ := ColumnWidths(rows)
+	sourceW := 0
+	if style.Source {
+		for _, r := range rows {
+			sourceW = max(sourceW, len(r.Source))
+		}
+	}
 	for _, r := range rows {
 		swatch := ""
-		if r.IsColor {
-			swatch = ColorSwatch(r.Value)
+		if r.IsColor && !style.NoColor {
+			swatch = ColorSwatch(r.swatchValue())
 		}
 		refChain := ""
 		if len(r.RefChain) > 0 {
-			refChain = " → " + strings.Join(r.RefChain, " → ")
+			refChain = style.arrow() + strings.Join(r.RefChain, style.arrow())
+		}
+		matched := ""
+		if len(r.Matched) > 0 {
+			matched = "  [" + strings.Join(r.Matched, ", ") + "]"
+		}
+		source := ""
+		if style.Source {
+			source = fmt.Sprintf("%-*s  ", sourceW, r.Source)
 		}
-		fmt.Printf("%-*s  %-*s  %s%s%s\n", nameW, r.Name, typeW, r.Type, swatch, r.Value, refChain)
+		// Pad by hand, since highlighting adds escape codes to the width
+		fmt.Printf("%s%s  %s%s  %s%s%s%s%s\n",
+			style.highlight(r.Name), strings.Repeat(" ", nameW-len(r.Name)),
+			style.highlight(r.Type), strings.Repeat(" ", typeW-len(r.Type)),
+			source, swatch, style.highlight(r.Value), refChain, matched)
 	}
 	return nil
 }
 
-// Markdown renders rows as markdown tables grouped by type.
-func Markdown(rows []Row) error {
-	if len(rows) == 0 {
-		return nil
-	}
-
-	// Group rows by type, preserving order of first occurrence
+// groupRowsByType groups rows by type, preserving the order in which
+// each type first occurs.
+func groupRowsByType(rows []Row) ([]string, map[string][]Row) {
 	typeOrder := make([]string, 0)
 	byType := make(map[string][]Row)
 	for _, r := range rows {
@@ -182,6 +382,24 @@ func Markdown(rows []Row) error {
 		}
 		byType[r.Type] = append(byType[r.Type], r)
 	}
+	return typeOrder, byType
+}
+
+// typeHeading returns the section heading for a row type.
+func typeHeading(typ string) string {
+	if typ == "-" {
+		return "untyped"
+	}
+	return typ
+}
+
+// Markdown renders rows as markdown tables grouped by type.
+func Markdown(rows []Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	typeOrder, byType := groupRowsByType(rows)
 
 	first := true
 	for _, typ := range typeOrder {
@@ -191,12 +409,7 @@ func Markdown(rows []Row) error {
 		}
 		first = false
 
-		// Heading
-		heading := typ
-		if heading == "-" {
-			heading = "untyped"
-		}
-		fmt.Printf("## %s\n\n", heading)
+		fmt.Printf("## %s\n\n", typeHeading(typ))
 
 		// Calculate column widths for this group
 		nameW, valW, refW := 4, 5, 0
@@ -239,23 +452,133 @@ func Markdown(rows []Row) error {
 	return nil
 }
 
-// CSS renders rows as CSS custom properties.
-func CSS(rows []Row) error {
-	fmt.Println(":root {")
+// CSS renders rows as CSS custom properties in a rule for selector,
+// which defaults to ":root". The selector "none" writes bare
+// declarations, e.g. for a style attribute.
+func CSS(rows []Row, selector string) error {
+	if selector == "" {
+		selector = ":root"
+	}
+	bare := selector == "none"
+	indent := "  "
+	if bare {
+		indent = ""
+	} else {
+		fmt.Printf("%s {\n", selector)
+	}
 	for _, r := range rows {
-		if strings.HasPrefix(r.Value, "{") && strings.Contains(r.Value, ":") {
+		if r.CSSValue == "" {
 			continue
 		}
-		fmt.Printf("  %s: %s;\n", r.Name, r.Value)
+		fmt.Printf("%s%s: %s;\n", indent, r.Name, r.CSSValue)
+	}
+	if !bare {
+		fmt.Println("}")
 	}
-	fmt.Println("}")
 	return nil
 }
 
-// Names renders just the token names, one per line.
-func Names(rows []Row) error {
+// NameStyle selects how Names prints each token.
+type NameStyle string
+
+const (
+	NameStyleCSS   NameStyle = "css"   // CSS variable name, e.g. --rh-color-brand-primary
+	NameStyleDot   NameStyle = "dot"   // dot path, e.g. color.brand.primary
+	NameStyleShort NameStyle = "short" // last path segment, e.g. primary
+)
+
+// ParseNameStyle parses a --name-style flag value.
+func ParseNameStyle(s string) (NameStyle, error) {
+	switch style := NameStyle(s); style {
+	case NameStyleCSS, NameStyleDot, NameStyleShort:
+		return style, nil
+	default:
+		return "", fmt.Errorf("invalid name style %q: expected css, dot, or short", s)
+	}
+}
+
+// Names renders just the token names, one per line, in the given style.
+// Rows without a path fall back to the CSS variable name.
+func Names(rows []Row, style NameStyle) error {
 	for _, r := range rows {
-		fmt.Println(r.Name)
+		name := r.Name
+		if len(r.Path) > 0 {
+			switch style {
+			case NameStyleDot:
+				name = strings.Join(r.Path, ".")
+			case NameStyleShort:
+				name = r.Path[len(r.Path)-1]
+			}
+		}
+		fmt.Println(name)
+	}
+	return nil
+}
+
+// Tree renders rows as an indented tree of groups and tokens.
+// Leaves show the token value, preceded by a swatch for colors.
+func Tree(w io.Writer, rows []Row, style Style) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return writeTreeNode(w, BuildHierarchy(rows), "", style)
+}
+
+// treeEntry is a group or token at one level of the tree.
+type treeEntry struct {
+	name  string
+	group *HierarchyNode
+	row   *Row
+}
+
+func writeTreeNode(w io.Writer, node *HierarchyNode, indent string, style Style) error {
+	entries := make([]treeEntry, 0, len(node.Children)+len(node.Tokens))
+	for name, child := range node.Children {
+		entries = append(entries, treeEntry{name: name, group: child})
+	}
+	for i := range node.Tokens {
+		r := &node.Tokens[i]
+		name := r.Name
+		if len(r.Path) > 0 {
+			name = r.Path[len(r.Path)-1]
+		}
+		entries = append(entries, treeEntry{name: name, row: r})
+	}
+	// Tokens sort before groups of the same name (e.g. a $root token)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].name != entries[j].name {
+			return entries[i].name < entries[j].name
+		}
+		return entries[i].row != nil && entries[j].row == nil
+	})
+
+	for i, e := range entries {
+		branch, next := "├─ ", "│  "
+		if i == len(entries)-1 {
+			branch, next = "└─ ", "   "
+		}
+		if style.ASCII {
+			branch, next = "|- ", "|  "
+			if i == len(entries)-1 {
+				branch, next = "`- ", "   "
+			}
+		}
+		if e.group != nil {
+			if _, err := fmt.Fprintf(w, "%s%s%s\n", indent, branch, e.name); err != nil {
+				return err
+			}
+			if err := writeTreeNode(w, e.group, indent+next, style); err != nil {
+				return err
+			}
+			continue
+		}
+		swatch := ""
+		if e.row.IsColor && !style.NoColor {
+			swatch = ColorSwatch(e.row.swatchValue())
+		}
+		if _, err := fmt.Fprintf(w, "%s%s%s: %s%s\n", indent, branch, e.name, swatch, e.row.Value); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -395,11 +718,24 @@ func generateTOCRecursive(node *HierarchyNode, depth int, maxDepth int, sb *stri
 }
 
 // MarkdownWithOptions renders rows as markdown with hierarchy grouping and options.
+// With GroupByType, rows are grouped into one section per type instead.
 func MarkdownWithOptions(rows []Row, opts MarkdownOptions) error {
+	// Frontmatter comes first even without tokens, so the page stays valid
+	if len(opts.Frontmatter) > 0 {
+		if err := writeFrontmatter(opts.Frontmatter); err != nil {
+			return err
+		}
+	}
+
 	if len(rows) == 0 {
 		return nil
 	}
 
+	if opts.GroupBy == GroupByType {
+		markdownByType(rows, opts)
+		return nil
+	}
+
 	hierarchy := BuildHierarchy(rows)
 
 	// Inject group metadata if provided
@@ -422,6 +758,28 @@ func MarkdownWithOptions(rows []Row, opts MarkdownOptions) error {
 	return nil
 }
 
+// markdownByType renders a flat section per type, in the order each
+// type first occurs, with a TOC entry per section when requested.
+func markdownByType(rows []Row, opts MarkdownOptions) {
+	typeOrder, byType := groupRowsByType(rows)
+
+	if opts.IncludeTOC {
+		fmt.Print("## Table Of Contents\n\n")
+		for _, typ := range typeOrder {
+			heading := typeHeading(typ)
+			fmt.Printf("- [%s](#%s)\n", heading, slugify(heading))
+		}
+		fmt.Println()
+	}
+
+	for _, typ := range typeOrder {
+		heading := typeHeading(typ)
+		fmt.Printf("## %s {#%s}\n\n", heading, slugify(heading))
+		renderTokenTable(byType[typ], opts)
+		fmt.Println()
+	}
+}
+
 func injectGroupMeta(node *HierarchyNode, meta map[string]GroupMeta) {
 	if len(node.Path) > 0 {
 		key := strings.Join(node.Path, ".")
@@ -482,91 +840,84 @@ func renderTokenTable(tokens []Row, opts MarkdownOptions) {
 		return
 	}
 
-	// Calculate column widths
-	nameW, valW, descW, refW := 4, 5, 11, 9 // minimums for headers
 	hasRefs := false
 	hasDesc := false
-	hasDeprecated := false
-
 	for _, r := range tokens {
-		displayName := formatTokenName(r, opts.ShowLinks)
-		if len(displayName) > nameW {
-			nameW = len(displayName)
-		}
-		if len(r.Value) > valW {
-			valW = len(r.Value)
-		}
 		if r.Description != "" || r.DeprecationMessage != "" {
 			hasDesc = true
-			desc := formatDescription(r)
-			if len(desc) > descW {
-				descW = len(desc)
-			}
 		}
 		if len(r.RefChain) > 0 {
 			hasRefs = true
-			refStr := formatRefChain(r.RefChain, opts.ShowLinks)
-			if len(refStr) > refW {
-				refW = len(refStr)
-			}
-		}
-		if r.Deprecated {
-			hasDeprecated = true
 		}
 	}
 
-	// Ensure minimum widths for headers
-	if descW < 11 {
-		descW = 11 // "Description"
+	// Build the columns this table needs; deprecation is shown inline
+	// in the name and description
+	headers := []string{"Name", "Value"}
+	if hasDesc {
+		headers = append(headers, "Description")
 	}
-	if refW < 9 {
-		refW = 9 // "Reference"
+	if hasRefs {
+		headers = append(headers, "Reference")
+	}
+	if opts.ShowSource {
+		headers = append(headers, "Source")
 	}
 
-	_ = hasDeprecated // deprecation is shown inline in name and description
-
-	// Render table header
-	if hasRefs && hasDesc {
-		fmt.Printf("| %-*s | %-*s | %-*s | %-*s |\n", nameW, "Name", valW, "Value", descW, "Description", refW, "Reference")
-		fmt.Printf("|-%s-|-%s-|-%s-|-%s-|\n",
-			strings.Repeat("-", nameW), strings.Repeat("-", valW),
-			strings.Repeat("-", descW), strings.Repeat("-", refW))
-	} else if hasRefs {
-		fmt.Printf("| %-*s | %-*s | %-*s |\n", nameW, "Name", valW, "Value", refW, "Reference")
-		fmt.Printf("|-%s-|-%s-|-%s-|\n",
-			strings.Repeat("-", nameW), strings.Repeat("-", valW), strings.Repeat("-", refW))
-	} else if hasDesc {
-		fmt.Printf("| %-*s | %-*s | %-*s |\n", nameW, "Name", valW, "Value", descW, "Description")
-		fmt.Printf("|-%s-|-%s-|-%s-|\n",
-			strings.Repeat("-", nameW), strings.Repeat("-", valW), strings.Repeat("-", descW))
-	} else {
-		fmt.Printf("| %-*s | %-*s |\n", nameW, "Name", valW, "Value")
-		fmt.Printf("|-%s-|-%s-|\n", strings.Repeat("-", nameW), strings.Repeat("-", valW))
+	cells := make([][]string, len(tokens))
+	for i, r := range tokens {
+		row := []string{
+			formatTokenName(r, opts.ShowLinks, opts.LinkBase),
+			formatValue(r, opts.ColorSwatches),
+		}
+		if hasDesc {
+			row = append(row, formatDescription(r, opts.ShowLinks, opts.LinkBase))
+		}
+		if hasRefs {
+			row = append(row, formatRefChain(r.RefChain, opts.ShowLinks, opts.LinkBase))
+		}
+		if opts.ShowSource {
+			row = append(row, r.Source)
+		}
+		cells[i] = row
 	}
 
-	// Render rows
-	for _, r := range tokens {
-		displayName := formatTokenName(r, opts.ShowLinks)
-		desc := formatDescription(r)
-		refStr := formatRefChain(r.RefChain, opts.ShowLinks)
-
-		if hasRefs && hasDesc {
-			fmt.Printf("| %-*s | %-*s | %-*s | %-*s |\n", nameW, displayName, valW, r.Value, descW, desc, refW, refStr)
-		} else if hasRefs {
-			fmt.Printf("| %-*s | %-*s | %-*s |\n", nameW, displayName, valW, r.Value, refW, refStr)
-		} else if hasDesc {
-			fmt.Printf("| %-*s | %-*s | %-*s |\n", nameW, displayName, valW, r.Value, descW, desc)
-		} else {
-			fmt.Printf("| %-*s | %-*s |\n", nameW, displayName, valW, r.Value)
+	// Columns are at least as wide as their headers
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = len(h)
+	}
+	for _, row := range cells {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
 		}
 	}
+
+	writeTableRow(headers, widths)
+	rules := make([]string, len(widths))
+	for i, w := range widths {
+		rules[i] = strings.Repeat("-", w)
+	}
+	fmt.Printf("|-%s-|\n", strings.Join(rules, "-|-"))
+	for _, row := range cells {
+		writeTableRow(row, widths)
+	}
 }
 
-func formatTokenName(r Row, showLinks bool) string {
+// writeTableRow prints one markdown table row, padding each cell to its
+// column width.
+func writeTableRow(cells []string, widths []int) {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		padded[i] = fmt.Sprintf("%-*s", widths[i], cell)
+	}
+	fmt.Printf("| %s |\n", strings.Join(padded, " | "))
+}
+
+func formatTokenName(r Row, showLinks bool, linkBase string) string {
 	name := r.Name
 	if showLinks {
-		slug := slugify(r.Name)
-		name = fmt.Sprintf("[%s](#%s)", r.Name, slug)
+		name = tokenLink(r.Name, linkBase)
 	}
 	if r.Deprecated {
 		name = "~~" + name + "~~"
@@ -574,7 +925,16 @@ func formatTokenName(r Row, showLinks bool) string {
 	return name
 }
 
-func formatDescription(r Row) string {
+// formatValue returns the row's value cell, led by a swatch for colors
+// when swatches is set.
+func formatValue(r Row, swatches bool) string {
+	if swatches && r.IsColor {
+		return MarkdownSwatch(r.swatchValue()) + r.Value
+	}
+	return r.Value
+}
+
+func formatDescription(r Row, showLinks bool, linkBase string) string {
 	desc := r.Description
 	if r.Deprecated && r.DeprecationMessage != "" {
 		if desc != "" {
@@ -584,20 +944,31 @@ func formatDescription(r Row) string {
 	} else if r.Deprecated && desc == "" {
 		desc = "*Deprecated*"
 	}
+	if r.Deprecated && r.Replacement != "" {
+		desc += " Use " + formatRefChain([]string{r.Replacement}, showLinks, linkBase) + " instead."
+	}
 	return desc
 }
 
-func formatRefChain(chain []string, showLinks bool) string {
+func formatRefChain(chain []string, showLinks bool, linkBase string) string {
 	if len(chain) == 0 {
 		return ""
 	}
 	if showLinks {
 		parts := make([]string, len(chain))
 		for i, ref := range chain {
-			slug := slugify(ref)
-			parts[i] = fmt.Sprintf("[%s](#%s)", ref, slug)
+			parts[i] = tokenLink(ref, linkBase)
 		}
 		return strings.Join(parts, " → ")
 	}
 	return strings.Join(chain, " → ")
 }
+
+// tokenLink returns a markdown link to a token's anchor.
+// linkBase replaces the leading "#" so links can target another page.
+func tokenLink(name, linkBase string) string {
+	if linkBase == "" {
+		linkBase = "#"
+	}
+	return fmt.Sprintf("[%s](%s%s)", name, linkBase, slugify(name))
+}
@@ -9,9 +9,12 @@ package render
 import (
 	"bytes"
 	"os"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
+	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/testutil"
 	"bennypowers.dev/asimonim/token"
 )
@@ -163,10 +166,11 @@ func TestGenerateTOC(t *testing.T) {
 
 func TestFormatTokenName(t *testing.T) {
 	tests := []struct {
-		name       string
-		row        Row
-		showLinks  bool
-		expected   string
+		name      string
+		row       Row
+		showLinks bool
+		linkBase  string
+		expected  string
 	}{
 		{
 			name:      "plain name",
@@ -192,11 +196,18 @@ func TestFormatTokenName(t *testing.T) {
 			showLinks: true,
 			expected:  "~~[--color-primary](#color-primary)~~",
 		},
+		{
+			name:      "with link base",
+			row:       Row{Name: "--color-primary"},
+			showLinks: true,
+			linkBase:  "tokens/colors#",
+			expected:  "[--color-primary](tokens/colors#color-primary)",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatTokenName(tt.row, tt.showLinks)
+			result := formatTokenName(tt.row, tt.showLinks, tt.linkBase)
 			if result != tt.expected {
 				t.Errorf("formatTokenName() = %q, want %q", result, tt.expected)
 			}
@@ -206,9 +217,11 @@ func TestFormatTokenName(t *testing.T) {
 
 func TestFormatDescription(t *testing.T) {
 	tests := []struct {
-		name     string
-		row      Row
-		expected string
+		name      string
+		row       Row
+		showLinks bool
+		linkBase  string
+		expected  string
 	}{
 		{
 			name:     "plain description",
@@ -230,11 +243,22 @@ func TestFormatDescription(t *testing.T) {
 			row:      Row{Deprecated: true},
 			expected: "*Deprecated*",
 		},
+		{
+			name:     "deprecated with replacement",
+			row:      Row{Deprecated: true, DeprecationMessage: "Renamed", Replacement: "--color-new"},
+			expected: "*Deprecated: Renamed* Use --color-new instead.",
+		},
+		{
+			name:      "deprecated with linked replacement",
+			row:       Row{Deprecated: true, Replacement: "--color-new"},
+			showLinks: true,
+			expected:  "*Deprecated* Use [--color-new](#color-new) instead.",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatDescription(tt.row)
+			result := formatDescription(tt.row, tt.showLinks, tt.linkBase)
 			if result != tt.expected {
 				t.Errorf("formatDescription() = %q, want %q", result, tt.expected)
 			}
@@ -247,6 +271,7 @@ func TestFormatRefChain(t *testing.T) {
 		name      string
 		chain     []string
 		showLinks bool
+		linkBase  string
 		expected  string
 	}{
 		{
@@ -279,11 +304,24 @@ func TestFormatRefChain(t *testing.T) {
 			showLinks: true,
 			expected:  "[--color-primary](#color-primary) → [--color-base](#color-base)",
 		},
+		{
+			name:     "link base ignored without links",
+			chain:    []string{"--color-primary"},
+			linkBase: "tokens/colors#",
+			expected: "--color-primary",
+		},
+		{
+			name:      "refs with link base",
+			chain:     []string{"--color-primary", "--color-base"},
+			showLinks: true,
+			linkBase:  "tokens/colors#",
+			expected:  "[--color-primary](tokens/colors#color-primary) → [--color-base](tokens/colors#color-base)",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatRefChain(tt.chain, tt.showLinks)
+			result := formatRefChain(tt.chain, tt.showLinks, tt.linkBase)
 			if result != tt.expected {
 				t.Errorf("formatRefChain() = %q, want %q", result, tt.expected)
 			}
@@ -320,6 +358,53 @@ func TestComputeRowsWithNewFields(t *testing.T) {
 	if len(row.Path) != 2 || row.Path[0] != "color" || row.Path[1] != "primary" {
 		t.Errorf("expected path [color, primary], got %v", row.Path)
 	}
+	if row.CSSValue != "#FF6B35" {
+		t.Errorf("expected CSS value #FF6B35, got %q", row.CSSValue)
+	}
+}
+
+func TestComputeRows_Placeholder(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-brand", Type: "color", Path: []string{"color", "brand"}, IsResolved: true},
+		{Name: "color-link", Type: "color", Path: []string{"color", "link"}, Value: "{color.brand}",
+			RawValue: "{color.brand}", IsResolved: true, ResolutionChain: []string{"color-brand"}},
+	}
+
+	rows := ComputeRows(tokens, false)
+
+	for _, row := range rows {
+		if row.Value != PlaceholderMarker {
+			t.Errorf("%s: expected value %q, got %q", row.Name, PlaceholderMarker, row.Value)
+		}
+		if row.IsColor {
+			t.Errorf("%s: expected no swatch", row.Name)
+		}
+	}
+	if rows[0].CSSValue != "" {
+		t.Errorf("expected no CSS value for the placeholder, got %q", rows[0].CSSValue)
+	}
+	if len(rows[1].RefChain) != 1 {
+		t.Errorf("expected the alias to keep its chain, got %v", rows[1].RefChain)
+	}
+}
+
+func TestComputeRows_Raw(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-link", Type: "color", Path: []string{"color", "link"}, Value: "{color.brand}", RawValue: "{color.brand}"},
+		{Name: "color-focus", Type: "color", Path: []string{"color", "focus"}, Value: "#/color/brand", RawValue: "#/color/brand"},
+	}
+
+	raw := ComputeRows(tokens, true)
+	for _, row := range raw {
+		if row.Value != "{color.brand}" {
+			t.Errorf("%s: expected raw value {color.brand}, got %q", row.Name, row.Value)
+		}
+	}
+
+	rows := ComputeRows(tokens, false)
+	if rows[0].Value != "--color-brand" {
+		t.Errorf("expected reference as a CSS variable, got %q", rows[0].Value)
+	}
 }
 
 func TestColumnWidths(t *testing.T) {
@@ -376,6 +461,151 @@ func TestColorSwatch(t *testing.T) {
 	}
 }
 
+func TestMarkdownSwatch(t *testing.T) {
+	swatch := MarkdownSwatch("rgb(255 0 0)")
+	if !strings.Contains(swatch, "background:#ff0000") {
+		t.Errorf("expected hex background, got %q", swatch)
+	}
+	if swatch := MarkdownSwatch(`red"><script>`); swatch != "" {
+		t.Errorf("expected empty swatch for invalid color, got %q", swatch)
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	color := Row{Value: "oklch(0.7 0.15 180)", IsColor: true, Swatch: "#00b4a0"}
+	plain := Row{Value: "4px"}
+
+	if got := formatValue(color, false); got != color.Value {
+		t.Errorf("formatValue without swatches = %q, want %q", got, color.Value)
+	}
+	got := formatValue(color, true)
+	if !strings.Contains(got, "background:#00b4a0") || !strings.HasSuffix(got, color.Value) {
+		t.Errorf("formatValue with swatches = %q, want swatch before value", got)
+	}
+	if got := formatValue(plain, true); got != plain.Value {
+		t.Errorf("formatValue for non-color = %q, want %q", got, plain.Value)
+	}
+}
+
+func TestComputeRows_WideGamutSwatch(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name: "color-vivid",
+			Type: token.TypeColor,
+			Path: []string{"color", "vivid"},
+			RawValue: map[string]any{
+				"colorSpace": "display-p3",
+				"components": []any{1.0, 0.0, 0.0},
+			},
+			SchemaVersion: schema.V2025_10,
+		},
+	}
+
+	rows := ComputeRows(tokens, false)
+	if !rows[0].IsColor {
+		t.Fatalf("expected display-p3 token %q to be a color", rows[0].Value)
+	}
+	if rows[0].Swatch != "#ff0000" {
+		t.Errorf("Swatch = %q, want #ff0000", rows[0].Swatch)
+	}
+
+	var buf bytes.Buffer
+	if err := Tree(&buf, rows, Style{}); err != nil {
+		t.Fatalf("Tree() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), ColorSwatch("#ff0000")+rows[0].Value) {
+		t.Errorf("expected swatch before display-p3 value, got %q", buf.String())
+	}
+}
+
+func TestTree(t *testing.T) {
+	rows := []Row{
+		{Name: "--spacing-small", Value: "4px", Path: []string{"spacing", "small"}},
+		{Name: "--color-brand-primary", Value: "#FF6B35", IsColor: true, Path: []string{"color", "brand", "primary"}},
+		{Name: "--color-brand-accent", Value: "blue", IsColor: true, Path: []string{"color", "brand", "accent"}},
+		{Name: "--color-text", Value: "{color.brand.primary}", Path: []string{"color", "text"}},
+	}
+
+	var buf bytes.Buffer
+	if err := Tree(&buf, rows, Style{}); err != nil {
+		t.Fatalf("Tree() error: %v", err)
+	}
+
+	swatch := ColorSwatch("#FF6B35")
+	expected := "" +
+		"├─ color\n" +
+		"│  ├─ brand\n" +
+		"│  │  ├─ accent: " + ColorSwatch("blue") + "blue\n" +
+		"│  │  └─ primary: " + swatch + "#FF6B35\n" +
+		"│  └─ text: {color.brand.primary}\n" +
+		"└─ spacing\n" +
+		"   └─ small: 4px\n"
+	if buf.String() != expected {
+		t.Errorf("Tree() output mismatch\ngot:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestTree_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Tree(&buf, nil, Style{}); err != nil {
+		t.Errorf("Tree(nil) returned error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
+
+func TestTree_ASCII(t *testing.T) {
+	rows := []Row{
+		{Name: "--color-primary", Value: "#FF6B35", IsColor: true, Path: []string{"color", "primary"}},
+		{Name: "--spacing-small", Value: "4px", Path: []string{"spacing", "small"}},
+	}
+
+	var buf bytes.Buffer
+	if err := Tree(&buf, rows, Style{ASCII: true, NoColor: true}); err != nil {
+		t.Fatalf("Tree() error: %v", err)
+	}
+
+	expected := "" +
+		"|- color\n" +
+		"|  `- primary: #FF6B35\n" +
+		"`- spacing\n" +
+		"   `- small: 4px\n"
+	if buf.String() != expected {
+		t.Errorf("Tree() output mismatch\ngot:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestDetectStyle(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Run("non-terminal disables color", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		got := DetectStyle(f, false, false)
+		if !got.NoColor || got.ASCII {
+			t.Errorf("DetectStyle() = %+v, want NoColor only", got)
+		}
+	})
+
+	t.Run("ascii implies no color", func(t *testing.T) {
+		got := DetectStyle(f, false, true)
+		if !got.NoColor || !got.ASCII {
+			t.Errorf("DetectStyle() = %+v, want NoColor and ASCII", got)
+		}
+	})
+
+	t.Run("NO_COLOR", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		if got := DetectStyle(os.Stdout, false, false); !got.NoColor {
+			t.Errorf("DetectStyle() = %+v, want NoColor", got)
+		}
+	})
+}
+
 func TestNameToCSSVar(t *testing.T) {
 	tests := []struct {
 		name, prefix, want string
@@ -438,7 +668,7 @@ func TestTable(t *testing.T) {
 	}
 
 	output := captureStdout(t, func() {
-		_ = Table(rows)
+		_ = Table(rows, Style{})
 	})
 
 	if !strings.Contains(output, "--color-primary") {
@@ -449,8 +679,57 @@ func TestTable(t *testing.T) {
 	}
 }
 
+func TestTable_Matched(t *testing.T) {
+	rows := []Row{
+		{Name: "--color-primary", Type: "color", Value: "#FF6B35", Matched: []string{"name", "description"}},
+		{Name: "--spacing-small", Type: "dimension", Value: "4px"},
+	}
+
+	output := captureStdout(t, func() {
+		_ = Table(rows, Style{NoColor: true, Highlight: regexp.MustCompile("primary")})
+	})
+
+	if !strings.Contains(output, "#FF6B35  [name, description]\n") {
+		t.Errorf("expected matched fields column, got:\n%s", output)
+	}
+	if strings.Contains(output, "\x1b[") {
+		t.Errorf("expected no highlighting without color, got:\n%q", output)
+	}
+
+	output = captureStdout(t, func() {
+		_ = Table(rows, Style{Highlight: regexp.MustCompile("(?i)PRIMARY")})
+	})
+
+	if !strings.Contains(output, "--color-\x1b[7mprimary\x1b[0m  color      ") {
+		t.Errorf("expected highlighted match with columns aligned, got:\n%q", output)
+	}
+}
+
+func TestTable_Source(t *testing.T) {
+	rows := []Row{
+		{Name: "--color-primary", Type: "color", Value: "#FF6B35", Source: "color.json"},
+		{Name: "--spacing-small", Type: "dimension", Value: "4px", Source: "spacing.json"},
+	}
+
+	output := captureStdout(t, func() {
+		_ = Table(rows, Style{NoColor: true, Source: true})
+	})
+
+	if !strings.Contains(output, "color      color.json    #FF6B35\n") {
+		t.Errorf("expected aligned source column, got:\n%s", output)
+	}
+
+	output = captureStdout(t, func() {
+		_ = Table(rows, Style{NoColor: true})
+	})
+
+	if strings.Contains(output, "color.json") {
+		t.Errorf("expected no source column without Source, got:\n%s", output)
+	}
+}
+
 func TestTable_Empty(t *testing.T) {
-	err := Table(nil)
+	err := Table(nil, Style{})
 	if err != nil {
 		t.Errorf("Table(nil) returned error: %v", err)
 	}
@@ -462,7 +741,7 @@ func TestTable_WithRefChain(t *testing.T) {
 	}
 
 	output := captureStdout(t, func() {
-		_ = Table(rows)
+		_ = Table(rows, Style{})
 	})
 
 	if !strings.Contains(output, "→") {
@@ -470,14 +749,31 @@ func TestTable_WithRefChain(t *testing.T) {
 	}
 }
 
+func TestTable_ASCII(t *testing.T) {
+	rows := []Row{
+		{Name: "--color-secondary", Type: "color", Value: "#FF6B35", IsColor: true, RefChain: []string{"--color-primary"}},
+	}
+
+	output := captureStdout(t, func() {
+		_ = Table(rows, Style{ASCII: true, NoColor: true})
+	})
+
+	if !strings.Contains(output, "#FF6B35 -> --color-primary") {
+		t.Errorf("table output should contain ASCII arrow, got:\n%s", output)
+	}
+	if strings.Contains(output, "\x1b[") {
+		t.Error("table output should not contain ANSI escapes")
+	}
+}
+
 func TestCSS(t *testing.T) {
 	rows := []Row{
-		{Name: "--color-primary", Value: "#FF6B35"},
-		{Name: "--spacing-small", Value: "4px"},
+		{Name: "--color-primary", Value: "#FF6B35", CSSValue: "#FF6B35"},
+		{Name: "--spacing-small", Value: "4px", CSSValue: "4px"},
 	}
 
 	output := captureStdout(t, func() {
-		_ = CSS(rows)
+		_ = CSS(rows, "")
 	})
 
 	if !strings.Contains(output, ":root {") {
@@ -491,17 +787,35 @@ func TestCSS(t *testing.T) {
 	}
 }
 
+func TestCSS_Selector(t *testing.T) {
+	rows := []Row{{Name: "--color-primary", CSSValue: "#FF6B35"}}
+
+	output := captureStdout(t, func() {
+		_ = CSS(rows, "html[data-theme=dark]")
+	})
+	if want := "html[data-theme=dark] {\n  --color-primary: #FF6B35;\n}\n"; output != want {
+		t.Errorf("CSS output = %q, want %q", output, want)
+	}
+
+	output = captureStdout(t, func() {
+		_ = CSS(rows, "none")
+	})
+	if want := "--color-primary: #FF6B35;\n"; output != want {
+		t.Errorf("bare CSS output = %q, want %q", output, want)
+	}
+}
+
 func TestCSS_SkipsMapValues(t *testing.T) {
 	rows := []Row{
 		{Name: "--structured", Value: `{"colorSpace": "srgb"}`},
-		{Name: "--simple", Value: "#FF6B35"},
+		{Name: "--simple", Value: "#FF6B35", CSSValue: "#FF6B35"},
 	}
 
 	output := captureStdout(t, func() {
-		_ = CSS(rows)
+		_ = CSS(rows, "")
 	})
 
-	// Map-like values starting with { and containing : should be skipped
+	// Values without a CSS representation should be skipped
 	if strings.Contains(output, "--structured") {
 		t.Error("CSS should skip map-like values")
 	}
@@ -517,7 +831,7 @@ func TestNames(t *testing.T) {
 	}
 
 	output := captureStdout(t, func() {
-		_ = Names(rows)
+		_ = Names(rows, NameStyleCSS)
 	})
 
 	if !strings.Contains(output, "--color-primary\n") {
@@ -528,6 +842,52 @@ func TestNames(t *testing.T) {
 	}
 }
 
+func TestNames_Styles(t *testing.T) {
+	rows := []Row{
+		{Name: "--rh-color-brand-primary", Path: []string{"color", "brand", "primary"}},
+		{Name: "--no-path"},
+	}
+
+	tests := []struct {
+		style NameStyle
+		want  string
+	}{
+		{NameStyleCSS, "--rh-color-brand-primary\n--no-path\n"},
+		{NameStyleDot, "color.brand.primary\n--no-path\n"},
+		{NameStyleShort, "primary\n--no-path\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			output := captureStdout(t, func() {
+				_ = Names(rows, tt.style)
+			})
+			if output != tt.want {
+				t.Errorf("Names(%s) = %q, want %q", tt.style, output, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNameStyle(t *testing.T) {
+	if style, err := ParseNameStyle("dot"); err != nil || style != NameStyleDot {
+		t.Errorf("ParseNameStyle(dot) = %q, %v", style, err)
+	}
+	if _, err := ParseNameStyle("camel"); err == nil {
+		t.Error("expected error for unknown name style")
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	for input, want := range map[string]GroupBy{"": GroupByHierarchy, "hierarchy": GroupByHierarchy, "type": GroupByType} {
+		if groupBy, err := ParseGroupBy(input); err != nil || groupBy != want {
+			t.Errorf("ParseGroupBy(%q) = %q, %v; want %q", input, groupBy, err, want)
+		}
+	}
+	if _, err := ParseGroupBy("path"); err == nil {
+		t.Error("expected error for unknown group-by")
+	}
+}
+
 func TestMarkdown(t *testing.T) {
 	rows := []Row{
 		{Name: "--color-primary", Type: "color", Value: "#FF6B35"},
@@ -585,6 +945,81 @@ func TestMarkdown_WithRefChain(t *testing.T) {
 	}
 }
 
+func TestMarkdownWithOptions_ShowSource(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "spacing-small", Value: "4px", Type: "dimension", Path: []string{"spacing", "small"}, FilePath: "/tokens/spacing.json"},
+	}
+	rows := ComputeRows(tokens, false)
+	if rows[0].Source != "spacing.json" {
+		t.Fatalf("Source = %q, want %q", rows[0].Source, "spacing.json")
+	}
+
+	output := captureStdout(t, func() {
+		_ = MarkdownWithOptions(rows, MarkdownOptions{ShowSource: true})
+	})
+
+	want := "| Name            | Value | Source       |\n" +
+		"|-----------------|-------|--------------|\n" +
+		"| --spacing-small | 4px   | spacing.json |\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("expected source column, got:\n%s", output)
+	}
+}
+
+func TestMarkdownWithOptions_Frontmatter(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "spacing-small", Value: "4px", Type: "dimension", Path: []string{"spacing", "small"}},
+	}
+	rows := ComputeRows(tokens, false)
+
+	output := captureStdout(t, func() {
+		err := MarkdownWithOptions(rows, MarkdownOptions{
+			Frontmatter: map[string]any{"title": "Tokens", "weight": "10", "summary": "Sizes: small"},
+		})
+		if err != nil {
+			t.Errorf("MarkdownWithOptions() error = %v", err)
+		}
+	})
+
+	want := "---\nsummary: 'Sizes: small'\ntitle: Tokens\nweight: \"10\"\n---\n\n"
+	if !strings.HasPrefix(output, want) {
+		t.Errorf("expected frontmatter block first, got:\n%s", output)
+	}
+	if !strings.Contains(output, "--spacing-small") {
+		t.Errorf("expected tokens after frontmatter, got:\n%s", output)
+	}
+}
+
+func TestMarkdownWithOptions_FrontmatterWithoutRows(t *testing.T) {
+	output := captureStdout(t, func() {
+		_ = MarkdownWithOptions(nil, MarkdownOptions{Frontmatter: map[string]any{"title": "Tokens"}})
+	})
+	if output != "---\ntitle: Tokens\n---\n\n" {
+		t.Errorf("expected only the frontmatter block, got:\n%q", output)
+	}
+}
+
+func TestParseFrontmatter(t *testing.T) {
+	got, err := ParseFrontmatter([]string{"title=Tokens", "description=a=b", "title=Design Tokens"})
+	if err != nil {
+		t.Fatalf("ParseFrontmatter() error = %v", err)
+	}
+	want := map[string]any{"title": "Design Tokens", "description": "a=b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFrontmatter() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"title", "=Tokens"} {
+		if _, err := ParseFrontmatter([]string{bad}); err == nil {
+			t.Errorf("ParseFrontmatter(%q) expected error", bad)
+		}
+	}
+
+	if got, err := ParseFrontmatter(nil); err != nil || got != nil {
+		t.Errorf("ParseFrontmatter(nil) = %v, %v; want nil, nil", got, err)
+	}
+}
+
 func TestMarkdownWithOptions_Empty(t *testing.T) {
 	err := MarkdownWithOptions(nil, MarkdownOptions{})
 	if err != nil {
@@ -670,3 +1105,26 @@ func TestMarkdownWithOptionsGolden(t *testing.T) {
 		t.Errorf("markdown output mismatch.\n\nExpected:\n%s\n\nActual:\n%s", expected, actual)
 	}
 }
+
+func TestMarkdownWithOptions_GroupByTypeGolden(t *testing.T) {
+	expected := testutil.LoadFixtureFile(t, "fixtures/markdown/by-type/expected.md")
+
+	tokens := []*token.Token{
+		{Name: "color-brand-primary", Value: "#FF6B35", Type: "color", Description: "Main brand color", Path: []string{"color", "brand", "primary"}},
+		{Name: "color-brand-secondary", Value: "#FF6B35", Type: "color", Path: []string{"color", "brand", "secondary"}, ResolutionChain: []string{"color-brand-primary"}, ResolvedValue: "#FF6B35"},
+		{Name: "spacing-small", Value: "4px", Type: "dimension", Path: []string{"spacing", "small"}},
+		{Name: "surface-border", Value: "#CCCCCC", Type: "color", Path: []string{"surface", "border"}},
+		{Name: "surface-gap", Value: "8px", Type: "dimension", Path: []string{"surface", "gap"}},
+		{Name: "label", Value: "Hello", Path: []string{"label"}},
+	}
+
+	rows := ComputeRows(tokens, false)
+	actual := captureStdout(t, func() {
+		_ = MarkdownWithOptions(rows, MarkdownOptions{GroupBy: GroupByType, IncludeTOC: true})
+	})
+	testutil.UpdateGoldenFile(t, "fixtures/markdown/by-type/expected.md", []byte(actual))
+
+	if actual != string(expected) {
+		t.Errorf("markdown output mismatch.\n\nExpected:\n%s\n\nActual:\n%s", expected, actual)
+	}
+}
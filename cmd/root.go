@@ -11,9 +11,14 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"bennypowers.dev/asimonim/internal/logger"
+
 	"bennypowers.dev/asimonim/cmd/convert"
+	initcmd "bennypowers.dev/asimonim/cmd/init"
 	"bennypowers.dev/asimonim/cmd/list"
 	mcpcmd "bennypowers.dev/asimonim/cmd/mcp"
+	"bennypowers.dev/asimonim/cmd/rename"
+	schemacmd "bennypowers.dev/asimonim/cmd/schema"
 	"bennypowers.dev/asimonim/cmd/search"
 	"bennypowers.dev/asimonim/cmd/validate"
 	"bennypowers.dev/asimonim/cmd/version"
@@ -36,20 +41,27 @@ func init() {
 // Each call returns an isolated command tree with no shared state.
 func NewRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
-		Use:   "asimonim",
-		Short: "Parse and work with design tokens definitions",
-		Long:  `asimonim parses and validates design token files, defined by the Design Tokens Community Group specification.`,
+		Use:               "asimonim",
+		Short:             "Parse and work with design tokens definitions",
+		Long:              `asimonim parses and validates design token files, defined by the Design Tokens Community Group specification.`,
+		PersistentPreRunE: setLogLevel,
 	}
 
 	rootCmd.PersistentFlags().StringP("schema", "s", "", "Force schema version (draft, v2025.10)")
 	rootCmd.PersistentFlags().StringP("prefix", "p", "", "Prefix for output variable names")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Log extra detail, such as detected schemas")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Only report errors that fail the command")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
 
 	_ = viper.BindPFlag("schema", rootCmd.PersistentFlags().Lookup("schema"))
 	_ = viper.BindPFlag("prefix", rootCmd.PersistentFlags().Lookup("prefix"))
 
 	rootCmd.AddCommand(convert.NewCmd())
+	rootCmd.AddCommand(initcmd.NewCmd())
 	rootCmd.AddCommand(list.NewCmd())
 	rootCmd.AddCommand(mcpcmd.NewCmd())
+	rootCmd.AddCommand(rename.NewCmd())
+	rootCmd.AddCommand(schemacmd.NewCmd())
 	rootCmd.AddCommand(search.NewCmd())
 	rootCmd.AddCommand(validate.NewCmd())
 	rootCmd.AddCommand(version.NewCmd())
@@ -57,6 +69,21 @@ func NewRootCmd() *cobra.Command {
 	return rootCmd
 }
 
+// setLogLevel applies the --verbose and --quiet flags to the shared logger.
+func setLogLevel(cmd *cobra.Command, _ []string) error {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	switch {
+	case quiet:
+		logger.SetLevel(logger.LevelQuiet)
+	case verbose:
+		logger.SetLevel(logger.LevelVerbose)
+	default:
+		logger.SetLevel(logger.LevelNormal)
+	}
+	return nil
+}
+
 func initConfig() {
 	// Look for config in .config directory
 	viper.SetConfigName("design-tokens")
@@ -0,0 +1,87 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package schemacmd provides the schema command for asimonim.
+package schemacmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"bennypowers.dev/asimonim/schema"
+)
+
+// Cmd is the schema cobra command.
+var Cmd = NewCmd()
+
+// NewCmd creates a fresh schema command and its subcommands.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Show supported schema versions",
+		Long:  `Show the DTCG schema versions asimonim supports, for choosing a --schema value.`,
+	}
+	cmd.AddCommand(newListCmd())
+	return cmd
+}
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List supported schema versions",
+		Long:  `List each supported schema version with its $schema URL and features.`,
+		Args:  cobra.NoArgs,
+		RunE:  runList,
+	}
+	cmd.Flags().StringP("format", "f", "text", "Output format (text, json)")
+	return cmd
+}
+
+// versionInfo describes a schema version in json output.
+type versionInfo struct {
+	Version  string   `json:"version"`
+	URL      string   `json:"url"`
+	Features []string `json:"features"`
+}
+
+func runList(cmd *cobra.Command, _ []string) error {
+	format, _ := cmd.Flags().GetString("format")
+
+	versions := schema.Versions()
+	switch format {
+	case "json":
+		infos := make([]versionInfo, len(versions))
+		for i, v := range versions {
+			infos[i] = versionInfo{Version: v.String(), URL: v.URL(), Features: v.Features()}
+		}
+		out, err := json.MarshalIndent(infos, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling schema versions: %w", err)
+		}
+		fmt.Println(string(out))
+	case "text":
+		width := 0
+		for _, v := range versions {
+			width = max(width, len(v.String()))
+		}
+		indent := strings.Repeat(" ", width+2)
+		for i, v := range versions {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%-*s  %s\n", width, v.String(), v.URL())
+			for _, feature := range v.Features() {
+				fmt.Printf("%s- %s\n", indent, feature)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown format: %s (valid: text, json)", format)
+	}
+	return nil
+}
@@ -17,9 +17,12 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"bennypowers.dev/asimonim/cmd/complete"
+	"bennypowers.dev/asimonim/cmd/filter"
 	"bennypowers.dev/asimonim/cmd/render"
 	"bennypowers.dev/asimonim/config"
 	"bennypowers.dev/asimonim/fs"
+	"bennypowers.dev/asimonim/internal/logger"
 	"bennypowers.dev/asimonim/parser"
 	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/specifier"
@@ -32,26 +35,46 @@ var Cmd = NewCmd()
 // NewCmd creates a fresh search command with its own flags.
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "search <query> [files...]",
-		Short: "Search tokens by name, value, or type",
-		Long:  `Search design tokens by name, value, or type with optional regex support.`,
-		Args:  cobra.MinimumNArgs(1),
-		RunE:  run,
+		Use:               "search <query> [files...]",
+		Short:             "Search tokens by name, value, or type",
+		Long:              `Search design tokens by name, value, or type with optional regex support.`,
+		Args:              cobra.MinimumNArgs(1),
+		RunE:              run,
+		ValidArgsFunction: completeArgs,
 	}
 	cmd.Flags().Bool("name", false, "Search names only")
 	cmd.Flags().Bool("value", false, "Search values only")
 	cmd.Flags().String("type", "", "Filter by token type")
+	cmd.Flags().StringSlice("exclude-type", nil, "Hide tokens of this type (repeatable)")
 	cmd.Flags().Bool("regex", false, "Query is a regex")
 	cmd.Flags().String("format", "table", "Output format: table, names, markdown")
 	cmd.Flags().String("group", "", "Filter by group/path prefix (e.g., color.brand)")
 	cmd.Flags().Bool("deprecated", false, "Show only deprecated tokens")
 	cmd.Flags().Bool("no-deprecated", false, "Hide deprecated tokens")
+	cmd.Flags().String("filter", "", `Filter expression, e.g. 'type == "color" && !deprecated'`)
 	cmd.Flags().Bool("toc", false, "Include table of contents (markdown only)")
 	cmd.Flags().Int("toc-depth", 3, "Maximum TOC depth (1-6)")
 	cmd.Flags().Bool("links", false, "Add anchor links to tokens (markdown only)")
+	cmd.Flags().Bool("no-color", false, "Disable color swatches (also disabled by NO_COLOR or non-terminal output)")
+	cmd.Flags().Bool("ascii", false, "Use ASCII arrows instead of Unicode, without color")
+	cmd.Flags().Bool("swatches", false, "Show inline HTML color swatches (markdown only)")
+	cmd.Flags().String("group-by", "hierarchy", "Markdown sections: hierarchy (nested by path) or type (markdown only)")
+	cmd.Flags().StringArray("frontmatter", nil, "YAML frontmatter key=value to put at the top of the page (repeatable, markdown only)")
+	cmd.Flags().String("name-style", "css", "Names to print with --format names: css, dot, or short")
+	cmd.Flags().Bool("show-match", false, "Show which fields matched and highlight matches (table only)")
+	cmd.Flags().Bool("count", false, "Print only the number of matching tokens")
+	_ = cmd.RegisterFlagCompletionFunc("group", complete.Groups(complete.ArgsAfterQuery))
 	return cmd
 }
 
+// completeArgs offers token paths for the query and files after it.
+func completeArgs(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return complete.Tokens(complete.ArgsAfterQuery)(cmd, args, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveDefault
+}
+
 func run(cmd *cobra.Command, args []string) error {
 	query := args[0]
 	files := args[1:]
@@ -59,26 +82,58 @@ func run(cmd *cobra.Command, args []string) error {
 	nameOnly, _ := cmd.Flags().GetBool("name")
 	valueOnly, _ := cmd.Flags().GetBool("value")
 	typeFilter, _ := cmd.Flags().GetString("type")
+	excludeTypes, _ := cmd.Flags().GetStringSlice("exclude-type")
 	useRegex, _ := cmd.Flags().GetBool("regex")
 	format, _ := cmd.Flags().GetString("format")
 	schemaFlag, _ := cmd.Flags().GetString("schema")
 	groupFilter, _ := cmd.Flags().GetString("group")
 	onlyDeprecated, _ := cmd.Flags().GetBool("deprecated")
 	hideDeprecated, _ := cmd.Flags().GetBool("no-deprecated")
+	filterFlag, _ := cmd.Flags().GetString("filter")
 	includeTOC, _ := cmd.Flags().GetBool("toc")
 	tocDepth, _ := cmd.Flags().GetInt("toc-depth")
 	showLinks, _ := cmd.Flags().GetBool("links")
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	ascii, _ := cmd.Flags().GetBool("ascii")
+	swatches, _ := cmd.Flags().GetBool("swatches")
+	nameStyleFlag, _ := cmd.Flags().GetString("name-style")
+	groupByFlag, _ := cmd.Flags().GetString("group-by")
+	showMatch, _ := cmd.Flags().GetBool("show-match")
+	frontmatterFlag, _ := cmd.Flags().GetStringArray("frontmatter")
+	count, _ := cmd.Flags().GetBool("count")
 
 	if onlyDeprecated && hideDeprecated {
 		return fmt.Errorf("cannot use --deprecated and --no-deprecated together")
 	}
 
+	nameStyle, err := render.ParseNameStyle(nameStyleFlag)
+	if err != nil {
+		return err
+	}
+
+	groupBy, err := render.ParseGroupBy(groupByFlag)
+	if err != nil {
+		return err
+	}
+	frontmatter, err := render.ParseFrontmatter(frontmatterFlag)
+	if err != nil {
+		return err
+	}
+
 	if tocDepth < 1 || tocDepth > 6 {
 		return fmt.Errorf("toc-depth must be between 1 and 6, got %d", tocDepth)
 	}
 
+	expr := filter.FlagExpr(typeFilter, excludeTypes, groupFilter, onlyDeprecated, hideDeprecated)
+	if filterFlag != "" {
+		userExpr, err := filter.Parse(filterFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --filter: %w", err)
+		}
+		expr = filter.And(expr, userExpr)
+	}
+
 	var pattern *regexp.Regexp
-	var err error
 	if useRegex {
 		pattern, err = regexp.Compile(query)
 		if err != nil {
@@ -86,7 +141,7 @@ func run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	filesystem := fs.NewOSFileSystem()
+	filesystem := fs.WithURLs(fs.NewOSFileSystem(), nil)
 	jsonParser := parser.NewJSONParser()
 
 	cwd, err := os.Getwd()
@@ -143,12 +198,13 @@ func run(cmd *cobra.Command, args []string) error {
 	}
 
 	var matches []*token.Token
+	matchedFields := make(map[*token.Token][]string)
 	var allGroupMeta = make(map[string]render.GroupMeta)
 
 	for _, rf := range resolvedFiles {
 		data, err := filesystem.ReadFile(rf.Path)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", rf.Specifier, err)
+			logger.Error("Error reading %s: %v", rf.Specifier, err)
 			continue
 		}
 
@@ -163,9 +219,10 @@ func run(cmd *cobra.Command, args []string) error {
 		if version == schema.Unknown {
 			version, err = schema.DetectVersion(data, nil)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error detecting schema for %s: %v\n", rf.Specifier, err)
+				logger.Error("Error detecting schema for %s: %v", rf.Specifier, err)
 				continue
 			}
+			logger.Debug("Detected schema %s for %s", version, rf.Specifier)
 		}
 
 		// Get per-file options from config (use original specifier for matching)
@@ -176,31 +233,27 @@ func run(cmd *cobra.Command, args []string) error {
 		}
 		tokens, err := jsonParser.ParseFile(filesystem, rf.Path, opts)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", rf.Specifier, err)
+			logger.Error("Error parsing %s: %v", rf.Specifier, err)
 			continue
 		}
 
 		for _, tok := range tokens {
-			matched := false
-			if nameOnly {
-				matched = matchString(tok.Name, query, pattern)
-			} else if valueOnly {
-				matched = matchString(tok.Value, query, pattern)
-			} else {
-				matched = matchString(tok.Name, query, pattern) ||
-					matchString(tok.Value, query, pattern) ||
-					matchString(tok.Type, query, pattern) ||
-					matchString(tok.Description, query, pattern)
-			}
-
-			if matched {
+			if fields := matchFields(tok, query, pattern, nameOnly, valueOnly); len(fields) > 0 {
 				matches = append(matches, tok)
+				matchedFields[tok] = fields
 			}
 		}
 	}
 
 	// Apply filters
-	matches = filterTokens(matches, typeFilter, groupFilter, onlyDeprecated, hideDeprecated)
+	if expr != nil {
+		matches = expr.Filter(matches)
+	}
+
+	if count {
+		fmt.Println(len(matches))
+		return nil
+	}
 
 	sort.Slice(matches, func(i, j int) bool {
 		return matches[i].Name < matches[j].Name
@@ -208,65 +261,60 @@ func run(cmd *cobra.Command, args []string) error {
 
 	// Compute display rows
 	rows := render.ComputeRows(matches, false)
+	if showMatch {
+		for i, tok := range matches {
+			rows[i].Matched = matchedFields[tok]
+		}
+	}
 
 	switch format {
 	case "names":
-		return render.Names(rows)
+		return render.Names(rows, nameStyle)
 	case "markdown", "md":
 		opts := render.MarkdownOptions{
-			GroupMeta:  allGroupMeta,
-			IncludeTOC: includeTOC,
-			TOCDepth:   tocDepth,
-			ShowLinks:  showLinks,
+			GroupMeta:     allGroupMeta,
+			IncludeTOC:    includeTOC,
+			TOCDepth:      tocDepth,
+			ShowLinks:     showLinks,
+			ColorSwatches: swatches,
+			GroupBy:       groupBy,
+			Frontmatter:   frontmatter,
 		}
 		return render.MarkdownWithOptions(rows, opts)
 	default:
-		return render.Table(rows)
-	}
-}
-
-func filterTokens(tokens []*token.Token, typeFilter, groupFilter string, onlyDeprecated, hideDeprecated bool) []*token.Token {
-	result := tokens
-
-	if typeFilter != "" {
-		filtered := make([]*token.Token, 0, len(result))
-		for _, tok := range result {
-			if tok.Type == typeFilter {
-				filtered = append(filtered, tok)
+		style := render.DetectStyle(os.Stdout, noColor, ascii)
+		if showMatch {
+			style.Highlight = pattern
+			if style.Highlight == nil {
+				style.Highlight = regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
 			}
 		}
-		result = filtered
+		return render.Table(rows, style)
 	}
+}
 
-	if groupFilter != "" {
-		filtered := make([]*token.Token, 0, len(result))
-		for _, tok := range result {
-			if strings.HasPrefix(tok.DotPath(), groupFilter) {
-				filtered = append(filtered, tok)
-			}
-		}
-		result = filtered
+// matchFields returns the fields of tok that match the query, in display
+// order. With nameOnly or valueOnly, only that field is checked.
+func matchFields(tok *token.Token, query string, pattern *regexp.Regexp, nameOnly, valueOnly bool) []string {
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"name", tok.Name},
+		{"value", tok.Value},
+		{"type", tok.Type},
+		{"description", tok.Description},
 	}
-
-	if onlyDeprecated {
-		filtered := make([]*token.Token, 0, len(result))
-		for _, tok := range result {
-			if tok.Deprecated {
-				filtered = append(filtered, tok)
-			}
+	var matched []string
+	for _, f := range fields {
+		if (nameOnly && f.name != "name") || (valueOnly && f.name != "value") {
+			continue
 		}
-		result = filtered
-	} else if hideDeprecated {
-		filtered := make([]*token.Token, 0, len(result))
-		for _, tok := range result {
-			if !tok.Deprecated {
-				filtered = append(filtered, tok)
-			}
+		if matchString(f.value, query, pattern) {
+			matched = append(matched, f.name)
 		}
-		result = filtered
 	}
-
-	return result
+	return matched
 }
 
 func matchString(s, query string, pattern *regexp.Regexp) bool {
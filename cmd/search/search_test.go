@@ -8,6 +8,7 @@ package search
 
 import (
 	"regexp"
+	"slices"
 	"testing"
 
 	"bennypowers.dev/asimonim/token"
@@ -44,47 +45,37 @@ func TestMatchString(t *testing.T) {
 	}
 }
 
-func TestFilterTokens(t *testing.T) {
-	tokens := []*token.Token{
-		{Name: "color-primary", Type: "color", Path: []string{"color", "primary"}, Deprecated: false},
-		{Name: "color-secondary", Type: "color", Path: []string{"color", "secondary"}, Deprecated: true},
-		{Name: "spacing-small", Type: "dimension", Path: []string{"spacing", "small"}, Deprecated: false},
-		{Name: "spacing-large", Type: "dimension", Path: []string{"spacing", "large"}, Deprecated: false},
-		{Name: "font-body", Type: "fontFamily", Path: []string{"font", "body"}, Deprecated: true},
+func TestMatchFields(t *testing.T) {
+	tok := &token.Token{
+		Name:        "color-secondary",
+		Value:       "{color.primary}",
+		Type:        "color",
+		Description: "Secondary color alias",
 	}
 
-	t.Run("no filters", func(t *testing.T) {
-		result := filterTokens(tokens, "", "", false, false)
-		if len(result) != 5 {
-			t.Errorf("expected 5 tokens, got %d", len(result))
-		}
-	})
-
-	t.Run("filter by type", func(t *testing.T) {
-		result := filterTokens(tokens, "color", "", false, false)
-		if len(result) != 2 {
-			t.Errorf("expected 2 color tokens, got %d", len(result))
-		}
-	})
-
-	t.Run("filter by group", func(t *testing.T) {
-		result := filterTokens(tokens, "", "color", false, false)
-		if len(result) != 2 {
-			t.Errorf("expected 2 tokens in color group, got %d", len(result))
-		}
-	})
-
-	t.Run("deprecated only", func(t *testing.T) {
-		result := filterTokens(tokens, "", "", true, false)
-		if len(result) != 2 {
-			t.Errorf("expected 2 deprecated tokens, got %d", len(result))
-		}
-	})
+	tests := []struct {
+		name      string
+		query     string
+		pattern   *regexp.Regexp
+		nameOnly  bool
+		valueOnly bool
+		expected  []string
+	}{
+		{"all fields", "color", nil, false, false, []string{"name", "value", "type", "description"}},
+		{"value only field", "primary", nil, false, false, []string{"value"}},
+		{"case insensitive", "ALIAS", nil, false, false, []string{"description"}},
+		{"name only", "color", nil, true, false, []string{"name"}},
+		{"value only", "secondary", nil, false, true, nil},
+		{"regex", "", regexp.MustCompile(`^color`), false, false, []string{"name", "type"}},
+		{"no match", "spacing", nil, false, false, nil},
+	}
 
-	t.Run("hide deprecated", func(t *testing.T) {
-		result := filterTokens(tokens, "", "", false, true)
-		if len(result) != 3 {
-			t.Errorf("expected 3 non-deprecated tokens, got %d", len(result))
-		}
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchFields(tok, tt.query, tt.pattern, tt.nameOnly, tt.valueOnly)
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("matchFields() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
 }
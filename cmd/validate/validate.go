@@ -19,6 +19,8 @@ import (
 	"bennypowers.dev/asimonim/resolver"
 	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/specifier"
+	"bennypowers.dev/asimonim/token"
+	"bennypowers.dev/asimonim/validator"
 )
 
 // Cmd is the validate cobra command.
@@ -35,15 +37,29 @@ func NewCmd() *cobra.Command {
 	}
 	cmd.Flags().Bool("strict", false, "Fail on warnings")
 	cmd.Flags().Bool("quiet", false, "Only output errors")
+	cmd.Flags().Bool("types", false, "Check that token values are plausible for their $type")
+	cmd.Flags().Bool("strict-types", false, "Warn about tokens whose $type is not a DTCG type")
+	cmd.Flags().Bool("require-descriptions", false, "Fail on tokens without a $description")
+	cmd.Flags().StringSlice("description-types", nil, "Only require descriptions for these token types")
+	cmd.Flags().StringSlice("description-groups", nil, "Only require descriptions under these groups (dot paths)")
+	cmd.Flags().Bool("allow-undocumented-deprecated", false, "Don't require descriptions for deprecated tokens")
+	cmd.Flags().String("baseline", "", "Fail on tokens removed or retyped since this baseline token file")
 	return cmd
 }
 
 func run(cmd *cobra.Command, args []string) error {
 	quiet, _ := cmd.Flags().GetBool("quiet")
 	strict, _ := cmd.Flags().GetBool("strict")
+	checkTypes, _ := cmd.Flags().GetBool("types")
+	strictTypes, _ := cmd.Flags().GetBool("strict-types")
+	requireDescriptions, _ := cmd.Flags().GetBool("require-descriptions")
+	descriptionTypes, _ := cmd.Flags().GetStringSlice("description-types")
+	descriptionGroups, _ := cmd.Flags().GetStringSlice("description-groups")
+	allowUndocumentedDeprecated, _ := cmd.Flags().GetBool("allow-undocumented-deprecated")
+	baselineFlag, _ := cmd.Flags().GetString("baseline")
 	schemaFlag, _ := cmd.Flags().GetString("schema")
 
-	filesystem := fs.NewOSFileSystem()
+	filesystem := fs.WithURLs(fs.NewOSFileSystem(), nil)
 	jsonParser := parser.NewJSONParser()
 
 	cwd, err := os.Getwd()
@@ -102,6 +118,9 @@ func run(cmd *cobra.Command, args []string) error {
 
 	hasErrors := false
 	hasWarnings := false
+	var allTokens []*token.Token
+	// resolvedCount is the number of files whose tokens are in allTokens
+	resolvedCount := 0
 
 	for _, rf := range resolvedFiles {
 		if !quiet {
@@ -128,21 +147,25 @@ func run(cmd *cobra.Command, args []string) error {
 		// Get per-file options from config (use original specifier for matching)
 		opts := cfg.OptionsForFile(rf.Specifier)
 		opts.SkipPositions = true // CLI doesn't need LSP position tracking
+		opts.StrictTypes = strictTypes
 		if version != schema.Unknown {
 			opts.SchemaVersion = version
 		}
+		var typeWarnings []parser.Warning
+		opts.OnWarning = func(w parser.Warning) { typeWarnings = append(typeWarnings, w) }
 		tokens, err := jsonParser.ParseFile(filesystem, rf.Path, opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", rf.Specifier, err)
 			hasErrors = true
 			continue
 		}
-
-		graph := resolver.BuildDependencyGraph(tokens)
-		if cycle := graph.FindCycle(); cycle != nil {
-			fmt.Fprintf(os.Stderr, "Circular reference in %s: %v\n", rf.Specifier, cycle)
-			hasErrors = true
-			continue
+		if len(typeWarnings) > 0 {
+			hasWarnings = true
+			if !quiet {
+				for _, w := range typeWarnings {
+					fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+				}
+			}
 		}
 
 		if err := resolver.ResolveAliases(tokens, version); err != nil {
@@ -150,6 +173,43 @@ func run(cmd *cobra.Command, args []string) error {
 			hasErrors = true
 			continue
 		}
+		allTokens = append(allTokens, tokens...)
+		resolvedCount++
+
+		// A value of another type is likely a copy-paste error, but may
+		// be deliberate, so it's a warning
+		if mismatches := validator.ValidateValueTypeMatch(tokens); len(mismatches) > 0 {
+			hasWarnings = true
+			if !quiet {
+				for _, verr := range mismatches {
+					fmt.Fprintf(os.Stderr, "Warning: type mismatch: %s\n", verr.Error())
+				}
+			}
+		}
+
+		if checkTypes {
+			typeErrors := validator.ValidateTypes(tokens)
+			for _, verr := range typeErrors {
+				fmt.Fprintf(os.Stderr, "Type error: %s\n", verr.Error())
+			}
+			if len(typeErrors) > 0 {
+				hasErrors = true
+			}
+		}
+
+		if requireDescriptions {
+			descErrors := validator.ValidateDescriptions(tokens, validator.DescriptionOptions{
+				Types:          descriptionTypes,
+				Groups:         descriptionGroups,
+				SkipDeprecated: allowUndocumentedDeprecated,
+			})
+			for _, verr := range descErrors {
+				fmt.Fprintf(os.Stderr, "Description error: %s\n", verr.Error())
+			}
+			if len(descErrors) > 0 {
+				hasErrors = true
+			}
+		}
 
 		// Check for deprecated tokens (warnings)
 		deprecatedCount := 0
@@ -170,6 +230,27 @@ func run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Tokens of a file that failed would all look removed, so the
+	// comparison is only made once every file resolves
+	if baselineFlag != "" && resolvedCount < len(resolvedFiles) {
+		fmt.Fprintf(os.Stderr, "Skipping baseline comparison, since %d file(s) failed to resolve\n", len(resolvedFiles)-resolvedCount)
+	} else if baselineFlag != "" {
+		baseline, err := loadBaseline(filesystem, jsonParser, specResolver, cfg, baselineFlag, schemaVersion)
+		if err != nil {
+			return err
+		}
+		if !quiet {
+			fmt.Printf("Comparing against baseline %s...\n", baselineFlag)
+		}
+		baselineErrors := validator.ValidateBaseline(baseline, allTokens)
+		for _, verr := range baselineErrors {
+			fmt.Fprintf(os.Stderr, "Breaking change: %s\n", verr.Error())
+		}
+		if len(baselineErrors) > 0 {
+			hasErrors = true
+		}
+	}
+
 	if hasErrors {
 		return fmt.Errorf("validation failed")
 	}
@@ -183,3 +264,44 @@ func run(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// loadBaseline parses and resolves the baseline token file at spec.
+func loadBaseline(
+	filesystem fs.FileSystem,
+	jsonParser *parser.JSONParser,
+	specResolver specifier.Resolver,
+	cfg *config.Config,
+	spec string,
+	schemaVersion schema.Version,
+) ([]*token.Token, error) {
+	rf, err := specResolver.Resolve(spec)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving baseline %s: %w", spec, err)
+	}
+	data, err := filesystem.ReadFile(rf.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading baseline %s: %w", spec, err)
+	}
+	version := schemaVersion
+	if version == schema.Unknown {
+		version, err = schema.DetectVersion(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error detecting schema for baseline %s: %w", spec, err)
+		}
+	}
+	// Parse with the same config options as the current files, so group
+	// markers and prefixes produce comparable paths
+	opts := cfg.OptionsForFile(rf.Specifier)
+	opts.SkipPositions = true
+	if version != schema.Unknown {
+		opts.SchemaVersion = version
+	}
+	tokens, err := jsonParser.ParseFile(filesystem, rf.Path, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing baseline %s: %w", spec, err)
+	}
+	if err := resolver.ResolveAliases(tokens, version); err != nil {
+		return nil, fmt.Errorf("resolution error in baseline %s: %w", spec, err)
+	}
+	return tokens, nil
+}
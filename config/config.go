@@ -9,6 +9,11 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -31,6 +36,11 @@ type Config struct {
 	// GroupMarkers are token names that can be both tokens and groups (draft only).
 	GroupMarkers []string `yaml:"groupMarkers" json:"groupMarkers"`
 
+	// PrivatePrefix marks tokens as private when their name starts with it.
+	// Private tokens are left out of convert output unless --include-private
+	// is passed. Defaults to DefaultPrivatePrefix if empty.
+	PrivatePrefix string `yaml:"privatePrefix" json:"privatePrefix"`
+
 	// Schema forces a specific schema version (optional).
 	// Valid values: "draft", "v2025.10"
 	Schema string `yaml:"schema" json:"schema"`
@@ -50,6 +60,12 @@ type Config struct {
 	// Outputs specifies multiple output files to generate.
 	// When set, the convert command will generate all specified outputs in a single pass.
 	Outputs []OutputSpec `yaml:"outputs" json:"outputs"`
+
+	// FileMode and DirMode are the octal permissions for files and
+	// directories the convert command writes, e.g. "0600".
+	// Default to "0644" and "0755" if empty.
+	FileMode string `yaml:"fileMode" json:"fileMode"`
+	DirMode  string `yaml:"dirMode" json:"dirMode"`
 }
 
 // FormatsConfig contains format-specific configuration.
@@ -90,6 +106,15 @@ type OutputSpec struct {
 	//   - "path[N]": split by Nth path segment (0-indexed)
 	// Only applies when Path contains {group} template.
 	SplitBy string `yaml:"splitBy" json:"splitBy"`
+
+	// Type restricts this output to tokens of one $type (e.g. "color").
+	// Empty includes all tokens.
+	Type string `yaml:"type" json:"type"`
+
+	// EmitDTS writes a .d.ts declaration file next to a JavaScript token
+	// map, so the .js output needs no TypeScript build step.
+	// Only applies to the js format with --js-export map.
+	EmitDTS bool `yaml:"emitDts" json:"emitDts"`
 }
 
 // FileSpec represents a token file specification.
@@ -116,6 +141,15 @@ func (f *FileSpec) UnmarshalYAML(node *yaml.Node) error {
 	return node.Decode((*rawFileSpec)(f))
 }
 
+// MarshalYAML writes a FileSpec with no overrides as a plain path.
+func (f FileSpec) MarshalYAML() (any, error) {
+	if f.Prefix == "" && len(f.GroupMarkers) == 0 {
+		return f.Path, nil
+	}
+	type rawFileSpec FileSpec
+	return rawFileSpec(f), nil
+}
+
 // UnmarshalJSON handles both string and object forms for FileSpec.
 func (f *FileSpec) UnmarshalJSON(data []byte) error {
 	var s string
@@ -138,6 +172,28 @@ func Default() *Config {
 	}
 }
 
+// DefaultPrivatePrefix is the token name prefix that marks a token as private.
+const DefaultPrivatePrefix = "_"
+
+// IsPrivate reports whether a token path names a private token, i.e. its
+// last segment starts with the private prefix. A segment that is exactly
+// the prefix, or is a group marker, is not private, so a "_" group marker
+// token is still exported.
+func (c *Config) IsPrivate(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	prefix := c.PrivatePrefix
+	if prefix == "" {
+		prefix = DefaultPrivatePrefix
+	}
+	leaf := path[len(path)-1]
+	if leaf == prefix || slices.Contains(c.GroupMarkers, leaf) {
+		return false
+	}
+	return strings.HasPrefix(leaf, prefix)
+}
+
 // SchemaVersion returns the parsed schema version from the Schema field.
 // Returns schema.Unknown if the field is empty or invalid.
 func (c *Config) SchemaVersion() schema.Version {
@@ -176,6 +232,15 @@ func (c *Config) OptionsForFile(path string) parser.Options {
 	return opts
 }
 
+// ParseFileMode parses octal permissions such as "0600" or "755".
+func ParseFileMode(s string) (fs.FileMode, error) {
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("invalid mode %q: expected octal permissions, e.g. 0644", s)
+	}
+	return fs.FileMode(mode), nil
+}
+
 // FilePaths returns the list of file paths from all FileSpecs.
 func (c *Config) FilePaths() []string {
 	paths := make([]string, 0, len(c.Files))
@@ -8,6 +8,8 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/fs"
 	"path/filepath"
 	"strings"
@@ -16,6 +18,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	asimfs "bennypowers.dev/asimonim/fs"
+	"bennypowers.dev/asimonim/internal/logger"
 	"bennypowers.dev/asimonim/specifier"
 )
 
@@ -28,47 +31,81 @@ const ConfigDir = ".config"
 // configExtensions are the supported config file extensions in priority order.
 var configExtensions = []string{".yaml", ".yml", ".json"}
 
-// Load searches for .config/design-tokens.{yaml,yml,json} from rootDir.
-// Returns nil if no config found (not an error).
-func Load(filesystem asimfs.FileSystem, rootDir string) (*Config, error) {
+// FindFile returns the path of the config file Load would read from
+// rootDir, or "" if there is none.
+func FindFile(filesystem asimfs.FileSystem, rootDir string) string {
 	for _, ext := range configExtensions {
 		configPath := filepath.Join(rootDir, ConfigDir, ConfigFileName+ext)
-		if !filesystem.Exists(configPath) {
-			continue
+		if filesystem.Exists(configPath) {
+			return configPath
 		}
+	}
+	return ""
+}
 
-		data, err := filesystem.ReadFile(configPath)
-		if err != nil {
+// Load searches for .config/design-tokens.{yaml,yml,json} from rootDir.
+// Returns nil if no config found (not an error).
+func Load(filesystem asimfs.FileSystem, rootDir string) (*Config, error) {
+	configPath := FindFile(filesystem, rootDir)
+	if configPath == "" {
+		return nil, nil
+	}
+
+	data, err := filesystem.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	switch filepath.Ext(configPath) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, cfg); err != nil {
 			return nil, err
 		}
-
-		cfg := &Config{}
-		switch ext {
-		case ".yaml", ".yml":
-			if err := yaml.Unmarshal(data, cfg); err != nil {
-				return nil, err
-			}
-		case ".json":
-			if err := json.Unmarshal(data, cfg); err != nil {
-				return nil, err
-			}
+	case ".json":
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, err
 		}
-
-		return cfg, nil
 	}
 
-	return nil, nil
+	return cfg, nil
 }
 
 // LoadOrDefault returns config or defaults if not found.
+// A malformed config file falls back to defaults, and invalid values are
+// kept; both are reported as warnings. Use LoadStrict to treat them as errors.
 func LoadOrDefault(filesystem asimfs.FileSystem, rootDir string) *Config {
 	cfg, err := Load(filesystem, rootDir)
-	if err != nil || cfg == nil {
+	if err != nil {
+		logger.Warn("ignoring config in %s: %v", rootDir, err)
+		return Default()
+	}
+	if cfg == nil {
 		return Default()
 	}
+	for _, verr := range cfg.Validate() {
+		logger.Warn("config: %v", verr)
+	}
 	return cfg
 }
 
+// LoadStrict is like Load, but also validates the config.
+// Returns an error if the config file is malformed or has invalid values.
+// Returns defaults if no config is found.
+func LoadStrict(filesystem asimfs.FileSystem, rootDir string) (*Config, error) {
+	cfg, err := Load(filesystem, rootDir)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return Default(), nil
+	}
+	if errs := cfg.Validate(); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return cfg, nil
+}
+
 // ExpandFiles expands glob patterns in Files and returns absolute paths.
 // Paths starting with npm: are passed through unchanged.
 func (c *Config) ExpandFiles(filesystem asimfs.FileSystem, rootDir string) ([]string, error) {
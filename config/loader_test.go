@@ -9,6 +9,8 @@ package config
 import (
 	"testing"
 
+	"gopkg.in/yaml.v3"
+
 	"bennypowers.dev/asimonim/internal/mapfs"
 	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/specifier"
@@ -183,6 +185,32 @@ func TestConfig_OptionsForFile(t *testing.T) {
 	})
 }
 
+func TestConfig_IsPrivate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		path []string
+		want bool
+	}{
+		{"default prefix", Config{}, []string{"color", "_base"}, true},
+		{"public token", Config{}, []string{"color", "primary"}, false},
+		{"private group is not enough", Config{}, []string{"_internal", "primary"}, false},
+		{"bare prefix is a group marker", Config{}, []string{"color", "_"}, false},
+		{"configured group marker", Config{GroupMarkers: []string{"_default"}}, []string{"color", "_default"}, false},
+		{"custom prefix", Config{PrivatePrefix: "internal-"}, []string{"color", "internal-base"}, true},
+		{"custom prefix ignores underscore", Config{PrivatePrefix: "internal-"}, []string{"color", "_base"}, false},
+		{"empty path", Config{}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.IsPrivate(tt.path); got != tt.want {
+				t.Errorf("IsPrivate(%v) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestConfig_FilePaths(t *testing.T) {
 	cfg := &Config{
 		Files: []FileSpec{
@@ -601,3 +629,26 @@ func TestLoad_WithResolvers(t *testing.T) {
 		t.Errorf("expected file path './overrides.json', got %q", cfg.Files[0].Path)
 	}
 }
+
+func TestFileSpec_MarshalYAML(t *testing.T) {
+	files := []FileSpec{
+		{Path: "./tokens.json"},
+		{Path: "./brand.json", Prefix: "brand"},
+	}
+	data, err := yaml.Marshal(files)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "- ./tokens.json\n- path: ./brand.json\n  prefix: brand\n  groupMarkers: []\n"
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+
+	var roundTrip []FileSpec
+	if err := yaml.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if roundTrip[0].Path != "./tokens.json" || roundTrip[1].Prefix != "brand" {
+		t.Errorf("unexpected round trip: %+v", roundTrip)
+	}
+}
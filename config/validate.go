@@ -0,0 +1,89 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"bennypowers.dev/asimonim/convert"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/specifier"
+)
+
+// splitByPathPattern matches path[N] split-by values.
+var splitByPathPattern = regexp.MustCompile(`^path\[\d+\]$`)
+
+// Validate checks the config for values that would otherwise be silently
+// ignored or fall back to defaults, such as a misspelled schema version.
+// Returns one error per problem found, or nil if the config is valid.
+func (c *Config) Validate() []error {
+	var errs []error
+
+	if c.Schema != "" {
+		if _, err := schema.FromString(c.Schema); err != nil {
+			errs = append(errs, fmt.Errorf("schema: %w", err))
+		}
+	}
+
+	if c.CDN != "" {
+		if _, err := specifier.ParseCDN(c.CDN); err != nil {
+			errs = append(errs, fmt.Errorf("cdn: %w", err))
+		}
+	}
+
+	if c.FileMode != "" {
+		if _, err := ParseFileMode(c.FileMode); err != nil {
+			errs = append(errs, fmt.Errorf("fileMode: %w", err))
+		}
+	}
+
+	if c.DirMode != "" {
+		if _, err := ParseFileMode(c.DirMode); err != nil {
+			errs = append(errs, fmt.Errorf("dirMode: %w", err))
+		}
+	}
+
+	for i, out := range c.Outputs {
+		if out.Path == "" {
+			errs = append(errs, fmt.Errorf("outputs[%d]: path is required", i))
+		}
+		if _, err := convert.ParseFormat(out.Format); err != nil {
+			errs = append(errs, fmt.Errorf("outputs[%d]: %w", i, err))
+		}
+		if format, err := convert.ParseFormat(out.Format); err == nil && format == convert.FormatIOSAssets && strings.Contains(out.Path, "{group}") {
+			errs = append(errs, fmt.Errorf("outputs[%d]: ios-assets does not support split outputs", i))
+		}
+		if !validSplitBy(out.SplitBy) {
+			errs = append(errs, fmt.Errorf(
+				"outputs[%d]: unknown splitBy %q (valid: topLevel, type, path[N])",
+				i, out.SplitBy,
+			))
+		}
+		if out.EmitDTS {
+			if format, err := convert.ParseFormat(out.Format); err == nil && format != convert.FormatJS {
+				errs = append(errs, fmt.Errorf("outputs[%d]: emitDts requires the js format, got %s", i, out.Format))
+			}
+			if strings.Contains(out.Path, "{group}") {
+				errs = append(errs, fmt.Errorf("outputs[%d]: emitDts does not support split outputs", i))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validSplitBy reports whether s is a recognized split strategy.
+func validSplitBy(s string) bool {
+	switch s {
+	case "", "topLevel", "type":
+		return true
+	default:
+		return splitByPathPattern.MatchString(s)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/testutil"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr []string
+	}{
+		{
+			name: "empty config",
+			cfg:  Config{},
+		},
+		{
+			name: "valid config",
+			cfg: Config{
+				Schema:   "v2025.10",
+				CDN:      "jsdelivr",
+				FileMode: "0600",
+				DirMode:  "750",
+				Outputs: []OutputSpec{
+					{Format: "scss", Path: "tokens.scss"},
+					{Format: "js", Path: "js/{group}.ts", SplitBy: "type"},
+					{Format: "css", Path: "css/{group}.css", SplitBy: "path[1]"},
+					{Format: "js", Path: "tokens.js", EmitDTS: true},
+				},
+			},
+		},
+		{
+			name:    "misspelled schema",
+			cfg:     Config{Schema: "drafft"},
+			wantErr: []string{"schema:"},
+		},
+		{
+			name:    "unknown cdn",
+			cfg:     Config{CDN: "unpkgg"},
+			wantErr: []string{"cdn:"},
+		},
+		{
+			name:    "invalid modes",
+			cfg:     Config{FileMode: "rw-r--r--", DirMode: "01777"},
+			wantErr: []string{"fileMode:", "dirMode:"},
+		},
+		{
+			name:    "unknown output format",
+			cfg:     Config{Outputs: []OutputSpec{{Format: "sccs", Path: "tokens.scss"}}},
+			wantErr: []string{"outputs[0]: unknown format"},
+		},
+		{
+			name:    "missing output path",
+			cfg:     Config{Outputs: []OutputSpec{{Format: "css"}}},
+			wantErr: []string{"outputs[0]: path is required"},
+		},
+		{
+			name:    "invalid splitBy",
+			cfg:     Config{Outputs: []OutputSpec{{Format: "css", Path: "{group}.css", SplitBy: "path[x]"}}},
+			wantErr: []string{`outputs[0]: unknown splitBy "path[x]"`},
+		},
+		{
+			name:    "emitDts on a non-js output",
+			cfg:     Config{Outputs: []OutputSpec{{Format: "css", Path: "tokens.css", EmitDTS: true}}},
+			wantErr: []string{"outputs[0]: emitDts requires the js format"},
+		},
+		{
+			name:    "emitDts on a split output",
+			cfg:     Config{Outputs: []OutputSpec{{Format: "js", Path: "js/{group}.js", EmitDTS: true}}},
+			wantErr: []string{"outputs[0]: emitDts does not support split outputs"},
+		},
+		{
+			name:    "ios-assets on a split output",
+			cfg:     Config{Outputs: []OutputSpec{{Format: "ios-assets", Path: "{group}.xcassets"}}},
+			wantErr: []string{"outputs[0]: ios-assets does not support split outputs"},
+		},
+		{
+			name: "multiple problems",
+			cfg: Config{
+				Schema:  "v2026",
+				Outputs: []OutputSpec{{Format: "css", Path: "a.css"}, {Format: "nope", Path: "b"}},
+			},
+			wantErr: []string{"schema:", "outputs[1]: unknown format"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.cfg.Validate()
+			if len(errs) != len(tt.wantErr) {
+				t.Fatalf("Validate() returned %d errors, want %d: %v", len(errs), len(tt.wantErr), errs)
+			}
+			for i, want := range tt.wantErr {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("error[%d] = %q, want it to contain %q", i, errs[i].Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadStrict_Invalid(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/config/invalid", "/project")
+
+	_, err := LoadStrict(mfs, "/project")
+	if err == nil {
+		t.Fatal("expected error for invalid config")
+	}
+	for _, want := range []string{"drafft", "unpkgg", "sccs", "path[x]"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got: %v", want, err)
+		}
+	}
+}
+
+func TestLoadStrict_Valid(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/config/simple", "/project")
+
+	cfg, err := LoadStrict(mfs, "/project")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Prefix != "rh" {
+		t.Errorf("expected prefix 'rh', got %q", cfg.Prefix)
+	}
+}
+
+func TestLoadStrict_NotFound(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/draft/simple", "/project")
+
+	cfg, err := LoadStrict(mfs, "/project")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg == nil {
+		t.Fatal("expected default config, got nil")
+	}
+}
+
+func TestLoadOrDefault_InvalidKeepsValues(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/config/invalid", "/project")
+
+	cfg := LoadOrDefault(mfs, "/project")
+	if cfg.Prefix != "rh" {
+		t.Errorf("expected prefix 'rh', got %q", cfg.Prefix)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	asimfs "bennypowers.dev/asimonim/fs"
+	"bennypowers.dev/asimonim/internal/logger"
+)
+
+// defaultWatchInterval is how often Watch polls the config file of a
+// FileSystem that isn't the OS file system.
+const defaultWatchInterval = time.Second
+
+// Watch calls onChange with the reloaded config whenever the config file
+// under rootDir is created, modified, or removed. Removing the config file
+// reports the default config. Watch blocks until ctx is cancelled, so
+// embedders typically run it in its own goroutine.
+//
+// On the OS file system, or a FileSystem wrapping it such as the one
+// returned by fs.WithURLs, Watch is notified of changes by the operating
+// system. Other FileSystem implementations, which it can't be notified
+// about, are polled every second.
+func Watch(ctx context.Context, filesystem asimfs.FileSystem, rootDir string, onChange func(*Config)) {
+	if asimfs.OSBacked(filesystem) {
+		err := watchEvents(ctx, filesystem, rootDir, nil, onChange)
+		if err == nil {
+			return
+		}
+		logger.Debug("config: polling %s for changes: %v", rootDir, err)
+	}
+	poll(ctx, filesystem, rootDir, defaultWatchInterval, nil, onChange)
+}
+
+// watcher calls onChange when the config under rootDir differs from the
+// last one it saw.
+type watcher struct {
+	filesystem asimfs.FileSystem
+	rootDir    string
+	onChange   func(*Config)
+	last       []byte
+}
+
+func newWatcher(filesystem asimfs.FileSystem, rootDir string, onChange func(*Config)) *watcher {
+	return &watcher{
+		filesystem: filesystem,
+		rootDir:    rootDir,
+		onChange:   onChange,
+		last:       snapshotConfig(filesystem, rootDir),
+	}
+}
+
+func (w *watcher) check() {
+	current := snapshotConfig(w.filesystem, w.rootDir)
+	if bytes.Equal(current, w.last) {
+		return
+	}
+	w.last = current
+	w.onChange(LoadOrDefault(w.filesystem, w.rootDir))
+}
+
+// watchEvents watches rootDir and its config directory for file system
+// events. It returns an error without calling ready if the watch can't be
+// set up, and nil once ctx is cancelled. ready, if not nil, is called
+// once the initial config has been read.
+func watchEvents(ctx context.Context, filesystem asimfs.FileSystem, rootDir string, ready func(), onChange func(*Config)) error {
+	events, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer events.Close()
+
+	// The root is watched so that a config directory created later is
+	// noticed; the config directory itself may not exist yet.
+	configDir := filepath.Join(rootDir, ConfigDir)
+	if err := events.Add(rootDir); err != nil {
+		return err
+	}
+	_ = events.Add(configDir)
+
+	w := newWatcher(filesystem, rootDir, onChange)
+	if ready != nil {
+		ready()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events.Events:
+			if !ok {
+				return nil
+			}
+			if event.Name == configDir && event.Has(fsnotify.Create) {
+				_ = events.Add(configDir)
+			}
+			if event.Name == configDir || filepath.Dir(event.Name) == configDir {
+				w.check()
+			}
+		case err, ok := <-events.Errors:
+			if !ok {
+				return nil
+			}
+			logger.Debug("config: watching %s: %v", rootDir, err)
+		}
+	}
+}
+
+// poll compares the config under rootDir with the last one it saw every
+// interval. ready, if not nil, is called once the initial config has
+// been read.
+func poll(ctx context.Context, filesystem asimfs.FileSystem, rootDir string, interval time.Duration, ready func(), onChange func(*Config)) {
+	w := newWatcher(filesystem, rootDir, onChange)
+	if ready != nil {
+		ready()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// snapshotConfig returns the path and contents of the config file that Load
+// would read, or nil if there is none.
+func snapshotConfig(filesystem asimfs.FileSystem, rootDir string) []byte {
+	for _, ext := range configExtensions {
+		configPath := filepath.Join(rootDir, ConfigDir, ConfigFileName+ext)
+		if !filesystem.Exists(configPath) {
+			continue
+		}
+		data, err := filesystem.ReadFile(configPath)
+		if err != nil {
+			return nil
+		}
+		return append([]byte(configPath+"\x00"), data...)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	asimfs "bennypowers.dev/asimonim/fs"
+	"bennypowers.dev/asimonim/internal/mapfs"
+)
+
+func TestWatch_ReloadsOnChange(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/project/.config/design-tokens.yaml", "prefix: old\n", 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan *Config, 4)
+	ready := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		poll(ctx, mfs, "/project", 5*time.Millisecond, func() { close(ready) }, func(cfg *Config) {
+			changes <- cfg
+		})
+	}()
+
+	<-ready
+	if err := mfs.WriteFile("/project/.config/design-tokens.yaml", []byte("prefix: new\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	select {
+	case cfg := <-changes:
+		if cfg.Prefix != "new" {
+			t.Errorf("expected reloaded prefix 'new', got %q", cfg.Prefix)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for config change")
+	}
+
+	if err := mfs.Remove("/project/.config/design-tokens.yaml"); err != nil {
+		t.Fatalf("failed to remove config: %v", err)
+	}
+
+	select {
+	case cfg := <-changes:
+		if cfg.Prefix != "" {
+			t.Errorf("expected default config after removal, got prefix %q", cfg.Prefix)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for config removal")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not return after context cancellation")
+	}
+}
+
+func TestWatch_NoChangeNoCallback(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/project/.config/design-tokens.yaml", "prefix: rh\n", 0644)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	called := false
+	poll(ctx, mfs, "/project", 5*time.Millisecond, nil, func(*Config) {
+		called = true
+	})
+
+	if called {
+		t.Error("expected no callback when config is unchanged")
+	}
+}
+
+func TestWatchEvents_ReloadsOnChange(t *testing.T) {
+	root := t.TempDir()
+	configDir := filepath.Join(root, ConfigDir)
+	configPath := filepath.Join(configDir, ConfigFileName+".yaml")
+	osfs := asimfs.NewOSFileSystem()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan *Config, 4)
+	ready := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		errs <- watchEvents(ctx, osfs, root, func() { close(ready) }, func(cfg *Config) {
+			changes <- cfg
+		})
+	}()
+
+	select {
+	case <-ready:
+	case err := <-errs:
+		t.Fatalf("watchEvents() error = %v", err)
+	}
+
+	next := func(what string) *Config {
+		t.Helper()
+		select {
+		case cfg := <-changes:
+			return cfg
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", what)
+			return nil
+		}
+	}
+
+	// The config directory doesn't exist when the watch starts
+	if err := os.Mkdir(configDir, 0o755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("prefix: new\n"), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if cfg := next("config creation"); cfg.Prefix != "new" {
+		t.Errorf("expected reloaded prefix 'new', got %q", cfg.Prefix)
+	}
+
+	if err := os.Remove(configPath); err != nil {
+		t.Fatalf("failed to remove config: %v", err)
+	}
+	if cfg := next("config removal"); cfg.Prefix != "" {
+		t.Errorf("expected default config after removal, got prefix %q", cfg.Prefix)
+	}
+
+	cancel()
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Errorf("watchEvents() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watchEvents did not return after context cancellation")
+	}
+}
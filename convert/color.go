@@ -0,0 +1,170 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// colorFunctionPattern matches CSS color functions whose color space has a
+// DTCG equivalent, capturing the function name and its arguments.
+var colorFunctionPattern = regexp.MustCompile(`(?i)^\s*(hsla?|hwb|lab|lch|oklab|oklch)\(\s*(.*?)\s*\)\s*$`)
+
+// colorChannel describes how to read one component of a color function.
+type colorChannel struct {
+	// percentScale is the component value that 100% corresponds to.
+	percentScale float64
+	// hue marks an angle channel, which accepts deg, rad, grad, and turn units.
+	hue bool
+}
+
+var (
+	hueChannel        = colorChannel{hue: true}
+	percentChannel    = colorChannel{percentScale: 100}
+	labAChannel       = colorChannel{percentScale: 125}
+	lchChromaChannel  = colorChannel{percentScale: 150}
+	oklabLChannel     = colorChannel{percentScale: 1}
+	oklabABChannel    = colorChannel{percentScale: 0.4}
+	fractionChannel   = colorChannel{percentScale: 1}
+	colorFunctionArgs = map[string][3]colorChannel{
+		"hsl":   {hueChannel, percentChannel, percentChannel},
+		"hwb":   {hueChannel, percentChannel, percentChannel},
+		"lab":   {percentChannel, labAChannel, labAChannel},
+		"lch":   {percentChannel, lchChromaChannel, hueChannel},
+		"oklab": {oklabLChannel, oklabABChannel, oklabABChannel},
+		"oklch": {oklabLChannel, oklabABChannel, hueChannel},
+	}
+	// fractionChannels are the components of color(), e.g.
+	// color(display-p3 1 0.5 0), where 100% is 1.
+	fractionChannels = [3]colorChannel{fractionChannel, fractionChannel, fractionChannel}
+)
+
+// parseColorFunction reads an hsl(), hwb(), lab(), lch(), oklab(), or
+// oklch() color string into its DTCG color space, components, and alpha.
+// Both the legacy comma syntax and the modern space/slash syntax are
+// accepted. The "none" keyword is kept as a string component.
+func parseColorFunction(colorStr string) (colorSpace string, components []any, alpha float64, ok bool) {
+	m := colorFunctionPattern.FindStringSubmatch(colorStr)
+	if m == nil {
+		return "", nil, 0, false
+	}
+	colorSpace = strings.ToLower(m[1])
+	if colorSpace == "hsla" {
+		colorSpace = "hsl"
+	}
+
+	components, alpha, ok = parseColorArgs(m[2], colorFunctionArgs[colorSpace])
+	if !ok {
+		return "", nil, 0, false
+	}
+	return colorSpace, components, alpha, true
+}
+
+// parseColorArgs reads the three components and optional alpha of a
+// color function's arguments, in either the legacy comma syntax or the
+// modern space/slash syntax. Alpha is 1 when it is left out.
+func parseColorArgs(args string, channels [3]colorChannel) (components []any, alpha float64, ok bool) {
+	var alphaArg string
+	if before, after, found := strings.Cut(args, "/"); found {
+		args, alphaArg = before, strings.TrimSpace(after)
+	}
+	var fields []string
+	if strings.Contains(args, ",") {
+		for f := range strings.SplitSeq(args, ",") {
+			fields = append(fields, strings.TrimSpace(f))
+		}
+	} else {
+		fields = strings.Fields(args)
+	}
+	if len(fields) == 4 && alphaArg == "" {
+		fields, alphaArg = fields[:3], fields[3]
+	}
+	if len(fields) != 3 {
+		return nil, 0, false
+	}
+
+	components = make([]any, 3)
+	for i, field := range fields {
+		v, err := parseColorChannel(field, channels[i])
+		if err != nil {
+			return nil, 0, false
+		}
+		components[i] = v
+	}
+
+	alpha = 1
+	if alphaArg != "" {
+		v, err := parseColorChannel(alphaArg, fractionChannel)
+		if err != nil {
+			return nil, 0, false
+		}
+		if f, isNum := v.(float64); isNum {
+			alpha = f
+		} else {
+			alpha = 0
+		}
+	}
+	return components, alpha, true
+}
+
+// parseColorChannel parses one color function argument, returning either a
+// float64 or the string "none".
+func parseColorChannel(s string, ch colorChannel) (any, error) {
+	s = strings.ToLower(s)
+	if s == "none" {
+		return s, nil
+	}
+	if num, found := strings.CutSuffix(s, "%"); found {
+		v, err := strconv.ParseFloat(num, 64)
+		if err != nil || ch.hue {
+			return nil, fmt.Errorf("invalid color channel %q", s)
+		}
+		return roundChannel(v / 100 * ch.percentScale), nil
+	}
+	if ch.hue {
+		return parseHue(s)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color channel %q", s)
+	}
+	return v, nil
+}
+
+// parseHue parses a CSS angle into degrees.
+func parseHue(s string) (any, error) {
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"grad", 360.0 / 400},
+		{"turn", 360},
+		{"deg", 1},
+		{"rad", 180 / math.Pi},
+	}
+	scale := 1.0
+	for _, u := range units {
+		if num, found := strings.CutSuffix(s, u.suffix); found {
+			s, scale = num, u.scale
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hue %q", s)
+	}
+	return roundChannel(v * scale), nil
+}
+
+// roundChannel trims floating point noise introduced by unit scaling.
+func roundChannel(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
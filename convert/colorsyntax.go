@@ -0,0 +1,45 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColorSyntax controls the CSS syntax of structured colors in css and
+// scss output.
+type ColorSyntax string
+
+const (
+	// ColorSyntaxModern writes colors as hex where possible, otherwise with
+	// the space-separated CSS Color 4 functions, such as color(srgb 1 0 0 / 0.5).
+	ColorSyntaxModern ColorSyntax = "modern"
+
+	// ColorSyntaxLegacy writes sRGB and hsl colors with the comma-separated
+	// rgb(), rgba(), hsl() and hsla() functions that older tools accept.
+	// Colors in other spaces may be outside sRGB and keep the modern syntax.
+	ColorSyntaxLegacy ColorSyntax = "legacy"
+)
+
+// ValidColorSyntaxes returns the names of all color syntaxes.
+func ValidColorSyntaxes() []string {
+	return []string{string(ColorSyntaxModern), string(ColorSyntaxLegacy)}
+}
+
+// ParseColorSyntax parses a color syntax name. An empty string selects
+// ColorSyntaxModern.
+func ParseColorSyntax(s string) (ColorSyntax, error) {
+	switch syntax := ColorSyntax(strings.ToLower(s)); syntax {
+	case "":
+		return ColorSyntaxModern, nil
+	case ColorSyntaxModern, ColorSyntaxLegacy:
+		return syntax, nil
+	default:
+		return "", fmt.Errorf("unknown color syntax: %s (valid: %s)", s, strings.Join(ValidColorSyntaxes(), ", "))
+	}
+}
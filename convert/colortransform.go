@@ -0,0 +1,188 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mazznoer/csscolorparser"
+
+	"bennypowers.dev/asimonim/parser/common"
+	"bennypowers.dev/asimonim/token"
+)
+
+// ColorTransform controls what happens to colors outside sRGB, for
+// targets that can only display sRGB.
+type ColorTransform string
+
+const (
+	// ColorTransformNone leaves colors in their authored color space.
+	ColorTransformNone ColorTransform = "none"
+
+	// ColorTransformSRGB gamut-maps wide-gamut colors to sRGB, clipping
+	// colors sRGB can't display to the nearest displayable value.
+	ColorTransformSRGB ColorTransform = "srgb"
+
+	// ColorTransformSRGBOnly drops wide-gamut colors from the output.
+	ColorTransformSRGBOnly ColorTransform = "srgb-only"
+)
+
+// ValidColorTransforms returns the names of all color transforms.
+func ValidColorTransforms() []string {
+	return []string{string(ColorTransformNone), string(ColorTransformSRGB), string(ColorTransformSRGBOnly)}
+}
+
+// ParseColorTransform parses a color transform name. An empty string
+// selects ColorTransformNone.
+func ParseColorTransform(s string) (ColorTransform, error) {
+	switch transform := ColorTransform(strings.ToLower(s)); transform {
+	case "":
+		return ColorTransformNone, nil
+	case ColorTransformNone, ColorTransformSRGB, ColorTransformSRGBOnly:
+		return transform, nil
+	default:
+		return "", fmt.Errorf("unknown color transform: %s (valid: %s)", s, strings.Join(ValidColorTransforms(), ", "))
+	}
+}
+
+// ColorChange describes a color token that TransformColors converted or
+// dropped.
+type ColorChange struct {
+	// Token is the token as it was before the transform.
+	Token *token.Token
+	// ColorSpace is the token's original color space.
+	ColorSpace string
+	// Dropped is true when the token was removed rather than converted.
+	Dropped bool
+}
+
+// srgbColorSpaces are the color spaces that describe sRGB colors.
+var srgbColorSpaces = map[string]bool{"srgb": true, "hsl": true, "hwb": true}
+
+// TransformColors applies transform to the color tokens outside sRGB,
+// returning the tokens to keep and a record of each token it changed.
+// Converted tokens are copies; the input tokens are not modified.
+// Colors in spaces with no conversion to sRGB, such as a98-rgb, are
+// dropped even when converting. Aliases keep their reference and only
+// have their resolved value converted.
+func TransformColors(tokens []*token.Token, transform ColorTransform) ([]*token.Token, []ColorChange) {
+	if transform == ColorTransformNone || transform == "" {
+		return tokens, nil
+	}
+
+	kept := make([]*token.Token, 0, len(tokens))
+	var changes []ColorChange
+	for _, tok := range tokens {
+		if tok.Type != token.TypeColor {
+			kept = append(kept, tok)
+			continue
+		}
+		value := tok.ResolvedValue
+		if value == nil {
+			value = tok.RawValue
+		}
+		color, ok := wideGamutColor(value)
+		if !ok {
+			kept = append(kept, tok)
+			continue
+		}
+
+		change := ColorChange{Token: tok, ColorSpace: color.ColorSpace}
+		converted, ok := srgbValue(color, value)
+		if transform == ColorTransformSRGBOnly || !ok {
+			change.Dropped = true
+			changes = append(changes, change)
+			continue
+		}
+
+		clone := tok.WithPrefix(tok.Prefix)
+		clone.ResolvedValue = converted
+		if len(tok.ResolutionChain) == 0 {
+			clone.RawValue = converted
+			if s, isString := converted.(string); isString {
+				clone.Value = s
+			}
+		}
+		kept = append(kept, clone)
+		changes = append(changes, change)
+	}
+	return kept, changes
+}
+
+// cssColorFunctionPattern matches the CSS color() function, capturing the
+// color space and its arguments.
+var cssColorFunctionPattern = regexp.MustCompile(`(?i)^\s*color\(\s*([a-z0-9-]+)\s+(.*?)\s*\)\s*$`)
+
+// wideGamutColor reads a structured or string color value, returning it
+// if its color space is not sRGB.
+func wideGamutColor(value any) (*common.ObjectColorValue, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		space, _ := v["colorSpace"].(string)
+		components, _ := v["components"].([]any)
+		if space == "" || srgbColorSpaces[space] {
+			return nil, false
+		}
+		color := &common.ObjectColorValue{ColorSpace: space, Components: components}
+		if alpha, ok := v["alpha"].(float64); ok {
+			color.Alpha = &alpha
+		}
+		return color, true
+	case string:
+		if space, components, alpha, ok := parseColorFunction(v); ok {
+			if srgbColorSpaces[space] {
+				return nil, false
+			}
+			return &common.ObjectColorValue{ColorSpace: space, Components: components, Alpha: &alpha}, true
+		}
+		return parseCSSColorFunction(v)
+	}
+	return nil, false
+}
+
+// parseCSSColorFunction reads a color(<space> c1 c2 c3 [/ alpha]) string.
+// Percentages are read as fractions of 1.
+func parseCSSColorFunction(s string) (*common.ObjectColorValue, bool) {
+	m := cssColorFunctionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, false
+	}
+	space := strings.ToLower(m[1])
+	if srgbColorSpaces[space] {
+		return nil, false
+	}
+	components, alpha, ok := parseColorArgs(m[2], fractionChannels)
+	if !ok {
+		return nil, false
+	}
+	return &common.ObjectColorValue{ColorSpace: space, Components: components, Alpha: &alpha}, true
+}
+
+// srgbValue converts color to sRGB, in the same form as the original
+// value: a structured color for structured values, or hex for strings.
+func srgbValue(color *common.ObjectColorValue, original any) (any, bool) {
+	r, g, b, ok := color.SRGB()
+	if !ok {
+		return nil, false
+	}
+	alpha := 1.0
+	if color.Alpha != nil {
+		alpha = *color.Alpha
+	}
+	hex := csscolorparser.Color{R: r, G: g, B: b, A: alpha}.HexString()
+	if _, isString := original.(string); isString {
+		return hex, true
+	}
+	return map[string]any{
+		"colorSpace": "srgb",
+		"components": []any{roundChannel(r), roundChannel(g), roundChannel(b)},
+		"alpha":      alpha,
+		"hex":        hex,
+	}, true
+}
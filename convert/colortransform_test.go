@@ -0,0 +1,163 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert_test
+
+import (
+	"reflect"
+	"testing"
+
+	"bennypowers.dev/asimonim/convert"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/token"
+)
+
+func colorTokens() []*token.Token {
+	p3 := map[string]any{"colorSpace": "display-p3", "components": []any{1.0, 0.0, 0.0}, "alpha": 1.0}
+	return []*token.Token{
+		{Name: "srgb", Path: []string{"srgb"}, Type: token.TypeColor, SchemaVersion: schema.V2025_10,
+			RawValue: map[string]any{"colorSpace": "srgb", "components": []any{1.0, 0.0, 0.0}}},
+		{Name: "p3", Path: []string{"p3"}, Type: token.TypeColor, SchemaVersion: schema.V2025_10,
+			RawValue: p3, ResolvedValue: p3, IsResolved: true},
+		{Name: "alias", Path: []string{"alias"}, Type: token.TypeColor, SchemaVersion: schema.V2025_10,
+			RawValue: map[string]any{"$ref": "#/p3"}, ResolvedValue: p3, IsResolved: true, ResolutionChain: []string{"p3"}},
+		{Name: "a98", Path: []string{"a98"}, Type: token.TypeColor, SchemaVersion: schema.V2025_10,
+			RawValue: map[string]any{"colorSpace": "a98-rgb", "components": []any{1.0, 0.0, 0.0}}},
+		{Name: "oklch", Path: []string{"oklch"}, Type: token.TypeColor, SchemaVersion: schema.Draft,
+			Value: "oklch(62.8% 0.2577 29.23)", RawValue: "oklch(62.8% 0.2577 29.23)"},
+		{Name: "hsl", Path: []string{"hsl"}, Type: token.TypeColor, SchemaVersion: schema.Draft,
+			Value: "hsl(0 100% 50%)", RawValue: "hsl(0 100% 50%)"},
+		{Name: "space", Path: []string{"space"}, Type: token.TypeDimension, SchemaVersion: schema.Draft,
+			Value: "4px", RawValue: "4px"},
+	}
+}
+
+func names(tokens []*token.Token) []string {
+	result := make([]string, len(tokens))
+	for i, tok := range tokens {
+		result[i] = tok.Name
+	}
+	return result
+}
+
+func TestParseColorTransform(t *testing.T) {
+	for input, want := range map[string]convert.ColorTransform{
+		"":          convert.ColorTransformNone,
+		"none":      convert.ColorTransformNone,
+		"SRGB":      convert.ColorTransformSRGB,
+		"srgb-only": convert.ColorTransformSRGBOnly,
+	} {
+		got, err := convert.ParseColorTransform(input)
+		if err != nil || got != want {
+			t.Errorf("ParseColorTransform(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := convert.ParseColorTransform("p3"); err == nil {
+		t.Error("expected error for unknown color transform")
+	}
+}
+
+func TestParseColorSyntax(t *testing.T) {
+	for input, want := range map[string]convert.ColorSyntax{
+		"":       convert.ColorSyntaxModern,
+		"modern": convert.ColorSyntaxModern,
+		"Legacy": convert.ColorSyntaxLegacy,
+	} {
+		got, err := convert.ParseColorSyntax(input)
+		if err != nil || got != want {
+			t.Errorf("ParseColorSyntax(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := convert.ParseColorSyntax("rgb"); err == nil {
+		t.Error("expected error for unknown color syntax")
+	}
+}
+
+func TestTransformColors_None(t *testing.T) {
+	tokens := colorTokens()
+	kept, changes := convert.TransformColors(tokens, convert.ColorTransformNone)
+	if len(kept) != len(tokens) || changes != nil {
+		t.Errorf("expected no changes, got %v, %v", names(kept), changes)
+	}
+}
+
+func TestTransformColors_SRGBOnly(t *testing.T) {
+	kept, changes := convert.TransformColors(colorTokens(), convert.ColorTransformSRGBOnly)
+
+	if got, want := names(kept), []string{"srgb", "hsl", "space"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("kept %v, want %v", got, want)
+	}
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes, got %d", len(changes))
+	}
+	for _, c := range changes {
+		if !c.Dropped {
+			t.Errorf("expected %s to be dropped", c.Token.Name)
+		}
+	}
+	if changes[0].ColorSpace != "display-p3" || changes[3].ColorSpace != "oklch" {
+		t.Errorf("unexpected color spaces: %s, %s", changes[0].ColorSpace, changes[3].ColorSpace)
+	}
+}
+
+func TestTransformColors_SRGB(t *testing.T) {
+	tokens := colorTokens()
+	kept, changes := convert.TransformColors(tokens, convert.ColorTransformSRGB)
+
+	if got, want := names(kept), []string{"srgb", "p3", "alias", "oklch", "hsl", "space"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("kept %v, want %v", got, want)
+	}
+	dropped := 0
+	for _, c := range changes {
+		if c.Dropped {
+			dropped++
+			if c.Token.Name != "a98" {
+				t.Errorf("unexpected drop of %s", c.Token.Name)
+			}
+		}
+	}
+	if len(changes) != 4 || dropped != 1 {
+		t.Errorf("expected 3 conversions and 1 drop, got %d changes, %d dropped", len(changes), dropped)
+	}
+
+	p3 := kept[1]
+	want := map[string]any{"colorSpace": "srgb", "components": []any{1.0, 0.0, 0.0}, "alpha": 1.0, "hex": "#ff0000"}
+	if !reflect.DeepEqual(p3.RawValue, want) || !reflect.DeepEqual(p3.ResolvedValue, want) {
+		t.Errorf("p3 = %v, want %v", p3.RawValue, want)
+	}
+	if tokens[1].RawValue.(map[string]any)["colorSpace"] != "display-p3" {
+		t.Error("expected the input token to be left alone")
+	}
+
+	alias := kept[2]
+	if !reflect.DeepEqual(alias.RawValue, map[string]any{"$ref": "#/p3"}) {
+		t.Errorf("expected alias to keep its reference, got %v", alias.RawValue)
+	}
+	if !reflect.DeepEqual(alias.ResolvedValue, want) {
+		t.Errorf("alias resolved = %v, want %v", alias.ResolvedValue, want)
+	}
+
+	oklch := kept[3]
+	if oklch.Value != "#ff0000" || oklch.RawValue != "#ff0000" {
+		t.Errorf("oklch = %q, want #ff0000", oklch.Value)
+	}
+}
+
+func TestTransformColors_CSSColorFunction(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "p3", Path: []string{"p3"}, Type: token.TypeColor, SchemaVersion: schema.Draft,
+			Value: "color(display-p3 0 0 1 / 50%)", RawValue: "color(display-p3 0 0 1 / 50%)"},
+		{Name: "srgb", Path: []string{"srgb"}, Type: token.TypeColor, SchemaVersion: schema.Draft,
+			Value: "color(srgb 0 0 1)", RawValue: "color(srgb 0 0 1)"},
+	}
+	kept, changes := convert.TransformColors(tokens, convert.ColorTransformSRGB)
+	if len(kept) != 2 || len(changes) != 1 {
+		t.Fatalf("expected 2 kept and 1 change, got %v, %v", names(kept), changes)
+	}
+	if kept[0].Value != "#0000ff80" {
+		t.Errorf("p3 = %q, want #0000ff80", kept[0].Value)
+	}
+}
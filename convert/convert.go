@@ -10,10 +10,12 @@ package convert
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/mazznoer/csscolorparser"
 
+	"bennypowers.dev/asimonim/convert/formatter"
 	"bennypowers.dev/asimonim/parser/common"
 	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/token"
@@ -33,15 +35,43 @@ type Options struct {
 	// instead of nested groups.
 	Flatten bool
 
+	// FlattenDepth collapses the innermost N group levels into
+	// delimiter-separated keys while keeping outer groups nested.
+	// For example, with depth 1, color.brand.primary is written as
+	// {"color": {"brand-primary": ...}}. Zero keeps the full nesting,
+	// and a depth at least as deep as the token paths is fully flat.
+	// Ignored when Flatten is set.
+	FlattenDepth int
+
 	// Delimiter is the separator for flattened keys (default "-").
 	Delimiter string
 
+	// RefStyle controls how references are written in DTCG and YAML
+	// output, independent of OutputSchema. Defaults to the output
+	// schema's own reference syntax.
+	RefStyle RefStyle
+
+	// StripExtensions omits $extensions from serialized tokens.
+	StripExtensions bool
+
+	// StripDescriptions omits $description from serialized tokens.
+	StripDescriptions bool
+
+	// HoistTypes writes a $type shared by every token in a group once on
+	// the group instead of on each token, relying on the parser's group
+	// type inheritance. Ignored when Flatten is set.
+	HoistTypes bool
+
 	// Format specifies the output format (default FormatDTCG).
 	Format Format
 
 	// Prefix is added to output variable names.
 	Prefix string
 
+	// NameTransform overrides how formatters build token names.
+	// See formatter.Options.NameTransform for the contract.
+	NameTransform func(path []string, tok *token.Token) string
+
 	// Header is the content to prepend to the output.
 	// Formatters wrap this in appropriate comment syntax.
 	Header string
@@ -54,8 +84,36 @@ type Options struct {
 	// Valid values: "" (plain CSS, default), "lit" (Lit css tagged template)
 	CSSModule string
 
+	// CustomMediaGroup is the dot-separated token group emitted by
+	// FormatCustomMedia. Defaults to "breakpoint".
+	CustomMediaGroup string
+
+	// OmitGroupComments suppresses group/section comments in formats
+	// that write them (currently scss).
+	OmitGroupComments bool
+
+	// SCSSMap writes scss output as a single nested map with a token()
+	// accessor function instead of flat variables.
+	SCSSMap bool
+
+	// Hex8 writes translucent sRGB colors as 8-digit hex (#RRGGBBAA) in
+	// css and scss output.
+	Hex8 bool
+
+	// ColorSyntax selects modern or legacy CSS color functions in css and
+	// scss output. Empty means ColorSyntaxModern.
+	ColorSyntax ColorSyntax
+
+	// CSSReferences writes aliases in css output as var() calls to the
+	// aliased token's property instead of its resolved value.
+	CSSReferences bool
+
+	// IncludePlaceholders writes tokens with no value, such as
+	// "$value": null, in css and scss output, which leave them out by default.
+	IncludePlaceholders bool
+
 	// SnippetType specifies the snippet output format.
-	// Valid values: "vscode" (default), "textmate", "zed"
+	// Valid values: "vscode" (default), "textmate", "zed", "sublime"
 	SnippetType string
 
 	// JSModule specifies the JavaScript module format.
@@ -70,9 +128,17 @@ type Options struct {
 	// Valid values: "values" (default), "map"
 	JSExport string
 
-	// JSMapMode specifies the map mode for split file generation.
-	// Valid values: "" (full), "types", "module"
-	// Set internally during split output, not via CLI flag.
+	// OmitJSDoc leaves doc comments, such as token descriptions, out of
+	// TypeScript output. JSDoc-typed output keeps them.
+	OmitJSDoc bool
+
+	// RootFontSize is the size of 1rem in px, which rem and em
+	// dimensions are scaled by in swift output. Zero means 16.
+	RootFontSize float64
+
+	// JSMapMode specifies the map mode for split and emitDts output.
+	// Valid values: "" (full), "types", "module", "js", "dts"
+	// Set internally during split and emitDts output, not via CLI flag.
 	JSMapMode string
 
 	// JSMapTypesPath is the import path for shared types.
@@ -82,6 +148,13 @@ type Options struct {
 	// JSMapClassName is the class name for extended TokenMap.
 	// Used when JSMapMode is "module".
 	JSMapClassName string
+
+	// Template is the Go text/template source for FormatTemplate output.
+	Template string
+
+	// OnWarning, if not nil, is called with each token a formatter skips
+	// or can't write as authored.
+	OnWarning func(formatter.Warning)
 }
 
 // DefaultOptions returns options with sensible defaults.
@@ -115,10 +188,17 @@ func Serialize(tokens []*token.Token, opts Options) map[string]any {
 		opts.OutputSchema = opts.InputSchema
 	}
 
+	strip := stripMeta{extensions: opts.StripExtensions, descriptions: opts.StripDescriptions}
 	if opts.Flatten {
-		return buildFlatStructure(tokens, opts.InputSchema, opts.OutputSchema, opts.Delimiter)
+		return buildFlatStructure(tokens, opts.InputSchema, opts.OutputSchema, opts.RefStyle, strip, opts.Delimiter)
 	}
-	return buildNestedStructure(tokens, opts.InputSchema, opts.OutputSchema)
+	return buildNestedStructure(tokens, opts.InputSchema, opts.OutputSchema, opts.RefStyle, strip, opts.FlattenDepth, opts.Delimiter, opts.HoistTypes)
+}
+
+// stripMeta selects the optional metadata keys serializeToken omits.
+type stripMeta struct {
+	extensions   bool
+	descriptions bool
 }
 
 // SerializeTokens converts parsed tokens to a DTCG map structure.
@@ -141,6 +221,8 @@ func SerializeTokens(
 func buildFlatStructure(
 	tokens []*token.Token,
 	inputSchema, outputSchema schema.Version,
+	refStyle RefStyle,
+	strip stripMeta,
 	delimiter string,
 ) map[string]any {
 	result := make(map[string]any)
@@ -153,7 +235,7 @@ func buildFlatStructure(
 	for _, tok := range tokens {
 		// Use Path segments joined by delimiter for flattened keys
 		key := strings.Join(tok.Path, delimiter)
-		tokenMap := serializeToken(tok, inputSchema, outputSchema)
+		tokenMap := serializeToken(tok, inputSchema, outputSchema, refStyle, strip)
 		result[key] = tokenMap
 	}
 
@@ -161,9 +243,17 @@ func buildFlatStructure(
 }
 
 // buildNestedStructure creates a nested map following the token paths.
+// When flattenDepth is positive, the last flattenDepth+1 segments of each
+// path are joined with delimiter into a single key. When hoistTypes is
+// set, common token types are moved up to their groups.
 func buildNestedStructure(
 	tokens []*token.Token,
 	inputSchema, outputSchema schema.Version,
+	refStyle RefStyle,
+	strip stripMeta,
+	flattenDepth int,
+	delimiter string,
+	hoistTypes bool,
 ) map[string]any {
 	result := make(map[string]any)
 
@@ -174,7 +264,7 @@ func buildNestedStructure(
 
 	for _, tok := range tokens {
 		current := result
-		path := tok.Path
+		path := collapsePath(tok.Path, flattenDepth, delimiter)
 
 		// Navigate/create nested structure up to parent
 		for i := 0; i < len(path)-1; i++ {
@@ -191,36 +281,100 @@ func buildNestedStructure(
 
 		// Set the token at the final key
 		if len(path) > 0 {
-			current[path[len(path)-1]] = serializeToken(tok, inputSchema, outputSchema)
+			current[path[len(path)-1]] = serializeToken(tok, inputSchema, outputSchema, refStyle, strip)
+		}
+	}
+
+	if hoistTypes {
+		// The root is not a group, so its children keep their own $type
+		for key, child := range result {
+			if group, ok := child.(map[string]any); ok && !strings.HasPrefix(key, "$") {
+				hoistGroupType(group)
+			}
 		}
 	}
 
 	return result
 }
 
+// hoistGroupType moves a $type shared by all of a group's children onto
+// the group, working up from the innermost groups. It returns the type
+// of node: a token's own $type, or the group's hoisted $type.
+func hoistGroupType(node map[string]any) string {
+	if _, isToken := node["$value"]; isToken {
+		typ, _ := node["$type"].(string)
+		return typ
+	}
+
+	var children []map[string]any
+	shared, same := "", true
+	for key, child := range node {
+		// $root is the group's own token; other $ keys are group properties
+		if strings.HasPrefix(key, "$") && key != "$root" {
+			continue
+		}
+		m, ok := child.(map[string]any)
+		if !ok {
+			continue
+		}
+		typ := hoistGroupType(m)
+		if len(children) == 0 {
+			shared = typ
+		} else if typ != shared {
+			same = false
+		}
+		children = append(children, m)
+	}
+
+	if !same || shared == "" {
+		return ""
+	}
+	for _, child := range children {
+		delete(child, "$type")
+	}
+	node["$type"] = shared
+	return shared
+}
+
+// collapsePath joins the last depth+1 segments of path with delimiter,
+// leaving the leading segments as separate group levels.
+func collapsePath(path []string, depth int, delimiter string) []string {
+	if depth <= 0 || len(path) < 2 {
+		return path
+	}
+	split := max(len(path)-1-depth, 0)
+	collapsed := make([]string, 0, split+1)
+	collapsed = append(collapsed, path[:split]...)
+	return append(collapsed, strings.Join(path[split:], delimiter))
+}
+
 // serializeToken converts a single token to its DTCG map representation.
-func serializeToken(tok *token.Token, inputSchema, outputSchema schema.Version) map[string]any {
+func serializeToken(tok *token.Token, inputSchema, outputSchema schema.Version, refStyle RefStyle, strip stripMeta) map[string]any {
 	result := make(map[string]any)
 
 	// Handle value conversion
-	value := convertValue(tok, inputSchema, outputSchema)
-	if value != nil {
-		result["$value"] = value
-	}
+	// Placeholders keep a null $value, which still marks them as tokens
+	result["$value"] = convertValue(tok, inputSchema, outputSchema, refStyle)
 
-	if tok.Type != "" {
+	if tok.Type != "" && !tok.TypeFromAlias {
 		result["$type"] = tok.Type
 	}
 
-	if tok.Description != "" {
+	if tok.Description != "" && !strip.descriptions {
 		result["$description"] = tok.Description
 	}
 
-	if len(tok.Extensions) > 0 {
+	if len(tok.Extensions) > 0 && !strip.extensions {
 		result["$extensions"] = tok.Extensions
 	}
 
-	if tok.Deprecated {
+	if tok.Deprecated && tok.Replacement != "" {
+		deprecated := map[string]any{"replacement": tok.Replacement}
+		if tok.DeprecationMessage != "" {
+			deprecated["message"] = tok.DeprecationMessage
+		}
+		result["$deprecated"] = deprecated
+	} else if tok.Deprecated {
 		result["$deprecated"] = true
 		if tok.DeprecationMessage != "" {
 			result["$deprecationMessage"] = tok.DeprecationMessage
@@ -230,13 +384,32 @@ func serializeToken(tok *token.Token, inputSchema, outputSchema schema.Version)
 	return result
 }
 
-// convertValue handles value conversion between schemas.
-func convertValue(tok *token.Token, inputSchema, outputSchema schema.Version) any {
+// convertValue handles value conversion between schemas, then rewrites
+// references to refStyle unless it is RefStyleDefault.
+func convertValue(tok *token.Token, inputSchema, outputSchema schema.Version, refStyle RefStyle) any {
+	value := convertSchemaValue(tok, inputSchema, outputSchema)
+	if refStyle == RefStyleDefault {
+		return value
+	}
+	return applyRefStyle(value, refStyle)
+}
+
+// convertSchemaValue converts a token's value from inputSchema to outputSchema.
+func convertSchemaValue(tok *token.Token, inputSchema, outputSchema schema.Version) any {
 	rawValue := tok.RawValue
 	if rawValue == nil {
+		if tok.Value == "" {
+			return nil
+		}
 		rawValue = tok.Value
 	}
 
+	// The Editor's Draft can't reference a value and override some of its
+	// fields, so write the merged value instead
+	if _, _, ok := token.PartialRef(rawValue); ok && outputSchema == schema.Draft && len(tok.ResolutionChain) > 0 {
+		rawValue = tok.ResolvedValue
+	}
+
 	// If same schema, pass through with minimal conversion
 	if inputSchema == outputSchema {
 		return convertReferences(rawValue, inputSchema, outputSchema)
@@ -275,6 +448,11 @@ func convertDraftToV2025(tok *token.Token, rawValue any) any {
 			return convertStringColorToStructured(v)
 		}
 
+		// Check if it's a dimension or duration and convert to structured format
+		if units, ok := structuredUnits[tok.Type]; ok {
+			return convertStringUnitValueToStructured(v, units)
+		}
+
 		return v
 
 	case map[string]any:
@@ -369,6 +547,21 @@ func convertArrayReferences(arr []any, inputSchema, outputSchema schema.Version)
 // convertStringColorToStructured converts a string color to v2025_10 structured format.
 func convertStringColorToStructured(colorStr string) any {
 	c, err := csscolorparser.Parse(colorStr)
+
+	// Keep the authored color space for functions DTCG can represent natively
+	if colorSpace, components, alpha, ok := parseColorFunction(colorStr); ok {
+		result := map[string]any{
+			"colorSpace": colorSpace,
+			"components": components,
+			"alpha":      alpha,
+		}
+		// Include hex as a fallback when the color has an sRGB equivalent
+		if err == nil {
+			result["hex"] = c.HexString()
+		}
+		return result
+	}
+
 	if err != nil {
 		// If parsing fails, return the original string
 		return colorStr
@@ -381,9 +574,9 @@ func convertStringColorToStructured(colorStr string) any {
 		"alpha":      c.A,
 	}
 
-	// Include hex for convenience
-	if strings.HasPrefix(colorStr, "#") {
-		result["hex"] = colorStr
+	// Include hex for convenience, keeping authored hex in full form
+	if hex, ok := common.ExpandHex(colorStr); ok {
+		result["hex"] = hex
 	} else {
 		result["hex"] = c.HexString()
 	}
@@ -391,6 +584,24 @@ func convertStringColorToStructured(colorStr string) any {
 	return result
 }
 
+// structuredUnits are the units v2025_10 allows in structured values, by
+// token type. Values in other units have no structured form.
+var structuredUnits = map[string][]string{
+	token.TypeDimension: {"px", "rem"},
+	token.TypeDuration:  {"ms", "s"},
+}
+
+// convertStringUnitValueToStructured converts a string like "0.5rem" to
+// v2025_10 structured format, {"value": 0.5, "unit": "rem"}. Compound
+// values such as calc() and values in other units are returned unchanged.
+func convertStringUnitValueToStructured(s string, units []string) any {
+	n, unit, ok := common.ParseNumberWithUnit(strings.TrimSpace(s))
+	if !ok || !slices.Contains(units, unit) {
+		return s
+	}
+	return map[string]any{"value": n, "unit": unit}
+}
+
 // convertStructuredColorToString converts a v2025_10 structured color to a string.
 func convertStructuredColorToString(colorObj map[string]any) string {
 	// If hex field is provided, use it
@@ -112,6 +112,71 @@ func TestSerialize_NestedPreserve(t *testing.T) {
 	}
 }
 
+func TestSerialize_FlattenDepth(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "primary", Path: []string{"color", "brand", "primary"}, Value: "#FF6B35", RawValue: "#FF6B35", Type: token.TypeColor},
+		{Name: "small", Path: []string{"spacing", "small"}, Value: "4px", RawValue: "4px", Type: token.TypeDimension},
+	}
+	leaf := func(value, tokenType string) map[string]any {
+		return map[string]any{"$value": value, "$type": tokenType}
+	}
+
+	tests := []struct {
+		name      string
+		depth     int
+		delimiter string
+		want      map[string]any
+	}{
+		{
+			name:  "depth 0 is fully nested",
+			depth: 0,
+			want: map[string]any{
+				"color":   map[string]any{"brand": map[string]any{"primary": leaf("#FF6B35", "color")}},
+				"spacing": map[string]any{"small": leaf("4px", "dimension")},
+			},
+		},
+		{
+			name:  "depth 1 collapses the innermost group",
+			depth: 1,
+			want: map[string]any{
+				"color":         map[string]any{"brand-primary": leaf("#FF6B35", "color")},
+				"spacing-small": leaf("4px", "dimension"),
+			},
+		},
+		{
+			name:      "depth 1 uses the delimiter",
+			depth:     1,
+			delimiter: "_",
+			want: map[string]any{
+				"color":         map[string]any{"brand_primary": leaf("#FF6B35", "color")},
+				"spacing_small": leaf("4px", "dimension"),
+			},
+		},
+		{
+			name:  "large depth is fully flat",
+			depth: 10,
+			want: map[string]any{
+				"color-brand-primary": leaf("#FF6B35", "color"),
+				"spacing-small":       leaf("4px", "dimension"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convert.Serialize(tokens, convert.Options{
+				InputSchema:  schema.Draft,
+				OutputSchema: schema.Draft,
+				FlattenDepth: tt.depth,
+				Delimiter:    tt.delimiter,
+			})
+			if !reflect.DeepEqual(tt.want, got) {
+				t.Errorf("Serialize() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSerialize_DraftToV2025(t *testing.T) {
 	mfs := testutil.NewFixtureFS(t, "fixtures/convert/draft-to-stable", "/test")
 
@@ -330,6 +395,45 @@ func TestSerialize_CustomDelimiter(t *testing.T) {
 	}
 }
 
+func TestSerialize_V2025ToDraft_PartialRef(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/v2025_10/partial-refs", schema.V2025_10)
+
+	draft := convert.Serialize(tokens, convert.Options{InputSchema: schema.V2025_10, OutputSchema: schema.Draft})
+	shadows := draft["shadow"].(map[string]any)
+	brand, ok := shadows["brand"].(map[string]any)["$value"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected the merged shadow as an object, got %v", shadows["brand"])
+	}
+	if _, hasRef := brand["$ref"]; hasRef || brand["offsetY"] == nil || brand["color"] == nil {
+		t.Errorf("expected the base shadow merged with the color override, got %v", brand)
+	}
+	if alias := shadows["alias"].(map[string]any)["$value"]; alias != "{shadow.base}" {
+		t.Errorf("expected a whole-value $ref to stay a reference, got %v", alias)
+	}
+
+	stable := convert.Serialize(tokens, convert.Options{InputSchema: schema.V2025_10, OutputSchema: schema.V2025_10})
+	raised := stable["shadow"].(map[string]any)["raised"].(map[string]any)["$value"].(map[string]any)
+	if raised["$ref"] != "#/shadow/brand" || len(raised) != 3 {
+		t.Errorf("expected v2025.10 output to keep the $ref and overrides, got %v", raised)
+	}
+}
+
+func TestSerialize_AliasKeepsAuthoredType(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/v2025_10/alias-chain", schema.V2025_10)
+
+	result := convert.Serialize(tokens, convert.Options{InputSchema: schema.V2025_10, OutputSchema: schema.V2025_10})
+	surface := result["surface"].(map[string]any)
+	for _, name := range []string{"base", "card"} {
+		if typ, ok := surface[name].(map[string]any)["$type"]; ok {
+			t.Errorf("surface.%s: expected no $type, since none was authored, got %v", name, typ)
+		}
+	}
+	white := result["color"].(map[string]any)["white"].(map[string]any)
+	if white["$type"] != "color" {
+		t.Errorf("color.white: expected its own $type, got %v", white["$type"])
+	}
+}
+
 func TestSerialize_BasicDraftRoundtrip(t *testing.T) {
 	// Test that basic tokens roundtrip through serialization unchanged
 	mfs := testutil.NewFixtureFS(t, "fixtures/draft/simple", "/test")
@@ -698,6 +802,40 @@ func TestSerialize_SerializeToken_DeprecatedWithoutMessage(t *testing.T) {
 	}
 }
 
+func TestSerialize_SerializeToken_DeprecatedWithReplacement(t *testing.T) {
+	// Test that a replacement is written back in the object form
+	tokens := []*token.Token{
+		{
+			Name:               "color-old",
+			Value:              "#000000",
+			Type:               "color",
+			Path:               []string{"color", "old"},
+			Deprecated:         true,
+			DeprecationMessage: "Renamed",
+			Replacement:        "color.new",
+		},
+	}
+
+	result := convert.Serialize(tokens, convert.Options{
+		InputSchema:  schema.Draft,
+		OutputSchema: schema.Draft,
+	})
+
+	colorGroup := result["color"].(map[string]any)
+	old := colorGroup["old"].(map[string]any)
+
+	deprecated, ok := old["$deprecated"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected $deprecated object, got %v", old["$deprecated"])
+	}
+	if deprecated["message"] != "Renamed" {
+		t.Errorf("expected message 'Renamed', got %v", deprecated["message"])
+	}
+	if deprecated["replacement"] != "color.new" {
+		t.Errorf("expected replacement 'color.new', got %v", deprecated["replacement"])
+	}
+}
+
 func TestSerialize_ConvertDraftToV2025_EmbeddedReference(t *testing.T) {
 	// Test that embedded references (partial string references) pass through as-is
 	tokens := []*token.Token{
@@ -878,6 +1016,39 @@ func TestSerialize_ConvertV2025ToDraft_PlainString(t *testing.T) {
 	}
 }
 
+func TestConvertStringColorToStructured_ShorthandHex(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantHex   string
+		wantAlpha float64
+	}{
+		{"#f00", "#ff0000", 1},
+		{"#f00a", "#ff0000aa", 0xaa / 255.0},
+		{"#FF6B35", "#FF6B35", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := convert.Serialize([]*token.Token{
+				{Name: "c", Type: "color", Path: []string{"c"}, RawValue: tt.input},
+			}, convert.Options{
+				InputSchema:  schema.Draft,
+				OutputSchema: schema.V2025_10,
+			})
+			value, ok := result["c"].(map[string]any)["$value"].(map[string]any)
+			if !ok {
+				t.Fatalf("expected structured color, got %v", result["c"])
+			}
+			if value["hex"] != tt.wantHex {
+				t.Errorf("hex = %v, want %s", value["hex"], tt.wantHex)
+			}
+			if value["alpha"] != tt.wantAlpha {
+				t.Errorf("alpha = %v, want %v", value["alpha"], tt.wantAlpha)
+			}
+		})
+	}
+}
+
 func TestConvertStringColorToStructured_NonHexColor(t *testing.T) {
 	// Test converting non-hex color strings (e.g., rgb, named colors)
 	tokens := []*token.Token{
@@ -929,6 +1100,70 @@ func TestConvertStringColorToStructured_NonHexColor(t *testing.T) {
 	}
 }
 
+func TestConvertStringColorToStructured_ColorFunctions(t *testing.T) {
+	tests := []struct {
+		input          string
+		wantSpace      string
+		wantComponents []any
+		wantAlpha      float64
+	}{
+		{"hsl(120,50%,50%)", "hsl", []any{120.0, 50.0, 50.0}, 1},
+		{"hsla(0.5turn, 100%, 25%, 0.5)", "hsl", []any{180.0, 100.0, 25.0}, 0.5},
+		{"hwb(90deg 10% 20% / 50%)", "hwb", []any{90.0, 10.0, 20.0}, 0.5},
+		{"lab(50% 40 -20)", "lab", []any{50.0, 40.0, -20.0}, 1},
+		{"oklch(70% 0.15 none)", "oklch", []any{0.7, 0.15, "none"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tokens := []*token.Token{
+				{Name: "c", Type: "color", Path: []string{"c"}, RawValue: tt.input},
+			}
+			result := convert.Serialize(tokens, convert.Options{
+				InputSchema:  schema.Draft,
+				OutputSchema: schema.V2025_10,
+			})
+			value, ok := result["c"].(map[string]any)["$value"].(map[string]any)
+			if !ok {
+				t.Fatalf("expected structured color, got %v", result["c"])
+			}
+			if value["colorSpace"] != tt.wantSpace {
+				t.Errorf("colorSpace = %v, want %s", value["colorSpace"], tt.wantSpace)
+			}
+			if !reflect.DeepEqual(value["components"], tt.wantComponents) {
+				t.Errorf("components = %v, want %v", value["components"], tt.wantComponents)
+			}
+			if value["alpha"] != tt.wantAlpha {
+				t.Errorf("alpha = %v, want %v", value["alpha"], tt.wantAlpha)
+			}
+		})
+	}
+}
+
+func TestConvertColorFunction_RoundTrip(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "c", Type: "color", Path: []string{"c"}, RawValue: "hsl(120,50%,50%)"},
+	}
+	v2025 := convert.Serialize(tokens, convert.Options{
+		InputSchema:  schema.Draft,
+		OutputSchema: schema.V2025_10,
+	})
+	structured := v2025["c"].(map[string]any)["$value"].(map[string]any)
+	if _, ok := structured["hex"].(string); !ok {
+		t.Error("expected hex fallback alongside hsl components")
+	}
+
+	back := convert.Serialize([]*token.Token{
+		{Name: "c", Type: "color", Path: []string{"c"}, RawValue: structured, SchemaVersion: schema.V2025_10},
+	}, convert.Options{
+		InputSchema:  schema.V2025_10,
+		OutputSchema: schema.Draft,
+	})
+	if got := back["c"].(map[string]any)["$value"]; got != structured["hex"] {
+		t.Errorf("round trip = %v, want the hex fallback %v", got, structured["hex"])
+	}
+}
+
 func TestConvertStringColorToStructured_InvalidColor(t *testing.T) {
 	// Test that invalid color strings are returned as-is
 	tokens := []*token.Token{
@@ -954,8 +1189,8 @@ func TestConvertStringColorToStructured_InvalidColor(t *testing.T) {
 	}
 }
 
-func TestSerialize_EmptyStringValue(t *testing.T) {
-	// Token with empty string Value (default zero value) still produces $value
+func TestSerialize_PlaceholderValue(t *testing.T) {
+	// A token with no value is a placeholder, and still produces $value
 	tokens := []*token.Token{
 		{
 			Name: "empty",
@@ -970,9 +1205,9 @@ func TestSerialize_EmptyStringValue(t *testing.T) {
 	})
 
 	empty := result["empty"].(map[string]any)
-	// Empty string value passes through as ""
-	if empty["$value"] != "" {
-		t.Errorf("expected empty string $value, got %v", empty["$value"])
+	// Placeholders round-trip as "$value": null
+	if value, ok := empty["$value"]; !ok || value != nil {
+		t.Errorf("expected null $value, got %v (present: %v)", value, ok)
 	}
 }
 
@@ -1140,3 +1375,236 @@ func TestSerialize_DefaultOptions(t *testing.T) {
 		t.Error("expected non-nil result with default options")
 	}
 }
+
+func TestSerialize_RefStyle(t *testing.T) {
+	draftTokens := []*token.Token{
+		{Name: "color-link", Type: "color", Path: []string{"color", "link"}, RawValue: "{color.brand.primary}"},
+		{Name: "text-greeting", Type: "string", Path: []string{"text", "greeting"}, RawValue: "Hello {user.name}"},
+		{Name: "border-focus", Type: "border", Path: []string{"border", "focus"}, RawValue: map[string]any{
+			"width": "2px", "style": "solid", "color": "{color.brand.primary}",
+		}},
+	}
+	v2025Tokens := []*token.Token{
+		{Name: "color-link", Type: "color", Path: []string{"color", "link"}, RawValue: map[string]any{"$ref": "#/color/brand/primary"}},
+		{Name: "text-greeting", Type: "string", Path: []string{"text", "greeting"}, RawValue: "Hello {user.name}"},
+		{Name: "border-focus", Type: "border", Path: []string{"border", "focus"}, RawValue: map[string]any{
+			"width": "2px", "style": "solid", "color": map[string]any{"$ref": "#/color/brand/primary"},
+		}},
+	}
+	jsonRef := map[string]any{"$ref": "#/color/brand/primary"}
+
+	tests := []struct {
+		name         string
+		tokens       []*token.Token
+		input        schema.Version
+		output       schema.Version
+		style        convert.RefStyle
+		wantLink     any
+		wantGreeting string
+		wantColor    any
+	}{
+		{"curly from v2025", v2025Tokens, schema.V2025_10, schema.V2025_10, convert.RefStyleCurly, "{color.brand.primary}", "Hello {user.name}", "{color.brand.primary}"},
+		{"slash from draft", draftTokens, schema.Draft, schema.Draft, convert.RefStyleSlash, "{color/brand/primary}", "Hello {user/name}", "{color/brand/primary}"},
+		{"slash from v2025", v2025Tokens, schema.V2025_10, schema.Draft, convert.RefStyleSlash, "{color/brand/primary}", "Hello {user/name}", "{color/brand/primary}"},
+		{"json-ref in draft output", draftTokens, schema.Draft, schema.Draft, convert.RefStyleJSONRef, jsonRef, "Hello {user.name}", jsonRef},
+		{"default follows schema", draftTokens, schema.Draft, schema.V2025_10, convert.RefStyleDefault, jsonRef, "Hello {user.name}", "{color.brand.primary}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := convert.Serialize(tt.tokens, convert.Options{
+				InputSchema:  tt.input,
+				OutputSchema: tt.output,
+				RefStyle:     tt.style,
+			})
+
+			link := result["color"].(map[string]any)["link"].(map[string]any)["$value"]
+			if !reflect.DeepEqual(link, tt.wantLink) {
+				t.Errorf("link $value = %v, want %v", link, tt.wantLink)
+			}
+			greeting := result["text"].(map[string]any)["greeting"].(map[string]any)["$value"]
+			if greeting != tt.wantGreeting {
+				t.Errorf("greeting $value = %v, want %v", greeting, tt.wantGreeting)
+			}
+			border := result["border"].(map[string]any)["focus"].(map[string]any)["$value"].(map[string]any)
+			if !reflect.DeepEqual(border["color"], tt.wantColor) {
+				t.Errorf("border color = %v, want %v", border["color"], tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestSerialize_StripMeta(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name:        "color-primary",
+			Type:        "color",
+			Path:        []string{"color", "primary"},
+			RawValue:    "#ff0000",
+			Description: "Primary brand color",
+			Extensions:  map[string]any{"com.example.tool": map[string]any{"id": "abc123"}},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		opts            convert.Options
+		wantDescription bool
+		wantExtensions  bool
+	}{
+		{"default keeps everything", convert.Options{}, true, true},
+		{"strip extensions", convert.Options{StripExtensions: true}, true, false},
+		{"strip descriptions", convert.Options{StripDescriptions: true}, false, true},
+		{"strip both", convert.Options{StripExtensions: true, StripDescriptions: true, Flatten: true}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := convert.Serialize(tokens, tt.opts)
+			var primary map[string]any
+			if tt.opts.Flatten {
+				primary = result["color-primary"].(map[string]any)
+			} else {
+				primary = result["color"].(map[string]any)["primary"].(map[string]any)
+			}
+			if _, ok := primary["$description"]; ok != tt.wantDescription {
+				t.Errorf("has $description = %v, want %v", ok, tt.wantDescription)
+			}
+			if _, ok := primary["$extensions"]; ok != tt.wantExtensions {
+				t.Errorf("has $extensions = %v, want %v", ok, tt.wantExtensions)
+			}
+			if primary["$value"] != "#ff0000" {
+				t.Errorf("$value = %v, want #ff0000", primary["$value"])
+			}
+		})
+	}
+}
+
+func TestSerialize_HoistTypes(t *testing.T) {
+	input := `{
+  "color": {
+    "brand": {
+      "primary": {"$type": "color", "$value": "#ff0000"},
+      "secondary": {"$type": "color", "$value": "{color.brand.primary}"}
+    },
+    "text": {"$type": "color", "$value": "#000000"}
+  },
+  "ui": {
+    "accent": {"$type": "color", "$value": "{color.brand.primary}"},
+    "space": {
+      "sm": {"$type": "dimension", "$value": "4px"},
+      "md": {"$type": "dimension", "$value": "8px"}
+    }
+  },
+  "untyped": {"$value": "plain"}
+}`
+	p := parser.NewJSONParser()
+	tokens, err := p.Parse([]byte(input), parser.Options{SchemaVersion: schema.Draft, SkipPositions: true})
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	result := convert.Serialize(tokens, convert.Options{InputSchema: schema.Draft, HoistTypes: true})
+
+	group := func(path ...string) map[string]any {
+		m := result
+		for _, key := range path {
+			m = m[key].(map[string]any)
+		}
+		return m
+	}
+	if got := group("color")["$type"]; got != "color" {
+		t.Errorf("color $type = %v, want color", got)
+	}
+	for _, path := range [][]string{{"color", "brand"}, {"color", "brand", "primary"}, {"color", "text"}} {
+		if _, ok := group(path...)["$type"]; ok {
+			t.Errorf("expected $type hoisted off %v", path)
+		}
+	}
+	if _, ok := group("ui")["$type"]; ok {
+		t.Error("expected mixed ui group to have no $type")
+	}
+	if got := group("ui", "accent")["$type"]; got != "color" {
+		t.Errorf("ui.accent $type = %v, want color", got)
+	}
+	if got := group("ui", "space")["$type"]; got != "dimension" {
+		t.Errorf("ui.space $type = %v, want dimension", got)
+	}
+	if _, ok := group("untyped")["$type"]; ok {
+		t.Error("expected untyped root token to stay untyped")
+	}
+
+	// Re-parsing the hoisted output yields the same tokens
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	reparsed, err := p.Parse(data, parser.Options{SchemaVersion: schema.Draft, SkipPositions: true})
+	if err != nil {
+		t.Fatalf("failed to re-parse: %v", err)
+	}
+	type summary struct{ typ, value string }
+	summarize := func(tokens []*token.Token) map[string]summary {
+		m := make(map[string]summary, len(tokens))
+		for _, tok := range tokens {
+			m[tok.Name] = summary{tok.Type, tok.Value}
+		}
+		return m
+	}
+	if got, want := summarize(reparsed), summarize(tokens); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip changed tokens:\ngot  %v\nwant %v", got, want)
+	}
+}
+
+func TestParseRefStyle(t *testing.T) {
+	for _, s := range []string{"", "curly", "slash", "json-ref", "JSON-REF"} {
+		if _, err := convert.ParseRefStyle(s); err != nil {
+			t.Errorf("ParseRefStyle(%q) error: %v", s, err)
+		}
+	}
+	if _, err := convert.ParseRefStyle("pointer"); err == nil {
+		t.Error("expected error for unknown reference style")
+	}
+}
+
+func TestSerialize_ConvertDraftToV2025_StructuredDimensions(t *testing.T) {
+	tests := []struct {
+		name     string
+		tokType  string
+		rawValue string
+		want     any
+	}{
+		{"rem", "dimension", "0.5rem", map[string]any{"value": 0.5, "unit": "rem"}},
+		{"px", "dimension", "16px", map[string]any{"value": 16.0, "unit": "px"}},
+		{"negative", "dimension", "-2px", map[string]any{"value": -2.0, "unit": "px"}},
+		{"inner space", "dimension", "1.5 rem", "1.5 rem"},
+		{"milliseconds", "duration", "200ms", map[string]any{"value": 200.0, "unit": "ms"}},
+		{"seconds", "duration", "1.5s", map[string]any{"value": 1.5, "unit": "s"}},
+		{"unsupported unit", "dimension", "50%", "50%"},
+		{"em", "dimension", "1em", "1em"},
+		{"compound", "dimension", "calc(1rem + 2px)", "calc(1rem + 2px)"},
+		{"unitless", "dimension", "0", "0"},
+		{"duration unit on dimension", "dimension", "100ms", "100ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := []*token.Token{{
+				Name:     "size",
+				Type:     tt.tokType,
+				Path:     []string{"size"},
+				RawValue: tt.rawValue,
+			}}
+
+			result := convert.Serialize(tokens, convert.Options{
+				InputSchema:  schema.Draft,
+				OutputSchema: schema.V2025_10,
+			})
+
+			got := result["size"].(map[string]any)["$value"]
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("$value = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -13,12 +13,19 @@ import (
 	"bennypowers.dev/asimonim/convert/formatter"
 	"bennypowers.dev/asimonim/convert/formatter/android"
 	"bennypowers.dev/asimonim/convert/formatter/css"
+	"bennypowers.dev/asimonim/convert/formatter/custom"
+	"bennypowers.dev/asimonim/convert/formatter/custommedia"
 	"bennypowers.dev/asimonim/convert/formatter/dtcg"
+	"bennypowers.dev/asimonim/convert/formatter/figmatokens"
 	"bennypowers.dev/asimonim/convert/formatter/flatjson"
+	"bennypowers.dev/asimonim/convert/formatter/html"
 	"bennypowers.dev/asimonim/convert/formatter/js"
 	"bennypowers.dev/asimonim/convert/formatter/scss"
 	"bennypowers.dev/asimonim/convert/formatter/snippets"
 	"bennypowers.dev/asimonim/convert/formatter/swift"
+	"bennypowers.dev/asimonim/convert/formatter/xcassets"
+	"bennypowers.dev/asimonim/convert/formatter/yaml"
+	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/token"
 )
 
@@ -29,6 +36,9 @@ const (
 	// FormatDTCG outputs DTCG-compliant JSON (default).
 	FormatDTCG Format = "dtcg"
 
+	// FormatYAML outputs the DTCG structure as YAML.
+	FormatYAML Format = "yaml"
+
 	// FormatFlatJSON outputs flat key-value JSON.
 	FormatFlatJSON Format = "json"
 
@@ -49,22 +59,46 @@ const (
 	// Use CSSSelector and CSSModule options to customize output.
 	FormatCSS Format = "css"
 
+	// FormatCustomMedia outputs CSS @custom-media rules for dimension tokens.
+	// Use the CustomMediaGroup option to choose the token group.
+	FormatCustomMedia Format = "css-custom-media"
+
 	// FormatSnippets outputs editor snippets (VSCode, TextMate, etc).
 	// Use SnippetType option to specify the output format.
 	FormatSnippets Format = "snippets"
+
+	// FormatTokensStudio outputs Tokens Studio for Figma JSON.
+	FormatTokensStudio Format = "tokens-studio"
+
+	// FormatHTML outputs a self-contained HTML page for browsing tokens.
+	FormatHTML Format = "html"
+
+	// FormatIOSAssets outputs color tokens as an Xcode asset catalog, a
+	// directory of color sets. Use FormatAssetCatalog, not FormatTokens.
+	FormatIOSAssets Format = "ios-assets"
+
+	// FormatTemplate renders tokens through a user-supplied Go text/template.
+	// Use the Template option to provide the template source.
+	FormatTemplate Format = "template"
 )
 
 // ValidFormats returns all valid format strings.
 func ValidFormats() []string {
 	return []string{
 		string(FormatDTCG),
+		string(FormatYAML),
 		string(FormatFlatJSON),
 		string(FormatAndroid),
 		string(FormatSwift),
 		string(FormatJS),
 		string(FormatSCSS),
 		string(FormatCSS),
+		string(FormatCustomMedia),
 		string(FormatSnippets),
+		string(FormatTokensStudio),
+		string(FormatHTML),
+		string(FormatIOSAssets),
+		string(FormatTemplate),
 	}
 }
 
@@ -75,6 +109,8 @@ func ParseFormat(s string) (Format, error) {
 	switch strings.ToLower(s) {
 	case "dtcg", "":
 		return FormatDTCG, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
 	case "json", "flat", "flat-json":
 		return FormatFlatJSON, nil
 	case "android", "xml":
@@ -87,8 +123,18 @@ func ParseFormat(s string) (Format, error) {
 		return FormatSCSS, nil
 	case "css":
 		return FormatCSS, nil
+	case "css-custom-media", "custom-media":
+		return FormatCustomMedia, nil
 	case "snippets":
 		return FormatSnippets, nil
+	case "tokens-studio", "figma-tokens", "figmatokens":
+		return FormatTokensStudio, nil
+	case "html":
+		return FormatHTML, nil
+	case "ios-assets", "xcassets":
+		return FormatIOSAssets, nil
+	case "template":
+		return FormatTemplate, nil
 	default:
 		return "", fmt.Errorf("unknown format: %s (valid: %s)", s, strings.Join(ValidFormats(), ", "))
 	}
@@ -96,11 +142,7 @@ func ParseFormat(s string) (Format, error) {
 
 // FormatTokens converts tokens to the specified output format.
 func FormatTokens(tokens []*token.Token, format Format, opts Options) ([]byte, error) {
-	fmtOpts := formatter.Options{
-		Prefix:    opts.Prefix,
-		Delimiter: opts.Delimiter,
-		Header:    opts.Header,
-	}
+	fmtOpts := formatterOptions(opts)
 
 	var f formatter.Formatter
 	switch format {
@@ -108,12 +150,18 @@ func FormatTokens(tokens []*token.Token, format Format, opts Options) ([]byte, e
 		f = dtcg.New(func(t []*token.Token) map[string]any {
 			return Serialize(t, opts)
 		})
+	case FormatYAML:
+		f = yaml.New(func(t []*token.Token) map[string]any {
+			return Serialize(t, opts)
+		})
 	case FormatFlatJSON:
 		f = flatjson.New()
 	case FormatAndroid:
 		f = android.New()
 	case FormatSwift:
-		f = swift.New()
+		f = swift.NewWithOptions(swift.Options{
+			RootFontSize: opts.RootFontSize,
+		})
 	case FormatJS:
 		f = js.NewWithOptions(js.Options{
 			Module:    js.Module(opts.JSModule),
@@ -122,21 +170,88 @@ func FormatTokens(tokens []*token.Token, format Format, opts Options) ([]byte, e
 			MapMode:   js.MapMode(opts.JSMapMode),
 			TypesPath: opts.JSMapTypesPath,
 			ClassName: opts.JSMapClassName,
+			OmitJSDoc: opts.OmitJSDoc,
 		})
 	case FormatSCSS:
-		f = scss.New()
+		f = scss.NewWithOptions(scss.Options{
+			OmitGroupComments:   opts.OmitGroupComments,
+			Map:                 opts.SCSSMap,
+			Hex8:                opts.Hex8,
+			LegacyColors:        opts.ColorSyntax == ColorSyntaxLegacy,
+			IncludePlaceholders: opts.IncludePlaceholders,
+		})
 	case FormatCSS:
-		f = css.NewWithOptions(css.Options{
-			Selector: css.Selector(opts.CSSSelector),
-			Module:   css.Module(opts.CSSModule),
+		f = cssFormatter(opts)
+	case FormatCustomMedia:
+		f = custommedia.NewWithOptions(custommedia.Options{
+			Group: opts.CustomMediaGroup,
 		})
 	case FormatSnippets:
 		f = snippets.NewWithOptions(snippets.Options{
 			Type: snippets.Type(opts.SnippetType),
 		})
+	case FormatTokensStudio:
+		f = figmatokens.New(func(t []*token.Token) map[string]any {
+			// Tokens Studio reads draft-style values and references
+			return Serialize(t, Options{
+				InputSchema:       opts.InputSchema,
+				OutputSchema:      schema.Draft,
+				StripExtensions:   opts.StripExtensions,
+				StripDescriptions: opts.StripDescriptions,
+			})
+		})
+	case FormatHTML:
+		f = html.New()
+	case FormatIOSAssets:
+		return nil, fmt.Errorf("%s writes a directory of files; use FormatAssetCatalog", format)
+	case FormatTemplate:
+		if opts.Template == "" {
+			return nil, fmt.Errorf("template format requires a template")
+		}
+		f = custom.NewWithOptions(custom.Options{
+			Template: opts.Template,
+		})
 	default:
 		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
 
 	return f.Format(tokens, fmtOpts)
 }
+
+// FormatThemes converts several themes to a single CSS file, with one
+// block of custom properties per theme selected by an attribute, e.g.
+// [data-theme="dark"] for the attribute data-theme. The CSS options in
+// opts apply to every block.
+func FormatThemes(themes []css.Theme, attribute string, opts Options) ([]byte, error) {
+	return cssFormatter(opts).FormatThemes(themes, attribute, formatterOptions(opts))
+}
+
+// FormatAssetCatalog converts color tokens to an Xcode asset catalog,
+// returning each file's content keyed by its slash-separated path within
+// the .xcassets directory.
+func FormatAssetCatalog(tokens []*token.Token, opts Options) (map[string][]byte, error) {
+	return xcassets.New().Format(tokens, formatterOptions(opts))
+}
+
+// formatterOptions returns the options shared by all formatters.
+func formatterOptions(opts Options) formatter.Options {
+	return formatter.Options{
+		Prefix:        opts.Prefix,
+		Delimiter:     opts.Delimiter,
+		Header:        opts.Header,
+		NameTransform: opts.NameTransform,
+		OnWarning:     opts.OnWarning,
+	}
+}
+
+// cssFormatter returns a CSS formatter configured from opts.
+func cssFormatter(opts Options) *css.Formatter {
+	return css.NewWithOptions(css.Options{
+		Selector:            css.Selector(opts.CSSSelector),
+		Module:              css.Module(opts.CSSModule),
+		Hex8:                opts.Hex8,
+		LegacyColors:        opts.ColorSyntax == ColorSyntaxLegacy,
+		References:          opts.CSSReferences,
+		IncludePlaceholders: opts.IncludePlaceholders,
+	})
+}
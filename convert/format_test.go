@@ -44,6 +44,8 @@ func TestParseFormat(t *testing.T) {
 	}{
 		{"dtcg", convert.FormatDTCG, false},
 		{"", convert.FormatDTCG, false},
+		{"yaml", convert.FormatYAML, false},
+		{"yml", convert.FormatYAML, false},
 		{"json", convert.FormatFlatJSON, false},
 		{"flat", convert.FormatFlatJSON, false},
 		{"flat-json", convert.FormatFlatJSON, false},
@@ -55,6 +57,14 @@ func TestParseFormat(t *testing.T) {
 		{"javascript", convert.FormatJS, false},
 		{"scss", convert.FormatSCSS, false},
 		{"sass", convert.FormatSCSS, false},
+		{"tokens-studio", convert.FormatTokensStudio, false},
+		{"figma-tokens", convert.FormatTokensStudio, false},
+		{"html", convert.FormatHTML, false},
+		{"ios-assets", convert.FormatIOSAssets, false},
+		{"xcassets", convert.FormatIOSAssets, false},
+		{"template", convert.FormatTemplate, false},
+		{"css-custom-media", convert.FormatCustomMedia, false},
+		{"custom-media", convert.FormatCustomMedia, false},
 		{"invalid", "", true},
 		{"typescript", "", true},
 		{"ts", "", true},
@@ -223,6 +233,44 @@ func TestFormatTokens_SCSS(t *testing.T) {
 	}
 }
 
+func TestFormatTokens_NameTransform(t *testing.T) {
+	tokens := loadTestTokens(t)
+	opts := convert.DefaultOptions()
+	opts.Prefix = "rh"
+	// Drop the redundant color group from color token names
+	opts.NameTransform = func(path []string, tok *token.Token) string {
+		if tok.Type == token.TypeColor && len(path) > 1 && path[0] == "color" {
+			path = path[1:]
+		}
+		return "rh-" + strings.Join(path, "-")
+	}
+
+	for _, tt := range []struct {
+		format convert.Format
+		want   []string
+	}{
+		{convert.FormatCSS, []string{"--rh-primary:", "--rh-spacing-small:"}},
+		{convert.FormatSCSS, []string{"$rh-primary:", "$rh-spacing-small:"}},
+		{convert.FormatFlatJSON, []string{`"rh-primary":`, `"rh-spacing-small":`}},
+	} {
+		t.Run(string(tt.format), func(t *testing.T) {
+			output, err := convert.FormatTokens(tokens, tt.format, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			result := string(output)
+			for _, want := range tt.want {
+				if !strings.Contains(result, want) {
+					t.Errorf("expected %q in output:\n%s", want, result)
+				}
+			}
+			if strings.Contains(result, "rh-color-primary") {
+				t.Errorf("expected transform to replace default name, got:\n%s", result)
+			}
+		})
+	}
+}
+
 func TestFormatTokens_DTCG(t *testing.T) {
 	tokens := loadTestTokens(t)
 	opts := convert.DefaultOptions()
@@ -278,7 +326,7 @@ func TestFormatTokens_JS_CJS(t *testing.T) {
 func TestValidFormats(t *testing.T) {
 	formats := convert.ValidFormats()
 
-	expected := []string{"dtcg", "json", "android", "swift", "js", "scss", "css", "snippets"}
+	expected := []string{"dtcg", "yaml", "json", "android", "swift", "js", "scss", "css", "css-custom-media", "snippets", "tokens-studio", "html", "ios-assets", "template"}
 	if len(formats) != len(expected) {
 		t.Errorf("expected %d formats, got %d: %v", len(expected), len(formats), formats)
 	}
@@ -289,3 +337,59 @@ func TestValidFormats(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatTokens_StableKeyOrder(t *testing.T) {
+	// Each run parses afresh, so composite values and $extensions are
+	// new maps whose iteration order differs between runs
+	format := func(f convert.Format) []byte {
+		mfs := testutil.NewFixtureFS(t, "fixtures/convert/composites", "/test")
+		tokens, err := parser.NewJSONParser().ParseFile(mfs, "/test/tokens.json", parser.Options{
+			SchemaVersion: schema.Draft,
+			SkipPositions: true,
+		})
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+		if err := resolver.ResolveAliases(tokens, schema.Draft); err != nil {
+			t.Fatalf("failed to resolve aliases: %v", err)
+		}
+		opts := convert.DefaultOptions()
+		opts.InputSchema = schema.Draft
+		data, err := convert.FormatTokens(tokens, f, opts)
+		if err != nil {
+			t.Fatalf("FormatTokens(%s) error: %v", f, err)
+		}
+		return data
+	}
+
+	for _, f := range []convert.Format{
+		convert.FormatDTCG,
+		convert.FormatYAML,
+		convert.FormatFlatJSON,
+		convert.FormatJS,
+		convert.FormatSwift,
+		convert.FormatSCSS,
+		convert.FormatCSS,
+	} {
+		t.Run(string(f), func(t *testing.T) {
+			first := format(f)
+			for range 10 {
+				if again := format(f); string(again) != string(first) {
+					t.Fatalf("output changed between runs:\nfirst:\n%s\nagain:\n%s", first, again)
+				}
+			}
+		})
+	}
+
+	// Object keys are written sorted
+	for f, golden := range map[convert.Format]string{
+		convert.FormatDTCG: "fixtures/convert/composites/expected.json",
+		convert.FormatJS:   "fixtures/convert/composites/expected.ts",
+	} {
+		actual := format(f)
+		testutil.UpdateGoldenFile(t, golden, actual)
+		if expected := testutil.LoadFixtureFile(t, golden); string(actual) != string(expected) {
+			t.Errorf("%s output mismatch.\n\nExpected:\n%s\n\nActual:\n%s", f, expected, actual)
+		}
+	}
+}
@@ -38,6 +38,7 @@ func New() *Formatter {
 
 // Format converts tokens to Android XML resource format.
 func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
+	tokens = formatter.WithoutPlaceholders(tokens)
 	var sb strings.Builder
 	sb.WriteString(`<?xml version="1.0" encoding="utf-8"?>`)
 	sb.WriteString("\n")
@@ -52,12 +53,11 @@ func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byt
 
 	for _, tok := range sorted {
 		baseName := formatter.ToSnakeCase(strings.Join(tok.Path, "_"))
-		name := formatter.ApplyPrefix(baseName, opts.Prefix, "_")
-		value := toAndroidValue(tok)
-		xmlType := xmlType(tok.Type)
+		name := opts.TokenName(tok, formatter.ApplyPrefix(baseName, opts.Prefix, "_"))
+		res := resourceFor(tok)
 
-		fmt.Fprintf(&sb, "    <%s name=\"%s\">%s</%s>\n",
-			xmlType, formatter.EscapeXML(name), formatter.EscapeXML(value), xmlType)
+		fmt.Fprintf(&sb, "    <%s name=\"%s\"%s>%s</%s>\n",
+			res.element, formatter.EscapeXML(name), res.attrs, formatter.EscapeXML(res.value), res.element)
 	}
 
 	sb.WriteString("</resources>\n")
@@ -70,7 +70,7 @@ func toAndroidValue(tok *token.Token) string {
 
 	switch tok.Type {
 	case token.TypeColor:
-		if m, ok := value.(map[string]any); ok {
+		if m, ok := value.(map[string]any); ok && tok.IsStructuredColor() {
 			return structuredColorToAndroid(m, tok.Name)
 		}
 	case token.TypeDimension:
@@ -200,17 +200,75 @@ func colorSpaceToSRGB(space string, c []float64) (r, g, b float64) {
 func clamp(v, lo, hi int) int     { return max(lo, min(hi, v)) }
 func clampF(v float64) float64    { return max(0, min(1, v)) }
 
-func xmlType(tokenType string) string {
-	switch tokenType {
+// resource is the XML element, extra attributes, and unescaped text
+// content for a token.
+type resource struct {
+	element string
+	attrs   string
+	value   string
+}
+
+// resourceFor routes a token to its Android resource element. Whole
+// numbers and font weights become <integer>, other numbers a float
+// <item>, booleans <bool>, and strings and any type without a dedicated
+// resource a <string>.
+func resourceFor(tok *token.Token) resource {
+	switch tok.Type {
 	case token.TypeColor:
-		return "color"
+		return resource{element: "color", value: toAndroidValue(tok)}
 	case token.TypeDimension:
-		return "dimen"
-	case token.TypeNumber:
-		return "integer"
-	case token.TypeString, token.TypeFontFamily:
-		return "string"
-	default:
-		return "string"
+		return resource{element: "dimen", value: toAndroidValue(tok)}
+	case token.TypeNumber, token.TypeFontWeight:
+		if n, ok := numericValue(tok); ok {
+			if n == float64(int64(n)) {
+				return resource{element: "integer", value: fmt.Sprintf("%d", int64(n))}
+			}
+			return resource{element: "item", attrs: ` type="dimen" format="float"`, value: fmt.Sprintf("%g", n)}
+		}
+	case token.TypeBoolean:
+		if b, ok := formatter.ResolvedValue(tok).(bool); ok {
+			return resource{element: "bool", value: fmt.Sprintf("%t", b)}
+		}
+	}
+	return resource{element: "string", value: escapeAndroidString(toAndroidValue(tok))}
+}
+
+// fontWeights maps the DTCG font weight keywords to numeric weights.
+var fontWeights = map[string]float64{
+	"thin": 100, "hairline": 100,
+	"extra-light": 200, "ultra-light": 200,
+	"light":  300,
+	"normal": 400, "regular": 400, "book": 400,
+	"medium":    500,
+	"semi-bold": 600, "demi-bold": 600,
+	"bold":       700,
+	"extra-bold": 800, "ultra-bold": 800,
+	"black": 900, "heavy": 900,
+	"extra-black": 950, "ultra-black": 950,
+}
+
+// numericValue returns a number or font weight token's value as a float.
+func numericValue(tok *token.Token) (float64, bool) {
+	switch v := formatter.ResolvedValue(tok).(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		if w, ok := fontWeights[v]; ok && tok.Type == token.TypeFontWeight {
+			return w, true
+		}
+	}
+	return 0, false
+}
+
+// escapeAndroidString escapes the characters that aapt treats specially
+// in string resources: backslashes, quotes, newlines, and a leading @ or ?
+// that would otherwise be read as a resource reference.
+func escapeAndroidString(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `"`, `\"`, "\n", `\n`).Replace(s)
+	if strings.HasPrefix(s, "@") || strings.HasPrefix(s, "?") {
+		s = `\` + s
 	}
+	return s
 }
@@ -164,6 +164,61 @@ func TestFormat_UnknownTokenType(t *testing.T) {
 	}
 }
 
+func TestFormat_ResourceTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      string
+		value    any
+		expected string
+	}{
+		{"integer number", token.TypeNumber, float64(3), `<integer name="tok">3</integer>`},
+		{"float number", token.TypeNumber, 1.5, `<item name="tok" type="dimen" format="float">1.5</item>`},
+		{"numeric font weight", token.TypeFontWeight, float64(600), `<integer name="tok">600</integer>`},
+		{"keyword font weight", token.TypeFontWeight, "semi-bold", `<integer name="tok">600</integer>`},
+		{"unknown font weight", token.TypeFontWeight, "chunky", `<string name="tok">chunky</string>`},
+		{"true boolean", token.TypeBoolean, true, `<bool name="tok">true</bool>`},
+		{"false boolean", token.TypeBoolean, false, `<bool name="tok">false</bool>`},
+		{"string", token.TypeString, "Sign in", `<string name="tok">Sign in</string>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := []*token.Token{{Name: "tok", Path: []string{"tok"}, Type: tt.typ, RawValue: tt.value}}
+			result, err := android.New().Format(tokens, formatter.Options{})
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if !strings.Contains(string(result), tt.expected) {
+				t.Errorf("expected %s, got:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormat_StringEscaping(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{`Don't <panic> & "relax"`, `<string name="tok">Don\&apos;t &lt;panic&gt; &amp; \&quot;relax\&quot;</string>`},
+		{"line one\nline two", `<string name="tok">line one\nline two</string>`},
+		{`C:\tokens`, `<string name="tok">C:\\tokens</string>`},
+		{"@handle", `<string name="tok">\@handle</string>`},
+		{"?attr", `<string name="tok">\?attr</string>`},
+	}
+
+	for _, tt := range tests {
+		tokens := []*token.Token{{Name: "tok", Path: []string{"tok"}, Type: token.TypeString, RawValue: tt.value}}
+		result, err := android.New().Format(tokens, formatter.Options{})
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+		if !strings.Contains(string(result), tt.expected) {
+			t.Errorf("Format(%q): expected %s, got:\n%s", tt.value, tt.expected, result)
+		}
+	}
+}
+
 func TestFormat_WideGamutColorSpaces(t *testing.T) {
 	allTokens := testutil.ParseFixtureTokens(t, "fixtures/v2025_10/all-color-spaces", schema.V2025_10)
 
@@ -354,4 +409,21 @@ func TestFormat_LowValueColorSpaces(t *testing.T) {
 			t.Errorf("expected token %q for %s, got:\n%s", expectedToken, tc.name, output)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestFormat_SkipsPlaceholders(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	result, err := android.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := string(result)
+	for _, name := range []string{`"color_brand"`, `"color_link"`} {
+		if strings.Contains(output, name) {
+			t.Errorf("expected placeholder %s to be left out, got:\n%s", name, output)
+		}
+	}
+	if !strings.Contains(output, `<color name="color_text">`) {
+		t.Errorf("expected %s to be kept, got:\n%s", `<color name="color_text">`, output)
+	}
+}
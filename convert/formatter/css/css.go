@@ -10,11 +10,10 @@ package css
 import (
 	"encoding/json"
 	"fmt"
-	"regexp"
+	"strconv"
 	"strings"
 
 	"bennypowers.dev/asimonim/convert/formatter"
-	"bennypowers.dev/asimonim/parser/common"
 	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/token"
 )
@@ -50,10 +49,26 @@ type Options struct {
 	// Module controls the JavaScript module wrapper.
 	// Empty string means plain CSS output.
 	Module Module
-}
 
-// secondsDurationPattern matches duration values like "2s", "0.5s", "-1.5s".
-var secondsDurationPattern = regexp.MustCompile(`^[+-]?\d+(\.\d+)?s$`)
+	// Hex8 writes translucent sRGB colors as #RRGGBBAA instead of
+	// color(srgb ... / alpha).
+	Hex8 bool
+
+	// LegacyColors writes sRGB, hsl and hwb colors with the legacy rgb(),
+	// rgba(), hsl() and hsla() functions, for tools that reject color()
+	// and the space-separated syntax. Hex8 takes precedence.
+	LegacyColors bool
+
+	// References writes aliases as var() calls to the property of the
+	// token they alias, so overriding that property also changes the
+	// alias. Aliases of tokens missing from the output keep their value.
+	References bool
+
+	// IncludePlaceholders writes tokens with no value, such as
+	// "$value": null, as "initial", the guaranteed-invalid value, for
+	// themes to override. By default they are left out.
+	IncludePlaceholders bool
+}
 
 // Formatter outputs CSS custom properties.
 type Formatter struct {
@@ -83,7 +98,57 @@ func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byt
 		selector = SelectorRoot
 	}
 
-	// Write header/preamble
+	f.writePreamble(&sb)
+	f.writeBlock(&sb, string(selector), tokens, opts)
+	f.writeClosing(&sb)
+
+	return []byte(sb.String()), nil
+}
+
+// Theme is a named set of tokens for FormatThemes.
+type Theme struct {
+	// Name is the attribute value that selects the theme.
+	Name string
+
+	// Tokens are the theme's tokens.
+	Tokens []*token.Token
+}
+
+// FormatThemes writes one block of custom properties per theme, each
+// selected by an attribute with the theme's name as its value, e.g.
+// [data-theme="dark"]. Themes share property names, so whichever theme
+// applies to an element sets its values. With SelectorHost the blocks
+// select the host element, e.g. :host([data-theme="dark"]).
+func (f *Formatter) FormatThemes(themes []Theme, attribute string, opts formatter.Options) ([]byte, error) {
+	var sb strings.Builder
+
+	f.writePreamble(&sb)
+	for i, theme := range themes {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		f.writeBlock(&sb, f.themeSelector(attribute, theme.Name), theme.Tokens, opts)
+	}
+	f.writeClosing(&sb)
+
+	return []byte(sb.String()), nil
+}
+
+// themeSelector returns the selector for the theme called name.
+func (f *Formatter) themeSelector(attribute, name string) string {
+	attr := fmt.Sprintf("[%s=%s]", attribute, strconv.Quote(name))
+	switch f.opts.Selector {
+	case "", SelectorRoot:
+		return attr
+	case SelectorHost:
+		return ":host(" + attr + ")"
+	default:
+		return string(f.opts.Selector) + attr
+	}
+}
+
+// writePreamble writes the generated-file comment or module opening.
+func (f *Formatter) writePreamble(sb *strings.Builder) {
 	switch f.opts.Module {
 	case ModuleLit:
 		sb.WriteString("import { css } from 'lit';\n\n")
@@ -92,92 +157,76 @@ func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byt
 		sb.WriteString("/* Generated by asimonim */\n")
 		sb.WriteString("/* Do not edit manually */\n\n")
 	}
+}
 
-	// Write selector
-	fmt.Fprintf(&sb, "%s {\n", selector)
-
-	sorted := formatter.SortTokens(tokens)
-
-	for _, tok := range sorted {
-		baseName := formatter.ToKebabCase(strings.Join(tok.Path, "-"))
-		name := formatter.ApplyPrefix(baseName, opts.Prefix, "-")
+// writeClosing closes the module opened by writePreamble.
+func (f *Formatter) writeClosing(sb *strings.Builder) {
+	if f.opts.Module == ModuleLit {
+		sb.WriteString("`;\n")
+	}
+}
 
-		value := formatter.ResolvedValue(tok)
-		cssValue := ToCSSValue(tok.Type, value)
+// writeBlock writes tokens as custom properties in a rule for selector.
+func (f *Formatter) writeBlock(sb *strings.Builder, selector string, tokens []*token.Token, opts formatter.Options) {
+	fmt.Fprintf(sb, "%s {\n", selector)
 
-		if tok.Description != "" {
-			fmt.Fprintf(&sb, "  /* %s */\n", tok.Description)
-		}
-		fmt.Fprintf(&sb, "  --%s: %s;\n", name, cssValue)
+	if !f.opts.IncludePlaceholders {
+		tokens = formatter.WithoutPlaceholders(tokens)
 	}
 
-	sb.WriteString("}\n")
-
-	// Write module closing
-	if f.opts.Module == ModuleLit {
-		sb.WriteString("`;\n")
+	var byName map[string]*token.Token
+	if f.opts.References {
+		byName = make(map[string]*token.Token, len(tokens))
+		for _, tok := range tokens {
+			byName[tok.Name] = tok
+		}
 	}
 
-	return []byte(sb.String()), nil
-}
+	for _, tok := range formatter.SortTokens(tokens) {
+		name := propertyName(tok, opts)
 
-// ToCSSValue converts a token value to a CSS-compatible string.
-func ToCSSValue(tokenType string, value any) string {
-	switch tokenType {
-	case token.TypeColor:
-		if m, ok := value.(map[string]any); ok {
-			// Structured color objects are a v2025.10 feature; draft colors are always strings.
-			if colorVal, err := common.ParseColorValue(m, schema.V2025_10); err == nil {
-				return colorVal.ToCSS()
-			}
-			return formatter.MarshalFallback(m)
+		cssValue, ok := tok.CSSValue()
+		if tok.IsPlaceholder() {
+			cssValue, ok = "initial", true
 		}
-		return fmt.Sprintf("%v", value)
-	case token.TypeDimension:
-		if m, ok := value.(map[string]any); ok {
-			if v, hasValue := m["value"]; hasValue && v != nil {
-				if u, hasUnit := m["unit"].(string); hasUnit {
-					return fmt.Sprintf("%v%s", v, u)
-				}
-			}
-			return formatter.MarshalFallback(m)
+		if !ok {
+			opts.Warn(tok, "skipped, since its value has no CSS representation")
+			continue
 		}
-		return fmt.Sprintf("%v", value)
-	case token.TypeNumber, token.TypeFontWeight:
-		switch v := value.(type) {
-		case float64:
-			if v == float64(int(v)) {
-				return fmt.Sprintf("%d", int(v))
-			}
-			return fmt.Sprintf("%g", v)
-		case int:
-			return fmt.Sprintf("%d", v)
+		if hex, ok := formatter.Hex8Color(tok); ok && f.opts.Hex8 {
+			cssValue = hex
+		} else if legacy, ok := formatter.LegacyColor(tok); ok && f.opts.LegacyColors {
+			cssValue = legacy
 		}
-		return fmt.Sprintf("%v", value)
-	case token.TypeFontFamily:
-		if s, ok := value.(string); ok {
-			// Quote font names with spaces
-			if strings.Contains(s, " ") && !strings.HasPrefix(s, "\"") && !strings.HasPrefix(s, "'") {
-				return fmt.Sprintf("%q", s)
+		// A reference with overrides has no var() form, so it keeps the merged value
+		if _, _, partial := token.PartialRef(tok.RawValue); len(tok.ResolutionChain) > 0 && !partial {
+			if target, ok := byName[tok.ResolutionChain[0]]; ok {
+				cssValue = "var(--" + propertyName(target, opts) + ")"
 			}
-			return s
 		}
-	case token.TypeCubicBezier:
-		if arr, ok := value.([]any); ok && len(arr) == 4 {
-			return fmt.Sprintf("cubic-bezier(%v, %v, %v, %v)", arr[0], arr[1], arr[2], arr[3])
+
+		if tok.Description != "" {
+			fmt.Fprintf(sb, "  /* %s */\n", tok.Description)
 		}
+		fmt.Fprintf(sb, "  --%s: %s;\n", name, cssValue)
 	}
 
-	if s, ok := value.(string); ok {
-		// Values that are already valid CSS
-		if strings.HasPrefix(s, "#") || strings.HasSuffix(s, "px") ||
-			strings.HasSuffix(s, "rem") || strings.HasSuffix(s, "em") ||
-			strings.HasSuffix(s, "%") || strings.HasSuffix(s, "ms") ||
-			strings.HasSuffix(s, "vw") || strings.HasSuffix(s, "vh") ||
-			strings.HasSuffix(s, "deg") || strings.HasSuffix(s, "rad") ||
-			secondsDurationPattern.MatchString(s) {
-			return s
-		}
+	sb.WriteString("}\n")
+}
+
+// propertyName returns a token's custom property name, without dashes.
+func propertyName(tok *token.Token, opts formatter.Options) string {
+	baseName := formatter.ToKebabCase(strings.Join(tok.Path, "-"))
+	return opts.TokenName(tok, formatter.ApplyPrefix(baseName, opts.Prefix, "-"))
+}
+
+// ToCSSValue converts a token value to a CSS-compatible string.
+// It formats value as (*token.Token).CSSValue would for a token of the
+// given type, falling back to JSON for values with no CSS representation.
+func ToCSSValue(tokenType string, value any) string {
+	tok := &token.Token{Type: tokenType, RawValue: value, SchemaVersion: schema.V2025_10}
+	if s, ok := tok.CSSValue(); ok {
+		return s
 	}
 
 	// Avoid rendering maps/slices as Go literals
@@ -9,6 +9,7 @@ package css_test
 import (
 	"encoding/json"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -442,8 +443,9 @@ func TestToCSSValue_DimensionNilValue(t *testing.T) {
 }
 
 func TestToCSSValue_MapFallback(t *testing.T) {
-	// Non-color maps should JSON serialize
-	value := map[string]any{"fontFamily": "Arial", "fontSize": "16px"}
+	// Maps without a CSS representation should JSON serialize.
+	// A font shorthand needs a size, so this typography value has none.
+	value := map[string]any{"fontFamily": "Arial", "letterSpacing": "1px"}
 	result := css.ToCSSValue("typography", value)
 	if !strings.Contains(result, "fontFamily") {
 		t.Errorf("expected JSON-serialized map, got %q", result)
@@ -523,3 +525,250 @@ func TestDimensionMissingUnit(t *testing.T) {
 		t.Errorf("dimension without unit rendered as Go map literal: %q", result)
 	}
 }
+
+func TestFormat_Hex8(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name: "color-overlay", Path: []string{"color", "overlay"}, Type: token.TypeColor,
+			SchemaVersion: schema.V2025_10,
+			RawValue:      map[string]any{"colorSpace": "srgb", "components": []any{0.0, 0.0, 0.0}, "alpha": 0.5},
+		},
+		{
+			Name: "color-solid", Path: []string{"color", "solid"}, Type: token.TypeColor,
+			SchemaVersion: schema.V2025_10,
+			RawValue:      map[string]any{"colorSpace": "srgb", "components": []any{1.0, 0.0, 0.0}, "alpha": 1.0},
+		},
+	}
+
+	plain, err := css.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	if !strings.Contains(string(plain), "--color-overlay: color(srgb 0 0 0 / 0.5);") {
+		t.Errorf("expected color() without Hex8, got:\n%s", plain)
+	}
+
+	result, err := css.NewWithOptions(css.Options{Hex8: true}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	output := string(result)
+	if !strings.Contains(output, "--color-overlay: #00000080;") {
+		t.Errorf("expected 8-digit hex for translucent color, got:\n%s", output)
+	}
+	if !strings.Contains(output, "--color-solid: #FF0000;") {
+		t.Errorf("expected 6-digit hex for opaque color, got:\n%s", output)
+	}
+}
+
+func TestFormat_LegacyColors(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name: "color-overlay", Path: []string{"color", "overlay"}, Type: token.TypeColor,
+			SchemaVersion: schema.V2025_10,
+			RawValue:      map[string]any{"colorSpace": "srgb", "components": []any{0.0, 0.0, 0.0}, "alpha": 0.5},
+		},
+		{
+			Name: "color-muted", Path: []string{"color", "muted"}, Type: token.TypeColor,
+			SchemaVersion: schema.V2025_10,
+			RawValue:      map[string]any{"colorSpace": "hsl", "components": []any{210.0, 20.0, 50.0}},
+		},
+		{
+			Name: "color-vivid", Path: []string{"color", "vivid"}, Type: token.TypeColor,
+			SchemaVersion: schema.V2025_10,
+			RawValue:      map[string]any{"colorSpace": "display-p3", "components": []any{0.0, 1.0, 0.0}},
+		},
+	}
+
+	modern, err := css.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	legacy, err := css.NewWithOptions(css.Options{LegacyColors: true}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+
+	for _, tt := range []struct{ modern, legacy string }{
+		{"--color-overlay: color(srgb 0 0 0 / 0.5);", "--color-overlay: rgba(0, 0, 0, 0.5);"},
+		{"--color-muted: hsl(210 20 50);", "--color-muted: hsl(210, 20%, 50%);"},
+		{"--color-vivid: color(display-p3 0 1 0);", "--color-vivid: color(display-p3 0 1 0);"},
+	} {
+		if !strings.Contains(string(modern), tt.modern) {
+			t.Errorf("expected modern output to contain %q, got:\n%s", tt.modern, modern)
+		}
+		if !strings.Contains(string(legacy), tt.legacy) {
+			t.Errorf("expected legacy output to contain %q, got:\n%s", tt.legacy, legacy)
+		}
+	}
+
+	both, err := css.NewWithOptions(css.Options{LegacyColors: true, Hex8: true}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	if !strings.Contains(string(both), "--color-overlay: #00000080;") {
+		t.Errorf("expected Hex8 to take precedence, got:\n%s", both)
+	}
+}
+
+func TestFormat_References(t *testing.T) {
+	tokens, err := parser.NewJSONParser().Parse([]byte(`{
+  "color": {
+    "$type": "color",
+    "brand": { "$value": "#ff0000" },
+    "action": { "$value": "{color.brand}" },
+    "hover": { "$value": "{color.action}" }
+  }
+}`), parser.Options{})
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if err := resolver.ResolveAliases(tokens, schema.Draft); err != nil {
+		t.Fatalf("ResolveAliases error: %v", err)
+	}
+
+	plain, err := css.New().Format(tokens, formatter.Options{Prefix: "ds"})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	if !strings.Contains(string(plain), "--ds-color-action: #ff0000;") {
+		t.Errorf("expected resolved alias without References, got:\n%s", plain)
+	}
+
+	result, err := css.NewWithOptions(css.Options{References: true}).Format(tokens, formatter.Options{Prefix: "ds"})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	output := string(result)
+	for _, want := range []string{
+		"--ds-color-brand: #ff0000;",
+		"--ds-color-action: var(--ds-color-brand);",
+		"--ds-color-hover: var(--ds-color-action);",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q, got:\n%s", want, output)
+		}
+	}
+
+	// An alias whose target isn't in the output keeps its value
+	action := testutil.TokenByPath(t, tokens, "color.action")
+	partial, err := css.NewWithOptions(css.Options{References: true}).Format([]*token.Token{action}, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	if !strings.Contains(string(partial), "--color-action: #ff0000;") {
+		t.Errorf("expected resolved value for an alias of a missing token, got:\n%s", partial)
+	}
+}
+
+func TestFormat_ReferencesPartialRef(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/v2025_10/partial-refs", schema.V2025_10)
+
+	result, err := css.NewWithOptions(css.Options{References: true}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	output := string(result)
+	if !strings.Contains(output, "--shadow-alias: var(--shadow-base);") {
+		t.Errorf("expected a whole-value $ref as var(), got:\n%s", output)
+	}
+	if !strings.Contains(output, "--shadow-raised: 0px 8px 16px color(srgb 1 0.4 0.2 / 0.5);") {
+		t.Errorf("expected a $ref with overrides to keep its merged value, got:\n%s", output)
+	}
+}
+
+func TestFormat_Placeholders(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	result, err := css.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	output := string(result)
+	if strings.Contains(output, "--color-brand") || strings.Contains(output, "--color-link") {
+		t.Errorf("expected placeholders to be left out by default, got:\n%s", output)
+	}
+	if !strings.Contains(output, "--color-text: #1b1b1b;") {
+		t.Errorf("expected other tokens to be kept, got:\n%s", output)
+	}
+
+	result, err = css.NewWithOptions(css.Options{IncludePlaceholders: true, References: true}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	output = string(result)
+	for _, want := range []string{
+		"/* Set by each theme */\n  --color-brand: initial;",
+		"--color-link: var(--color-brand);",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestFormatThemes(t *testing.T) {
+	light := []*token.Token{{Name: "color-bg", Path: []string{"color", "bg"}, Type: token.TypeColor, Value: "#fff"}}
+	dark := []*token.Token{{Name: "color-bg", Path: []string{"color", "bg"}, Type: token.TypeColor, Value: "#000"}}
+	themes := []css.Theme{{Name: "light", Tokens: light}, {Name: "dark", Tokens: dark}}
+
+	tests := []struct {
+		name     string
+		opts     css.Options
+		contains []string
+	}{
+		{"root", css.Options{}, []string{
+			"[data-theme=\"light\"] {\n  --color-bg: #fff;\n}\n\n[data-theme=\"dark\"] {\n  --color-bg: #000;\n}\n",
+		}},
+		{"host", css.Options{Selector: css.SelectorHost}, []string{
+			`:host([data-theme="light"]) {`,
+			`:host([data-theme="dark"]) {`,
+		}},
+		{"lit", css.Options{Module: css.ModuleLit}, []string{
+			"export default css`\n[data-theme=\"light\"] {",
+			"--color-bg: #000;\n}\n`;\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := css.NewWithOptions(tt.opts).FormatThemes(themes, "data-theme", formatter.Options{})
+			if err != nil {
+				t.Fatalf("FormatThemes error: %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(result), want) {
+					t.Errorf("expected %q, got:\n%s", want, result)
+				}
+			}
+		})
+	}
+
+	result, err := css.New().FormatThemes([]css.Theme{{Name: `a"b`, Tokens: light}}, "data-theme", formatter.Options{})
+	if err != nil {
+		t.Fatalf("FormatThemes error: %v", err)
+	}
+	if !strings.Contains(string(result), `[data-theme="a\"b"] {`) {
+		t.Errorf("expected the theme name to be escaped, got:\n%s", result)
+	}
+}
+
+func TestFormat_WarnsAboutSkippedTokens(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "shadow-odd", Path: []string{"shadow", "odd"}, Type: token.TypeShadow, RawValue: map[string]any{"offsetX": "wide"}},
+		{Name: "color-primary", Path: []string{"color", "primary"}, Type: token.TypeColor, Value: "#FF6B35", RawValue: "#FF6B35"},
+	}
+
+	var warnings []string
+	opts := formatter.Options{OnWarning: func(w formatter.Warning) { warnings = append(warnings, w.String()) }}
+	result, err := css.New().Format(tokens, opts)
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	if strings.Contains(string(result), "--shadow-odd") || !strings.Contains(string(result), "--color-primary") {
+		t.Errorf("expected only shadow-odd to be skipped, got:\n%s", result)
+	}
+	want := []string{"shadow.odd: skipped, since its value has no CSS representation"}
+	if !slices.Equal(warnings, want) {
+		t.Errorf("warnings = %q, want %q", warnings, want)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package custom provides user-defined text/template formatting for design tokens.
+package custom
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/token"
+)
+
+// Options configures the custom template formatter.
+type Options struct {
+	formatter.Options
+
+	// Template is the text/template source used to render output.
+	Template string
+}
+
+// Formatter renders tokens through a user-supplied Go text/template.
+type Formatter struct {
+	opts Options
+}
+
+// NewWithOptions creates a new template formatter with the specified options.
+func NewWithOptions(opts Options) *Formatter {
+	return &Formatter{opts: opts}
+}
+
+// templateData is the root value passed to the template.
+type templateData struct {
+	Tokens []Token
+	Prefix string
+	Header string
+}
+
+// Token is the view of a design token exposed to templates.
+type Token struct {
+	Name               string   // Token name (e.g., "color-primary")
+	CSSVar             string   // CSS custom property name with prefix (e.g., "--rh-color-primary")
+	DotPath            string   // Dot-separated path (e.g., "color.primary")
+	Type               string   // Token type
+	DisplayValue       string   // Human-readable resolved value
+	Description        string   // Token description
+	Deprecated         bool     // Whether this token is deprecated
+	DeprecationMessage string   // Optional message explaining deprecation
+	Path               []string // Token path segments
+
+	tok *token.Token
+}
+
+// funcs are the helper functions available to templates.
+var funcs = template.FuncMap{
+	"toKebab":  formatter.ToKebabCase,
+	"toCamel":  formatter.ToCamelCase,
+	"resolved": resolved,
+}
+
+// resolved returns the resolved value of a token, for templates that need
+// the raw structured value rather than DisplayValue.
+func resolved(t Token) any {
+	return formatter.ResolvedValue(t.tok)
+}
+
+// Format executes the template against the sorted tokens.
+func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
+	tokens = formatter.WithoutPlaceholders(tokens)
+	tmpl, err := template.New("custom").Funcs(funcs).Parse(f.opts.Template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	sorted := formatter.SortTokens(tokens)
+	data := templateData{
+		Tokens: make([]Token, 0, len(sorted)),
+		Prefix: opts.Prefix,
+		Header: opts.Header,
+	}
+	for _, tok := range sorted {
+		name := opts.TokenName(tok, formatter.ApplyPrefix(formatter.ToKebabCase(strings.Join(tok.Path, "-")), opts.Prefix, "-"))
+		data.Tokens = append(data.Tokens, Token{
+			Name:               tok.Name,
+			CSSVar:             "--" + name,
+			DotPath:            tok.DotPath(),
+			Type:               tok.Type,
+			DisplayValue:       tok.DisplayValue(),
+			Description:        tok.Description,
+			Deprecated:         tok.Deprecated,
+			DeprecationMessage: tok.DeprecationMessage,
+			Path:               tok.Path,
+			tok:                tok,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package custom_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/convert/formatter/custom"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/testutil"
+	"bennypowers.dev/asimonim/token"
+)
+
+func TestFormat_Basic(t *testing.T) {
+	fixturePath := filepath.Join("fixtures", "basic")
+	tokens := testutil.ParseFixtureTokens(t, fixturePath, schema.Draft)
+
+	tmpl := testutil.LoadFixtureFile(t, filepath.Join(fixturePath, "template.tmpl"))
+
+	fmtOpts := formatter.Options{}
+	optData := testutil.LoadFixtureFile(t, filepath.Join(fixturePath, "options.json"))
+	if err := json.Unmarshal(optData, &fmtOpts); err != nil {
+		t.Fatalf("failed to parse options.json: %v", err)
+	}
+
+	f := custom.NewWithOptions(custom.Options{Template: string(tmpl)})
+	result, err := f.Format(tokens, fmtOpts)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	goldenRelPath := filepath.Join(fixturePath, "expected.txt")
+	testutil.UpdateGoldenFile(t, goldenRelPath, result)
+	expected := testutil.LoadFixtureFile(t, goldenRelPath)
+
+	if string(result) != string(expected) {
+		t.Errorf("output mismatch.\n\nGot:\n%s\n\nExpected:\n%s", result, expected)
+	}
+}
+
+func TestFormat_Resolved(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/v2025_10/all-color-spaces", schema.V2025_10)
+	tok := testutil.TokenByPath(t, tokens, "spacing.small")
+
+	f := custom.NewWithOptions(custom.Options{
+		Template: `{{range .Tokens}}{{with resolved .}}{{.value}}{{.unit}}{{end}}{{end}}`,
+	})
+	result, err := f.Format([]*token.Token{tok}, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if string(result) != "4px" {
+		t.Errorf("expected resolved value 4px, got %q", result)
+	}
+}
+
+func TestFormat_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "parse error",
+			template: "{{range .Tokens}",
+			want:     "failed to parse template",
+		},
+		{
+			name:     "execution error",
+			template: "{{.Missing}}",
+			want:     "failed to execute template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := custom.NewWithOptions(custom.Options{Template: tt.template})
+			_, err := f.Format(nil, formatter.Options{})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat_SkipsPlaceholders(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	result, err := custom.NewWithOptions(custom.Options{Template: "{{range .Tokens}}{{.Name}}: {{.DisplayValue}}\\n{{end}}"}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := string(result)
+	for _, name := range []string{"color-brand", "color-link"} {
+		if strings.Contains(output, name) {
+			t.Errorf("expected placeholder %s to be left out, got:\n%s", name, output)
+		}
+	}
+	if !strings.Contains(output, "color-text: #1b1b1b") {
+		t.Errorf("expected %s to be kept, got:\n%s", "color-text: #1b1b1b", output)
+	}
+}
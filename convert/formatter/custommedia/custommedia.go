@@ -0,0 +1,97 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package custommedia provides CSS @custom-media formatting for design tokens.
+package custommedia
+
+import (
+	"fmt"
+	"strings"
+
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/token"
+)
+
+// DefaultGroup is the token group emitted when Options.Group is empty.
+const DefaultGroup = "breakpoint"
+
+// Options configures the custom media formatter.
+type Options struct {
+	formatter.Options
+
+	// Group is the dot-separated token path whose dimension tokens become
+	// custom media queries. Defaults to DefaultGroup.
+	Group string
+}
+
+// Formatter outputs @custom-media rules for breakpoint-like dimension tokens.
+type Formatter struct {
+	opts Options
+}
+
+// New creates a new custom media formatter with default options.
+func New() *Formatter {
+	return &Formatter{opts: Options{Group: DefaultGroup}}
+}
+
+// NewWithOptions creates a new custom media formatter with the given options.
+func NewWithOptions(opts Options) *Formatter {
+	if opts.Group == "" {
+		opts.Group = DefaultGroup
+	}
+	return &Formatter{opts: opts}
+}
+
+// Format converts dimension tokens under the configured group to
+// @custom-media rules, e.g. @custom-media --breakpoint-md (min-width: 768px);
+// Tokens outside the group, and non-dimension tokens within it, are skipped.
+func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
+	tokens = formatter.WithoutPlaceholders(tokens)
+	var sb strings.Builder
+
+	if opts.Header != "" {
+		sb.WriteString(formatter.FormatHeader(opts.Header, formatter.CSSComments))
+	} else {
+		sb.WriteString("/* Generated by asimonim */\n")
+		sb.WriteString("/* Do not edit manually */\n\n")
+	}
+
+	group := strings.Split(f.opts.Group, ".")
+
+	for _, tok := range formatter.SortTokens(tokens) {
+		if tok.Type != token.TypeDimension || !inGroup(tok.Path, group) {
+			continue
+		}
+
+		baseName := formatter.ToKebabCase(strings.Join(tok.Path, "-"))
+		name := opts.TokenName(tok, formatter.ApplyPrefix(baseName, opts.Prefix, "-"))
+		value, ok := tok.CSSValue()
+		if !ok {
+			opts.Warn(tok, "skipped, since its value has no CSS representation")
+			continue
+		}
+
+		if tok.Description != "" {
+			fmt.Fprintf(&sb, "/* %s */\n", tok.Description)
+		}
+		fmt.Fprintf(&sb, "@custom-media --%s (min-width: %s);\n", name, value)
+	}
+
+	return []byte(sb.String()), nil
+}
+
+// inGroup reports whether path lies strictly beneath the group path.
+func inGroup(path, group []string) bool {
+	if len(path) <= len(group) {
+		return false
+	}
+	for i, segment := range group {
+		if path[i] != segment {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package custommedia_test
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/convert/formatter/custommedia"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/testutil"
+	"bennypowers.dev/asimonim/token"
+)
+
+func TestFormat_Basic(t *testing.T) {
+	fixturePath := filepath.Join("fixtures", "basic")
+	tokens := testutil.ParseFixtureTokens(t, fixturePath, schema.Draft)
+
+	result, err := custommedia.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	goldenRelPath := filepath.Join(fixturePath, "expected.css")
+	testutil.UpdateGoldenFile(t, goldenRelPath, result)
+	expected := testutil.LoadFixtureFile(t, goldenRelPath)
+
+	if string(result) != string(expected) {
+		t.Errorf("output mismatch.\n\nGot:\n%s\n\nExpected:\n%s", result, expected)
+	}
+}
+
+func TestFormat_GroupAndPrefix(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, filepath.Join("fixtures", "basic"), schema.Draft)
+
+	f := custommedia.NewWithOptions(custommedia.Options{Group: "space"})
+	result, err := f.Format(tokens, formatter.Options{Prefix: "ds", Header: "Media queries"})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := string(result)
+	if !strings.HasPrefix(output, "/* Media queries */\n") {
+		t.Errorf("expected CSS header comment, got:\n%s", output)
+	}
+	if !strings.Contains(output, "@custom-media --ds-space-md (min-width: 16px);") {
+		t.Errorf("expected prefixed space query, got:\n%s", output)
+	}
+	if strings.Contains(output, "breakpoint") {
+		t.Errorf("expected tokens outside the group to be skipped, got:\n%s", output)
+	}
+}
+
+func TestFormat_SkipsPlaceholders(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "breakpoint-sm", Path: []string{"breakpoint", "sm"}, Type: token.TypeDimension},
+		{Name: "breakpoint-md", Path: []string{"breakpoint", "md"}, Type: token.TypeDimension, Value: "768px", RawValue: "768px"},
+	}
+
+	result, err := custommedia.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := string(result)
+	if strings.Contains(output, "breakpoint-sm") {
+		t.Errorf("expected placeholder to be left out, got:\n%s", output)
+	}
+	if !strings.Contains(output, "@custom-media --breakpoint-md (min-width: 768px);") {
+		t.Errorf("expected breakpoint-md to be kept, got:\n%s", output)
+	}
+}
+
+func TestFormat_WarnsAboutSkippedTokens(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "breakpoint-odd", Path: []string{"breakpoint", "odd"}, Type: token.TypeDimension, RawValue: map[string]any{"value": "wide"}},
+		{Name: "breakpoint-md", Path: []string{"breakpoint", "md"}, Type: token.TypeDimension, Value: "768px", RawValue: "768px"},
+	}
+
+	var warnings []string
+	opts := formatter.Options{OnWarning: func(w formatter.Warning) { warnings = append(warnings, w.String()) }}
+	result, err := custommedia.New().Format(tokens, opts)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if strings.Contains(string(result), "breakpoint-odd") {
+		t.Errorf("expected breakpoint-odd to be skipped, got:\n%s", result)
+	}
+	want := []string{"breakpoint.odd: skipped, since its value has no CSS representation"}
+	if !slices.Equal(warnings, want) {
+		t.Errorf("warnings = %q, want %q", warnings, want)
+	}
+}
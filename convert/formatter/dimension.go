@@ -0,0 +1,61 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package formatter
+
+import "bennypowers.dev/asimonim/parser/common"
+
+// DefaultRootPx is the root font size, in px, that rem dimensions are
+// scaled by when no other size is configured, as in browsers.
+const DefaultRootPx = 16
+
+// ParseDimension splits a dimension value into its number and unit. It
+// accepts strings like "16px", draft style, structured {value, unit}
+// objects, v2025.10 style, and plain numbers, which have no unit. Strings
+// are parsed with common.ParseNumberWithUnit.
+func ParseDimension(value any) (float64, string, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, "", true
+	case int:
+		return float64(v), "", true
+	case int64:
+		return float64(v), "", true
+	case string:
+		return common.ParseNumberWithUnit(v)
+	case map[string]any:
+		num, _, ok := ParseDimension(v["value"])
+		if !ok {
+			return 0, "", false
+		}
+		unit, _ := v["unit"].(string)
+		return num, unit, true
+	default:
+		return 0, "", false
+	}
+}
+
+// DimensionToFloat converts a dimension value to a number of px, or of
+// points on platforms where 1px is 1pt. rem and em values are scaled by
+// rootPx, and % values become a fraction, so 50% is 0.5. Unitless values
+// are returned as they are. It returns false for values it can't parse
+// and for other units, such as vw, which have no fixed size.
+func DimensionToFloat(value any, rootPx float64) (float64, bool) {
+	num, unit, ok := ParseDimension(value)
+	if !ok {
+		return 0, false
+	}
+	switch unit {
+	case "", "px":
+		return num, true
+	case "rem", "em":
+		return num * rootPx, true
+	case "%":
+		return num / 100, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package figmatokens provides Tokens Studio for Figma JSON formatting for design tokens.
+package figmatokens
+
+import (
+	"encoding/json"
+	"strings"
+
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/token"
+)
+
+// DefaultSetName is the token set that holds all tokens in the output.
+const DefaultSetName = "global"
+
+// Formatter outputs Tokens Studio-compatible JSON: a single token set
+// alongside the $themes and $metadata keys Tokens Studio expects.
+type Formatter struct {
+	// Serialize is the function used to convert tokens to DTCG map structure.
+	// Tokens Studio expects draft-style values, so it should serialize to
+	// the Editor's Draft schema.
+	Serialize func(tokens []*token.Token) map[string]any
+
+	// SetName is the name of the token set. Defaults to DefaultSetName.
+	SetName string
+}
+
+// New creates a new Tokens Studio formatter with the given serialization function.
+func New(serialize func(tokens []*token.Token) map[string]any) *Formatter {
+	return &Formatter{Serialize: serialize, SetName: DefaultSetName}
+}
+
+// Format converts tokens to Tokens Studio JSON.
+func (f *Formatter) Format(tokens []*token.Token, _ formatter.Options) ([]byte, error) {
+	setName := f.SetName
+	if setName == "" {
+		setName = DefaultSetName
+	}
+
+	result := map[string]any{
+		setName:   convertGroup(f.Serialize(tokens), nil),
+		"$themes": []any{},
+		"$metadata": map[string]any{
+			"tokenSetOrder": []string{setName},
+		},
+	}
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// convertGroup converts a DTCG group to a Tokens Studio group.
+// Group-level $ keys (e.g. $schema) have no Tokens Studio equivalent.
+func convertGroup(group map[string]any, path []string) map[string]any {
+	result := make(map[string]any, len(group))
+	for key, v := range group {
+		if strings.HasPrefix(key, "$") {
+			continue
+		}
+		child, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		childPath := append(path[:len(path):len(path)], key)
+		if _, isToken := child["$value"]; isToken {
+			result[key] = convertToken(child, childPath)
+		} else {
+			result[key] = convertGroup(child, childPath)
+		}
+	}
+	return result
+}
+
+// convertToken converts a serialized DTCG token to a Tokens Studio token.
+func convertToken(tok map[string]any, path []string) map[string]any {
+	dtcgType, _ := tok["$type"].(string)
+	value := tok["$value"]
+	if dtcgType == token.TypeShadow {
+		value = convertShadow(value)
+	}
+
+	result := map[string]any{
+		"value": value,
+		"type":  MapType(dtcgType, path),
+	}
+	if desc, ok := tok["$description"].(string); ok && desc != "" {
+		result["description"] = desc
+	}
+	if ext, ok := tok["$extensions"]; ok {
+		result["$extensions"] = ext
+	}
+	return result
+}
+
+// MapType returns the Tokens Studio type for a DTCG type.
+// Tokens Studio splits dimensions by purpose, so dimension tokens are
+// mapped by the names in their path (e.g. spacing, radius), falling
+// back to "dimension". Types without a counterpart map to "other".
+func MapType(dtcgType string, path []string) string {
+	switch dtcgType {
+	case token.TypeColor, token.TypeGradient:
+		return "color"
+	case token.TypeDimension:
+		return dimensionType(path)
+	case token.TypeFontFamily:
+		return "fontFamilies"
+	case token.TypeFontWeight:
+		return "fontWeights"
+	case token.TypeNumber:
+		return "number"
+	case token.TypeString:
+		return "text"
+	case token.TypeBoolean:
+		return "boolean"
+	case token.TypeBorder:
+		return "border"
+	case token.TypeShadow:
+		return "boxShadow"
+	case token.TypeTypography:
+		return "typography"
+	default:
+		return "other"
+	}
+}
+
+// dimensionTypes maps path name fragments to Tokens Studio dimension
+// types. More specific fragments come first, so that letterSpacing is
+// not taken for spacing.
+var dimensionTypes = []struct {
+	fragment string
+	typ      string
+}{
+	{"radius", "borderRadius"},
+	{"borderwidth", "borderWidth"},
+	{"fontsize", "fontSizes"},
+	{"lineheight", "lineHeights"},
+	{"letterspacing", "letterSpacing"},
+	{"paragraphspacing", "paragraphSpacing"},
+	{"spacing", "spacing"},
+	{"space", "spacing"},
+	{"gap", "spacing"},
+	{"sizing", "sizing"},
+	{"size", "sizing"},
+}
+
+// dimensionType picks a Tokens Studio dimension type from a token path.
+// The path is matched as one name, so font.size matches like font-size.
+func dimensionType(path []string) string {
+	name := normalizeName(strings.Join(path, ""))
+	for _, dt := range dimensionTypes {
+		if strings.Contains(name, dt.fragment) {
+			return dt.typ
+		}
+	}
+	return "dimension"
+}
+
+// normalizeName lowercases a name and drops separators.
+func normalizeName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(name)
+}
+
+// convertShadow renames DTCG shadow fields to Tokens Studio boxShadow fields.
+// Shadows may be a single object or a list of layers.
+func convertShadow(value any) any {
+	switch v := value.(type) {
+	case []any:
+		layers := make([]any, len(v))
+		for i, layer := range v {
+			layers[i] = convertShadow(layer)
+		}
+		return layers
+	case map[string]any:
+		shadow := make(map[string]any, len(v))
+		for key, field := range v {
+			switch key {
+			case "offsetX":
+				shadow["x"] = field
+			case "offsetY":
+				shadow["y"] = field
+			case "inset":
+				// Handled below
+			default:
+				shadow[key] = field
+			}
+		}
+		shadow["type"] = "dropShadow"
+		if inset, _ := v["inset"].(bool); inset {
+			shadow["type"] = "innerShadow"
+		}
+		return shadow
+	default:
+		return value
+	}
+}
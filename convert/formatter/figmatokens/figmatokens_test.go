@@ -0,0 +1,144 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package figmatokens_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"bennypowers.dev/asimonim/convert"
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/convert/formatter/figmatokens"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/testutil"
+	"bennypowers.dev/asimonim/token"
+)
+
+func newFormatter() *figmatokens.Formatter {
+	return figmatokens.New(func(tokens []*token.Token) map[string]any {
+		return convert.Serialize(tokens, convert.Options{})
+	})
+}
+
+func TestFormat_Basic(t *testing.T) {
+	fixturePath := filepath.Join("fixtures", "basic")
+	tokens := testutil.ParseFixtureTokens(t, fixturePath, schema.Draft)
+
+	result, err := newFormatter().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	goldenRelPath := filepath.Join(fixturePath, "expected.json")
+	testutil.UpdateGoldenFile(t, goldenRelPath, result)
+	expected := testutil.LoadFixtureFile(t, goldenRelPath)
+
+	if string(result) != string(expected) {
+		t.Errorf("output mismatch.\n\nGot:\n%s\n\nExpected:\n%s", result, expected)
+	}
+}
+
+func TestFormat_SetName(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-red", Path: []string{"color", "red"}, Type: token.TypeColor, Value: "#f00"},
+	}
+
+	f := newFormatter()
+	f.SetName = "brand"
+	result, err := f.Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(result, &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if _, ok := doc["brand"]; !ok {
+		t.Errorf("expected token set %q, got:\n%s", "brand", result)
+	}
+	order := doc["$metadata"].(map[string]any)["tokenSetOrder"].([]any)
+	if len(order) != 1 || order[0] != "brand" {
+		t.Errorf("tokenSetOrder = %v, want [brand]", order)
+	}
+}
+
+func TestMapType(t *testing.T) {
+	tests := []struct {
+		dtcgType string
+		path     []string
+		want     string
+	}{
+		{token.TypeColor, []string{"color", "primary"}, "color"},
+		{token.TypeGradient, []string{"gradient", "hero"}, "color"},
+		{token.TypeDimension, []string{"spacing", "small"}, "spacing"},
+		{token.TypeDimension, []string{"space", "4"}, "spacing"},
+		{token.TypeDimension, []string{"layout", "gap"}, "spacing"},
+		{token.TypeDimension, []string{"border", "radius", "md"}, "borderRadius"},
+		{token.TypeDimension, []string{"border-radius-md"}, "borderRadius"},
+		{token.TypeDimension, []string{"border", "width", "thin"}, "borderWidth"},
+		{token.TypeDimension, []string{"font", "size", "lg"}, "fontSizes"},
+		{token.TypeDimension, []string{"font", "letterSpacing", "wide"}, "letterSpacing"},
+		{token.TypeDimension, []string{"line-height", "tight"}, "lineHeights"},
+		{token.TypeDimension, []string{"icon", "size", "sm"}, "sizing"},
+		{token.TypeDimension, []string{"breakpoint", "md"}, "dimension"},
+		{token.TypeFontFamily, []string{"font", "family", "body"}, "fontFamilies"},
+		{token.TypeFontWeight, []string{"font", "weight", "bold"}, "fontWeights"},
+		{token.TypeNumber, []string{"opacity", "half"}, "number"},
+		{token.TypeString, []string{"label"}, "text"},
+		{token.TypeBoolean, []string{"flag"}, "boolean"},
+		{token.TypeBorder, []string{"border", "default"}, "border"},
+		{token.TypeShadow, []string{"shadow", "raised"}, "boxShadow"},
+		{token.TypeTypography, []string{"type", "body"}, "typography"},
+		{token.TypeDuration, []string{"motion", "fast"}, "other"},
+		{token.TypeCubicBezier, []string{"easing", "standard"}, "other"},
+		{"", []string{"untyped"}, "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dtcgType+"/"+filepath.Join(tt.path...), func(t *testing.T) {
+			if got := figmatokens.MapType(tt.dtcgType, tt.path); got != tt.want {
+				t.Errorf("MapType(%q, %v) = %q, want %q", tt.dtcgType, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat_InsetShadow(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name: "shadow-inner",
+			Path: []string{"shadow", "inner"},
+			Type: token.TypeShadow,
+			RawValue: []any{
+				map[string]any{"color": "#000", "offsetX": "0px", "offsetY": "1px", "blur": "2px", "spread": "0px", "inset": true},
+			},
+		},
+	}
+
+	result, err := newFormatter().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(result, &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	inner := doc["global"].(map[string]any)["shadow"].(map[string]any)["inner"].(map[string]any)
+	layer := inner["value"].([]any)[0].(map[string]any)
+	if layer["type"] != "innerShadow" {
+		t.Errorf("type = %v, want innerShadow", layer["type"])
+	}
+	if layer["y"] != "1px" {
+		t.Errorf("y = %v, want 1px", layer["y"])
+	}
+	if _, ok := layer["inset"]; ok {
+		t.Error("expected inset to be dropped")
+	}
+}
@@ -25,6 +25,7 @@ func New() *Formatter {
 
 // Format converts tokens to flat key-value JSON.
 func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
+	tokens = formatter.WithoutPlaceholders(tokens)
 	delimiter := opts.Delimiter
 	if delimiter == "" {
 		delimiter = "-"
@@ -32,7 +33,7 @@ func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byt
 
 	result := make(map[string]any)
 	for _, tok := range tokens {
-		key := formatter.ApplyPrefix(strings.Join(tok.Path, delimiter), opts.Prefix, delimiter)
+		key := opts.TokenName(tok, formatter.ApplyPrefix(strings.Join(tok.Path, delimiter), opts.Prefix, delimiter))
 		result[key] = formatter.ResolvedValue(tok)
 	}
 
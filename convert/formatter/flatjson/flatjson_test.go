@@ -8,10 +8,13 @@ package flatjson_test
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"bennypowers.dev/asimonim/convert/formatter"
 	"bennypowers.dev/asimonim/convert/formatter/flatjson"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/testutil"
 	"bennypowers.dev/asimonim/token"
 )
 
@@ -109,3 +112,21 @@ func TestFormat_UsesResolvedValue(t *testing.T) {
 		t.Errorf("expected resolved value #FF6B35, got %v", parsed["color-secondary"])
 	}
 }
+
+func TestFormat_SkipsPlaceholders(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	result, err := flatjson.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := string(result)
+	for _, name := range []string{`"color-brand"`, `"color-link"`} {
+		if strings.Contains(output, name) {
+			t.Errorf("expected placeholder %s to be left out, got:\n%s", name, output)
+		}
+	}
+	if !strings.Contains(output, `"color-text"`) {
+		t.Errorf("expected %s to be kept, got:\n%s", `"color-text"`, output)
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"strings"
 	"unicode"
 
+	"bennypowers.dev/asimonim/parser/common"
+	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/token"
 )
 
@@ -35,6 +37,76 @@ type Options struct {
 	// Header is the content to prepend to the output.
 	// Formatters wrap this in appropriate comment syntax.
 	Header string
+
+	// NameTransform, when set, replaces the default name construction for
+	// each token. It receives the token's path and the token, and returns
+	// the complete name: Prefix is not applied and no case conversion is
+	// done. Formatters still add their own syntax around the name, such as
+	// the "--" of a CSS custom property or the "$" of an SCSS variable, so
+	// the result should be valid for the target format. Nil keeps the
+	// default names.
+	NameTransform func(path []string, tok *token.Token) string
+
+	// OnWarning, if not nil, is called with each token the formatter
+	// skips or can't write as authored. Nil drops the warnings.
+	OnWarning func(Warning)
+}
+
+// TokenName returns the output name for tok: the NameTransform result
+// when one is set, otherwise defaultName.
+func (o Options) TokenName(tok *token.Token, defaultName string) string {
+	if o.NameTransform != nil {
+		return o.NameTransform(tok.Path, tok)
+	}
+	return defaultName
+}
+
+// Hex8Color formats tok's value as 8-digit hex when it is a translucent
+// structured sRGB color. It returns false for any other token, including
+// opaque colors, which formatters already write as 6-digit hex.
+func Hex8Color(tok *token.Token) (string, bool) {
+	obj, ok := structuredColor(tok)
+	if !ok {
+		return "", false
+	}
+	hex := obj.ToHex8()
+	if hex == obj.ToCSS() {
+		return "", false
+	}
+	return hex, true
+}
+
+// LegacyColor formats tok's value in the legacy rgb(), rgba(), hsl() or
+// hsla() syntax when it is a structured sRGB, hsl or hwb color. It returns
+// false for any other token, including opaque sRGB colors, which
+// formatters already write as hex, and colors in other color spaces.
+func LegacyColor(tok *token.Token) (string, bool) {
+	obj, ok := structuredColor(tok)
+	if !ok {
+		return "", false
+	}
+	legacy := obj.ToLegacyCSS()
+	if legacy == obj.ToCSS() {
+		return "", false
+	}
+	return legacy, true
+}
+
+// structuredColor parses tok's resolved value as a structured color.
+func structuredColor(tok *token.Token) (*common.ObjectColorValue, bool) {
+	if tok.Type != token.TypeColor {
+		return nil, false
+	}
+	m, ok := ResolvedValue(tok).(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	colorVal, err := common.ParseColorValue(m, schema.V2025_10)
+	if err != nil {
+		return nil, false
+	}
+	obj, ok := colorVal.(*common.ObjectColorValue)
+	return obj, ok
 }
 
 // ResolvedValue returns the resolved value for a token, falling back to raw or original value.
@@ -61,6 +133,18 @@ func SortTokens(tokens []*token.Token) []*token.Token {
 	return sorted
 }
 
+// WithoutPlaceholders returns the tokens that have a value, leaving out
+// placeholders and aliases of placeholders, which have nothing to write.
+func WithoutPlaceholders(tokens []*token.Token) []*token.Token {
+	kept := make([]*token.Token, 0, len(tokens))
+	for _, tok := range tokens {
+		if !tok.IsPlaceholder() {
+			kept = append(kept, tok)
+		}
+	}
+	return kept
+}
+
 // GroupByType groups tokens by their type.
 func GroupByType(tokens []*token.Token) map[string][]*token.Token {
 	groups := make(map[string][]*token.Token)
@@ -177,6 +261,27 @@ func MarshalFallback(m map[string]any) string {
 	return fmt.Sprintf("%v", m)
 }
 
+// QuoteSCSSValue returns s as a double-quoted Sass string. Backslashes
+// and double quotes are escaped, and newlines become the CSS escape \a so
+// the string stays on one line. Less strings use the same syntax.
+func QuoteSCSSValue(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\', '"':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\a `)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
 // EscapeXML escapes special XML characters.
 func EscapeXML(s string) string {
 	s = strings.ReplaceAll(s, "&", "&amp;")
@@ -218,6 +323,11 @@ var (
 		BlockEnd:        "-->",
 		BlockLinePrefix: "  ",
 	}
+	// CSSComments uses CSS block comments (/* ... */), since CSS has no line comments.
+	CSSComments = CommentStyle{
+		BlockStart: "/*",
+		BlockEnd:   "*/",
+	}
 	// SCSSComments uses SCSS-style line comments (// ...).
 	SCSSComments = CommentStyle{
 		LinePrefix: "// ",
@@ -367,6 +367,53 @@ func TestEscapeXML(t *testing.T) {
 	}
 }
 
+func TestQuoteSCSSValue(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		// plain text
+		{"Red Hat Text", `"Red Hat Text"`},
+		// double quotes
+		{`say "hi"`, `"say \"hi\""`},
+		// single quotes need no escaping
+		{"it's", `"it's"`},
+		// backslash
+		{`\2192`, `"\\2192"`},
+		// newline
+		{"a\nb", `"a\a b"`},
+		// empty string
+		{"", `""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := formatter.QuoteSCSSValue(tt.input)
+			if result != tt.expected {
+				t.Errorf("QuoteSCSSValue(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOptions_TokenName(t *testing.T) {
+	tok := &token.Token{Name: "color.primary", Path: []string{"color", "primary"}}
+
+	if got := (formatter.Options{}).TokenName(tok, "color-primary"); got != "color-primary" {
+		t.Errorf("TokenName() without transform = %q, want %q", got, "color-primary")
+	}
+
+	opts := formatter.Options{
+		Prefix: "rh",
+		NameTransform: func(path []string, _ *token.Token) string {
+			return path[len(path)-1]
+		},
+	}
+	if got := opts.TokenName(tok, "rh-color-primary"); got != "primary" {
+		t.Errorf("TokenName() with transform = %q, want %q", got, "primary")
+	}
+}
+
 func TestResolvedValue(t *testing.T) {
 	t.Run("nil token", func(t *testing.T) {
 		result := formatter.ResolvedValue(nil)
@@ -447,3 +494,37 @@ func TestFormatHeader_SingleLine_BlockComment(t *testing.T) {
 		t.Errorf("FormatHeader single line block comment = %q, expected %q", result, expected)
 	}
 }
+
+func TestDimensionToFloat(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  any
+		want   float64
+		wantOK bool
+	}{
+		{"px string", "16px", 16, true},
+		{"rem string", "1.5rem", 15, true},
+		{"spaced rem string", "1.5 rem", 0, false},
+		{"em string", "2em", 20, true},
+		{"percent string", "50%", 0.5, true},
+		{"unitless string", "0", 0, true},
+		{"negative px", "-4px", -4, true},
+		{"number", 8.0, 8, true},
+		{"int", 8, 8, true},
+		{"int64", int64(8), 8, true},
+		{"structured px", map[string]any{"value": 4.0, "unit": "px"}, 4, true},
+		{"structured rem", map[string]any{"value": 0.5, "unit": "rem"}, 5, true},
+		{"viewport unit", "10vw", 0, false},
+		{"structured nil value", map[string]any{"value": nil, "unit": "px"}, 0, false},
+		{"not a dimension", "calc(1px + 2px)", 0, false},
+		{"nil", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := formatter.DimensionToFloat(tt.value, 10)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("DimensionToFloat(%v) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package html provides a self-contained HTML gallery of design tokens.
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+	stdhtml "html"
+	"maps"
+	"slices"
+	"strings"
+
+	"bennypowers.dev/asimonim/cmd/render"
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/token"
+)
+
+// title is the page title and top-level heading.
+const title = "Design Tokens"
+
+// Formatter outputs an HTML page listing tokens in a table per group.
+type Formatter struct{}
+
+// New creates a new HTML formatter.
+func New() *Formatter {
+	return &Formatter{}
+}
+
+// stylesheet is embedded in the page so it can be shared as a single file.
+const stylesheet = `    body { font-family: system-ui, sans-serif; margin: 2rem; color: #1b1b1b; }
+    section section { margin-inline-start: 1rem; }
+    table { border-collapse: collapse; width: 100%; margin-block-end: 2rem; }
+    th, td { text-align: start; padding: 0.5rem; border-block-end: 1px solid #d2d2d2; vertical-align: top; }
+    code { font-family: ui-monospace, monospace; }
+    .swatch { display: inline-block; width: 1.5rem; height: 1.5rem; margin-inline-end: 0.5rem; vertical-align: middle; border: 1px solid #8a8d90; border-radius: 4px; background-image: linear-gradient(45deg, #eee 25%, transparent 25% 75%, #eee 75%); background-size: 8px 8px; }
+    .swatch > span { display: block; width: 100%; height: 100%; border-radius: 3px; }
+    .alias { display: block; color: #6a6e73; font-size: 0.875em; }
+    .deprecated { text-decoration: line-through; }
+`
+
+// Format renders tokens as an HTML page with a section per token group,
+// nested like the groups themselves, each with a table of its tokens.
+// Colors get a swatch filled with their CSS value, so structured colors
+// show in their own color space where the browser supports it.
+func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
+	tokens = formatter.SortTokens(formatter.WithoutPlaceholders(tokens))
+	var sb strings.Builder
+
+	sb.WriteString("<!DOCTYPE html>\n")
+	if opts.Header != "" {
+		sb.WriteString(formatter.FormatHeader(opts.Header, formatter.XMLComments))
+	}
+	sb.WriteString("<html lang=\"en\">\n<head>\n  <meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "  <title>%s</title>\n", title)
+	sb.WriteString("  <style>\n" + stylesheet + "  </style>\n</head>\n<body>\n")
+	fmt.Fprintf(&sb, "  <h1>%s</h1>\n", title)
+
+	root := render.BuildHierarchy(render.ComputeRows(tokens, false))
+	writeGroup(&sb, root, formatter.IndexByPath(tokens), opts, 0)
+
+	sb.WriteString("</body>\n</html>\n")
+	return []byte(sb.String()), nil
+}
+
+// writeGroup writes the table of node's own tokens, then a section for
+// each child group. depth is the nesting of node below the page, which
+// sets the heading level of its children.
+func writeGroup(sb *strings.Builder, node *render.HierarchyNode, index map[string]*token.Token, opts formatter.Options, depth int) {
+	indent := strings.Repeat("  ", depth+1)
+	if len(node.Tokens) > 0 {
+		sb.WriteString(indent + "<table>\n")
+		sb.WriteString(indent + "  <thead><tr><th>Token</th><th>Type</th><th>Value</th><th>Description</th></tr></thead>\n")
+		sb.WriteString(indent + "  <tbody>\n")
+		for _, row := range node.Tokens {
+			if tok, ok := index[strings.Join(row.Path, ".")]; ok {
+				writeRow(sb, indent+"    ", tok, opts)
+			}
+		}
+		sb.WriteString(indent + "  </tbody>\n" + indent + "</table>\n")
+	}
+
+	level := min(depth+2, 6)
+	for _, name := range slices.Sorted(maps.Keys(node.Children)) {
+		child := node.Children[name]
+		id := stdhtml.EscapeString(strings.Join(child.Path, "-"))
+		fmt.Fprintf(sb, "%s<section id=\"%s\">\n", indent, id)
+		fmt.Fprintf(sb, "%s  <h%d>%s</h%d>\n", indent, level, stdhtml.EscapeString(strings.Join(child.Path, ".")), level)
+		writeGroup(sb, child, index, opts, depth+1)
+		fmt.Fprintf(sb, "%s</section>\n", indent)
+	}
+}
+
+func writeRow(sb *strings.Builder, indent string, tok *token.Token, opts formatter.Options) {
+	baseName := formatter.ToKebabCase(strings.Join(tok.Path, "-"))
+	name := "--" + opts.TokenName(tok, formatter.ApplyPrefix(baseName, opts.Prefix, "-"))
+
+	nameCell := "<code>" + stdhtml.EscapeString(name) + "</code>"
+	if tok.Deprecated {
+		nameCell = `<code class="deprecated">` + stdhtml.EscapeString(name) + "</code>"
+	}
+
+	value, ok := tok.CSSValue()
+	if !ok {
+		value = displayValue(tok)
+	}
+	valueCell := "<code>" + stdhtml.EscapeString(value) + "</code>"
+	if ok && tok.Type == token.TypeColor {
+		valueCell = fmt.Sprintf(`<span class="swatch"><span style="background: %s"></span></span>`,
+			stdhtml.EscapeString(value)) + valueCell
+	}
+	if len(tok.ResolutionChain) > 0 && tok.Value != "" {
+		valueCell += `<span class="alias">` + stdhtml.EscapeString(tok.Value) + "</span>"
+	}
+
+	fmt.Fprintf(sb, "%s<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+		indent, nameCell, stdhtml.EscapeString(tok.Type), valueCell, stdhtml.EscapeString(tok.Description))
+}
+
+// displayValue formats values without a CSS representation.
+func displayValue(tok *token.Token) string {
+	switch v := formatter.ResolvedValue(tok).(type) {
+	case map[string]any:
+		return formatter.MarshalFallback(v)
+	case []any:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
+	case nil:
+		return tok.Value
+	}
+	return fmt.Sprintf("%v", formatter.ResolvedValue(tok))
+}
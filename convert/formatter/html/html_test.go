@@ -0,0 +1,77 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package html_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/convert/formatter/html"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/testutil"
+	"bennypowers.dev/asimonim/token"
+)
+
+func TestFormat_Gallery(t *testing.T) {
+	fixturePath := filepath.Join("fixtures", "gallery")
+	tokens := testutil.ParseFixtureTokens(t, fixturePath, schema.V2025_10)
+
+	result, err := html.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	goldenRelPath := filepath.Join(fixturePath, "expected.html")
+	testutil.UpdateGoldenFile(t, goldenRelPath, result)
+	expected := testutil.LoadFixtureFile(t, goldenRelPath)
+
+	if string(result) != string(expected) {
+		t.Errorf("output mismatch.\n\nGot:\n%s\n\nExpected:\n%s", result, expected)
+	}
+}
+
+func TestFormat_HeaderAndPrefix(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-red", Path: []string{"color", "red"}, Type: token.TypeColor, Value: "#f00", RawValue: "#f00"},
+	}
+
+	result, err := html.New().Format(tokens, formatter.Options{Prefix: "ds", Header: "Brand tokens"})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := string(result)
+	if !strings.HasPrefix(output, "<!DOCTYPE html>\n<!-- Brand tokens -->\n") {
+		t.Errorf("expected header comment after the doctype, got:\n%s", output)
+	}
+	if !strings.Contains(output, "<code>--ds-color-red</code>") {
+		t.Errorf("expected prefixed token name, got:\n%s", output)
+	}
+	if !strings.Contains(output, `<span style="background: #f00">`) {
+		t.Errorf("expected color swatch, got:\n%s", output)
+	}
+}
+
+func TestFormat_SkipsPlaceholders(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	result, err := html.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := string(result)
+	for _, name := range []string{"color-brand", "color-link"} {
+		if strings.Contains(output, name) {
+			t.Errorf("expected placeholder %s to be left out, got:\n%s", name, output)
+		}
+	}
+	if !strings.Contains(output, "color-text") {
+		t.Errorf("expected %s to be kept, got:\n%s", "color-text", output)
+	}
+}
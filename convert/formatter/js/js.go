@@ -53,6 +53,12 @@ const (
 	MapModeTypes MapMode = "types"
 	// MapModeModule outputs a split module that imports from shared types.
 	MapModeModule MapMode = "module"
+	// MapModeJS outputs a plain JavaScript implementation without types,
+	// to be paired with a MapModeDeclarations file.
+	MapModeJS MapMode = "js"
+	// MapModeDeclarations outputs a .d.ts declaration file describing the
+	// MapModeJS implementation.
+	MapModeDeclarations MapMode = "dts"
 )
 
 // Options configures the JS formatter.
@@ -63,13 +69,22 @@ type Options struct {
 	Types Types
 	// Export specifies what form the exports take: "values" (default), "map".
 	Export Export
-	// MapMode specifies the map mode: "" (full), "types", "module".
+	// MapMode specifies the map mode: "" (full), "types", "module", "js", "dts".
 	// Only used when Export is ExportMap.
 	MapMode MapMode
 	// TypesPath is the import path for shared types (used with MapModeModule).
 	TypesPath string
 	// ClassName is the class name for extended TokenMap (used with MapModeModule).
 	ClassName string
+	// OmitJSDoc leaves out doc comments, such as token descriptions, for
+	// smaller TypeScript output. It has no effect with TypesJSDoc, which
+	// needs JSDoc for its type annotations.
+	OmitJSDoc bool
+}
+
+// omitJSDoc reports whether doc comments should be left out.
+func (f *Formatter) omitJSDoc() bool {
+	return f.opts.OmitJSDoc && f.opts.Types == TypesTS
 }
 
 // Formatter outputs JavaScript/TypeScript with configurable options.
@@ -103,6 +118,7 @@ func NewWithOptions(opts Options) *Formatter {
 
 // Format converts tokens to JavaScript/TypeScript format.
 func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
+	tokens = formatter.WithoutPlaceholders(tokens)
 	switch f.opts.Export {
 	case ExportMap:
 		return f.formatMap(tokens, opts)
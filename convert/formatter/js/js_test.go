@@ -45,6 +45,19 @@ func TestFormat_MapBasic(t *testing.T) {
 	runFixtureTest(t, "map-basic", js.Options{Export: js.ExportMap})
 }
 
+func TestFormat_NoJSDoc(t *testing.T) {
+	runFixtureTest(t, "no-jsdoc", js.Options{OmitJSDoc: true})
+}
+
+func TestFormat_MapNoJSDoc(t *testing.T) {
+	runFixtureTest(t, "map-no-jsdoc", js.Options{Export: js.ExportMap, OmitJSDoc: true})
+}
+
+func TestFormat_OmitJSDocKeepsJSDocTypes(t *testing.T) {
+	// JSDoc is the type system for js output, so it is never omitted
+	runFixtureTest(t, "jsdoc-simple", js.Options{Types: js.TypesJSDoc, OmitJSDoc: true})
+}
+
 func TestFormat_EscapesQuotes(t *testing.T) {
 	runFixtureTest(t, "escapes-quotes", js.Options{})
 }
@@ -437,6 +450,12 @@ func TestInferValueType_ViaMapFormat(t *testing.T) {
 			value:     map[string]any{"value": float64(16), "unit": "px"},
 			wantType:  "Dimension",
 		},
+		{
+			name:      "boolean type",
+			tokenType: token.TypeBoolean,
+			value:     false,
+			wantType:  "boolean",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -553,6 +572,87 @@ func TestMapMode_Module(t *testing.T) {
 	}
 }
 
+func TestMapMode_JS(t *testing.T) {
+	tok := &token.Token{
+		Name:          "color-primary",
+		Path:          []string{"color", "primary"},
+		Type:          token.TypeColor,
+		ResolvedValue: "#FF0000",
+		IsResolved:    true,
+	}
+	for _, module := range []js.Module{js.ModuleESM, js.ModuleCJS} {
+		t.Run(string(module), func(t *testing.T) {
+			f := js.NewWithOptions(js.Options{
+				Export:  js.ExportMap,
+				MapMode: js.MapModeJS,
+				Module:  module,
+			})
+			result, err := f.Format([]*token.Token{tok}, formatter.Options{Prefix: "rh"})
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			out := string(result)
+			if !strings.Contains(out, `"--rh-color-primary":`) {
+				t.Errorf("expected token entry, got:\n%s", out)
+			}
+			for _, ts := range []string{"interface ", " as ", "<T", ": string"} {
+				if strings.Contains(out, ts) {
+					t.Errorf("expected no TypeScript syntax %q, got:\n%s", ts, out)
+				}
+			}
+			if module == js.ModuleCJS && !strings.Contains(out, "module.exports = { TokenMap, tokens };") {
+				t.Errorf("expected CommonJS exports, got:\n%s", out)
+			}
+			if module == js.ModuleESM && !strings.Contains(out, "export const tokens") {
+				t.Errorf("expected ES module exports, got:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestMapMode_Declarations(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name:          "color-primary",
+			Path:          []string{"color", "primary"},
+			Type:          token.TypeColor,
+			ResolvedValue: "#FF0000",
+			IsResolved:    true,
+		},
+		{
+			Name:          "space-sm",
+			Path:          []string{"space", "sm"},
+			Type:          token.TypeDimension,
+			ResolvedValue: "4px",
+			IsResolved:    true,
+		},
+	}
+	f := js.NewWithOptions(js.Options{
+		Export:  js.ExportMap,
+		MapMode: js.MapModeDeclarations,
+	})
+	result, err := f.Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	out := string(result)
+	for _, want := range []string{
+		`export type TokenName =`,
+		`"--color-primary"`,
+		`"--space-sm": DesignToken<string>;`,
+		`get<K extends keyof T>(name: K): T[K];`,
+		`get(name: TokenName):`,
+		`export declare const tokens: TokenMap<TokenEntries>;`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected declarations to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "new TokenMap") {
+		t.Errorf("expected no implementation in declarations, got:\n%s", out)
+	}
+}
+
 func TestMapMode_ModuleWithCustomOptions(t *testing.T) {
 	tokens := []*token.Token{
 		{
@@ -936,3 +1036,61 @@ func runFixtureTest(t *testing.T, fixtureName string, jsOpts js.Options) {
 		t.Errorf("output mismatch for fixture %q.\n\nGot:\n%s\n\nExpected:\n%s", fixtureName, gotStr, expectedStr)
 	}
 }
+
+func TestMapFormat_AliasedStructuredColor(t *testing.T) {
+	allTokens := testutil.ParseFixtureTokens(t, "fixtures/v2025_10/alias-chain", schema.V2025_10)
+	tok := testutil.TokenByPath(t, allTokens, "surface.card")
+
+	f := js.NewWithOptions(js.Options{Export: js.ExportMap})
+	result, err := f.Format([]*token.Token{tok}, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	out := string(result)
+	if !strings.Contains(out, "DesignToken<Color>") {
+		t.Errorf("expected aliased structured color to be typed Color, got:\n%s", out)
+	}
+	if !strings.Contains(out, "colorSpace") {
+		t.Errorf("expected structured color value for alias, got:\n%s", out)
+	}
+}
+
+func TestMapFormat_RatioNumber(t *testing.T) {
+	tok := &token.Token{
+		Name:          "aspect-wide",
+		Path:          []string{"aspect", "wide"},
+		Type:          token.TypeNumber,
+		ResolvedValue: "16/9",
+		IsResolved:    true,
+	}
+	f := js.NewWithOptions(js.Options{Export: js.ExportMap})
+	result, err := f.Format([]*token.Token{tok}, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	out := string(result)
+	if !strings.Contains(out, "DesignToken<string>") {
+		t.Errorf("expected ratio number to be typed string, got:\n%s", out)
+	}
+	if !strings.Contains(out, `"16/9"`) {
+		t.Errorf("expected ratio to pass through, got:\n%s", out)
+	}
+}
+
+func TestFormat_SkipsPlaceholders(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	result, err := js.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := string(result)
+	for _, name := range []string{"colorBrand", "colorLink"} {
+		if strings.Contains(output, name) {
+			t.Errorf("expected placeholder %s to be left out, got:\n%s", name, output)
+		}
+	}
+	if !strings.Contains(output, "colorText") {
+		t.Errorf("expected %s to be kept, got:\n%s", "colorText", output)
+	}
+}
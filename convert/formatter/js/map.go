@@ -39,6 +39,7 @@ type templateData struct {
 	Delimiter      string
 	UseJSDoc       bool
 	UseCJS         bool
+	OmitJSDoc      bool
 }
 
 // entryData holds data for a single token entry.
@@ -54,11 +55,19 @@ func (f *Formatter) formatMap(tokens []*token.Token, opts formatter.Options) ([]
 
 	switch f.opts.MapMode {
 	case MapModeTypes:
-		return f.executeTemplate("types.ts.tmpl", nil)
+		return f.executeTemplate("types.ts.tmpl", templateData{OmitJSDoc: f.omitJSDoc()})
 
 	case MapModeModule:
 		return f.formatSplitModule(sorted, opts)
 
+	case MapModeJS:
+		return f.executeTemplate("impl.js.tmpl", f.mapData(sorted, opts))
+
+	case MapModeDeclarations:
+		data := f.mapData(sorted, opts)
+		data.ValueTypeUnion = buildValueTypeUnion(collectValueTypes(sorted))
+		return f.executeTemplate("decl.d.ts.tmpl", data)
+
 	default:
 		return f.formatFull(sorted, opts)
 	}
@@ -66,16 +75,20 @@ func (f *Formatter) formatMap(tokens []*token.Token, opts formatter.Options) ([]
 
 // formatFull generates the complete output with types, class, and tokens.
 func (f *Formatter) formatFull(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
-	data := templateData{
+	return f.executeTemplate("full.ts.tmpl", f.mapData(tokens, opts))
+}
+
+// mapData returns the template data shared by the single-file map modes.
+func (f *Formatter) mapData(tokens []*token.Token, opts formatter.Options) templateData {
+	return templateData{
 		TokenNames: buildTokenNames(tokens, opts),
 		Entries:    buildEntries(tokens, opts),
 		Prefix:     escapeTS(opts.Prefix),
 		Delimiter:  escapeTS(defaultDelimiter(opts.Delimiter)),
 		UseJSDoc:   f.opts.Types == TypesJSDoc,
 		UseCJS:     f.opts.Module == ModuleCJS,
+		OmitJSDoc:  f.omitJSDoc(),
 	}
-
-	return f.executeTemplate("full.ts.tmpl", data)
 }
 
 // formatSplitModule generates a split module that imports from shared types.
@@ -121,6 +134,7 @@ func (f *Formatter) formatSplitModule(tokens []*token.Token, opts formatter.Opti
 		Delimiter:      escapeTS(defaultDelimiter(opts.Delimiter)),
 		UseJSDoc:       f.opts.Types == TypesJSDoc,
 		UseCJS:         f.opts.Module == ModuleCJS,
+		OmitJSDoc:      f.omitJSDoc(),
 	}
 
 	return f.executeTemplate("module.ts.tmpl", data)
@@ -182,7 +196,7 @@ func buildCSSVarName(tok *token.Token, opts formatter.Options) string {
 	if opts.Prefix != "" {
 		name = opts.Prefix + "-" + name
 	}
-	return "--" + name
+	return "--" + opts.TokenName(tok, name)
 }
 
 // buildDotPath constructs a dot-separated path like color.blue (no prefix).
@@ -341,11 +355,22 @@ func inferValueType(tok *token.Token) string {
 		return "string"
 
 	case token.TypeNumber, token.TypeFontWeight:
+		// Ratios ("16/9") and percentages ({"value": 50, "unit": "%"})
+		// are not plain numbers
+		switch formatter.ResolvedValue(tok).(type) {
+		case string:
+			return "string"
+		case map[string]any:
+			return "{ value: number; unit: string }"
+		}
 		return "number"
 
 	case token.TypeCubicBezier:
 		return "[number, number, number, number]"
 
+	case token.TypeBoolean:
+		return "boolean"
+
 	case token.TypeFontFamily:
 		value := formatter.ResolvedValue(tok)
 		if _, ok := value.([]any); ok {
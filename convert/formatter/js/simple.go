@@ -31,12 +31,12 @@ func (f *Formatter) formatSimple(tokens []*token.Token, opts formatter.Options)
 
 	for _, tok := range sorted {
 		baseName := formatter.ToCamelCase(strings.Join(tok.Path, "-"))
-		name := formatter.ApplyPrefixCamel(baseName, opts.Prefix)
+		name := opts.TokenName(tok, formatter.ApplyPrefixCamel(baseName, opts.Prefix))
 		value := formatter.ResolvedValue(tok)
 		jsValue := ToValue(value)
 
 		// Write description comment
-		if tok.Description != "" {
+		if tok.Description != "" && !f.omitJSDoc() {
 			sb.WriteString(f.formatDescription(tok.Description, value))
 		}
 
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package formatter
+
+import (
+	"strings"
+
+	"bennypowers.dev/asimonim/token"
+)
+
+// LightDark is a color with light and dark variants.
+type LightDark struct {
+	// Root is the token for the color itself, or Light when the tokens
+	// have no root, e.g. with the $root syntax.
+	Root  *token.Token
+	Light *token.Token
+	Dark  *token.Token
+}
+
+// IndexByPath maps each token's dot path to the token, for FindLightDark.
+func IndexByPath(tokens []*token.Token) map[string]*token.Token {
+	index := make(map[string]*token.Token, len(tokens))
+	for _, tok := range tokens {
+		index[strings.Join(tok.Path, ".")] = tok
+	}
+	return index
+}
+
+// FindLightDark returns the light-dark group tok belongs to, or nil.
+// index maps dot paths to tokens, as IndexByPath builds it.
+//
+// Detection rules (convention-based):
+// - A color token ending in ".light" that has a sibling ".dark" token
+// - A color token aliasing its own ".light" child
+func FindLightDark(tok *token.Token, index map[string]*token.Token) *LightDark {
+	if tok.Type != token.TypeColor {
+		return nil
+	}
+
+	tokPath := strings.Join(tok.Path, ".")
+
+	// Check if this token IS the root (aliases its light child)
+	if target, ok := tok.AliasTarget(); ok && target == tokPath+".light" {
+		light, hasLight := index[tokPath+".light"]
+		dark, hasDark := index[tokPath+".dark"]
+		if hasLight && hasDark {
+			return &LightDark{Root: tok, Light: light, Dark: dark}
+		}
+	}
+
+	// Check if this token is a light/dark child (convention-based detection)
+	if len(tok.Path) < 2 {
+		return nil
+	}
+
+	lastSegment := tok.Path[len(tok.Path)-1]
+	if lastSegment != "light" && lastSegment != "dark" {
+		return nil
+	}
+
+	parentPath := strings.Join(tok.Path[:len(tok.Path)-1], ".")
+	light, hasLight := index[parentPath+".light"]
+	dark, hasDark := index[parentPath+".dark"]
+	if !hasLight || !hasDark {
+		return nil
+	}
+
+	// The root token is optional, and may not exist with the $root syntax
+	root, hasRoot := index[parentPath]
+	if !hasRoot {
+		root = light
+	}
+
+	return &LightDark{Root: root, Light: light, Dark: dark}
+}
+
+// IsRoot reports whether tok is the group's root. When there's no root
+// token, the light token stands in for it, so formatters that emit one
+// entry per group emit it exactly once.
+func (g *LightDark) IsRoot(tok *token.Token) bool {
+	return tok == g.Root
+}
+
+// Path returns the path of the color the group describes: the root
+// token's path, or the light token's parent when there is no root.
+func (g *LightDark) Path() []string {
+	if g.Root != g.Light || len(g.Light.Path) < 2 {
+		return g.Root.Path
+	}
+	return g.Light.Path[:len(g.Light.Path)-1]
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package scss
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/token"
+)
+
+// RootKey is the map key holding the value of a token that also has
+// child tokens, e.g. color.primary alongside color.primary.hover.
+const RootKey = "$root"
+
+// mapNode is one level of the nested token map.
+type mapNode struct {
+	tok      *token.Token
+	children map[string]*mapNode
+}
+
+func (n *mapNode) child(key string) *mapNode {
+	if n.children == nil {
+		n.children = make(map[string]*mapNode)
+	}
+	c, ok := n.children[key]
+	if !ok {
+		c = &mapNode{}
+		n.children[key] = c
+	}
+	return c
+}
+
+// formatMap writes all tokens into a single nested SCSS map keyed by
+// token path, followed by an accessor function.
+func (f *Formatter) formatMap(tokens []*token.Token, opts formatter.Options, sb *strings.Builder) {
+	root := &mapNode{}
+	for _, tok := range tokens {
+		if len(tok.Path) == 0 {
+			continue
+		}
+		n := root
+		for _, key := range tok.Path {
+			n = n.child(key)
+		}
+		n.tok = tok
+	}
+
+	mapName := formatter.ApplyPrefix("tokens", opts.Prefix, "-")
+	funcName := formatter.ApplyPrefix("token", opts.Prefix, "-")
+
+	sb.WriteString("@use \"sass:map\";\n\n")
+	fmt.Fprintf(sb, "$%s: ", mapName)
+	f.writeMapNode(sb, root, 0)
+	sb.WriteString(";\n\n")
+
+	fmt.Fprintf(sb, "/// Returns the token at the given path, e.g. %s(color, primary).\n", funcName)
+	fmt.Fprintf(sb, "@function %s($path...) {\n", funcName)
+	fmt.Fprintf(sb, "  @return map.get($%s, $path...);\n", mapName)
+	sb.WriteString("}\n")
+}
+
+// writeMapNode writes the children of n as an SCSS map literal.
+func (f *Formatter) writeMapNode(sb *strings.Builder, n *mapNode, depth int) {
+	keys := make([]string, 0, len(n.children))
+	for key := range n.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	indent := strings.Repeat("  ", depth+1)
+	sb.WriteString("(\n")
+	for _, key := range keys {
+		c := n.children[key]
+		if c.children == nil {
+			f.writeMapEntry(sb, indent, key, c.tok)
+			continue
+		}
+		if c.tok != nil {
+			// A token with children keeps its own value under RootKey
+			c.child(RootKey).tok = c.tok
+		}
+		fmt.Fprintf(sb, "%s%s: ", indent, strconv.Quote(key))
+		f.writeMapNode(sb, c, depth+1)
+		sb.WriteString(",\n")
+	}
+	sb.WriteString(strings.Repeat("  ", depth) + ")")
+}
+
+func (f *Formatter) writeMapEntry(sb *strings.Builder, indent, key string, tok *token.Token) {
+	if tok.Description != "" {
+		fmt.Fprintf(sb, "%s// %s\n", indent, tok.Description)
+	}
+	value := f.value(tok)
+	if hasListComma(value) {
+		// Comma-separated lists would otherwise end the map entry
+		value = "(" + value + ")"
+	}
+	fmt.Fprintf(sb, "%s%s: %s,\n", indent, strconv.Quote(key), value)
+}
+
+// hasListComma reports whether value contains a comma outside of quotes
+// and parentheses, making it a comma-separated list.
+func hasListComma(value string) bool {
+	depth := 0
+	var quote rune
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0 && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			return true
+		}
+	}
+	return false
+}
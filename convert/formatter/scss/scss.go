@@ -23,14 +23,47 @@ import (
 // secondsDurationPattern matches duration values like "2s", "0.5s", "-1.5s".
 var secondsDurationPattern = regexp.MustCompile(`^[+-]?\d+(\.\d+)?s$`)
 
+// Options configures the SCSS formatter.
+type Options struct {
+	formatter.Options
+
+	// OmitGroupComments suppresses the "// Group" comment written
+	// before each top-level group.
+	OmitGroupComments bool
+
+	// Map writes a single nested $tokens map and a token() accessor
+	// function instead of flat variables.
+	Map bool
+
+	// Hex8 writes translucent sRGB colors as #RRGGBBAA instead of
+	// color(srgb ... / alpha).
+	Hex8 bool
+
+	// LegacyColors writes sRGB, hsl and hwb colors with the legacy rgb(),
+	// rgba(), hsl() and hsla() functions. Hex8 takes precedence.
+	LegacyColors bool
+
+	// IncludePlaceholders writes tokens with no value, such as
+	// "$value": null, as null, for themes to override. By default they
+	// are left out.
+	IncludePlaceholders bool
+}
+
 // Formatter outputs SCSS variables with kebab-case names.
-type Formatter struct{}
+type Formatter struct {
+	opts Options
+}
 
 // New creates a new SCSS formatter.
 func New() *Formatter {
 	return &Formatter{}
 }
 
+// NewWithOptions creates a new SCSS formatter with the specified options.
+func NewWithOptions(opts Options) *Formatter {
+	return &Formatter{opts: opts}
+}
+
 // Format converts tokens to SCSS variables.
 func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
 	var sb strings.Builder
@@ -43,6 +76,15 @@ func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byt
 		sb.WriteString("// Do not edit manually\n\n")
 	}
 
+	if !f.opts.IncludePlaceholders {
+		tokens = formatter.WithoutPlaceholders(tokens)
+	}
+
+	if f.opts.Map {
+		f.formatMap(tokens, opts, &sb)
+		return []byte(sb.String()), nil
+	}
+
 	groups := make(map[string][]*token.Token)
 	for _, tok := range tokens {
 		if len(tok.Path) > 0 {
@@ -58,14 +100,15 @@ func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byt
 
 	for _, groupName := range groupNames {
 		group := groups[groupName]
-		sb.WriteString(fmt.Sprintf("// %s\n", formatter.ToTitleCase(groupName)))
+		if !f.opts.OmitGroupComments {
+			sb.WriteString(fmt.Sprintf("// %s\n", formatter.ToTitleCase(groupName)))
+		}
 
 		sorted := formatter.SortTokens(group)
 		for _, tok := range sorted {
 			baseName := formatter.ToKebabCase(strings.Join(tok.Path, "-"))
-			name := formatter.ApplyPrefix(baseName, opts.Prefix, "-")
-			value := formatter.ResolvedValue(tok)
-			scssValue := toSCSSValue(tok.Type, value)
+			name := opts.TokenName(tok, formatter.ApplyPrefix(baseName, opts.Prefix, "-"))
+			scssValue := f.value(tok)
 
 			if tok.Description != "" {
 				sb.WriteString(fmt.Sprintf("/// %s\n", tok.Description))
@@ -78,6 +121,20 @@ func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byt
 	return []byte(sb.String()), nil
 }
 
+// value formats tok's resolved value as an SCSS value.
+func (f *Formatter) value(tok *token.Token) string {
+	if tok.IsPlaceholder() {
+		return "null"
+	}
+	if hex, ok := formatter.Hex8Color(tok); ok && f.opts.Hex8 {
+		return hex
+	}
+	if legacy, ok := formatter.LegacyColor(tok); ok && f.opts.LegacyColors {
+		return legacy
+	}
+	return toSCSSValue(tok.Type, formatter.ResolvedValue(tok))
+}
+
 func toSCSSValue(tokenType string, value any) string {
 	switch tokenType {
 	case token.TypeColor:
@@ -108,11 +165,35 @@ func toSCSSValue(tokenType string, value any) string {
 			return fmt.Sprintf("%g", v)
 		case int:
 			return fmt.Sprintf("%d", v)
+		case map[string]any:
+			// Percentages carry a unit hint, e.g. {"value": 50, "unit": "%"}
+			if n, hasValue := v["value"]; hasValue && v["unit"] == "%" {
+				return fmt.Sprintf("%v%%", n)
+			}
+			return formatter.MarshalFallback(v)
 		}
 		return fmt.Sprintf("%v", value)
 	case token.TypeFontFamily:
-		if s, ok := value.(string); ok {
-			return fmt.Sprintf("%q", s)
+		switch v := value.(type) {
+		case string:
+			if strings.Contains(v, ",") {
+				return fontStack(strings.Split(v, ","))
+			}
+			if isQuoted(v) {
+				return v
+			}
+			return formatter.QuoteSCSSValue(v)
+		case []any:
+			families := make([]string, 0, len(v))
+			for _, f := range v {
+				families = append(families, fmt.Sprintf("%v", f))
+			}
+			return fontStack(families)
+		}
+	case token.TypeString:
+		// Commas would otherwise make the value a list, e.g. in content strings
+		if s, ok := value.(string); ok && !isQuoted(s) && strings.ContainsAny(s, ",\"';\n") {
+			return formatter.QuoteSCSSValue(s)
 		}
 	}
 
@@ -123,6 +204,9 @@ func toSCSSValue(tokenType string, value any) string {
 			secondsDurationPattern.MatchString(s) {
 			return s
 		}
+		if !isQuoted(s) && strings.ContainsAny(s, "\"';\n") {
+			return formatter.QuoteSCSSValue(s)
+		}
 	}
 
 	// Avoid rendering maps/slices as Go literals
@@ -137,3 +221,30 @@ func toSCSSValue(tokenType string, value any) string {
 
 	return fmt.Sprintf("%v", value)
 }
+
+// fontStack joins font families into a comma-separated list, quoting the
+// names that contain spaces or quotes. Generic families such as sans-serif
+// are single identifiers and stay bare.
+func fontStack(families []string) string {
+	parts := make([]string, 0, len(families))
+	for _, f := range families {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !isQuoted(f) && strings.ContainsAny(f, " \"'") {
+			f = formatter.QuoteSCSSValue(f)
+		}
+		parts = append(parts, f)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// isQuoted reports whether s is already a quoted string.
+func isQuoted(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	q := s[0]
+	return (q == '"' || q == '\'') && s[len(s)-1] == q
+}
@@ -182,6 +182,95 @@ func TestFormat_FontFamilyQuoting(t *testing.T) {
 	}
 }
 
+func TestFormat_FontStacks(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name:     "font.body",
+			Path:     []string{"font", "body"},
+			Type:     token.TypeFontFamily,
+			RawValue: []any{"Red Hat Text", "Helvetica", "sans-serif"},
+		},
+		{
+			Name:     "font.display",
+			Path:     []string{"font", "display"},
+			Type:     token.TypeFontFamily,
+			RawValue: "Red Hat Display, 'Overpass', sans-serif",
+		},
+	}
+
+	f := scss.New()
+	result, err := f.Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, `$font-body: "Red Hat Text", Helvetica, sans-serif;`) {
+		t.Errorf("expected font stack from array, got:\n%s", output)
+	}
+	if !strings.Contains(output, `$font-display: "Red Hat Display", 'Overpass', sans-serif;`) {
+		t.Errorf("expected font stack from string, got:\n%s", output)
+	}
+
+	mapped, err := scss.NewWithOptions(scss.Options{Map: true}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.Contains(string(mapped), `"body": ("Red Hat Text", Helvetica, sans-serif),`) {
+		t.Errorf("expected font stack list in map, got:\n%s", mapped)
+	}
+}
+
+func TestFormat_QuotedContent(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name:     "content.quote",
+			Path:     []string{"content", "quote"},
+			Type:     token.TypeString,
+			RawValue: `He said "hi", then left`,
+		},
+		{
+			Name:     "content.arrow",
+			Path:     []string{"content", "arrow"},
+			Type:     token.TypeString,
+			RawValue: `"→"`,
+		},
+		{
+			Name:     "content.label",
+			Path:     []string{"content", "label"},
+			Type:     token.TypeString,
+			RawValue: "Menu; open",
+		},
+	}
+
+	f := scss.New()
+	result, err := f.Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := string(result)
+
+	if !strings.Contains(output, `$content-quote: "He said \"hi\", then left";`) {
+		t.Errorf("expected escaped content string, got:\n%s", output)
+	}
+	if !strings.Contains(output, `$content-arrow: "→";`) {
+		t.Errorf("expected already-quoted content to pass through, got:\n%s", output)
+	}
+	if !strings.Contains(output, `$content-label: "Menu; open";`) {
+		t.Errorf("expected semicolon to be quoted, got:\n%s", output)
+	}
+
+	mapped, err := scss.NewWithOptions(scss.Options{Map: true}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.Contains(string(mapped), `"quote": "He said \"hi\", then left",`) {
+		t.Errorf("expected quoted string not to be wrapped as a list, got:\n%s", mapped)
+	}
+}
+
 func TestFormat_DurationPatternMatching(t *testing.T) {
 	tokens := []*token.Token{
 		{
@@ -240,6 +329,18 @@ func TestFormat_NumberAndFontWeightValues(t *testing.T) {
 			Type:     token.TypeNumber,
 			RawValue: float64(42),
 		},
+		{
+			Name:     "opacity.muted",
+			Path:     []string{"opacity", "muted"},
+			Type:     token.TypeNumber,
+			RawValue: map[string]any{"value": float64(50), "unit": "%"},
+		},
+		{
+			Name:     "aspect.wide",
+			Path:     []string{"aspect", "wide"},
+			Type:     token.TypeNumber,
+			RawValue: "16/9",
+		},
 		{
 			Name:     "font.weight-bold",
 			Path:     []string{"font", "weight-bold"},
@@ -264,6 +365,14 @@ func TestFormat_NumberAndFontWeightValues(t *testing.T) {
 	if !strings.Contains(output, "$size-integer: 42;") {
 		t.Errorf("expected $size-integer: 42;, got:\n%s", output)
 	}
+	// percentage → "50%"
+	if !strings.Contains(output, "$opacity-muted: 50%;") {
+		t.Errorf("expected $opacity-muted: 50%%;, got:\n%s", output)
+	}
+	// ratio passes through
+	if !strings.Contains(output, "$aspect-wide: 16/9;") {
+		t.Errorf("expected $aspect-wide: 16/9;, got:\n%s", output)
+	}
 	// fontWeight 700 → "700"
 	if !strings.Contains(output, "$font-weight-bold: 700;") {
 		t.Errorf("expected $font-weight-bold: 700;, got:\n%s", output)
@@ -440,3 +549,196 @@ func TestFormat_StringValuesWithCSSUnits(t *testing.T) {
 		t.Errorf("expected $color-hex: #abc123;, got:\n%s", output)
 	}
 }
+
+func TestFormat_OmitGroupComments(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name:          "color.primary",
+			Path:          []string{"color", "primary"},
+			Type:          token.TypeColor,
+			SchemaVersion: schema.Draft,
+			RawValue:      "#ff0000",
+		},
+	}
+
+	result, err := scss.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.Contains(string(result), "// Color\n") {
+		t.Errorf("expected group comment by default, got:\n%s", result)
+	}
+
+	f := scss.NewWithOptions(scss.Options{OmitGroupComments: true})
+	result, err = f.Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := string(result)
+	if strings.Contains(output, "// Color\n") {
+		t.Errorf("expected no group comment, got:\n%s", output)
+	}
+	if !strings.Contains(output, "$color-primary: #ff0000;") {
+		t.Errorf("expected $color-primary: #ff0000;, got:\n%s", output)
+	}
+}
+
+func TestFormat_Map(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name:          "color.primary",
+			Path:          []string{"color", "primary"},
+			Type:          token.TypeColor,
+			SchemaVersion: schema.Draft,
+			RawValue:      "#ff0000",
+			Description:   "Brand color",
+		},
+		{
+			Name:          "color.primary.hover",
+			Path:          []string{"color", "primary", "hover"},
+			Type:          token.TypeColor,
+			SchemaVersion: schema.Draft,
+			RawValue:      "#cc0000",
+		},
+		{
+			Name:          "font.family.body",
+			Path:          []string{"font", "family", "body"},
+			Type:          token.TypeFontFamily,
+			SchemaVersion: schema.Draft,
+			RawValue:      "Red Hat Text, sans-serif",
+		},
+		{
+			Name:          "shadow.md",
+			Path:          []string{"shadow", "md"},
+			Type:          token.TypeShadow,
+			SchemaVersion: schema.Draft,
+			RawValue:      "0 1px 2px #000, 0 2px 4px #000",
+		},
+		{
+			Name:          "space.sm",
+			Path:          []string{"space", "sm"},
+			Type:          token.TypeDimension,
+			SchemaVersion: schema.Draft,
+			RawValue:      "4px",
+		},
+	}
+
+	f := scss.NewWithOptions(scss.Options{Map: true})
+	result, err := f.Format(tokens, formatter.Options{Prefix: "rh"})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	expected := `// Generated by asimonim
+// Do not edit manually
+
+@use "sass:map";
+
+$rh-tokens: (
+  "color": (
+    "primary": (
+      // Brand color
+      "$root": #ff0000,
+      "hover": #cc0000,
+    ),
+  ),
+  "font": (
+    "family": (
+      "body": ("Red Hat Text", sans-serif),
+    ),
+  ),
+  "shadow": (
+    "md": (0 1px 2px #000, 0 2px 4px #000),
+  ),
+  "space": (
+    "sm": 4px,
+  ),
+);
+
+/// Returns the token at the given path, e.g. rh-token(color, primary).
+@function rh-token($path...) {
+  @return map.get($rh-tokens, $path...);
+}
+`
+	if string(result) != expected {
+		t.Errorf("Format() mismatch\ngot:\n%s\nwant:\n%s", result, expected)
+	}
+}
+
+func TestFormat_Hex8(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name:          "color.overlay",
+			Path:          []string{"color", "overlay"},
+			Type:          token.TypeColor,
+			SchemaVersion: schema.V2025_10,
+			RawValue:      map[string]any{"colorSpace": "srgb", "components": []any{1.0, 1.0, 1.0}, "alpha": 0.25},
+		},
+	}
+
+	result, err := scss.NewWithOptions(scss.Options{Hex8: true}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.Contains(string(result), "$color-overlay: #FFFFFF40;") {
+		t.Errorf("expected 8-digit hex, got:\n%s", result)
+	}
+
+	mapped, err := scss.NewWithOptions(scss.Options{Hex8: true, Map: true}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.Contains(string(mapped), `"overlay": #FFFFFF40,`) {
+		t.Errorf("expected 8-digit hex in map, got:\n%s", mapped)
+	}
+}
+
+func TestFormat_LegacyColors(t *testing.T) {
+	tokens := []*token.Token{
+		{
+			Name:          "color.overlay",
+			Path:          []string{"color", "overlay"},
+			Type:          token.TypeColor,
+			SchemaVersion: schema.V2025_10,
+			RawValue:      map[string]any{"colorSpace": "srgb", "components": []any{1.0, 1.0, 1.0}, "alpha": 0.25},
+		},
+	}
+
+	result, err := scss.NewWithOptions(scss.Options{LegacyColors: true}).Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.Contains(string(result), "$color-overlay: rgba(255, 255, 255, 0.25);") {
+		t.Errorf("expected rgba(), got:\n%s", result)
+	}
+}
+
+func TestFormat_Placeholders(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	result, err := scss.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	output := string(result)
+	if strings.Contains(output, "$color-brand") || strings.Contains(output, "$color-link") {
+		t.Errorf("expected placeholders to be left out by default, got:\n%s", output)
+	}
+
+	for _, opts := range []scss.Options{{IncludePlaceholders: true}, {IncludePlaceholders: true, Map: true}} {
+		result, err := scss.NewWithOptions(opts).Format(tokens, formatter.Options{})
+		if err != nil {
+			t.Fatalf("Format error: %v", err)
+		}
+		output := string(result)
+		want := []string{"$color-brand: null;", "$color-link: null;"}
+		if opts.Map {
+			want = []string{`"brand": null,`, `"link": null,`}
+		}
+		for _, w := range want {
+			if !strings.Contains(output, w) {
+				t.Errorf("expected %q, got:\n%s", w, output)
+			}
+		}
+	}
+}
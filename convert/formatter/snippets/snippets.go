@@ -29,6 +29,9 @@ const (
 
 	// TypeZed outputs Zed editor snippets format.
 	TypeZed Type = "zed"
+
+	// TypeSublime outputs Sublime Text .sublime-completions format.
+	TypeSublime Type = "sublime"
 )
 
 // Options configures the snippets formatter.
@@ -56,6 +59,22 @@ type ZedSnippet struct {
 	Description string   `json:"description,omitempty"`
 }
 
+// SublimeCompletions represents a Sublime Text .sublime-completions file.
+type SublimeCompletions struct {
+	Scope       string              `json:"scope"`
+	Completions []SublimeCompletion `json:"completions"`
+}
+
+// SublimeCompletion represents a single Sublime Text completion entry.
+// Sublime completions have one trigger each, so there are no alternate prefixes.
+type SublimeCompletion struct {
+	Trigger    string `json:"trigger"`
+	Contents   string `json:"contents"`
+	Kind       string `json:"kind"`
+	Annotation string `json:"annotation,omitempty"`
+	Details    string `json:"details,omitempty"`
+}
+
 // Formatter outputs editor snippets.
 type Formatter struct {
 	opts Options
@@ -76,11 +95,14 @@ func NewWithOptions(opts Options) *Formatter {
 
 // Format converts tokens to editor snippets format.
 func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
+	tokens = formatter.WithoutPlaceholders(tokens)
 	switch f.opts.Type {
 	case TypeTextMate:
 		return f.formatTextMate(tokens, opts)
 	case TypeZed:
 		return f.formatZed(tokens, opts)
+	case TypeSublime:
+		return f.formatSublime(tokens, opts)
 	default:
 		return f.formatVSCode(tokens, opts)
 	}
@@ -93,15 +115,15 @@ func (f *Formatter) formatVSCode(tokens []*token.Token, opts formatter.Options)
 	sorted := formatter.SortTokens(tokens)
 
 	// Build token index for light-dark detection
-	tokenIndex := buildTokenIndex(sorted, opts.Prefix)
+	tokenIndex := formatter.IndexByPath(sorted)
 
 	for _, tok := range sorted {
 		name := buildTokenName(tok.Path, opts.Prefix)
 
 		// Check if this token is part of a light-dark group
-		if group := findLightDarkGroup(tok, tokenIndex); group != nil {
+		if group := formatter.FindLightDark(tok, tokenIndex); group != nil {
 			// Only emit the combined snippet for the root token
-			if isRootToken(tok, group) {
+			if group.IsRoot(tok) {
 				rootName := getRootName(group, opts.Prefix)
 				snippet := buildLightDarkSnippet(group, rootName, opts)
 				snippetMap[rootName] = snippet
@@ -143,15 +165,15 @@ func (f *Formatter) formatTextMate(tokens []*token.Token, opts formatter.Options
 	sorted := formatter.SortTokens(tokens)
 
 	// Build token index for light-dark detection
-	tokenIndex := buildTokenIndex(sorted, opts.Prefix)
+	tokenIndex := formatter.IndexByPath(sorted)
 
 	for _, tok := range sorted {
 		name := buildTokenName(tok.Path, opts.Prefix)
 
 		// Check if this token is part of a light-dark group
-		if group := findLightDarkGroup(tok, tokenIndex); group != nil {
+		if group := formatter.FindLightDark(tok, tokenIndex); group != nil {
 			// Only emit the combined snippet for the root token
-			if isRootToken(tok, group) {
+			if group.IsRoot(tok) {
 				rootName := getRootName(group, opts.Prefix)
 				lightName := buildTokenName(group.Light.Path, opts.Prefix)
 				darkName := buildTokenName(group.Dark.Path, opts.Prefix)
@@ -181,15 +203,15 @@ func (f *Formatter) formatZed(tokens []*token.Token, opts formatter.Options) ([]
 	sorted := formatter.SortTokens(tokens)
 
 	// Build token index for light-dark detection
-	tokenIndex := buildTokenIndex(sorted, opts.Prefix)
+	tokenIndex := formatter.IndexByPath(sorted)
 
 	for _, tok := range sorted {
 		name := buildTokenName(tok.Path, opts.Prefix)
 
 		// Check if this token is part of a light-dark group
-		if group := findLightDarkGroup(tok, tokenIndex); group != nil {
+		if group := formatter.FindLightDark(tok, tokenIndex); group != nil {
 			// Only emit the combined snippet for the root token
-			if isRootToken(tok, group) {
+			if group.IsRoot(tok) {
 				rootName := getRootName(group, opts.Prefix)
 				snippet := buildZedLightDarkSnippet(group, rootName, opts)
 				snippetMap[rootName] = snippet
@@ -220,7 +242,7 @@ func buildZedSnippet(tok *token.Token, name string, _ formatter.Options) ZedSnip
 }
 
 // buildZedLightDarkSnippet creates a Zed snippet with light-dark() pattern.
-func buildZedLightDarkSnippet(group *lightDarkGroup, name string, opts formatter.Options) ZedSnippet {
+func buildZedLightDarkSnippet(group *formatter.LightDark, name string, opts formatter.Options) ZedSnippet {
 	lightName := buildTokenName(group.Light.Path, opts.Prefix)
 	darkName := buildTokenName(group.Dark.Path, opts.Prefix)
 
@@ -245,17 +267,81 @@ func buildZedLightDarkSnippet(group *lightDarkGroup, name string, opts formatter
 	return snippet
 }
 
-// lightDarkGroup represents a detected light-dark token group.
-type lightDarkGroup struct {
-	Root  *token.Token
-	Light *token.Token
-	Dark  *token.Token
+// formatSublime outputs a Sublime Text .sublime-completions JSON file.
+// Sublime completions are a list rather than a map, so entries keep the
+// sorted token order.
+func (f *Formatter) formatSublime(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
+	result := SublimeCompletions{
+		Scope:       "source.css, source.scss, source.less",
+		Completions: []SublimeCompletion{},
+	}
+
+	sorted := formatter.SortTokens(tokens)
+
+	// Build token index for light-dark detection
+	tokenIndex := formatter.IndexByPath(sorted)
+
+	for _, tok := range sorted {
+		name := buildTokenName(tok.Path, opts.Prefix)
+
+		// Check if this token is part of a light-dark group
+		if group := formatter.FindLightDark(tok, tokenIndex); group != nil {
+			// Only emit the combined completion for the root token
+			if group.IsRoot(tok) {
+				rootName := getRootName(group, opts.Prefix)
+				result.Completions = append(result.Completions, buildSublimeLightDarkCompletion(group, rootName, opts))
+			}
+			// Skip individual completions for light/dark children
+			continue
+		}
+
+		result.Completions = append(result.Completions, buildSublimeCompletion(tok, name))
+	}
+
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// buildSublimeCompletion creates a Sublime Text completion from a token.
+// The annotation shows the token's value next to the trigger in the popup.
+func buildSublimeCompletion(tok *token.Token, name string) SublimeCompletion {
+	completion := SublimeCompletion{
+		Trigger:    name,
+		Contents:   fmt.Sprintf("var(--%s)", name),
+		Kind:       "snippet",
+		Annotation: tok.DisplayValue(),
+	}
+
+	if tok.Description != "" {
+		completion.Details = tok.Description
+	}
+
+	return completion
 }
 
-// tokenIndexEntry holds a token and its computed name.
-type tokenIndexEntry struct {
-	Token *token.Token
-	Name  string
+// buildSublimeLightDarkCompletion creates a Sublime completion with light-dark() pattern.
+func buildSublimeLightDarkCompletion(group *formatter.LightDark, name string, opts formatter.Options) SublimeCompletion {
+	lightName := buildTokenName(group.Light.Path, opts.Prefix)
+	darkName := buildTokenName(group.Dark.Path, opts.Prefix)
+
+	// Get resolved color values for fallbacks
+	lightValue := getColorValue(group.Light)
+	darkValue := getColorValue(group.Dark)
+
+	completion := SublimeCompletion{
+		Trigger:    name,
+		Contents:   buildLightDarkBody(name, lightName, darkName, lightValue, darkValue),
+		Kind:       "snippet",
+		Annotation: "light-dark",
+	}
+
+	// Use description from real root if available, otherwise from light token
+	if group.Root != group.Light && group.Root.Description != "" {
+		completion.Details = group.Root.Description
+	} else if group.Light.Description != "" {
+		completion.Details = group.Light.Description
+	}
+
+	return completion
 }
 
 // buildTokenName creates a CSS custom property name from a token path.
@@ -267,17 +353,6 @@ func buildTokenName(path []string, prefix string) string {
 	return name
 }
 
-// buildTokenIndex creates a map from token path to token for efficient lookup.
-func buildTokenIndex(tokens []*token.Token, prefix string) map[string]*tokenIndexEntry {
-	index := make(map[string]*tokenIndexEntry, len(tokens))
-	for _, tok := range tokens {
-		path := strings.Join(tok.Path, ".")
-		name := buildTokenName(tok.Path, prefix)
-		index[path] = &tokenIndexEntry{Token: tok, Name: name}
-	}
-	return index
-}
-
 // buildLightDarkBody creates the CSS light-dark() function body.
 func buildLightDarkBody(name, lightName, darkName, lightValue, darkValue string) string {
 	if lightValue != "" && darkValue != "" {
@@ -292,111 +367,13 @@ func buildLightDarkBody(name, lightName, darkName, lightValue, darkValue string)
 	)
 }
 
-// findLightDarkGroup checks if a token is part of a light-dark group.
-// Returns the group if found, nil otherwise.
-//
-// Detection rules (convention-based):
-// - A color token ending in ".light" that has a sibling ".dark" token
-// - A color token with a Reference field that points to a ".light" child
-func findLightDarkGroup(tok *token.Token, index map[string]*tokenIndexEntry) *lightDarkGroup {
-	if tok.Type != token.TypeColor {
-		return nil
-	}
-
-	tokPath := strings.Join(tok.Path, ".")
-
-	// Check if this token IS the root (has Reference pointing to light child)
-	if tok.Reference != "" {
-		lightPath := fmt.Sprintf("%s.light", tokPath)
-		darkPath := fmt.Sprintf("%s.dark", tokPath)
-
-		expectedRef := fmt.Sprintf("{%s}", lightPath)
-		if tok.Reference == expectedRef {
-			lightEntry, hasLight := index[lightPath]
-			darkEntry, hasDark := index[darkPath]
-
-			if hasLight && hasDark {
-				return &lightDarkGroup{
-					Root:  tok,
-					Light: lightEntry.Token,
-					Dark:  darkEntry.Token,
-				}
-			}
-		}
-	}
-
-	// Check if this token is a light/dark child (convention-based detection)
-	if len(tok.Path) < 2 {
-		return nil
-	}
-
-	lastSegment := tok.Path[len(tok.Path)-1]
-	if lastSegment != "light" && lastSegment != "dark" {
-		return nil
-	}
-
-	// Build sibling paths
-	parentPath := strings.Join(tok.Path[:len(tok.Path)-1], ".")
-	lightPath := fmt.Sprintf("%s.light", parentPath)
-	darkPath := fmt.Sprintf("%s.dark", parentPath)
-
-	lightEntry, hasLight := index[lightPath]
-	darkEntry, hasDark := index[darkPath]
-
-	if !hasLight || !hasDark {
-		return nil
-	}
-
-	// Check if there's a root token (optional - may not exist if using $root syntax)
-	rootEntry, hasRoot := index[parentPath]
-
-	return &lightDarkGroup{
-		Root:  rootTokenOrLight(rootEntry, lightEntry, hasRoot),
-		Light: lightEntry.Token,
-		Dark:  darkEntry.Token,
-	}
-}
-
-// rootTokenOrLight returns the root token if it exists, otherwise the light token.
-// This allows light-dark detection to work even when the parser doesn't emit a root token.
-func rootTokenOrLight(rootEntry, lightEntry *tokenIndexEntry, hasRoot bool) *token.Token {
-	if hasRoot {
-		return rootEntry.Token
-	}
-	return lightEntry.Token
-}
-
-// isRootToken checks if the given token is the root of the light-dark group.
-// When there's no explicit root token, the light token is used as surrogate.
-func isRootToken(tok *token.Token, group *lightDarkGroup) bool {
-	if tok == group.Root {
-		return true
-	}
-	// When Root == Light, we're using light as surrogate root
-	// Only emit when processing the light token
-	if group.Root == group.Light {
-		return tok == group.Light
-	}
-	return false
-}
-
 // getRootName returns the CSS custom property name for the root of a light-dark group.
-// When using a surrogate root (light token), derives the parent name.
-func getRootName(group *lightDarkGroup, prefix string) string {
-	// If there's a real root token (different from light), use its path
-	if group.Root != group.Light {
-		return buildTokenName(group.Root.Path, prefix)
-	}
-	// Derive parent name from light token's path (remove ".light" suffix)
-	if len(group.Light.Path) > 1 {
-		parentPath := group.Light.Path[:len(group.Light.Path)-1]
-		return buildTokenName(parentPath, prefix)
-	}
-	return buildTokenName(group.Light.Path, prefix)
+func getRootName(group *formatter.LightDark, prefix string) string {
+	return buildTokenName(group.Path(), prefix)
 }
 
 // buildLightDarkSnippet creates a snippet with light-dark() pattern.
-func buildLightDarkSnippet(group *lightDarkGroup, name string, opts formatter.Options) Snippet {
+func buildLightDarkSnippet(group *formatter.LightDark, name string, opts formatter.Options) Snippet {
 	lightName := buildTokenName(group.Light.Path, opts.Prefix)
 	darkName := buildTokenName(group.Dark.Path, opts.Prefix)
 
@@ -40,6 +40,14 @@ func TestFormat_Zed(t *testing.T) {
 	runFixtureTest(t, "zed", snippets.Options{Type: snippets.TypeZed})
 }
 
+func TestFormat_Sublime(t *testing.T) {
+	runFixtureTest(t, "sublime", snippets.Options{Type: snippets.TypeSublime})
+}
+
+func TestFormat_SublimeLightDark(t *testing.T) {
+	runFixtureTest(t, "sublime-light-dark", snippets.Options{Type: snippets.TypeSublime})
+}
+
 func TestFormat_TextMateLightDark(t *testing.T) {
 	runFixtureTest(t, "textmate-light-dark", snippets.Options{Type: snippets.TypeTextMate})
 }
@@ -131,6 +139,9 @@ func runFixtureTestWithSchema(t *testing.T, fixtureName string, snippetOpts snip
 	if snippetOpts.Type == snippets.TypeTextMate {
 		expectedExt = ".plist"
 	}
+	if snippetOpts.Type == snippets.TypeSublime {
+		expectedExt = ".sublime-completions"
+	}
 	goldenRelPath := filepath.Join(fixturePath, "expected"+expectedExt)
 
 	// Update golden file if -update flag is set
@@ -183,3 +194,21 @@ func runFixtureTestWithNew(t *testing.T, fixtureName string) {
 		t.Errorf("output mismatch for fixture %q.\n\nGot:\n%s\n\nExpected:\n%s", fixtureName, gotStr, expectedStr)
 	}
 }
+
+func TestFormat_SkipsPlaceholders(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	result, err := snippets.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := string(result)
+	for _, name := range []string{"color-brand", "color-link"} {
+		if strings.Contains(output, name) {
+			t.Errorf("expected placeholder %s to be left out, got:\n%s", name, output)
+		}
+	}
+	if !strings.Contains(output, "color-text") {
+		t.Errorf("expected %s to be kept, got:\n%s", "color-text", output)
+	}
+}
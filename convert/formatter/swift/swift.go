@@ -9,6 +9,7 @@ package swift
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -19,16 +20,39 @@ import (
 	"bennypowers.dev/asimonim/token"
 )
 
+// Options configures the Swift formatter.
+type Options struct {
+	// RootFontSize is the size in points of 1rem, which rem and em
+	// dimensions are scaled by. Zero means formatter.DefaultRootPx.
+	RootFontSize float64
+}
+
 // Formatter outputs iOS Swift constants.
-type Formatter struct{}
+type Formatter struct {
+	opts Options
+}
 
 // New creates a new Swift formatter.
 func New() *Formatter {
 	return &Formatter{}
 }
 
+// NewWithOptions creates a new Swift formatter with the specified options.
+func NewWithOptions(opts Options) *Formatter {
+	return &Formatter{opts: opts}
+}
+
+// rootFontSize returns the size in points of 1rem.
+func (f *Formatter) rootFontSize() float64 {
+	if f.opts.RootFontSize > 0 {
+		return f.opts.RootFontSize
+	}
+	return formatter.DefaultRootPx
+}
+
 // Format converts tokens to Swift constants.
 func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
+	tokens = formatter.WithoutPlaceholders(tokens)
 	var sb strings.Builder
 
 	// Add header if provided, otherwise use default
@@ -71,9 +95,9 @@ func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byt
 
 		sorted := formatter.SortTokens(group)
 		for _, tok := range sorted {
-			name := formatter.ToCamelCase(strings.Join(tok.Path, "-"))
+			name := opts.TokenName(tok, formatter.ToCamelCase(strings.Join(tok.Path, "-")))
 			value := formatter.ResolvedValue(tok)
-			swiftValue := toSwiftValue(tok.Type, value)
+			swiftValue := toSwiftValue(tok.Type, value, f.rootFontSize())
 
 			if tok.Description != "" {
 				sb.WriteString(fmt.Sprintf("        /// %s\n", tok.Description))
@@ -89,9 +113,9 @@ func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byt
 		sb.WriteString("    public enum Other {\n")
 		sorted := formatter.SortTokens(ungrouped)
 		for _, tok := range sorted {
-			name := formatter.ToCamelCase(strings.Join(tok.Path, "-"))
+			name := opts.TokenName(tok, formatter.ToCamelCase(strings.Join(tok.Path, "-")))
 			value := formatter.ResolvedValue(tok)
-			swiftValue := toSwiftValue(tok.Type, value)
+			swiftValue := toSwiftValue(tok.Type, value, f.rootFontSize())
 			sb.WriteString(fmt.Sprintf("        public static let %s = %s\n", name, swiftValue))
 		}
 		sb.WriteString("    }\n")
@@ -116,7 +140,9 @@ func swiftEnumName(tokenType string) string {
 	return formatter.ToPascalCase(tokenType)
 }
 
-func toSwiftValue(tokenType string, value any) string {
+// toSwiftValue formats value as a Swift literal for tokenType. rootPx is
+// the size in points of 1rem.
+func toSwiftValue(tokenType string, value any, rootPx float64) string {
 	switch tokenType {
 	case token.TypeColor:
 		if colorObj, ok := value.(map[string]any); ok {
@@ -130,38 +156,13 @@ func toSwiftValue(tokenType string, value any) string {
 			return fmt.Sprintf("%q", s)
 		}
 	case token.TypeDimension:
+		if dim, ok := dimensionToSwift(value, rootPx); ok {
+			return dim
+		}
 		if m, ok := value.(map[string]any); ok {
-			if v, hasValue := m["value"]; hasValue && v != nil {
-				u, hasUnit := m["unit"].(string)
-				formatDim := func(numStr string) string {
-					if hasUnit {
-						safeUnit := strings.ReplaceAll(u, "/*", "")
-						safeUnit = strings.ReplaceAll(safeUnit, "*/", "")
-						safeUnit = strings.ReplaceAll(safeUnit, "\n", " ")
-						safeUnit = strings.ReplaceAll(safeUnit, "\r", " ")
-						return fmt.Sprintf("CGFloat(%s) /* %s */", numStr, safeUnit)
-					}
-					return fmt.Sprintf("CGFloat(%s)", numStr)
-				}
-				switch num := v.(type) {
-				case float64:
-					if num == float64(int(num)) {
-						return formatDim(fmt.Sprintf("%d", int(num)))
-					}
-					return formatDim(fmt.Sprintf("%g", num))
-				case int:
-					return formatDim(fmt.Sprintf("%d", num))
-				}
-			}
 			logger.Warn("dimension token has map structure but missing valid value")
 			return fmt.Sprintf("%q", formatter.MarshalFallback(m))
 		}
-		if s, ok := value.(string); ok {
-			s = strings.TrimSuffix(s, "px")
-			s = strings.TrimSuffix(s, "rem")
-			s = strings.TrimSuffix(s, "em")
-			return fmt.Sprintf("CGFloat(%s)", s)
-		}
 	case token.TypeDuration:
 		if s, ok := value.(string); ok {
 			var numStr string
@@ -198,6 +199,59 @@ func toSwiftValue(tokenType string, value any) string {
 	return fmt.Sprintf("%q", fmt.Sprintf("%v", value))
 }
 
+// dimensionToSwift formats a dimension as a CGFloat of points, taking
+// 1px as 1pt. rem and em values are scaled by rootPx and % values become
+// a fraction; both note the original value in a comment. Units without a
+// fixed size, such as vw, keep their number with the unit in a comment.
+func dimensionToSwift(value any, rootPx float64) (string, bool) {
+	if s, ok := value.(string); ok {
+		value = compactDimension(s)
+	}
+	num, unit, ok := formatter.ParseDimension(value)
+	if !ok {
+		return "", false
+	}
+	_, structured := value.(map[string]any)
+	points, fixed := formatter.DimensionToFloat(value, rootPx)
+	switch {
+	case !fixed:
+		return fmt.Sprintf("CGFloat(%s) /* %s */", formatFloat(num), swiftCommentSafe(unit)), true
+	case unit == "" || (unit == "px" && !structured):
+		return fmt.Sprintf("CGFloat(%s)", formatFloat(points)), true
+	case unit == "px":
+		return fmt.Sprintf("CGFloat(%s) /* px */", formatFloat(points)), true
+	default:
+		return fmt.Sprintf("CGFloat(%s) /* %s%s */", formatFloat(points), formatFloat(num), unit), true
+	}
+}
+
+// spacedDimensionPattern matches a dimension string with space around its
+// number and unit, like " 1.5 rem ".
+var spacedDimensionPattern = regexp.MustCompile(`^\s*([+-]?(?:\d+\.?\d*|\.\d+))\s*([a-zA-Z]+|%)?\s*$`)
+
+// compactDimension removes the space around a dimension's number and
+// unit, which Swift output tolerates though the DTCG format doesn't.
+func compactDimension(s string) string {
+	if m := spacedDimensionPattern.FindStringSubmatch(s); m != nil {
+		return m[1] + m[2]
+	}
+	return s
+}
+
+// formatFloat formats n without trailing zeros or an exponent.
+func formatFloat(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// swiftCommentSafe strips sequences from s that would end or break a
+// /* */ comment.
+func swiftCommentSafe(s string) string {
+	s = strings.ReplaceAll(s, "/*", "")
+	s = strings.ReplaceAll(s, "*/", "")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return strings.ReplaceAll(s, "\r", " ")
+}
+
 func structuredColorToSwift(colorObj map[string]any) string {
 	colorSpace, _ := colorObj["colorSpace"].(string)
 	componentsRaw, _ := colorObj["components"].([]any)
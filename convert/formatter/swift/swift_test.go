@@ -77,9 +77,9 @@ func TestFormat_V2025_10_StructuredDimensions(t *testing.T) {
 	if !strings.Contains(output, "CGFloat(4)") {
 		t.Errorf("expected CGFloat(4) for px dimension, got:\n%s", output)
 	}
-	// spacing.medium: {value: 1.5, unit: "rem"} → CGFloat(1.5)
-	if !strings.Contains(output, "CGFloat(1.5)") {
-		t.Errorf("expected CGFloat(1.5) for rem dimension, got:\n%s", output)
+	// spacing.medium: {value: 1.5, unit: "rem"} → 1.5 × 16pt
+	if !strings.Contains(output, "CGFloat(24) /* 1.5rem */") {
+		t.Errorf("expected CGFloat(24) for rem dimension, got:\n%s", output)
 	}
 
 	if strings.Contains(output, "map[") {
@@ -511,13 +511,13 @@ func TestFormat_DimensionStringValue(t *testing.T) {
 	if !strings.Contains(output, "CGFloat(16)") {
 		t.Errorf("expected CGFloat(16) for 16px string, got:\n%s", output)
 	}
-	// "2em" → CGFloat(2)
-	if !strings.Contains(output, "CGFloat(2)") {
-		t.Errorf("expected CGFloat(2) for 2em string, got:\n%s", output)
+	// "2em" → 2 × 16pt
+	if !strings.Contains(output, "CGFloat(32) /* 2em */") {
+		t.Errorf("expected CGFloat(32) for 2em string, got:\n%s", output)
 	}
-	// "1.5rem" → CGFloat(1.5)
-	if !strings.Contains(output, "CGFloat(1.5)") {
-		t.Errorf("expected CGFloat(1.5) for 1.5rem string, got:\n%s", output)
+	// "1.5rem" → 1.5 × 16pt
+	if !strings.Contains(output, "CGFloat(24) /* 1.5rem */") {
+		t.Errorf("expected CGFloat(24) for 1.5rem string, got:\n%s", output)
 	}
 }
 
@@ -570,6 +570,37 @@ func TestFormat_DimensionWithUnitComment(t *testing.T) {
 	}
 }
 
+func TestFormat_DimensionUnits(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "size.px", Path: []string{"size", "px"}, Type: token.TypeDimension, RawValue: "12px"},
+		{Name: "size.rem", Path: []string{"size", "rem"}, Type: token.TypeDimension, RawValue: map[string]any{"value": 1.25, "unit": "rem"}},
+		{Name: "size.unitless", Path: []string{"size", "unitless"}, Type: token.TypeDimension, RawValue: 0.0},
+		{Name: "size.percent", Path: []string{"size", "percent"}, Type: token.TypeDimension, RawValue: "50%"},
+		{Name: "size.viewport", Path: []string{"size", "viewport"}, Type: token.TypeDimension, RawValue: "10vw"},
+		{Name: "size.spaced", Path: []string{"size", "spaced"}, Type: token.TypeDimension, RawValue: " 1.5 rem "},
+	}
+
+	f := swift.NewWithOptions(swift.Options{RootFontSize: 20})
+	result, err := f.Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := string(result)
+
+	for _, want := range []string{
+		"sizePx = CGFloat(12)\n",
+		"sizeRem = CGFloat(25) /* 1.25rem */\n",
+		"sizeUnitless = CGFloat(0)\n",
+		"sizePercent = CGFloat(0.5) /* 50% */\n",
+		"sizeViewport = CGFloat(10) /* vw */\n",
+		"sizeSpaced = CGFloat(30) /* 1.5rem */\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q, got:\n%s", want, output)
+		}
+	}
+}
+
 // assertGolden compares result against a golden file, or updates the
 // golden file when -update is passed.
 func assertGolden(t *testing.T, result []byte, goldenPath string) {
@@ -589,3 +620,21 @@ func assertGolden(t *testing.T, result []byte, goldenPath string) {
 	require.NoError(t, err, "golden file %s not found; run with -update to create", goldenPath)
 	require.Equal(t, string(expected), string(result))
 }
+
+func TestFormat_SkipsPlaceholders(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	result, err := swift.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := string(result)
+	for _, name := range []string{"colorBrand", "colorLink"} {
+		if strings.Contains(output, name) {
+			t.Errorf("expected placeholder %s to be left out, got:\n%s", name, output)
+		}
+	}
+	if !strings.Contains(output, "colorText") {
+		t.Errorf("expected %s to be kept, got:\n%s", "colorText", output)
+	}
+}
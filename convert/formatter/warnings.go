@@ -0,0 +1,35 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package formatter
+
+import (
+	"fmt"
+
+	"bennypowers.dev/asimonim/token"
+)
+
+// Warning describes a token that a formatter left out of its output or
+// wrote differently than authored, such as a value with no CSS form.
+type Warning struct {
+	// Path is the dot path of the token.
+	Path string
+	// Message describes what the formatter did.
+	Message string
+}
+
+// String formats the warning as "path: message".
+func (w Warning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+// Warn reports a warning about tok to OnWarning, if it is set.
+func (o Options) Warn(tok *token.Token, format string, args ...any) {
+	if o.OnWarning == nil {
+		return
+	}
+	o.OnWarning(Warning{Path: tok.DotPath(), Message: fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package xcassets provides an Xcode asset catalog formatter for color tokens.
+package xcassets
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mazznoer/csscolorparser"
+
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/parser/common"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/token"
+)
+
+// Formatter outputs color tokens as color sets in an Xcode asset catalog.
+type Formatter struct{}
+
+// New creates a new asset catalog formatter.
+func New() *Formatter {
+	return &Formatter{}
+}
+
+// contents is the Contents.json of the catalog or of a color set.
+type contents struct {
+	Colors []colorEntry `json:"colors,omitempty"`
+	Info   info         `json:"info"`
+}
+
+type info struct {
+	Author  string `json:"author"`
+	Version int    `json:"version"`
+}
+
+// colorEntry is one appearance of a color set's color.
+type colorEntry struct {
+	Appearances []appearance `json:"appearances,omitempty"`
+	Color       color        `json:"color"`
+	Idiom       string       `json:"idiom"`
+}
+
+type appearance struct {
+	Appearance string `json:"appearance"`
+	Value      string `json:"value"`
+}
+
+type color struct {
+	ColorSpace string     `json:"color-space"`
+	Components components `json:"components"`
+}
+
+// components holds channels as Xcode writes them: decimal strings.
+type components struct {
+	Alpha string `json:"alpha"`
+	Blue  string `json:"blue"`
+	Green string `json:"green"`
+	Red   string `json:"red"`
+}
+
+// catalogInfo is the info block Xcode writes, so that saving the catalog
+// in Xcode doesn't rewrite every file.
+var catalogInfo = info{Author: "xcode", Version: 1}
+
+// Format returns the files of an asset catalog, keyed by their path
+// within the catalog directory: the catalog's own Contents.json, and a
+// Contents.json per color set, e.g. colorBrandPrimary.colorset/Contents.json.
+//
+// Each color token becomes a color set named like the Swift formatter's
+// constants. A light-dark group (see formatter.FindLightDark) becomes a
+// single color set whose dark variant has the dark appearance. Display P3
+// colors keep their color space; other color spaces are converted to sRGB.
+// Tokens of other types are skipped.
+func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) (map[string][]byte, error) {
+	tokens = formatter.WithoutPlaceholders(tokens)
+	files := make(map[string][]byte)
+	data, err := marshal(contents{Info: catalogInfo})
+	if err != nil {
+		return nil, err
+	}
+	files["Contents.json"] = data
+
+	sorted := formatter.SortTokens(tokens)
+	index := formatter.IndexByPath(sorted)
+
+	for _, tok := range sorted {
+		if tok.Type != token.TypeColor {
+			continue
+		}
+
+		var name string
+		var entries []colorEntry
+		if group := formatter.FindLightDark(tok, index); group != nil {
+			// Only emit the combined color set for the root token
+			if !group.IsRoot(tok) {
+				continue
+			}
+			name = colorSetName(group.Path(), opts)
+			if group.Root != group.Light {
+				name = opts.TokenName(group.Root, name)
+			}
+			light, lightOK := toColor(group.Light, opts)
+			dark, darkOK := toColor(group.Dark, opts)
+			if !lightOK || !darkOK {
+				continue
+			}
+			entries = []colorEntry{
+				{Color: light, Idiom: "universal"},
+				{
+					Appearances: []appearance{{Appearance: "luminosity", Value: "dark"}},
+					Color:       dark,
+					Idiom:       "universal",
+				},
+			}
+		} else {
+			name = opts.TokenName(tok, colorSetName(tok.Path, opts))
+			c, ok := toColor(tok, opts)
+			if !ok {
+				continue
+			}
+			entries = []colorEntry{{Color: c, Idiom: "universal"}}
+		}
+
+		data, err := marshal(contents{Colors: entries, Info: catalogInfo})
+		if err != nil {
+			return nil, err
+		}
+		files[path.Join(name+".colorset", "Contents.json")] = data
+	}
+
+	return files, nil
+}
+
+// colorSetName returns the default color set name for a token path, the
+// same camelCase name the Swift formatter gives its constant.
+func colorSetName(tokenPath []string, opts formatter.Options) string {
+	return formatter.ApplyPrefixCamel(formatter.ToCamelCase(strings.Join(tokenPath, "-")), opts.Prefix)
+}
+
+// toColor converts a color token's value to a color set color. It
+// returns false, with a warning, for values it can't read as a color.
+func toColor(tok *token.Token, opts formatter.Options) (color, bool) {
+	value := formatter.ResolvedValue(tok)
+
+	if m, ok := value.(map[string]any); ok {
+		// Structured color objects are a v2025.10 feature; draft colors are always strings.
+		colorVal, err := common.ParseColorValue(m, schema.V2025_10)
+		if err == nil {
+			if obj, ok := colorVal.(*common.ObjectColorValue); ok {
+				return structuredColor(obj, tok, opts)
+			}
+		}
+	} else if s, ok := value.(string); ok {
+		if c, err := csscolorparser.Parse(s); err == nil {
+			return newColor("srgb", c.R, c.G, c.B, c.A), true
+		}
+	}
+
+	opts.Warn(tok, "skipped, since %v is not a color the asset catalog can hold", value)
+	return color{}, false
+}
+
+// structuredColor converts a structured color, keeping Display P3 and
+// converting other color spaces to sRGB.
+func structuredColor(obj *common.ObjectColorValue, tok *token.Token, opts formatter.Options) (color, bool) {
+	alpha := 1.0
+	if obj.Alpha != nil {
+		alpha = *obj.Alpha
+	}
+
+	if obj.ColorSpace == "display-p3" && len(obj.Components) == 3 {
+		var c [3]float64
+		for i, comp := range obj.Components {
+			if v, ok := comp.(float64); ok {
+				c[i] = v
+			}
+		}
+		return newColor("display-p3", c[0], c[1], c[2], alpha), true
+	}
+
+	r, g, b, ok := obj.SRGB()
+	if !ok {
+		opts.Warn(tok, "skipped, since %s can't be converted to sRGB", obj.ColorSpace)
+		return color{}, false
+	}
+	// hsl and hwb are sRGB colors already
+	if cs := obj.ColorSpace; cs != "srgb" && cs != "hsl" && cs != "hwb" {
+		opts.Warn(tok, "downsampled from %s to sRGB for the asset catalog", cs)
+	}
+	return newColor("srgb", r, g, b, alpha), true
+}
+
+func newColor(colorSpace string, r, g, b, a float64) color {
+	return color{
+		ColorSpace: colorSpace,
+		Components: components{
+			Alpha: channel(a),
+			Blue:  channel(b),
+			Green: channel(g),
+			Red:   channel(r),
+		},
+	}
+}
+
+// channel formats a channel with three decimals, as Xcode does.
+func channel(v float64) string {
+	return fmt.Sprintf("%.3f", v)
+}
+
+func marshal(c contents) ([]byte, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package xcassets_test
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/convert/formatter/xcassets"
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/resolver"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/testutil"
+	"bennypowers.dev/asimonim/token"
+)
+
+func loadFixture(t *testing.T) []*token.Token {
+	t.Helper()
+	mfs := testutil.NewFixtureFS(t, "fixtures/catalog", "/test")
+	tokens, err := parser.NewJSONParser().ParseFile(mfs, "/test/tokens.json", parser.Options{
+		SchemaVersion: schema.V2025_10,
+		SkipPositions: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to parse tokens.json: %v", err)
+	}
+	if err := resolver.ResolveAliases(tokens, schema.V2025_10); err != nil {
+		t.Fatalf("failed to resolve aliases: %v", err)
+	}
+	return tokens
+}
+
+func TestFormat(t *testing.T) {
+	files, err := xcassets.New().Format(loadFixture(t), formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	// One color set per color, with the light-dark group combined and
+	// the dimension skipped
+	want := []string{
+		"Contents.json",
+		"colorAccent.colorset/Contents.json",
+		"colorBrand.colorset/Contents.json",
+		"colorSurface.colorset/Contents.json",
+		"colorVivid.colorset/Contents.json",
+	}
+	if got := slices.Sorted(maps.Keys(files)); !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+
+	for name, data := range files {
+		golden := filepath.Join("fixtures/catalog/expected", name)
+		testutil.UpdateGoldenFile(t, golden, data)
+		if expected := testutil.LoadFixtureFile(t, golden); string(data) != string(expected) {
+			t.Errorf("%s mismatch.\n\nExpected:\n%s\n\nActual:\n%s", name, expected, data)
+		}
+	}
+}
+
+func TestFormat_Prefix(t *testing.T) {
+	files, err := xcassets.New().Format(loadFixture(t), formatter.Options{Prefix: "rh"})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if _, ok := files["rhColorBrand.colorset/Contents.json"]; !ok {
+		t.Errorf("expected prefixed color set, got %v", slices.Sorted(maps.Keys(files)))
+	}
+}
+
+func TestFormat_DraftColors(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-primary", Path: []string{"color", "primary"}, Type: token.TypeColor, Value: "#ff000080"},
+		{Name: "color-invalid", Path: []string{"color", "invalid"}, Type: token.TypeColor, Value: "not-a-color"},
+	}
+	files, err := xcassets.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if _, ok := files["colorInvalid.colorset/Contents.json"]; ok {
+		t.Error("expected unparseable color to be skipped")
+	}
+	got := string(files["colorPrimary.colorset/Contents.json"])
+	for _, want := range []string{`"color-space": "srgb"`, `"red": "1.000"`, `"alpha": "0.502"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s in translucent sRGB red, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormat_Warnings(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-invalid", Path: []string{"color", "invalid"}, Type: token.TypeColor, Value: "not-a-color"},
+		{
+			Name: "color-vivid", Path: []string{"color", "vivid"}, Type: token.TypeColor,
+			ResolvedValue: map[string]any{"colorSpace": "oklch", "components": []any{0.7, 0.1, 30.0}},
+		},
+	}
+
+	var warnings []string
+	opts := formatter.Options{OnWarning: func(w formatter.Warning) { warnings = append(warnings, w.String()) }}
+	if _, err := xcassets.New().Format(tokens, opts); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	want := []string{
+		"color.invalid: skipped, since not-a-color is not a color the asset catalog can hold",
+		"color.vivid: downsampled from oklch to sRGB for the asset catalog",
+	}
+	if !slices.Equal(warnings, want) {
+		t.Errorf("warnings = %q, want %q", warnings, want)
+	}
+}
+
+func TestFormat_SkipsPlaceholders(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	result, err := xcassets.New().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	output := strings.Join(slices.Sorted(maps.Keys(result)), "\n")
+	for _, name := range []string{"colorBrand", "colorLink"} {
+		if strings.Contains(output, name) {
+			t.Errorf("expected placeholder %s to be left out, got:\n%s", name, output)
+		}
+	}
+	if !strings.Contains(output, "colorText") {
+		t.Errorf("expected %s to be kept, got:\n%s", "colorText", output)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+// Package yaml provides DTCG-compliant YAML formatting for design tokens.
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+
+	goyaml "gopkg.in/yaml.v3"
+
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/token"
+)
+
+// Formatter outputs DTCG-compliant YAML.
+type Formatter struct {
+	// Serialize is the function used to convert tokens to DTCG map structure.
+	// This allows the formatter to share serialization with the DTCG JSON output.
+	Serialize func(tokens []*token.Token) map[string]any
+}
+
+// New creates a new YAML formatter with the given serialization function.
+func New(serialize func(tokens []*token.Token) map[string]any) *Formatter {
+	return &Formatter{Serialize: serialize}
+}
+
+// Format converts tokens to DTCG-compliant YAML.
+// Keys are sorted, and $-prefixed keys are kept as-is.
+func (f *Formatter) Format(tokens []*token.Token, opts formatter.Options) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(formatter.FormatHeader(opts.Header, formatter.HashComments))
+
+	enc := goyaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(f.Serialize(tokens)); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
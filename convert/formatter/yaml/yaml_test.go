@@ -0,0 +1,101 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package yaml_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/convert"
+	"bennypowers.dev/asimonim/convert/formatter"
+	"bennypowers.dev/asimonim/convert/formatter/yaml"
+	"bennypowers.dev/asimonim/internal/mapfs"
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/resolver"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/testutil"
+	"bennypowers.dev/asimonim/token"
+)
+
+func newFormatter() *yaml.Formatter {
+	return yaml.New(func(tokens []*token.Token) map[string]any {
+		return convert.Serialize(tokens, convert.Options{})
+	})
+}
+
+func TestFormat_Basic(t *testing.T) {
+	fixturePath := filepath.Join("fixtures", "basic")
+	tokens := testutil.ParseFixtureTokens(t, fixturePath, schema.Draft)
+
+	result, err := newFormatter().Format(tokens, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	goldenRelPath := filepath.Join(fixturePath, "expected.yaml")
+	testutil.UpdateGoldenFile(t, goldenRelPath, result)
+	expected := testutil.LoadFixtureFile(t, goldenRelPath)
+
+	if string(result) != string(expected) {
+		t.Errorf("output mismatch.\n\nGot:\n%s\n\nExpected:\n%s", result, expected)
+	}
+}
+
+func TestFormat_RoundTrip(t *testing.T) {
+	original := testutil.ParseFixtureTokens(t, filepath.Join("fixtures", "basic"), schema.Draft)
+
+	result, err := newFormatter().Format(original, formatter.Options{})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	mfs := mapfs.New()
+	mfs.AddFile("/test/tokens.yaml", string(result), 0644)
+	reparsed, err := parser.NewJSONParser().ParseFile(mfs, "/test/tokens.yaml", parser.Options{
+		SchemaVersion: schema.Draft,
+		SkipPositions: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to re-parse YAML output: %v", err)
+	}
+	if err := resolver.ResolveAliases(reparsed, schema.Draft); err != nil {
+		t.Fatalf("failed to resolve re-parsed aliases: %v", err)
+	}
+
+	if len(reparsed) != len(original) {
+		t.Fatalf("re-parsed %d tokens, want %d", len(reparsed), len(original))
+	}
+	for _, want := range original {
+		got := testutil.TokenByPath(t, reparsed, want.DotPath())
+		if got.Type != want.Type {
+			t.Errorf("%s: $type = %q, want %q", want.DotPath(), got.Type, want.Type)
+		}
+		// YAML decodes integers as int where JSON gives float64, so compare display values.
+		if got.DisplayValue() != want.DisplayValue() {
+			t.Errorf("%s: $value = %q, want %q", want.DotPath(), got.DisplayValue(), want.DisplayValue())
+		}
+		if got.Description != want.Description {
+			t.Errorf("%s: $description = %q, want %q", want.DotPath(), got.Description, want.Description)
+		}
+		if got.Deprecated != want.Deprecated {
+			t.Errorf("%s: $deprecated = %v, want %v", want.DotPath(), got.Deprecated, want.Deprecated)
+		}
+	}
+}
+
+func TestFormat_Header(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, filepath.Join("fixtures", "basic"), schema.Draft)
+
+	result, err := newFormatter().Format(tokens, formatter.Options{Header: "Generated tokens"})
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if !strings.HasPrefix(string(result), "# Generated tokens\n\n") {
+		t.Errorf("expected YAML comment header, got:\n%s", result)
+	}
+}
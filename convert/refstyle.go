@@ -0,0 +1,101 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+
+	"bennypowers.dev/asimonim/parser/common"
+)
+
+// RefStyle controls how references are written in DTCG output.
+type RefStyle string
+
+const (
+	// RefStyleDefault follows the output schema: curly brace references
+	// for the Editor's Draft, $ref objects for whole-value references in v2025.10.
+	RefStyleDefault RefStyle = ""
+
+	// RefStyleCurly writes references as {color.primary}.
+	RefStyleCurly RefStyle = "curly"
+
+	// RefStyleSlash writes references as {color/primary}.
+	RefStyleSlash RefStyle = "slash"
+
+	// RefStyleJSONRef writes whole-value references as {"$ref": "#/color/primary"}.
+	// References embedded in longer strings have no $ref form and stay curly.
+	RefStyleJSONRef RefStyle = "json-ref"
+)
+
+// ValidRefStyles returns the names of all reference styles.
+func ValidRefStyles() []string {
+	return []string{string(RefStyleCurly), string(RefStyleSlash), string(RefStyleJSONRef)}
+}
+
+// ParseRefStyle parses a reference style name. An empty string selects
+// RefStyleDefault.
+func ParseRefStyle(s string) (RefStyle, error) {
+	switch style := RefStyle(strings.ToLower(s)); style {
+	case RefStyleDefault, RefStyleCurly, RefStyleSlash, RefStyleJSONRef:
+		return style, nil
+	default:
+		return "", fmt.Errorf("unknown reference style: %s (valid: %s)", s, strings.Join(ValidRefStyles(), ", "))
+	}
+}
+
+// applyRefStyle rewrites every reference in a converted value, including
+// references nested in composite values, to the given style.
+func applyRefStyle(value any, style RefStyle) any {
+	switch v := value.(type) {
+	case string:
+		if matched := curlyBraceRefPattern.FindStringSubmatch(v); matched != nil && matched[0] == v {
+			return formatRef(curlyRefPath(matched[1]), style)
+		}
+		if style == RefStyleJSONRef {
+			style = RefStyleCurly
+		}
+		return curlyBraceRefPattern.ReplaceAllStringFunc(v, func(match string) string {
+			return formatRef(curlyRefPath(match[1:len(match)-1]), style).(string)
+		})
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok && len(v) == 1 {
+			return formatRef(common.ConvertJSONPointerToTokenPath(ref), style)
+		}
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			result[k] = applyRefStyle(item, style)
+		}
+		return result
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = applyRefStyle(item, style)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// curlyRefPath returns the dot path of a curly brace reference body,
+// which may already use slashes.
+func curlyRefPath(ref string) string {
+	return strings.ReplaceAll(ref, "/", ".")
+}
+
+// formatRef writes a reference to the token at dot path in the given style.
+func formatRef(path string, style RefStyle) any {
+	switch style {
+	case RefStyleSlash:
+		return "{" + strings.ReplaceAll(path, ".", "/") + "}"
+	case RefStyleJSONRef:
+		return map[string]any{"$ref": common.ConvertTokenPathToJSONPointer(path)}
+	default:
+		return "{" + path + "}"
+	}
+}
@@ -20,6 +20,7 @@ type FileSystem interface {
 	WriteFile(name string, data []byte, perm fs.FileMode) error
 	ReadFile(name string) ([]byte, error)
 	Remove(name string) error
+	Chmod(name string, mode fs.FileMode) error
 
 	// Directory operations
 	MkdirAll(path string, perm fs.FileMode) error
@@ -37,6 +38,20 @@ type FileSystem interface {
 // OSFileSystem implements FileSystem using the standard os package.
 type OSFileSystem struct{}
 
+// OSBacked reports whether fsys reads local paths from the operating
+// system's file system, either as an OSFileSystem or as a wrapper around
+// one, such as the FileSystem returned by WithURLs. Wrappers report this
+// through an OSBacked() bool method.
+func OSBacked(fsys FileSystem) bool {
+	switch f := fsys.(type) {
+	case *OSFileSystem:
+		return true
+	case interface{ OSBacked() bool }:
+		return f.OSBacked()
+	}
+	return false
+}
+
 // NewOSFileSystem creates a new filesystem that uses the standard os package.
 func NewOSFileSystem() *OSFileSystem {
 	return &OSFileSystem{}
@@ -57,6 +72,11 @@ func (f *OSFileSystem) Remove(name string) error {
 	return os.Remove(name)
 }
 
+// Chmod changes the mode of the named file or directory.
+func (f *OSFileSystem) Chmod(name string, mode fs.FileMode) error {
+	return os.Chmod(name, mode)
+}
+
 // MkdirAll creates a directory path and all parents that do not exist.
 func (f *OSFileSystem) MkdirAll(path string, perm fs.FileMode) error {
 	return os.MkdirAll(path, perm)
@@ -135,6 +135,26 @@ func TestOSFileSystem_Remove(t *testing.T) {
 	}
 }
 
+func TestOSFileSystem_Chmod(t *testing.T) {
+	osfs := fs.NewOSFileSystem()
+	path := filepath.Join(t.TempDir(), "chmod-test.txt")
+	if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+		t.Fatalf("setup WriteFile error: %v", err)
+	}
+
+	if err := osfs.Chmod(path, 0600); err != nil {
+		t.Fatalf("Chmod error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat error: %v", err)
+	}
+	if got := info.Mode().Perm(); got != 0600 {
+		t.Errorf("mode = %o, want 600", got)
+	}
+}
+
 func TestOSFileSystem_TempDir(t *testing.T) {
 	osfs := fs.NewOSFileSystem()
 	td := osfs.TempDir()
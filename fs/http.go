@@ -0,0 +1,310 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package fs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"bennypowers.dev/asimonim/internal/version"
+)
+
+// ErrReadOnly is returned by operations that would modify a read-only
+// filesystem.
+var ErrReadOnly = errors.New("read-only file system")
+
+const (
+	// DefaultHTTPTimeout bounds requests made with the default client.
+	DefaultHTTPTimeout = 30 * time.Second
+
+	// DefaultHTTPMaxSize is the largest response HTTPFileSystem reads (10 MB).
+	DefaultHTTPMaxSize int64 = 10 * 1024 * 1024
+)
+
+// HTTPFileSystem is a read-only FileSystem that reads files over HTTP.
+// Names are resolved against the base URL, so with a base of
+// https://example.com/tokens/, "color.json" reads
+// https://example.com/tokens/color.json. Absolute http and https URLs are
+// read as-is.
+type HTTPFileSystem struct {
+	base   *url.URL
+	client *http.Client
+
+	// MaxSize limits the size of a response body. Defaults to DefaultHTTPMaxSize.
+	MaxSize int64
+}
+
+// NewHTTPFileSystem creates a read-only filesystem rooted at baseURL.
+// baseURL may be empty if every name will be an absolute URL. A nil
+// client uses one with DefaultHTTPTimeout.
+func NewHTTPFileSystem(baseURL string, client *http.Client) (*HTTPFileSystem, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+	return &HTTPFileSystem{base: base, client: client, MaxSize: DefaultHTTPMaxSize}, nil
+}
+
+// IsURL reports whether name is an http or https URL.
+func IsURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
+// url resolves name against the base URL.
+func (f *HTTPFileSystem) url(name string) (string, error) {
+	ref, err := url.Parse(name)
+	if err != nil {
+		return "", err
+	}
+	u := f.base.ResolveReference(ref)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("not an http URL: %s", u)
+	}
+	return u.String(), nil
+}
+
+// do sends a request for name, returning the response for a 200 status.
+// A 404 is reported as fs.ErrNotExist.
+func (f *HTTPFileSystem) do(method, op, name string) (*http.Response, error) {
+	u, err := f.url(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	req.Header.Set("User-Agent", "asimonim/"+version.Get())
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound, http.StatusGone:
+		_ = resp.Body.Close()
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	default:
+		_ = resp.Body.Close()
+		return nil, &fs.PathError{Op: op, Path: name, Err: &statusError{code: resp.StatusCode, status: resp.Status}}
+	}
+}
+
+// statusError is an unexpected HTTP response status.
+type statusError struct {
+	code   int
+	status string
+}
+
+func (e *statusError) Error() string { return e.status }
+
+// fetch GETs name, returning its body and file information.
+func (f *HTTPFileSystem) fetch(op, name string) ([]byte, httpFileInfo, error) {
+	resp, err := f.do(http.MethodGet, op, name)
+	if err != nil {
+		return nil, httpFileInfo{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.MaxSize+1))
+	if err != nil {
+		return nil, httpFileInfo{}, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if int64(len(data)) > f.MaxSize {
+		return nil, httpFileInfo{}, &fs.PathError{Op: op, Path: name, Err: fmt.Errorf("response exceeds maximum size of %d bytes", f.MaxSize)}
+	}
+	info := newHTTPFileInfo(name, resp)
+	info.size = int64(len(data))
+	return data, info, nil
+}
+
+// ReadFile fetches name with a GET request.
+func (f *HTTPFileSystem) ReadFile(name string) ([]byte, error) {
+	data, _, err := f.fetch("read", name)
+	return data, err
+}
+
+// Stat returns file information for name from a HEAD request, falling
+// back to GET for servers that do not allow HEAD.
+func (f *HTTPFileSystem) Stat(name string) (fs.FileInfo, error) {
+	resp, err := f.do(http.MethodHead, "stat", name)
+	var status *statusError
+	if errors.As(err, &status) && status.code == http.StatusMethodNotAllowed {
+		_, info, err := f.fetch("stat", name)
+		if err != nil {
+			return nil, err
+		}
+		return info, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	return newHTTPFileInfo(name, resp), nil
+}
+
+// Exists returns true if name can be fetched.
+func (f *HTTPFileSystem) Exists(name string) bool {
+	_, err := f.Stat(name)
+	return err == nil
+}
+
+// Open fetches name and returns it as a read-only file.
+func (f *HTTPFileSystem) Open(name string) (fs.File, error) {
+	data, info, err := f.fetch("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return &httpFile{Reader: bytes.NewReader(data), info: info}, nil
+}
+
+// ReadDir is not supported, since HTTP has no directory listings.
+func (f *HTTPFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.ErrUnsupported}
+}
+
+// WriteFile returns ErrReadOnly.
+func (f *HTTPFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return &fs.PathError{Op: "write", Path: name, Err: ErrReadOnly}
+}
+
+// Remove returns ErrReadOnly.
+func (f *HTTPFileSystem) Remove(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: ErrReadOnly}
+}
+
+// Chmod returns ErrReadOnly.
+func (f *HTTPFileSystem) Chmod(name string, mode fs.FileMode) error {
+	return &fs.PathError{Op: "chmod", Path: name, Err: ErrReadOnly}
+}
+
+// MkdirAll returns ErrReadOnly.
+func (f *HTTPFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	return &fs.PathError{Op: "mkdir", Path: path, Err: ErrReadOnly}
+}
+
+// TempDir returns the local default directory for temporary files.
+func (f *HTTPFileSystem) TempDir() string {
+	return os.TempDir()
+}
+
+// httpFileInfo describes a fetched file.
+type httpFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func newHTTPFileInfo(name string, resp *http.Response) httpFileInfo {
+	info := httpFileInfo{name: path.Base(resp.Request.URL.Path), size: resp.ContentLength}
+	if info.name == "." || info.name == "/" {
+		info.name = name
+	}
+	if info.size < 0 {
+		info.size = 0
+	}
+	if lm, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.modTime = lm
+	}
+	return info
+}
+
+func (i httpFileInfo) Name() string       { return i.name }
+func (i httpFileInfo) Size() int64        { return i.size }
+func (i httpFileInfo) Mode() fs.FileMode  { return 0444 }
+func (i httpFileInfo) ModTime() time.Time { return i.modTime }
+func (i httpFileInfo) IsDir() bool        { return false }
+func (i httpFileInfo) Sys() any           { return nil }
+
+// httpFile is an fs.File over a fetched response body.
+type httpFile struct {
+	*bytes.Reader
+	info httpFileInfo
+}
+
+func (f *httpFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *httpFile) Close() error               { return nil }
+
+// urlFileSystem reads URLs over HTTP and everything else from a local
+// filesystem.
+type urlFileSystem struct {
+	local  FileSystem
+	remote *HTTPFileSystem
+}
+
+// WithURLs returns a FileSystem that reads http and https URLs through an
+// HTTPFileSystem using client, and all other names through local. This
+// lets a command accept remote token files wherever it accepts paths.
+func WithURLs(local FileSystem, client *http.Client) FileSystem {
+	remote, _ := NewHTTPFileSystem("", client) // an empty base URL always parses
+	return &urlFileSystem{local: local, remote: remote}
+}
+
+// OSBacked reports whether local paths are on the OS file system.
+func (u *urlFileSystem) OSBacked() bool {
+	return OSBacked(u.local)
+}
+
+func (u *urlFileSystem) pick(name string) FileSystem {
+	if IsURL(name) {
+		return u.remote
+	}
+	return u.local
+}
+
+func (u *urlFileSystem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return u.pick(name).WriteFile(name, data, perm)
+}
+
+func (u *urlFileSystem) ReadFile(name string) ([]byte, error) {
+	return u.pick(name).ReadFile(name)
+}
+
+func (u *urlFileSystem) Remove(name string) error {
+	return u.pick(name).Remove(name)
+}
+
+func (u *urlFileSystem) Chmod(name string, mode fs.FileMode) error {
+	return u.pick(name).Chmod(name, mode)
+}
+
+func (u *urlFileSystem) MkdirAll(path string, perm fs.FileMode) error {
+	return u.pick(path).MkdirAll(path, perm)
+}
+
+func (u *urlFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return u.pick(name).ReadDir(name)
+}
+
+func (u *urlFileSystem) TempDir() string {
+	return u.local.TempDir()
+}
+
+func (u *urlFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return u.pick(name).Stat(name)
+}
+
+func (u *urlFileSystem) Exists(path string) bool {
+	return u.pick(path).Exists(path)
+}
+
+func (u *urlFileSystem) Open(name string) (fs.File, error) {
+	return u.pick(name).Open(name)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package fs_test
+
+import (
+	"errors"
+	"io"
+	iofs "io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"bennypowers.dev/asimonim/fs"
+)
+
+const tokensJSON = `{"color": {"red": {"$type": "color", "$value": "#ff0000"}}}`
+
+func newTokenServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/tokens/color.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 00:00:00 GMT")
+		_, _ = io.WriteString(w, tokensJSON)
+	})
+	mux.HandleFunc("/no-head.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		_, _ = io.WriteString(w, tokensJSON)
+	})
+	mux.HandleFunc("/broken.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHTTPFileSystem_ReadFile(t *testing.T) {
+	srv := newTokenServer(t)
+	hfs, err := fs.NewHTTPFileSystem(srv.URL+"/tokens/", srv.Client())
+	if err != nil {
+		t.Fatalf("NewHTTPFileSystem error: %v", err)
+	}
+
+	// Relative to the base URL
+	data, err := hfs.ReadFile("color.json")
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	if string(data) != tokensJSON {
+		t.Errorf("ReadFile = %q, want %q", data, tokensJSON)
+	}
+
+	// Absolute URLs ignore the base
+	if _, err := hfs.ReadFile(srv.URL + "/no-head.json"); err != nil {
+		t.Errorf("ReadFile absolute URL error: %v", err)
+	}
+
+	if _, err := hfs.ReadFile("missing.json"); !errors.Is(err, iofs.ErrNotExist) {
+		t.Errorf("ReadFile missing error = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := hfs.ReadFile("/broken.json"); err == nil {
+		t.Error("expected error for 500 response")
+	}
+
+	hfs.MaxSize = 4
+	if _, err := hfs.ReadFile("color.json"); err == nil {
+		t.Error("expected error for response over MaxSize")
+	}
+}
+
+func TestHTTPFileSystem_Stat(t *testing.T) {
+	srv := newTokenServer(t)
+	hfs, err := fs.NewHTTPFileSystem(srv.URL, srv.Client())
+	if err != nil {
+		t.Fatalf("NewHTTPFileSystem error: %v", err)
+	}
+
+	for _, name := range []string{"/tokens/color.json", "/no-head.json"} {
+		info, err := hfs.Stat(name)
+		if err != nil {
+			t.Fatalf("Stat(%s) error: %v", name, err)
+		}
+		if info.Name() != filepath.Base(name) || info.Size() != int64(len(tokensJSON)) || info.IsDir() {
+			t.Errorf("Stat(%s) = %s %d bytes dir=%v", name, info.Name(), info.Size(), info.IsDir())
+		}
+	}
+	if !hfs.Exists("/tokens/color.json") {
+		t.Error("expected Exists to be true")
+	}
+	if hfs.Exists("/tokens/missing.json") {
+		t.Error("expected Exists to be false for a 404")
+	}
+
+	f, err := hfs.Open("/tokens/color.json")
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	data, err := io.ReadAll(f)
+	if err != nil || string(data) != tokensJSON {
+		t.Errorf("Open contents = %q, %v", data, err)
+	}
+}
+
+func TestHTTPFileSystem_ReadOnly(t *testing.T) {
+	hfs, err := fs.NewHTTPFileSystem("https://example.com/", nil)
+	if err != nil {
+		t.Fatalf("NewHTTPFileSystem error: %v", err)
+	}
+	if err := hfs.WriteFile("tokens.json", nil, 0644); !errors.Is(err, fs.ErrReadOnly) {
+		t.Errorf("WriteFile error = %v, want ErrReadOnly", err)
+	}
+	if err := hfs.Remove("tokens.json"); !errors.Is(err, fs.ErrReadOnly) {
+		t.Errorf("Remove error = %v, want ErrReadOnly", err)
+	}
+	if err := hfs.MkdirAll("dir", 0755); !errors.Is(err, fs.ErrReadOnly) {
+		t.Errorf("MkdirAll error = %v, want ErrReadOnly", err)
+	}
+	if err := hfs.Chmod("tokens.json", 0600); !errors.Is(err, fs.ErrReadOnly) {
+		t.Errorf("Chmod error = %v, want ErrReadOnly", err)
+	}
+	if _, err := hfs.ReadDir("dir"); err == nil {
+		t.Error("expected ReadDir to be unsupported")
+	}
+}
+
+func TestWithURLs(t *testing.T) {
+	srv := newTokenServer(t)
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local.json")
+	ufs := fs.WithURLs(fs.NewOSFileSystem(), srv.Client())
+
+	if err := ufs.WriteFile(local, []byte("{}"), 0644); err != nil {
+		t.Fatalf("WriteFile local error: %v", err)
+	}
+	if data, err := ufs.ReadFile(local); err != nil || string(data) != "{}" {
+		t.Errorf("ReadFile local = %q, %v", data, err)
+	}
+	if data, err := ufs.ReadFile(srv.URL + "/tokens/color.json"); err != nil || string(data) != tokensJSON {
+		t.Errorf("ReadFile URL = %q, %v", data, err)
+	}
+	if err := ufs.WriteFile(srv.URL+"/tokens/color.json", nil, 0644); !errors.Is(err, fs.ErrReadOnly) {
+		t.Errorf("WriteFile URL error = %v, want ErrReadOnly", err)
+	}
+}
+
+func TestOSBacked(t *testing.T) {
+	remote, err := fs.NewHTTPFileSystem("", nil)
+	if err != nil {
+		t.Fatalf("NewHTTPFileSystem error: %v", err)
+	}
+	tests := []struct {
+		name string
+		fsys fs.FileSystem
+		want bool
+	}{
+		{"os", fs.NewOSFileSystem(), true},
+		{"os with URLs", fs.WithURLs(fs.NewOSFileSystem(), nil), true},
+		{"http", remote, false},
+		{"http with URLs", fs.WithURLs(remote, nil), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fs.OSBacked(tt.fsys); got != tt.want {
+				t.Errorf("OSBacked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -13,10 +13,24 @@ import (
 	"os"
 )
 
+// Level controls which messages are logged.
+type Level int
+
+const (
+	// LevelQuiet suppresses all messages. Errors that fail a command are
+	// still reported by the command itself.
+	LevelQuiet Level = iota
+	// LevelNormal logs errors, warnings, and informational messages.
+	LevelNormal
+	// LevelVerbose additionally logs debug messages.
+	LevelVerbose
+)
+
 var (
 	// Default logs to stderr. Set to io.Discard for silent mode (LSP, MCP).
 	output io.Writer = os.Stderr
 	logger *log.Logger
+	level  = LevelNormal
 )
 
 func init() {
@@ -30,17 +44,41 @@ func SetOutput(w io.Writer) {
 	logger = log.New(output, "", 0)
 }
 
+// SetLevel configures which messages are logged.
+func SetLevel(l Level) {
+	level = l
+}
+
+// GetLevel returns the current log level.
+func GetLevel() Level {
+	return level
+}
+
+// Error logs a recoverable error, such as a file that could not be read
+// while others are still processed.
+func Error(format string, args ...any) {
+	if level >= LevelNormal {
+		logger.Printf(format, args...)
+	}
+}
+
 // Warn logs a warning message.
 func Warn(format string, args ...any) {
-	logger.Printf("warning: "+format, args...)
+	if level >= LevelNormal {
+		logger.Printf("warning: "+format, args...)
+	}
 }
 
 // Info logs an informational message.
 func Info(format string, args ...any) {
-	logger.Printf(format, args...)
+	if level >= LevelNormal {
+		logger.Printf(format, args...)
+	}
 }
 
-// Debug logs a debug message. Currently same as Info.
+// Debug logs a debug message, only at LevelVerbose.
 func Debug(format string, args ...any) {
-	logger.Printf(format, args...)
+	if level >= LevelVerbose {
+		logger.Printf(format, args...)
+	}
 }
@@ -0,0 +1,46 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package logger
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	t.Cleanup(func() {
+		SetOutput(os.Stderr)
+		SetLevel(LevelNormal)
+	})
+
+	logAll := func() {
+		Error("error")
+		Warn("warn")
+		Info("info")
+		Debug("debug")
+	}
+
+	tests := []struct {
+		level Level
+		want  string
+	}{
+		{LevelQuiet, ""},
+		{LevelNormal, "error\nwarning: warn\ninfo\n"},
+		{LevelVerbose, "error\nwarning: warn\ninfo\ndebug\n"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		SetLevel(tt.level)
+		logAll()
+		if buf.String() != tt.want {
+			t.Errorf("level %d: got %q, want %q", tt.level, buf.String(), tt.want)
+		}
+	}
+}
@@ -105,6 +105,36 @@ func (mfs *MapFileSystem) Remove(name string) error {
 	return nil
 }
 
+// Chmod implements FileSystem. Directories are represented by their .keep
+// file, which takes the new mode.
+func (mfs *MapFileSystem) Chmod(name string, mode fs.FileMode) error {
+	mfs.mu.Lock()
+	defer mfs.mu.Unlock()
+
+	name = mfs.cleanPath(name)
+
+	if file, exists := mfs.mapFS[name]; exists {
+		file.Mode = file.Mode.Type() | mode.Perm()
+		return nil
+	}
+
+	// Anything else that exists is a directory
+	if _, err := fs.Stat(mfs.mapFS, name); err != nil {
+		return &fs.PathError{Op: "chmod", Path: name, Err: fs.ErrNotExist}
+	}
+	keepFile := name + "/.keep"
+	if file, exists := mfs.mapFS[keepFile]; exists {
+		file.Mode = mode.Perm()
+		return nil
+	}
+	mfs.mapFS[keepFile] = &fstest.MapFile{
+		Data:    []byte(""),
+		Mode:    mode.Perm(),
+		ModTime: mfs.modTime,
+	}
+	return nil
+}
+
 // MkdirAll implements FileSystem.
 func (mfs *MapFileSystem) MkdirAll(p string, perm fs.FileMode) error {
 	mfs.mu.Lock()
@@ -9,12 +9,16 @@ package load
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"bennypowers.dev/asimonim/config"
+	"bennypowers.dev/asimonim/convert"
 	"bennypowers.dev/asimonim/fs"
 	"bennypowers.dev/asimonim/parser"
 	"bennypowers.dev/asimonim/resolver"
@@ -27,7 +31,7 @@ var (
 	// ErrLocalResolution indicates that local filesystem resolution failed.
 	ErrLocalResolution = errors.New("local resolution failed")
 
-	// ErrNetworkFallback indicates that the CDN network fallback also failed.
+	// ErrNetworkFallback indicates that the CDN or mirror network fallback also failed.
 	ErrNetworkFallback = errors.New("network fallback failed")
 )
 
@@ -53,7 +57,7 @@ type Options struct {
 
 	// Fetcher enables opt-in network fallback for package specifiers.
 	// When set, if local resolution fails for an npm: or jsr: specifier,
-	// Load will attempt to fetch the content from a CDN.
+	// Load will attempt to fetch the content from a CDN. See also LocalMirror.
 	// Nil means no network fallback (default).
 	Fetcher Fetcher
 
@@ -62,9 +66,26 @@ type Options struct {
 	// Defaults to "unpkg" when empty. Only "esm.sh" supports jsr: specifiers.
 	CDN specifier.CDN
 
+	// LocalMirror is the base URL of a mirror for local specifiers, such
+	// as a published copy of the token files. When set along with Fetcher,
+	// a local file that isn't found on disk is fetched from the mirror at
+	// its path relative to Root, e.g. "tokens/color.json" from
+	// "https://example.com/tokens/v1/tokens/color.json". Local paths
+	// outside Root are never mirrored. Package specifiers use the CDN,
+	// not the mirror. Empty means no mirror (default).
+	LocalMirror string
+
 	// FetchTimeout is the maximum time to wait for a network fetch.
 	// Defaults to DefaultTimeout when zero. Has no effect if Fetcher is nil.
 	FetchTimeout time.Duration
+
+	// OnToken, when set, is called for each token after alias resolution,
+	// letting embedders filter or index tokens as they are loaded.
+	OnToken func(*token.Token)
+
+	// SkipMap makes Load and LoadAll return a nil map instead of building
+	// one. Use it with OnToken when the callback consumes every token.
+	SkipMap bool
 }
 
 // Load loads design tokens from a specifier with full resolution.
@@ -76,17 +97,154 @@ type Options struct {
 //
 // When Options.Fetcher is set, npm: and jsr: specifiers that fail local
 // resolution will fall back to fetching from a CDN (configurable via Options.CDN).
+// Local files that don't exist fall back to Options.LocalMirror, if set.
+//
+// Load checks ctx between phases and during alias resolution, returning
+// an error wrapping ctx.Err() once ctx is done.
 //
 // The loading process:
 //  1. Optionally loads config from .config/design-tokens.yaml
 //  2. Applies Options values (they take precedence over config)
-//  3. Resolves specifier to file content via filesystem (with optional CDN fallback)
+//  3. Resolves specifier to file content via filesystem (with optional CDN or mirror fallback)
 //  4. Detects schema version (if not specified)
 //  5. Parses tokens
 //  6. Resolves $extends (v2025.10)
 //  7. Resolves aliases
-//  8. Returns *token.Map
+//  8. Calls Options.OnToken for each token, if set
+//  9. Returns *token.Map, or nil when Options.SkipMap is set
 func Load(ctx context.Context, spec string, opts Options) (*token.Map, error) {
+	s, err := resolveSettings(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := resolveContent(ctx, spec, s.root, s.filesystem, opts.Fetcher, s.fetchTimeout, s.cdn, s.localMirror)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve specifier %q: %w", spec, err)
+	}
+
+	tokens, err := parseContent(ctx, content, s.prefix, s.groupMarkers, s.schemaVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine schema version for alias resolution
+	resolveVersion := s.schemaVersion
+	if resolveVersion == schema.Unknown && len(tokens) > 0 {
+		resolveVersion = tokens[0].SchemaVersion
+	}
+	if resolveVersion == schema.Unknown {
+		resolveVersion = schema.Draft
+	}
+
+	// Resolve aliases
+	if err := resolver.ResolveAliasesContext(ctx, tokens, resolveVersion); err != nil {
+		return nil, fmt.Errorf("failed to resolve aliases: %w", err)
+	}
+
+	return emit(tokens, opts, s.prefix), nil
+}
+
+// LoadAll loads design tokens from several specifiers into a single map.
+//
+// Each specifier is resolved and parsed like Load, using the schema version
+// detected from its own content. When Options.SchemaVersion (or the config
+// schema) is set, files written against a different version are converted
+// to it before merging; otherwise all files must share a schema version,
+// and LoadAll returns an error wrapping schema.ErrMixedSchemas if they don't.
+//
+// Aliases are resolved after merging, so tokens may reference tokens in
+// other files. When two files define the same token, the later one wins.
+// Like Load, LoadAll stops early once ctx is done.
+func LoadAll(ctx context.Context, specs []string, opts Options) (*token.Map, error) {
+	s, err := resolveSettings(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var allTokens []*token.Token
+	commonVersion := s.schemaVersion
+	var commonFrom string
+
+	for _, spec := range specs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		content, err := resolveContent(ctx, spec, s.root, s.filesystem, opts.Fetcher, s.fetchTimeout, s.cdn, s.localMirror)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve specifier %q: %w", spec, err)
+		}
+
+		tokens, err := parseContent(ctx, content, s.prefix, s.groupMarkers, schema.Unknown)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", spec, err)
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		version := tokens[0].SchemaVersion
+		switch {
+		case s.schemaVersion != schema.Unknown:
+			if version != s.schemaVersion {
+				tokens, err = convertTokens(ctx, tokens, version, s.schemaVersion, s.prefix, s.groupMarkers)
+				if err != nil {
+					return nil, fmt.Errorf("failed to convert %s from %s to %s: %w", spec, version, s.schemaVersion, err)
+				}
+			}
+		case commonVersion == schema.Unknown:
+			commonVersion = version
+			commonFrom = spec
+		case version != commonVersion:
+			return nil, fmt.Errorf(
+				"%w: %s is %s but %s is %s; set Options.SchemaVersion to convert them to a common version",
+				schema.ErrMixedSchemas, commonFrom, commonVersion, spec, version,
+			)
+		}
+
+		allTokens = append(allTokens, tokens...)
+	}
+
+	if commonVersion == schema.Unknown {
+		commonVersion = schema.Draft
+	}
+	if err := resolver.ResolveAliasesContext(ctx, allTokens, commonVersion); err != nil {
+		return nil, fmt.Errorf("failed to resolve aliases: %w", err)
+	}
+
+	return emit(allTokens, opts, s.prefix), nil
+}
+
+// emit passes each resolved token to opts.OnToken and builds the result
+// map, unless opts.SkipMap is set.
+func emit(tokens []*token.Token, opts Options, prefix string) *token.Map {
+	if opts.OnToken != nil {
+		for _, t := range tokens {
+			opts.OnToken(t)
+		}
+	}
+	if opts.SkipMap {
+		return nil
+	}
+	return token.NewMap(tokens, prefix)
+}
+
+// settings holds the effective load configuration after merging
+// Options with the config file.
+type settings struct {
+	filesystem    fs.FileSystem
+	root          string
+	prefix        string
+	groupMarkers  []string
+	schemaVersion schema.Version
+	cdn           specifier.CDN
+	localMirror   string
+	fetchTimeout  time.Duration
+}
+
+// resolveSettings applies defaults and config file values to opts.
+// Options values take precedence over the config file.
+func resolveSettings(opts Options) (*settings, error) {
 	// Set up filesystem
 	filesystem := opts.FS
 	if filesystem == nil {
@@ -141,17 +299,37 @@ func Load(ctx context.Context, spec string, opts Options) (*token.Map, error) {
 		cdn = parsed
 	}
 
-	// Resolve specifier to content
+	if opts.LocalMirror != "" {
+		u, err := url.Parse(opts.LocalMirror)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid local mirror %q: expected an http or https URL", opts.LocalMirror)
+		}
+	}
+
 	fetchTimeout := opts.FetchTimeout
 	if fetchTimeout == 0 {
 		fetchTimeout = DefaultTimeout
 	}
-	content, err := resolveContent(ctx, spec, root, filesystem, opts.Fetcher, fetchTimeout, cdn)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve specifier %q: %w", spec, err)
-	}
 
-	// Parse tokens
+	return &settings{
+		filesystem:    filesystem,
+		root:          root,
+		prefix:        prefix,
+		groupMarkers:  groupMarkers,
+		schemaVersion: schemaVersion,
+		cdn:           cdn,
+		localMirror:   opts.LocalMirror,
+		fetchTimeout:  fetchTimeout,
+	}, nil
+}
+
+// parseContent parses token file content and resolves $extends,
+// checking ctx before each step. A schemaVersion of schema.Unknown
+// detects the version from content.
+func parseContent(ctx context.Context, content []byte, prefix string, groupMarkers []string, schemaVersion schema.Version) ([]*token.Token, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	p := parser.NewJSONParser()
 	tokens, err := p.Parse(content, parser.Options{
 		Prefix:        prefix,
@@ -161,6 +339,9 @@ func Load(ctx context.Context, spec string, opts Options) (*token.Map, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse tokens: %w", err)
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	// Resolve $extends (for v2025.10)
 	tokens, err = resolver.ResolveGroupExtensions(tokens, content)
@@ -168,27 +349,27 @@ func Load(ctx context.Context, spec string, opts Options) (*token.Map, error) {
 		return nil, fmt.Errorf("failed to resolve $extends: %w", err)
 	}
 
-	// Determine schema version for alias resolution
-	resolveVersion := schemaVersion
-	if resolveVersion == schema.Unknown && len(tokens) > 0 {
-		resolveVersion = tokens[0].SchemaVersion
-	}
-	if resolveVersion == schema.Unknown {
-		resolveVersion = schema.Draft
-	}
+	return tokens, nil
+}
 
-	// Resolve aliases
-	if err := resolver.ResolveAliases(tokens, resolveVersion); err != nil {
-		return nil, fmt.Errorf("failed to resolve aliases: %w", err)
+// convertTokens rewrites tokens from one schema version to another by
+// serializing them as the target version and parsing the result.
+func convertTokens(ctx context.Context, tokens []*token.Token, from, to schema.Version, prefix string, groupMarkers []string) ([]*token.Token, error) {
+	data, err := json.Marshal(convert.Serialize(tokens, convert.Options{
+		InputSchema:  from,
+		OutputSchema: to,
+	}))
+	if err != nil {
+		return nil, err
 	}
-
-	return token.NewMap(tokens, prefix), nil
+	return parseContent(ctx, data, prefix, groupMarkers, to)
 }
 
 // resolveContent resolves a specifier to file content.
 // Tries local resolution first. If that fails and a Fetcher is provided,
-// falls back to CDN for package specifiers.
-func resolveContent(ctx context.Context, spec, root string, filesystem fs.FileSystem, fetcher Fetcher, fetchTimeout time.Duration, cdn specifier.CDN) ([]byte, error) {
+// falls back to CDN for package specifiers, or to localMirror for local
+// files.
+func resolveContent(ctx context.Context, spec, root string, filesystem fs.FileSystem, fetcher Fetcher, fetchTimeout time.Duration, cdn specifier.CDN, localMirror string) ([]byte, error) {
 	// Create resolver chain
 	res, err := specifier.NewDefaultResolver(filesystem, root)
 	if err != nil {
@@ -211,9 +392,12 @@ func resolveContent(ctx context.Context, spec, root string, filesystem fs.FileSy
 	// Read file content
 	content, readErr := filesystem.ReadFile(path)
 	if readErr != nil {
-		// File read failed — try CDN fallback (package specifiers only;
-		// local specifiers return localErr unchanged via CDNURL check)
+		// File read failed — try the mirror for local files, or the CDN
+		// for package specifiers
 		localErr := fmt.Errorf("failed to read %s: %w", path, readErr)
+		if resolved.Kind == specifier.KindLocal {
+			return fetchFromMirror(ctx, path, root, fetcher, fetchTimeout, localMirror, localErr)
+		}
 		return fetchFromCDN(ctx, spec, fetcher, fetchTimeout, cdn, localErr)
 	}
 
@@ -243,3 +427,31 @@ func fetchFromCDN(ctx context.Context, spec string, fetcher Fetcher, fetchTimeou
 
 	return content, nil
 }
+
+// fetchFromMirror attempts to fetch a local file from localMirror as a
+// fallback. Returns the original localErr if no fetcher or mirror is
+// provided, or the file is outside root.
+func fetchFromMirror(ctx context.Context, path, root string, fetcher Fetcher, fetchTimeout time.Duration, localMirror string, localErr error) ([]byte, error) {
+	if fetcher == nil || localMirror == "" {
+		return nil, localErr
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, localErr
+	}
+	mirrorURL, err := url.JoinPath(localMirror, filepath.ToSlash(rel))
+	if err != nil {
+		return nil, localErr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	content, fetchErr := fetcher.Fetch(ctx, mirrorURL)
+	if fetchErr != nil {
+		return nil, fmt.Errorf("%w (%w), %w: %w", ErrLocalResolution, localErr, ErrNetworkFallback, fetchErr)
+	}
+
+	return content, nil
+}
@@ -17,6 +17,7 @@ import (
 
 	"bennypowers.dev/asimonim/load"
 	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/token"
 )
 
 //go:embed testdata/cdn-fallback.json
@@ -107,6 +108,122 @@ func TestLoad_WithSchemaVersion(t *testing.T) {
 	}
 }
 
+func TestLoadAll_CrossFileAliases(t *testing.T) {
+	root := testdataDir()
+	tokenMap, err := load.LoadAll(t.Context(), []string{"simple.json", "multi/theme.json"}, load.Options{
+		Root: root,
+	})
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+
+	if tokenMap.Len() != 3 {
+		t.Errorf("expected 3 tokens, got %d", tokenMap.Len())
+	}
+
+	accent, ok := tokenMap.Get("color-accent")
+	if !ok {
+		t.Fatal("expected to find color-accent")
+	}
+	if !accent.IsResolved || accent.ResolvedValue != "#FF6B35" {
+		t.Errorf("accent.ResolvedValue = %v, want #FF6B35", accent.ResolvedValue)
+	}
+}
+
+func TestLoad_OnToken(t *testing.T) {
+	root := testdataDir()
+	var names []string
+	tokenMap, err := load.Load(t.Context(), "simple.json", load.Options{
+		Root: root,
+		OnToken: func(tok *token.Token) {
+			if !tok.IsResolved {
+				t.Errorf("expected %s to be resolved before OnToken", tok.Name)
+			}
+			names = append(names, tok.Name)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(names) != 2 {
+		t.Errorf("expected OnToken to be called for 2 tokens, got %v", names)
+	}
+	if tokenMap == nil || tokenMap.Len() != 2 {
+		t.Error("expected map to be built when SkipMap is unset")
+	}
+}
+
+func TestLoadAll_OnTokenSkipMap(t *testing.T) {
+	root := testdataDir()
+	var accent *token.Token
+	tokenMap, err := load.LoadAll(t.Context(), []string{"simple.json", "multi/theme.json"}, load.Options{
+		Root:    root,
+		SkipMap: true,
+		OnToken: func(tok *token.Token) {
+			if tok.Name == "color-accent" {
+				accent = tok
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+
+	if tokenMap != nil {
+		t.Errorf("expected nil map with SkipMap, got %d tokens", tokenMap.Len())
+	}
+	if accent == nil {
+		t.Fatal("expected OnToken to receive color-accent")
+	}
+	if accent.ResolvedValue != "#FF6B35" {
+		t.Errorf("accent.ResolvedValue = %v, want #FF6B35", accent.ResolvedValue)
+	}
+}
+
+func TestLoadAll_MixedSchemas(t *testing.T) {
+	root := testdataDir()
+	_, err := load.LoadAll(t.Context(), []string{"simple.json", "multi/stable.json"}, load.Options{
+		Root: root,
+	})
+	if !errors.Is(err, schema.ErrMixedSchemas) {
+		t.Fatalf("expected ErrMixedSchemas, got %v", err)
+	}
+}
+
+func TestLoadAll_ConvertsToSchemaVersion(t *testing.T) {
+	root := testdataDir()
+	tokenMap, err := load.LoadAll(t.Context(), []string{"simple.json", "multi/theme.json", "multi/stable.json"}, load.Options{
+		Root:          root,
+		SchemaVersion: schema.V2025_10,
+	})
+	if err != nil {
+		t.Fatalf("LoadAll() error = %v", err)
+	}
+
+	for _, tok := range tokenMap.All() {
+		if tok.SchemaVersion != schema.V2025_10 {
+			t.Errorf("%s: SchemaVersion = %v, want %v", tok.Name, tok.SchemaVersion, schema.V2025_10)
+		}
+	}
+
+	primary, ok := tokenMap.Get("color-primary")
+	if !ok {
+		t.Fatal("expected to find color-primary")
+	}
+	if !primary.IsStructuredColor() {
+		t.Errorf("expected draft color to be converted to a structured color, got %v", primary.RawValue)
+	}
+
+	accent, ok := tokenMap.Get("color-accent")
+	if !ok {
+		t.Fatal("expected to find color-accent")
+	}
+	if !accent.IsResolved || !accent.IsStructuredColor() {
+		t.Errorf("expected accent to resolve to the converted color, got %v", accent.ResolvedValue)
+	}
+}
+
 func TestLoad_FileNotFound(t *testing.T) {
 	root := testdataDir()
 	_, err := load.Load(t.Context(), "nonexistent.json", load.Options{
@@ -255,3 +372,127 @@ func TestLoad_NetworkFallbackError(t *testing.T) {
 		t.Errorf("expected ErrNetworkFallback in error chain, got: %v", err)
 	}
 }
+
+func TestLoad_LocalMirror(t *testing.T) {
+	fetcher := &mockFetcher{content: cdnFallbackFixture}
+	tokenMap, err := load.Load(t.Context(), "./json/missing.tokens.json", load.Options{
+		Root:        testdataDir(),
+		Fetcher:     fetcher,
+		LocalMirror: "https://example.com/tokens/v1/",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if fetcher.url != "https://example.com/tokens/v1/json/missing.tokens.json" {
+		t.Errorf("fetcher.url = %q, want mirror URL", fetcher.url)
+	}
+	if tokenMap.Len() != 1 {
+		t.Errorf("expected 1 token, got %d", tokenMap.Len())
+	}
+}
+
+func TestLoad_LocalMirror_LocalSuccessSkipsNetwork(t *testing.T) {
+	fetcher := &mockFetcher{}
+	_, err := load.Load(t.Context(), "simple.json", load.Options{
+		Root:        testdataDir(),
+		Fetcher:     fetcher,
+		LocalMirror: "https://example.com/tokens",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if fetcher.called {
+		t.Error("expected fetcher not to be called when the local file exists")
+	}
+}
+
+func TestLoad_LocalMirror_OutsideRoot(t *testing.T) {
+	fetcher := &mockFetcher{content: cdnFallbackFixture}
+	_, err := load.Load(t.Context(), "../missing.json", load.Options{
+		Root:        testdataDir(),
+		Fetcher:     fetcher,
+		LocalMirror: "https://example.com/tokens",
+	})
+	if err == nil {
+		t.Fatal("expected error for a missing file outside root")
+	}
+	if fetcher.called {
+		t.Error("expected fetcher not to be called for a path outside root")
+	}
+}
+
+func TestLoad_LocalMirror_NotUsedForPackages(t *testing.T) {
+	fetcher := &mockFetcher{content: cdnFallbackFixture}
+	_, err := load.Load(t.Context(), "npm:@rhds/tokens/json/rhds.tokens.json", load.Options{
+		Root:        testdataDir(),
+		Fetcher:     fetcher,
+		LocalMirror: "https://example.com/tokens",
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if fetcher.url != "https://unpkg.com/@rhds/tokens/json/rhds.tokens.json" {
+		t.Errorf("fetcher.url = %q, want unpkg URL", fetcher.url)
+	}
+}
+
+func TestLoad_LocalMirror_Error(t *testing.T) {
+	fetcher := &mockFetcher{err: fmt.Errorf("mirror unavailable")}
+	_, err := load.Load(t.Context(), "missing.json", load.Options{
+		Root:        testdataDir(),
+		Fetcher:     fetcher,
+		LocalMirror: "https://example.com/tokens",
+	})
+	if !errors.Is(err, load.ErrLocalResolution) || !errors.Is(err, load.ErrNetworkFallback) {
+		t.Errorf("expected ErrLocalResolution and ErrNetworkFallback in error chain, got: %v", err)
+	}
+}
+
+func TestLoad_LocalMirror_Invalid(t *testing.T) {
+	_, err := load.Load(t.Context(), "simple.json", load.Options{
+		Root:        testdataDir(),
+		LocalMirror: "example.com/tokens",
+	})
+	if err == nil {
+		t.Fatal("expected error for a mirror without a scheme")
+	}
+}
+
+func TestLoad_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := load.Load(ctx, "simple.json", load.Options{Root: testdataDir()})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	_, err = load.LoadAll(ctx, []string{"simple.json"}, load.Options{Root: testdataDir()})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("LoadAll: expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLoad_ExtendsAcrossRoots(t *testing.T) {
+	for _, file := range []string{"extends-array.json", "extends-multi.yaml"} {
+		t.Run(file, func(t *testing.T) {
+			tokenMap, err := load.Load(t.Context(), file, load.Options{Root: testdataDir()})
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			for _, name := range []string{"theme-red", "theme-blue", "theme-green"} {
+				tok, ok := tokenMap.Get(name)
+				if !ok {
+					t.Errorf("expected to find %s", name)
+					continue
+				}
+				if tok.Type != token.TypeColor {
+					t.Errorf("%s.Type = %q, want %q", name, tok.Type, token.TypeColor)
+				}
+			}
+			if red, ok := tokenMap.Get("theme-red"); ok && red.Value != "#FF0000" {
+				t.Errorf("theme-red.Value = %q, want #FF0000", red.Value)
+			}
+		})
+	}
+}
@@ -114,6 +114,7 @@ func (s *Server) parseAndAddTokens(data []byte, filePath, fileURI string, opts *
 	parsedTokens, err := parser.Parse(data, asimonimParser.Options{
 		Prefix:       opts.Prefix,
 		GroupMarkers: opts.GroupMarkers,
+		Format:       asimonimParser.FormatFromPath(filePath),
 	})
 	if err != nil {
 		return 0, err
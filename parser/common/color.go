@@ -119,26 +119,89 @@ func (o *ObjectColorValue) ToCSS() string {
 	}
 }
 
+// ToHex8 returns the color as 8-digit hex (#RRGGBBAA), rounding alpha to
+// the nearest byte. Opaque colors are written as ToCSS writes them, so an
+// opaque sRGB color keeps its 6-digit form. Colors that can't be written
+// as hex, such as those outside sRGB or with "none" components, also fall
+// back to ToCSS.
+func (o *ObjectColorValue) ToHex8() string {
+	if o.Alpha == nil || *o.Alpha >= AlphaThreshold || !o.hasRGBComponents() {
+		return o.ToCSS()
+	}
+	a := clamp(int(*o.Alpha*255+0.5), 0, 255)
+	return fmt.Sprintf("%s%02X", o.toHex(), a)
+}
+
+// ToLegacyCSS returns the color in the comma-separated rgb(), rgba(),
+// hsl() and hsla() syntax that predates CSS Color 4. Opaque sRGB colors,
+// and opaque colors with a hex field, are written as hex, as ToCSS writes
+// them. hwb colors, which have no legacy function, are written as rgb().
+// Other color spaces can describe colors outside sRGB, so they fall back
+// to ToCSS. "none" components count as zero, since the legacy syntax has
+// no keyword for them.
+func (o *ObjectColorValue) ToLegacyCSS() string {
+	hasAlpha := o.Alpha != nil && *o.Alpha < AlphaThreshold
+	if !hasAlpha && o.Hex != nil && *o.Hex != "" {
+		return o.ToCSS()
+	}
+	switch o.ColorSpace {
+	case "srgb", "hwb":
+		if !hasAlpha && o.canConvertToHex() {
+			return o.ToCSS()
+		}
+		r, g, b, ok := o.SRGB()
+		if !ok {
+			break
+		}
+		rgb := fmt.Sprintf("%d, %d, %d", toByte(r), toByte(g), toByte(b))
+		if hasAlpha {
+			return fmt.Sprintf("rgba(%s, %.4g)", rgb, *o.Alpha)
+		}
+		return fmt.Sprintf("rgb(%s)", rgb)
+	case "hsl":
+		if len(o.Components) != 3 {
+			break
+		}
+		var c [3]float64
+		for i, comp := range o.Components {
+			c[i], _ = comp.(float64)
+		}
+		hsl := fmt.Sprintf("%.4g, %.4g%%, %.4g%%", c[0], c[1], c[2])
+		if hasAlpha {
+			return fmt.Sprintf("hsla(%s, %.4g)", hsl, *o.Alpha)
+		}
+		return fmt.Sprintf("hsl(%s)", hsl)
+	}
+	return o.ToCSS()
+}
+
+// toByte scales an sRGB channel from 0-1 to 0-255.
+func toByte(v float64) int {
+	return clamp(int(v*255+0.5), 0, 255)
+}
+
 // canConvertToHex returns true if this sRGB color can be converted to hex.
 // Requires exactly 3 numeric components and alpha >= threshold.
 // Out-of-range component values will be clamped during conversion.
 func (o *ObjectColorValue) canConvertToHex() bool {
-	if len(o.Components) != 3 {
-		return false
-	}
-
 	// Check for alpha that would require rgba format
 	if o.Alpha != nil && *o.Alpha < AlphaThreshold {
 		return false
 	}
+	return o.hasRGBComponents()
+}
 
-	// All components must be numeric (not "none")
+// hasRGBComponents returns true if this is an sRGB color with exactly 3
+// numeric (not "none") components.
+func (o *ObjectColorValue) hasRGBComponents() bool {
+	if o.ColorSpace != "srgb" || len(o.Components) != 3 {
+		return false
+	}
 	for _, comp := range o.Components {
 		if _, ok := comp.(float64); !ok {
 			return false
 		}
 	}
-
 	return true
 }
 
@@ -150,6 +213,36 @@ func (o *ObjectColorValue) toHex() string {
 	return fmt.Sprintf("#%02X%02X%02X", r, g, b)
 }
 
+// ExpandHex returns the full #RRGGBB or #RRGGBBAA form of a CSS hex color,
+// expanding #RGB and #RGBA shorthand by doubling each digit, so #f00
+// becomes #ff0000. Full-length colors are returned as they are. It returns
+// false if s is not a hex color.
+func ExpandHex(s string) (string, bool) {
+	digits, ok := strings.CutPrefix(s, "#")
+	if !ok {
+		return "", false
+	}
+	for _, r := range digits {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return "", false
+		}
+	}
+	switch len(digits) {
+	case 6, 8:
+		return s, true
+	case 3, 4:
+		var sb strings.Builder
+		sb.WriteByte('#')
+		for i := range len(digits) {
+			sb.WriteByte(digits[i])
+			sb.WriteByte(digits[i])
+		}
+		return sb.String(), true
+	default:
+		return "", false
+	}
+}
+
 // clamp restricts a value to the given range.
 func clamp(value, min, max int) int {
 	if value < min {
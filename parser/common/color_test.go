@@ -428,3 +428,167 @@ func TestObjectColorValue_IsValid(t *testing.T) {
 		t.Error("expected IsValid() = false for empty components")
 	}
 }
+
+func TestObjectColorValue_ToHex8(t *testing.T) {
+	alpha := func(a float64) *float64 { return &a }
+	tests := []struct {
+		name     string
+		color    common.ObjectColorValue
+		expected string
+	}{
+		{
+			name:     "opaque srgb keeps 6 digits",
+			color:    common.ObjectColorValue{ColorSpace: "srgb", Components: []any{1.0, 0.0, 0.0}, Alpha: alpha(1)},
+			expected: "#FF0000",
+		},
+		{
+			name:     "no alpha keeps 6 digits",
+			color:    common.ObjectColorValue{ColorSpace: "srgb", Components: []any{0.0, 0.0, 1.0}},
+			expected: "#0000FF",
+		},
+		{
+			name:     "half alpha",
+			color:    common.ObjectColorValue{ColorSpace: "srgb", Components: []any{0.0, 0.0, 0.0}, Alpha: alpha(0.5)},
+			expected: "#00000080",
+		},
+		{
+			name:     "alpha rounds to nearest byte",
+			color:    common.ObjectColorValue{ColorSpace: "srgb", Components: []any{1.0, 1.0, 1.0}, Alpha: alpha(0.25)},
+			expected: "#FFFFFF40",
+		},
+		{
+			name:     "zero alpha",
+			color:    common.ObjectColorValue{ColorSpace: "srgb", Components: []any{1.0, 0.0, 0.0}, Alpha: alpha(0)},
+			expected: "#FF000000",
+		},
+		{
+			name:     "non-srgb falls back to ToCSS",
+			color:    common.ObjectColorValue{ColorSpace: "display-p3", Components: []any{1.0, 0.0, 0.0}, Alpha: alpha(0.5)},
+			expected: "color(display-p3 1 0 0 / 0.5)",
+		},
+		{
+			name:     "none component falls back to ToCSS",
+			color:    common.ObjectColorValue{ColorSpace: "srgb", Components: []any{"none", 0.0, 0.0}, Alpha: alpha(0.5)},
+			expected: "color(srgb none 0 0 / 0.5)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.color.ToHex8(); got != tt.expected {
+				t.Errorf("ToHex8() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandHex(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"#f00", "#ff0000", true},
+		{"#F00", "#FF0000", true},
+		{"#f00a", "#ff0000aa", true},
+		{"#FF6B35", "#FF6B35", true},
+		{"#ff6b3580", "#ff6b3580", true},
+		{"#ff00", "#ffff0000", true},
+		{"#ff000", "", false},
+		{"#ggg", "", false},
+		{"f00", "", false},
+		{"#", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := common.ExpandHex(tt.input)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("ExpandHex(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestObjectColorValue_ToLegacyCSS(t *testing.T) {
+	alpha := func(a float64) *float64 { return &a }
+	hex := func(h string) *string { return &h }
+	tests := []struct {
+		name   string
+		color  common.ObjectColorValue
+		modern string
+		legacy string
+	}{
+		{
+			name:   "opaque srgb is hex in both",
+			color:  common.ObjectColorValue{ColorSpace: "srgb", Components: []any{1.0, 0.0, 0.0}},
+			modern: "#FF0000",
+			legacy: "#FF0000",
+		},
+		{
+			name:   "translucent srgb",
+			color:  common.ObjectColorValue{ColorSpace: "srgb", Components: []any{1.0, 0.5, 0.0}, Alpha: alpha(0.5)},
+			modern: "color(srgb 1 0.5 0 / 0.5)",
+			legacy: "rgba(255, 128, 0, 0.5)",
+		},
+		{
+			name:   "srgb with none component",
+			color:  common.ObjectColorValue{ColorSpace: "srgb", Components: []any{"none", 1.0, 0.0}},
+			modern: "color(srgb none 1 0)",
+			legacy: "rgb(0, 255, 0)",
+		},
+		{
+			name:   "translucent srgb with hex",
+			color:  common.ObjectColorValue{ColorSpace: "srgb", Components: []any{0.0, 0.0, 0.0}, Alpha: alpha(0.25), Hex: hex("#000000")},
+			modern: "#000000",
+			legacy: "rgba(0, 0, 0, 0.25)",
+		},
+		{
+			name:   "hsl",
+			color:  common.ObjectColorValue{ColorSpace: "hsl", Components: []any{210.0, 50.0, 40.0}},
+			modern: "hsl(210 50 40)",
+			legacy: "hsl(210, 50%, 40%)",
+		},
+		{
+			name:   "translucent hsl",
+			color:  common.ObjectColorValue{ColorSpace: "hsl", Components: []any{"none", 0.0, 100.0}, Alpha: alpha(0.8)},
+			modern: "hsl(none 0 100 / 0.8)",
+			legacy: "hsla(0, 0%, 100%, 0.8)",
+		},
+		{
+			name:   "hwb becomes rgb",
+			color:  common.ObjectColorValue{ColorSpace: "hwb", Components: []any{0.0, 0.0, 0.0}},
+			modern: "hwb(0 0 0)",
+			legacy: "rgb(255, 0, 0)",
+		},
+		{
+			name:   "opaque hwb with hex keeps hex",
+			color:  common.ObjectColorValue{ColorSpace: "hwb", Components: []any{0.0, 0.0, 0.0}, Hex: hex("#ff0000")},
+			modern: "#ff0000",
+			legacy: "#ff0000",
+		},
+		{
+			name:   "wide gamut keeps color()",
+			color:  common.ObjectColorValue{ColorSpace: "display-p3", Components: []any{1.0, 0.0, 0.0}, Alpha: alpha(0.5)},
+			modern: "color(display-p3 1 0 0 / 0.5)",
+			legacy: "color(display-p3 1 0 0 / 0.5)",
+		},
+		{
+			name:   "oklch keeps its function",
+			color:  common.ObjectColorValue{ColorSpace: "oklch", Components: []any{0.7, 0.1, 200.0}},
+			modern: "oklch(0.7 0.1 200)",
+			legacy: "oklch(0.7 0.1 200)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.color.ToCSS(); got != tt.modern {
+				t.Errorf("ToCSS() = %q, want %q", got, tt.modern)
+			}
+			if got := tt.color.ToLegacyCSS(); got != tt.legacy {
+				t.Errorf("ToLegacyCSS() = %q, want %q", got, tt.legacy)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package common
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// NumberWithUnitPattern matches a number followed directly by an optional
+// unit of letters or %, like "1.5rem", "50%", or "0". It allows no
+// whitespace anywhere.
+var NumberWithUnitPattern = regexp.MustCompile(`^([+-]?(?:\d+\.?\d*|\.\d+))([a-zA-Z]+|%)?$`)
+
+// ParseNumberWithUnit splits a string like "1.5rem" into its number and
+// unit. The unit is "" for a plain number. It returns false for anything
+// NumberWithUnitPattern doesn't match, such as "1.5 rem" or "calc(1rem)".
+func ParseNumberWithUnit(s string) (float64, string, bool) {
+	m := NumberWithUnitPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, "", false
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return n, m[2], true
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package common
+
+import (
+	"math"
+)
+
+// matrix3 is a 3x3 matrix applied to column vectors.
+type matrix3 [3][3]float64
+
+func (m matrix3) apply(v [3]float64) [3]float64 {
+	var out [3]float64
+	for i, row := range m {
+		out[i] = row[0]*v[0] + row[1]*v[1] + row[2]*v[2]
+	}
+	return out
+}
+
+// Conversion matrices from the CSS Color 4 sample code, all ending in
+// linear-light sRGB.
+var (
+	linearP3ToLinearSRGB = matrix3{
+		{1.2249401762805598, -0.22494017628055996, 0},
+		{-0.04205695470968816, 1.0420569547096881, 0},
+		{-0.019637554590334432, -0.07863604555063188, 1.0982736001409663},
+	}
+	linearRec2020ToLinearSRGB = matrix3{
+		{1.6604910021084345, -0.5876411387885495, -0.07284986331988474},
+		{-0.12455047452159074, 1.1328998971259603, -0.00834942260436949},
+		{-0.018150763354905303, -0.10057889800800739, 1.1187296613629137},
+	}
+	xyzD65ToLinearSRGB = matrix3{
+		{3.2409699419045226, -1.537383177570094, -0.4986107602930034},
+		{-0.9692436362808796, 1.8759675015077202, 0.04155505740717559},
+		{0.05563007969699366, -0.20397695888897652, 1.0569715142428786},
+	}
+	xyzD50ToXYZD65 = matrix3{
+		{0.9554734527042182, -0.023098536874261423, 0.0632593086610217},
+		{-0.028369706963208136, 1.0099954580058226, 0.021041398966943008},
+		{0.012314001688319899, -0.020507696433477912, 1.3303659366080753},
+	}
+	oklabToLMS = matrix3{
+		{1, 0.3963377773761749, 0.2158037573099136},
+		{1, -0.1055613458156586, -0.0638541728258133},
+		{1, -0.0894841775298119, -1.2914855480194092},
+	}
+	lmsToLinearSRGB = matrix3{
+		{4.0767416360759583, -3.3077115392580629, 0.2309699031821043},
+		{-1.2684379732850315, 2.6097573492876882, -0.3413193760026570},
+		{-0.0041960761386756, -0.7034186179359362, 1.7076146940746117},
+	}
+	// d50White is the D50 reference white used by lab and lch.
+	d50White = [3]float64{0.3457 / 0.3585, 1, (1 - 0.3457 - 0.3585) / 0.3585}
+)
+
+// SRGB returns the color as sRGB components from 0 to 1, suitable for
+// display on a terminal or any other sRGB device. Colors outside the sRGB
+// gamut, such as saturated display-p3 colors, are clipped to the nearest
+// displayable value. "none" components count as zero. ok is false when
+// the color is not three components or its color space has no conversion
+// (a98-rgb and prophoto-rgb).
+func (o *ObjectColorValue) SRGB() (r, g, b float64, ok bool) {
+	if len(o.Components) != 3 {
+		return 0, 0, 0, false
+	}
+	var c [3]float64
+	for i, comp := range o.Components {
+		if v, isNum := comp.(float64); isNum {
+			c[i] = v
+		}
+	}
+
+	var linear [3]float64
+	switch o.ColorSpace {
+	case "srgb":
+		return clip(c)
+	case "hsl":
+		return clip(hslToSRGB(c[0], c[1]/100, c[2]/100))
+	case "hwb":
+		return clip(hwbToSRGB(c[0], c[1]/100, c[2]/100))
+	case "srgb-linear":
+		linear = c
+	case "display-p3":
+		linear = linearP3ToLinearSRGB.apply(mapChannels(c, srgbToLinear))
+	case "rec2020":
+		linear = linearRec2020ToLinearSRGB.apply(mapChannels(c, rec2020ToLinear))
+	case "xyz-d65", "xyz":
+		linear = xyzD65ToLinearSRGB.apply(c)
+	case "xyz-d50":
+		linear = xyzD65ToLinearSRGB.apply(xyzD50ToXYZD65.apply(c))
+	case "lab":
+		linear = xyzD65ToLinearSRGB.apply(xyzD50ToXYZD65.apply(labToXYZD50(c)))
+	case "lch":
+		linear = xyzD65ToLinearSRGB.apply(xyzD50ToXYZD65.apply(labToXYZD50(polarToRect(c))))
+	case "oklab":
+		linear = oklabToLinearSRGB(c)
+	case "oklch":
+		linear = oklabToLinearSRGB(polarToRect(c))
+	default:
+		return 0, 0, 0, false
+	}
+	return clip(mapChannels(linear, linearToSRGB))
+}
+
+func mapChannels(c [3]float64, f func(float64) float64) [3]float64 {
+	return [3]float64{f(c[0]), f(c[1]), f(c[2])}
+}
+
+func clip(c [3]float64) (r, g, b float64, ok bool) {
+	c = mapChannels(c, func(v float64) float64 { return math.Min(math.Max(v, 0), 1) })
+	return c[0], c[1], c[2], true
+}
+
+// srgbToLinear undoes the sRGB transfer function, which display-p3 shares.
+func srgbToLinear(v float64) float64 {
+	abs := math.Abs(v)
+	if abs <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Copysign(math.Pow((abs+0.055)/1.055, 2.4), v)
+}
+
+func linearToSRGB(v float64) float64 {
+	abs := math.Abs(v)
+	if abs <= 0.0031308 {
+		return v * 12.92
+	}
+	return math.Copysign(1.055*math.Pow(abs, 1/2.4)-0.055, v)
+}
+
+func rec2020ToLinear(v float64) float64 {
+	const alpha, beta = 1.09929682680944, 0.018053968510807
+	abs := math.Abs(v)
+	if abs < beta*4.5 {
+		return v / 4.5
+	}
+	return math.Copysign(math.Pow((abs+alpha-1)/alpha, 1/0.45), v)
+}
+
+// polarToRect converts lightness, chroma and hue in degrees to
+// lightness and a/b axes.
+func polarToRect(c [3]float64) [3]float64 {
+	h := c[2] * math.Pi / 180
+	return [3]float64{c[0], c[1] * math.Cos(h), c[1] * math.Sin(h)}
+}
+
+func labToXYZD50(c [3]float64) [3]float64 {
+	const kappa, epsilon = 24389.0 / 27, 216.0 / 24389
+	fy := (c[0] + 16) / 116
+	fx := c[1]/500 + fy
+	fz := fy - c[2]/200
+	f := func(t float64) float64 {
+		if t3 := t * t * t; t3 > epsilon {
+			return t3
+		}
+		return (116*t - 16) / kappa
+	}
+	y := c[0] / kappa
+	if c[0] > kappa*epsilon {
+		y = fy * fy * fy
+	}
+	return [3]float64{f(fx) * d50White[0], y * d50White[1], f(fz) * d50White[2]}
+}
+
+func oklabToLinearSRGB(c [3]float64) [3]float64 {
+	lms := mapChannels(oklabToLMS.apply(c), func(v float64) float64 { return v * v * v })
+	return lmsToLinearSRGB.apply(lms)
+}
+
+// hslToSRGB converts hue in degrees and saturation and lightness from
+// 0 to 1 to sRGB.
+func hslToSRGB(h, s, l float64) [3]float64 {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	f := func(n float64) float64 {
+		k := math.Mod(n+h/30, 12)
+		a := s * math.Min(l, 1-l)
+		return l - a*math.Max(-1, math.Min(math.Min(k-3, 9-k), 1))
+	}
+	return [3]float64{f(0), f(8), f(4)}
+}
+
+// hwbToSRGB converts hue in degrees and whiteness and blackness from
+// 0 to 1 to sRGB.
+func hwbToSRGB(h, w, bl float64) [3]float64 {
+	if w+bl >= 1 {
+		gray := w / (w + bl)
+		return [3]float64{gray, gray, gray}
+	}
+	rgb := hslToSRGB(h, 1, 0.5)
+	return mapChannels(rgb, func(v float64) float64 { return v*(1-w-bl) + w })
+}
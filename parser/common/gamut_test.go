@@ -0,0 +1,64 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package common_test
+
+import (
+	"math"
+	"testing"
+
+	"bennypowers.dev/asimonim/parser/common"
+)
+
+func TestObjectColorValue_SRGB(t *testing.T) {
+	tests := []struct {
+		name       string
+		colorSpace string
+		components []any
+		want       [3]float64
+	}{
+		{"srgb", "srgb", []any{1.0, 0.5, 0.0}, [3]float64{1, 0.5, 0}},
+		{"srgb clipped", "srgb", []any{1.2, -0.1, 0.5}, [3]float64{1, 0, 0.5}},
+		{"srgb-linear", "srgb-linear", []any{0.21404114, 0.0, 1.0}, [3]float64{0.5, 0, 1}},
+		{"display-p3 in sRGB gamut", "display-p3", []any{0.91749, 0.20036, 0.13856}, [3]float64{1, 0, 0}},
+		{"display-p3 out of gamut", "display-p3", []any{1.0, 0.0, 0.0}, [3]float64{1, 0, 0}},
+		{"hsl", "hsl", []any{120.0, 100.0, 25.0}, [3]float64{0, 0.5, 0}},
+		{"hwb", "hwb", []any{240.0, 20.0, 20.0}, [3]float64{0.2, 0.2, 0.8}},
+		{"hwb gray", "hwb", []any{0.0, 60.0, 60.0}, [3]float64{0.5, 0.5, 0.5}},
+		{"oklab white", "oklab", []any{1.0, 0.0, 0.0}, [3]float64{1, 1, 1}},
+		{"oklch red", "oklch", []any{0.62796, 0.25768, 29.234}, [3]float64{1, 0, 0}},
+		{"lab white", "lab", []any{100.0, 0.0, 0.0}, [3]float64{1, 1, 1}},
+		{"lch none hue", "lch", []any{50.0, 0.0, "none"}, [3]float64{0.4663, 0.4663, 0.4663}},
+		{"xyz-d65 white", "xyz-d65", []any{0.95046, 1.0, 1.08906}, [3]float64{1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &common.ObjectColorValue{ColorSpace: tt.colorSpace, Components: tt.components}
+			r, g, b, ok := c.SRGB()
+			if !ok {
+				t.Fatal("SRGB() ok = false")
+			}
+			for i, got := range []float64{r, g, b} {
+				if math.Abs(got-tt.want[i]) > 0.002 {
+					t.Errorf("SRGB() = %.4f, %.4f, %.4f, want %v", r, g, b, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestObjectColorValue_SRGB_Unsupported(t *testing.T) {
+	for _, c := range []*common.ObjectColorValue{
+		{ColorSpace: "a98-rgb", Components: []any{1.0, 0.0, 0.0}},
+		{ColorSpace: "srgb", Components: []any{1.0, 0.0}},
+	} {
+		if _, _, _, ok := c.SRGB(); ok {
+			t.Errorf("SRGB() ok = true for %s %v", c.ColorSpace, c.Components)
+		}
+	}
+}
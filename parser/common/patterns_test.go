@@ -214,3 +214,33 @@ func TestExtendsFieldPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestParseNumberWithUnit(t *testing.T) {
+	tests := []struct {
+		input  string
+		num    float64
+		unit   string
+		wantOK bool
+	}{
+		{"16px", 16, "px", true},
+		{"1.5rem", 1.5, "rem", true},
+		{"-.5em", -0.5, "em", true},
+		{"50%", 50, "%", true},
+		{"0", 0, "", true},
+		{"200ms", 200, "ms", true},
+		{"1.5 rem", 0, "", false},
+		{" 16px", 0, "", false},
+		{"16px ", 0, "", false},
+		{"5px%", 0, "", false},
+		{"calc(1rem + 2px)", 0, "", false},
+		{"", 0, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			num, unit, ok := common.ParseNumberWithUnit(tt.input)
+			if ok != tt.wantOK || num != tt.num || unit != tt.unit {
+				t.Errorf("ParseNumberWithUnit(%q) = %v, %q, %v; want %v, %q, %v", tt.input, num, unit, ok, tt.num, tt.unit, tt.wantOK)
+			}
+		})
+	}
+}
@@ -77,13 +77,22 @@ func ExtractReferencesFromValue(value any, version schema.Version) ([]Reference,
 }
 
 // ConvertJSONPointerToTokenPath converts a JSON Pointer path to a token path.
+// Escaped segments are decoded per RFC 6901, and a pointer to a token's
+// $value refers to the token itself.
 // Examples:
 //
 //	"#/color/brand/primary" -> "color.brand.primary"
 //	"color/brand/primary" -> "color.brand.primary"
+//	"#/color/on~1off" -> "color.on/off"
+//	"#/color/primary/$value" -> "color.primary"
 func ConvertJSONPointerToTokenPath(jsonPointer string) string {
 	jsonPointer = strings.TrimPrefix(jsonPointer, "#/")
-	return strings.ReplaceAll(jsonPointer, "/", ".")
+	jsonPointer = strings.TrimSuffix(jsonPointer, "/$value")
+	segments := strings.Split(jsonPointer, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+	return strings.Join(segments, ".")
 }
 
 // ConvertTokenPathToJSONPointer converts a token path to a JSON Pointer.
@@ -144,6 +144,10 @@ func TestConvertJSONPointerToTokenPath(t *testing.T) {
 		{"color/brand/primary", "color.brand.primary"},
 		{"#/single", "single"},
 		{"single", "single"},
+		{"#/color/on~1off", "color.on/off"},
+		{"#/color/tilde~0key", "color.tilde~key"},
+		{"#/color/primary/$value", "color.primary"},
+		{"#/color/primary/$value/components/0", "color.primary.$value.components.0"},
 	}
 
 	for _, tt := range tests {
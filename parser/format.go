@@ -0,0 +1,57 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package parser
+
+import (
+	"path"
+	"strings"
+)
+
+// Format is the syntax of token file data.
+type Format int
+
+const (
+	// FormatAuto detects JSON or YAML from the content: data starting
+	// with '{' is read as JSON, anything else as YAML.
+	FormatAuto Format = iota
+
+	// FormatJSON is JSON, tolerating comments and trailing commas.
+	FormatJSON
+
+	// FormatYAML is YAML, including flow-style YAML that starts with '{'.
+	FormatYAML
+)
+
+// FormatFromPath returns the Format for a file's extension: .json,
+// .jsonc, and .json5 are FormatJSON, .yaml and .yml are FormatYAML, and
+// any other extension (or none, as for stdin) is FormatAuto.
+// URLs are matched by the extension of their path.
+func FormatFromPath(name string) Format {
+	if i := strings.IndexAny(name, "?#"); i >= 0 && strings.Contains(name, "://") {
+		name = name[:i]
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json", ".jsonc", ".json5":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatAuto
+	}
+}
+
+// IsJSON reports whether data should be parsed as JSON under format.
+func (f Format) IsJSON(data []byte) bool {
+	switch f {
+	case FormatJSON:
+		return true
+	case FormatYAML:
+		return false
+	default:
+		return isLikelyJSON(data)
+	}
+}
@@ -7,8 +7,10 @@ license that can be found in the LICENSE file.
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"maps"
 	"math"
 	"slices"
@@ -25,6 +27,7 @@ import (
 )
 
 // JSONParser parses DTCG-compliant JSON token files.
+// It is safe for concurrent use.
 type JSONParser struct{}
 
 // NewJSONParser creates a new JSON token parser.
@@ -33,51 +36,86 @@ func NewJSONParser() *JSONParser {
 }
 
 // Parse parses JSON or YAML token data and returns tokens.
+//
+// JSON parsed with SkipPositions is streamed rather than decoded into a
+// map, which keeps memory use down for large generated token files.
 func (p *JSONParser) Parse(data []byte, opts Options) ([]*token.Token, error) {
-	var raw map[string]any
+	tokens, err := p.parse(data, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.StrictTypes {
+		checkTypes(tokens, opts.OnWarning)
+	}
+	return tokens, nil
+}
+
+func (p *JSONParser) parse(data []byte, opts Options) ([]*token.Token, error) {
+	isJSON := opts.Format.IsJSON(data)
+	if opts.SkipPositions && isJSON {
+		opts.SchemaVersion = detectSchemaVersion(data, opts.SchemaVersion)
+		tokens, err := p.parseJSONStream(jsonc.ToJSON(data), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+		return tokens, nil
+	}
+
+	var decoded []any
 	var positionData []byte
 
-	// Detect format: JSON typically starts with '{' or whitespace then '{'
-	// YAML uses indentation-based structure
-	if isLikelyJSON(data) {
+	if isJSON {
 		// JSON path: strip comments and parse
 		cleanJSON := jsonc.ToJSON(data)
+		var raw any
 		if err := json.Unmarshal(cleanJSON, &raw); err != nil {
 			return nil, fmt.Errorf("failed to parse JSON: %w", err)
 		}
+		decoded = []any{raw}
 		positionData = cleanJSON
 	} else {
-		// YAML path: parse directly with yaml.v3
-		var yamlRaw any
-		if err := yaml.Unmarshal(data, &yamlRaw); err != nil {
-			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		// YAML path: parse each document of the stream with yaml.v3
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		for {
+			var doc any
+			if err := dec.Decode(&doc); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to parse YAML: %w", err)
+			}
+			// Empty documents, e.g. after a trailing "---", hold no tokens
+			if doc == nil {
+				continue
+			}
+			// Normalize map types (YAML numeric keys create map[any]any)
+			decoded = append(decoded, normalizeMap(doc))
 		}
-		// Normalize map types (YAML numeric keys create map[any]any)
-		normalized := normalizeMap(yamlRaw)
-		var ok bool
-		raw, ok = normalized.(map[string]any)
-		if !ok {
+		if len(decoded) == 0 {
 			return nil, fmt.Errorf("YAML root must be an object")
 		}
 		positionData = data
 	}
 
-	// Auto-detect schema version if not explicitly set
-	if opts.SchemaVersion == schema.Unknown {
-		if detected, err := schema.DetectVersion(data, nil); err == nil {
-			opts.SchemaVersion = detected
-		} else {
-			opts.SchemaVersion = schema.Draft
-		}
-	}
+	opts.SchemaVersion = detectSchemaVersion(data, opts.SchemaVersion)
 
-	// Extract tokens using the single extraction path
+	// Extract tokens using the single extraction path. Tokens from
+	// several roots are returned in document order, like separate files.
 	result := []*token.Token{}
-	p.extractTokens(raw, []string{}, "", "", opts, &result)
+	for _, doc := range decoded {
+		roots, err := rootObjects(doc)
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range roots {
+			if err := p.extractTokens(raw, []string{}, "", "", 1, opts, &result); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	// Optional second pass: add position tracking
 	if !opts.SkipPositions {
-		if err := p.addPositions(positionData, result); err != nil {
+		if err := p.addPositions(positionData, result, opts.maxDepth()); err != nil {
 			return nil, err
 		}
 	}
@@ -85,6 +123,40 @@ func (p *JSONParser) Parse(data []byte, opts Options) ([]*token.Token, error) {
 	return result, nil
 }
 
+// rootObjects returns the token documents in a decoded root: the root
+// object itself, or each object of a top-level array, as some tools
+// export tokens as a list of groups.
+func rootObjects(root any) ([]map[string]any, error) {
+	switch v := root.(type) {
+	case map[string]any:
+		return []map[string]any{v}, nil
+	case []any:
+		roots := make([]map[string]any, 0, len(v))
+		for i, elem := range v {
+			m, ok := elem.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("root array element %d must be an object", i)
+			}
+			roots = append(roots, m)
+		}
+		return roots, nil
+	default:
+		return nil, fmt.Errorf("root must be an object or an array of objects")
+	}
+}
+
+// detectSchemaVersion returns version, or the version detected from data
+// when version is Unknown. Undetectable data is treated as Draft.
+func detectSchemaVersion(data []byte, version schema.Version) schema.Version {
+	if version != schema.Unknown {
+		return version
+	}
+	if detected, err := schema.DetectVersion(data, nil); err == nil {
+		return detected
+	}
+	return schema.Draft
+}
+
 // isLikelyJSON checks if data appears to be JSON rather than YAML.
 // JSON typically starts with '{' (optionally preceded by whitespace/BOM).
 func isLikelyJSON(data []byte) bool {
@@ -131,7 +203,12 @@ func normalizeMap(v any) any {
 
 // extractTokens recursively extracts tokens from a parsed map.
 // inheritedType is passed down from parent groups for $type inheritance.
-func (p *JSONParser) extractTokens(data map[string]any, jsonPath []string, path, inheritedType string, opts Options, result *[]*token.Token) {
+// depth is the nesting depth of data, which may not exceed opts.MaxDepth.
+func (p *JSONParser) extractTokens(data map[string]any, jsonPath []string, path, inheritedType string, depth int, opts Options, result *[]*token.Token) error {
+	if depth > opts.maxDepth() {
+		return depthError(jsonPath, opts.maxDepth())
+	}
+
 	// Check if this group has a $type that should be inherited by children
 	currentType := inheritedType
 	if groupType, ok := data["$type"].(string); ok {
@@ -197,10 +274,18 @@ func (p *JSONParser) extractTokens(data map[string]any, jsonPath []string, path,
 			}
 			childMap := p.filterChildMap(valueMap)
 			if len(childMap) > 0 {
-				p.extractTokens(childMap, currentPath, newPath, childType, opts, result)
+				if err := p.extractTokens(childMap, currentPath, newPath, childType, depth+1, opts, result); err != nil {
+					return err
+				}
 			}
 		}
 	}
+	return nil
+}
+
+// depthError reports that the object at jsonPath nests deeper than max.
+func depthError(jsonPath []string, max int) error {
+	return fmt.Errorf("%w: more than %d levels at %s", ErrMaxDepth, max, strings.Join(jsonPath, "."))
 }
 
 // isTransparent checks if a key is a transparent group marker.
@@ -243,7 +328,11 @@ func (p *JSONParser) createToken(key, path string, valueMap map[string]any, json
 	value := ""
 	var rawValue any
 
-	if dollarValue != nil {
+	if ref, ok := valueRef(dollarValue); ok && opts.SchemaVersion != schema.Draft {
+		// "$value": {"$ref": "#/..."} aliases the whole value, same as a token-level $ref
+		value = ref
+		rawValue = value
+	} else if dollarValue != nil {
 		if strVal, ok := dollarValue.(string); ok {
 			value = strVal
 			rawValue = value
@@ -292,6 +381,12 @@ func (p *JSONParser) createToken(key, path string, valueMap map[string]any, json
 		} else if depStr, ok := deprecated.(string); ok {
 			t.Deprecated = true
 			t.DeprecationMessage = depStr
+		} else if depObj, ok := deprecated.(map[string]any); ok {
+			t.Deprecated = true
+			t.DeprecationMessage, _ = depObj["message"].(string)
+			if replacement, ok := depObj["replacement"].(string); ok {
+				t.Replacement = strings.TrimSuffix(strings.TrimPrefix(replacement, "{"), "}")
+			}
 		}
 	}
 	if extensions, ok := valueMap["$extensions"].(map[string]any); ok {
@@ -301,6 +396,16 @@ func (p *JSONParser) createToken(key, path string, valueMap map[string]any, json
 	return t
 }
 
+// valueRef returns the JSON pointer of a $value that consists only of a $ref.
+func valueRef(dollarValue any) (string, bool) {
+	m, ok := dollarValue.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	ref, ok := m["$ref"].(string)
+	return ref, ok
+}
+
 // buildPaths builds the JSON path and string path.
 // Returns the new jsonPath slice and string path.
 // The returned slice shares capacity with the input for recursion efficiency,
@@ -328,7 +433,7 @@ func buildPaths(jsonPath []string, path, key string, transparent, nameTransparen
 
 // addPositions adds line/character positions to tokens by parsing with yaml.v3.
 // This is a second pass that only runs when position tracking is enabled.
-func (p *JSONParser) addPositions(data []byte, tokens []*token.Token) error {
+func (p *JSONParser) addPositions(data []byte, tokens []*token.Token, maxDepth int) error {
 	// Build a map from token path (as dot-separated string) to token pointer
 	tokenByPath := make(map[string]*token.Token, len(tokens))
 	for _, t := range tokens {
@@ -336,24 +441,40 @@ func (p *JSONParser) addPositions(data []byte, tokens []*token.Token) error {
 		tokenByPath[pathKey] = t
 	}
 
-	// Parse with yaml.v3 to get AST with position data
-	var root yaml.Node
-	if err := yaml.Unmarshal(data, &root); err != nil {
-		return fmt.Errorf("failed to parse JSON for positions: %w", err)
-	}
-
-	// Walk the AST and update token positions
-	if len(root.Content) > 0 {
-		p.walkForPositions(root.Content[0], []string{}, tokenByPath)
+	// Parse with yaml.v3 to get AST with position data, walking the
+	// roots of every document in the stream
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to parse JSON for positions: %w", err)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		roots := []*yaml.Node{doc.Content[0]}
+		if doc.Content[0].Kind == yaml.SequenceNode {
+			roots = doc.Content[0].Content
+		}
+		for _, root := range roots {
+			if err := p.walkForPositions(root, []string{}, 1, maxDepth, tokenByPath); err != nil {
+				return err
+			}
+		}
 	}
-
-	return nil
 }
 
 // walkForPositions walks the yaml AST to find token positions.
-func (p *JSONParser) walkForPositions(node *yaml.Node, jsonPath []string, tokenByPath map[string]*token.Token) {
+// Token values are walked too, so depth is checked here as well as in
+// extractTokens.
+func (p *JSONParser) walkForPositions(node *yaml.Node, jsonPath []string, depth, maxDepth int, tokenByPath map[string]*token.Token) error {
 	if node.Kind != yaml.MappingNode {
-		return
+		return nil
+	}
+	if depth > maxDepth {
+		return depthError(jsonPath, maxDepth)
 	}
 
 	for i := 0; i < len(node.Content); i += 2 {
@@ -398,18 +519,24 @@ func (p *JSONParser) walkForPositions(node *yaml.Node, jsonPath []string, tokenB
 		}
 
 		// Recurse into children
-		p.walkForPositions(valueNode, currentPath, tokenByPath)
+		if err := p.walkForPositions(valueNode, currentPath, depth+1, maxDepth, tokenByPath); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
 // ParseFile parses a JSON token file and returns tokens.
 func (p *JSONParser) ParseFile(filesystem fs.FileSystem, path string, opts Options) ([]*token.Token, error) {
+	if opts.Format == FormatAuto {
+		opts.Format = FormatFromPath(path)
+	}
 	data, err := filesystem.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
 	}
 
-	tokens, err := p.Parse(data, opts)
+	tokens, err := p.parse(data, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse file %s: %w", path, err)
 	}
@@ -419,6 +546,10 @@ func (p *JSONParser) ParseFile(filesystem fs.FileSystem, path string, opts Optio
 		t.FilePath = path
 	}
 
+	if opts.StrictTypes {
+		checkTypes(tokens, opts.OnWarning)
+	}
+
 	return tokens, nil
 }
 
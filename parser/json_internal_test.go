@@ -7,6 +7,11 @@ license that can be found in the LICENSE file.
 package parser
 
 import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -207,3 +212,81 @@ func TestNormalizeMap(t *testing.T) {
 		}
 	})
 }
+
+func TestParseJSONStream_MatchesMapPath(t *testing.T) {
+	// Every JSON fixture in the repo must produce the same tokens through
+	// the streaming path as through the map path.
+	var files []string
+	err := filepath.WalkDir("../testdata", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(path, ".json") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk fixtures: %v", err)
+	}
+
+	// Shapes the fixtures don't cover: late $type, duplicate keys,
+	// unsorted members, markers with values, and comments
+	inline := map[string]string{
+		"late type":      `{"color": {"red": {"$value": "#f00"}, "blue": {"$value": "#00f"}, "$type": "color"}}`,
+		"duplicate keys": `{"color": {"red": {"$value": "#f00"}, "red": {"$value": "#e00"}}}`,
+		"unsorted":       `{"z": {"$value": 1}, "a": {"b": {"$value": 2}, "$type": "number"}, "m": [1, {"$value": 3}]}`,
+		"marker value":   `{"color": {"_": {"$value": "#f00", "dark": {"$value": "#000"}}, "$type": "color"}}`,
+		"token children": `{"color": {"$value": "#f00", "nested": {"$value": "#000"}}}`,
+		"comments":       "{\n  // brand\n  \"color\": {\"$value\": \"#f00\"}\n}",
+	}
+	for name, content := range inline {
+		path := filepath.Join(t.TempDir(), name+".json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, path)
+	}
+
+	p := NewJSONParser()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		if !isLikelyJSON(data) {
+			continue
+		}
+		for _, markers := range [][]string{nil, {"_", "@", "DEFAULT"}} {
+			opts := Options{GroupMarkers: markers}
+			want, wantErr := p.Parse(data, opts)
+
+			opts.SkipPositions = true
+			got, gotErr := p.Parse(data, opts)
+
+			if (wantErr != nil) != (gotErr != nil) {
+				t.Errorf("%s: map path error %v, stream path error %v", file, wantErr, gotErr)
+				continue
+			}
+			for _, tok := range want {
+				tok.Line, tok.Character = 0, 0
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s (markers %v): stream path tokens differ from map path", file, markers)
+			}
+		}
+	}
+}
+
+func TestParseJSONStream_InvalidJSON(t *testing.T) {
+	p := NewJSONParser()
+	for _, input := range []string{
+		`{"color": {"$value": }`,
+		`{"color": {}} {}`,
+		`{"color": `,
+	} {
+		if _, err := p.Parse([]byte(input), Options{SkipPositions: true}); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
@@ -7,11 +7,14 @@ license that can be found in the LICENSE file.
 package parser_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"bennypowers.dev/asimonim/parser"
 	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/testutil"
+	"bennypowers.dev/asimonim/token"
 )
 
 func TestJSONParser_Parse(t *testing.T) {
@@ -102,6 +105,71 @@ func TestJSONParser_V2025_10_CurlyRefs(t *testing.T) {
 	}
 }
 
+func TestJSONParser_V2025_10_ValueRef(t *testing.T) {
+	content := []byte(`{
+  "$schema": "https://www.designtokens.org/schemas/2025.10.json",
+  "color": {
+    "$type": "color",
+    "primary": { "$value": { "colorSpace": "srgb", "components": [1, 0, 0] } },
+    "accent": { "$value": { "$ref": "#/color/primary" } }
+  }
+}`)
+
+	tokens, err := parser.NewJSONParser().Parse(content, parser.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tok := range tokens {
+		if tok.Name != "color-accent" {
+			continue
+		}
+		if tok.Value != "#/color/primary" {
+			t.Errorf("expected $value $ref to be read as an alias, got Value %q", tok.Value)
+		}
+		if !tok.IsAlias() {
+			t.Error("expected color-accent to be an alias")
+		}
+		return
+	}
+	t.Error("expected token color-accent not found")
+}
+
+func TestJSONParser_DeprecatedObject(t *testing.T) {
+	content := []byte(`{
+  "color": {
+    "$type": "color",
+    "new": { "$value": "#ff0000" },
+    "old": {
+      "$value": "#aa0000",
+      "$deprecated": { "message": "Renamed", "replacement": "{color.new}" }
+    }
+  }
+}`)
+
+	tokens, err := parser.NewJSONParser().Parse(content, parser.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tok := range tokens {
+		if tok.Name != "color-old" {
+			continue
+		}
+		if !tok.Deprecated {
+			t.Error("expected color-old to be deprecated")
+		}
+		if tok.DeprecationMessage != "Renamed" {
+			t.Errorf("DeprecationMessage = %q, want %q", tok.DeprecationMessage, "Renamed")
+		}
+		if tok.Replacement != "color.new" {
+			t.Errorf("Replacement = %q, want %q", tok.Replacement, "color.new")
+		}
+		return
+	}
+	t.Error("expected token color-old not found")
+}
+
 func TestJSONParser_ParseYAML(t *testing.T) {
 	mfs := testutil.NewFixtureFS(t, "fixtures/draft/simple-yaml", "/test")
 
@@ -139,6 +207,67 @@ func TestJSONParser_ParseYAML(t *testing.T) {
 	}
 }
 
+func TestJSONParser_ParseFlowYAML(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/draft/flow-yaml", "/test")
+	p := parser.NewJSONParser()
+
+	t.Run("yaml extension selects YAML", func(t *testing.T) {
+		tokens, err := p.ParseFile(mfs, "/test/tokens.yaml", parser.Options{
+			SchemaVersion: schema.Draft,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tokens) != 3 {
+			t.Errorf("expected 3 tokens, got %d", len(tokens))
+		}
+	})
+
+	t.Run("explicit format overrides sniffing", func(t *testing.T) {
+		tokens, err := p.ParseFile(mfs, "/test/tokens.txt", parser.Options{
+			SchemaVersion: schema.Draft,
+			Format:        parser.FormatYAML,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(tokens) != 3 {
+			t.Errorf("expected 3 tokens, got %d", len(tokens))
+		}
+	})
+
+	t.Run("unknown extension sniffs content", func(t *testing.T) {
+		if _, err := p.ParseFile(mfs, "/test/tokens.txt", parser.Options{
+			SchemaVersion: schema.Draft,
+		}); err == nil {
+			t.Error("expected flow YAML with unquoted keys to fail as JSON")
+		}
+	})
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want parser.Format
+	}{
+		{"tokens.json", parser.FormatJSON},
+		{"tokens.jsonc", parser.FormatJSON},
+		{"tokens.json5", parser.FormatJSON},
+		{"TOKENS.JSON", parser.FormatJSON},
+		{"tokens.yaml", parser.FormatYAML},
+		{"dir.json/tokens.yml", parser.FormatYAML},
+		{"https://example.com/tokens.yaml?v=2", parser.FormatYAML},
+		{"tokens.toml", parser.FormatAuto},
+		{"-", parser.FormatAuto},
+		{"", parser.FormatAuto},
+	}
+	for _, tt := range tests {
+		if got := parser.FormatFromPath(tt.path); got != tt.want {
+			t.Errorf("FormatFromPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
 func TestJSONParser_AutoDetectSchema(t *testing.T) {
 	t.Run("detects v2025.10 from $schema field", func(t *testing.T) {
 		mfs := testutil.NewFixtureFS(t, "fixtures/v2025_10/structured-colors", "/test")
@@ -305,3 +434,150 @@ func TestJSONParser_SkipPositions(t *testing.T) {
 		}
 	}
 }
+
+func TestJSONParser_NullValue(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/draft/placeholders", "/test")
+
+	p := parser.NewJSONParser()
+	tokens, err := p.ParseFile(mfs, "/test/tokens.json", parser.Options{
+		SchemaVersion: schema.Draft,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tokens) != 3 {
+		t.Fatalf("expected 3 tokens, got %d", len(tokens))
+	}
+
+	brand := testutil.TokenByPath(t, tokens, "color.brand")
+	if brand.Value != "" || brand.RawValue != nil {
+		t.Errorf("expected no value, got Value %q, RawValue %v", brand.Value, brand.RawValue)
+	}
+	if brand.Type != "color" || brand.Description != "Set by each theme" {
+		t.Errorf("expected type and description to be kept, got %q, %q", brand.Type, brand.Description)
+	}
+	if !brand.IsPlaceholder() {
+		t.Error("expected color.brand to be a placeholder")
+	}
+	if testutil.TokenByPath(t, tokens, "color.text").IsPlaceholder() {
+		t.Error("expected color.text not to be a placeholder")
+	}
+}
+
+func TestJSONParser_NullValueYAML(t *testing.T) {
+	p := parser.NewJSONParser()
+	tokens, err := p.Parse([]byte("color:\n  brand:\n    $type: color\n    $value: ~\n"), parser.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tokens) != 1 || !tokens[0].IsPlaceholder() {
+		t.Errorf("expected one placeholder token, got %v", tokens)
+	}
+}
+
+// nestedTokens returns a JSON document with a token nested depth groups
+// deep.
+func nestedTokens(depth int) []byte {
+	return []byte(strings.Repeat(`{"g":`, depth) +
+		`{"t":{"$type":"dimension","$value":"1px"}}` +
+		strings.Repeat("}", depth))
+}
+
+func TestJSONParser_MaxDepth(t *testing.T) {
+	p := parser.NewJSONParser()
+	for _, skipPositions := range []bool{false, true} {
+		opts := parser.Options{SchemaVersion: schema.Draft, SkipPositions: skipPositions, MaxDepth: 50}
+
+		tokens, err := p.Parse(nestedTokens(48), opts)
+		if err != nil {
+			t.Fatalf("SkipPositions=%v: unexpected error within the limit: %v", skipPositions, err)
+		}
+		if len(tokens) != 1 {
+			t.Errorf("SkipPositions=%v: expected 1 token, got %d", skipPositions, len(tokens))
+		}
+
+		if _, err := p.Parse(nestedTokens(50), opts); !errors.Is(err, parser.ErrMaxDepth) {
+			t.Errorf("SkipPositions=%v: expected ErrMaxDepth, got %v", skipPositions, err)
+		}
+	}
+}
+
+func TestJSONParser_MaxDepthDefault(t *testing.T) {
+	p := parser.NewJSONParser()
+	data := nestedTokens(parser.DefaultMaxDepth + 1)
+	for _, skipPositions := range []bool{false, true} {
+		_, err := p.Parse(data, parser.Options{SchemaVersion: schema.Draft, SkipPositions: skipPositions})
+		if !errors.Is(err, parser.ErrMaxDepth) {
+			t.Errorf("SkipPositions=%v: expected ErrMaxDepth, got %v", skipPositions, err)
+		}
+	}
+}
+
+func TestJSONParser_MaxDepthUnderToken(t *testing.T) {
+	// Objects nested under a token aren't extracted, but they are still
+	// walked for positions and by the stream decoder.
+	data := []byte(`{"t":{"$value":"1px",` + strings.Repeat(`"x":{`, 60) + strings.Repeat("}", 60) + `}}`)
+	p := parser.NewJSONParser()
+	for _, skipPositions := range []bool{false, true} {
+		_, err := p.Parse(data, parser.Options{SchemaVersion: schema.Draft, SkipPositions: skipPositions, MaxDepth: 50})
+		if !errors.Is(err, parser.ErrMaxDepth) {
+			t.Errorf("SkipPositions=%v: expected ErrMaxDepth, got %v", skipPositions, err)
+		}
+	}
+}
+
+func TestJSONParser_MultiDocumentYAML(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/draft/multi-document", "/test")
+
+	p := parser.NewJSONParser()
+	tokens, err := p.ParseFile(mfs, "/test/tokens.yaml", parser.Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertRootTokens(t, tokens)
+
+	// Positions are tracked in every document, counting lines from the
+	// start of the stream (0-based: spacing.small is on line 12)
+	if spacing := testutil.TokenByPath(t, tokens, "spacing.small"); spacing.Line != 11 {
+		t.Errorf("expected spacing.small on line 11, got %d", spacing.Line)
+	}
+}
+
+func TestJSONParser_RootArray(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/draft/root-array", "/test")
+
+	p := parser.NewJSONParser()
+	for _, skip := range []bool{false, true} {
+		tokens, err := p.ParseFile(mfs, "/test/tokens.json", parser.Options{SkipPositions: skip})
+		if err != nil {
+			t.Fatalf("SkipPositions=%v: unexpected error: %v", skip, err)
+		}
+		assertRootTokens(t, tokens)
+	}
+}
+
+func TestJSONParser_RootArrayOfScalars(t *testing.T) {
+	p := parser.NewJSONParser()
+	for _, skip := range []bool{false, true} {
+		_, err := p.Parse([]byte(`[{"color": {}}, "red"]`), parser.Options{Format: parser.FormatJSON, SkipPositions: skip})
+		if err == nil || !strings.Contains(err.Error(), "must be an object") {
+			t.Errorf("SkipPositions=%v: expected error for a non-object array element, got %v", skip, err)
+		}
+	}
+}
+
+// assertRootTokens checks for the tokens of the multi-document and
+// root-array fixtures, which split the same tokens across two roots.
+func assertRootTokens(t *testing.T, tokens []*token.Token) {
+	t.Helper()
+	if len(tokens) != 4 {
+		t.Errorf("expected 4 tokens, got %d", len(tokens))
+	}
+	for _, path := range []string{"color.primary", "color.secondary", "spacing.small"} {
+		testutil.TokenByPath(t, tokens, path)
+	}
+	if tok := testutil.TokenByPath(t, tokens, "spacing.large"); tok.Type != token.TypeDimension {
+		t.Errorf("expected spacing.large to inherit dimension type, got %q", tok.Type)
+	}
+}
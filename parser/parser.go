@@ -8,11 +8,21 @@ license that can be found in the LICENSE file.
 package parser
 
 import (
+	"errors"
+
 	"bennypowers.dev/asimonim/fs"
 	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/token"
 )
 
+// DefaultMaxDepth is the nesting depth limit used when Options.MaxDepth
+// is zero. It is far deeper than any real token file.
+const DefaultMaxDepth = 1000
+
+// ErrMaxDepth is returned when a document nests groups deeper than
+// Options.MaxDepth.
+var ErrMaxDepth = errors.New("maximum nesting depth exceeded")
+
 // Options configures token parsing.
 type Options struct {
 	// Prefix is the CSS variable prefix.
@@ -32,6 +42,31 @@ type Options struct {
 	// When true, Line and Character fields will be zero on all tokens.
 	// Use this when LSP features (go-to-definition) aren't needed.
 	SkipPositions bool
+
+	// Format selects the syntax of the data. The default, FormatAuto,
+	// sniffs the content; ParseFile sets it from the file extension.
+	Format Format
+
+	// MaxDepth limits how deeply objects may nest, guarding against
+	// untrusted input built to exhaust the stack. Zero selects
+	// DefaultMaxDepth.
+	MaxDepth int
+
+	// StrictTypes reports tokens whose $type is not a DTCG type, such as
+	// "colour", to OnWarning. Parsing still succeeds.
+	StrictTypes bool
+
+	// OnWarning, if not nil, is called with each warning found while
+	// parsing, in the order they are found.
+	OnWarning func(Warning)
+}
+
+// maxDepth returns the nesting depth limit for opts.
+func (opts Options) maxDepth() int {
+	if opts.MaxDepth > 0 {
+		return opts.MaxDepth
+	}
+	return DefaultMaxDepth
 }
 
 // Parser parses design token files.
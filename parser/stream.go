@@ -0,0 +1,253 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+
+	"bennypowers.dev/asimonim/parser/common"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/token"
+)
+
+// jsonStream extracts tokens from JSON with a json.Decoder, without
+// decoding the document into a map. Only $-prefixed members are
+// materialized; groups are walked member by member, and tokens are
+// appended to result in the same order extractTokens produces.
+type jsonStream struct {
+	p      *JSONParser
+	dec    *json.Decoder
+	opts   Options
+	result []*token.Token
+	depth  int
+}
+
+// memberRange is the span of result holding one member's tokens.
+type memberRange struct {
+	key        string
+	start, end int
+}
+
+// parseJSONStream is the streaming counterpart of extractTokens.
+func (p *JSONParser) parseJSONStream(data []byte, opts Options) ([]*token.Token, error) {
+	s := &jsonStream{
+		p:      p,
+		dec:    json.NewDecoder(bytes.NewReader(data)),
+		opts:   opts,
+		result: []*token.Token{},
+	}
+
+	start, err := s.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch start {
+	case json.Delim('{'):
+		if _, err := s.group([]string{}, ""); err != nil {
+			return nil, err
+		}
+	case json.Delim('['):
+		// A top-level array of groups; see rootObjects
+		for i := 0; s.dec.More(); i++ {
+			elem, err := s.dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			if elem != json.Delim('{') {
+				return nil, fmt.Errorf("root array element %d must be an object", i)
+			}
+			if _, err := s.group([]string{}, ""); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := s.dec.Token(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("root must be an object or an array of objects")
+	}
+	if _, err := s.dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid character after top-level value")
+	}
+	return s.result, nil
+}
+
+// group decodes the members of an object whose opening brace has been
+// consumed, appending the tokens of its child members to result.
+// It returns the object's $-prefixed members.
+//
+// A group's $type may follow its children, so it is applied to untyped
+// tokens once the object closes. Nested groups close first, so the
+// nearest $type wins, as with top-down inheritance.
+func (s *jsonStream) group(jsonPath []string, path string) (map[string]any, error) {
+	s.depth++
+	defer func() { s.depth-- }()
+	if s.depth > s.opts.maxDepth() {
+		return nil, depthError(jsonPath, s.opts.maxDepth())
+	}
+
+	var meta map[string]any
+	var members []memberRange
+	groupStart := len(s.result)
+
+	for {
+		t, err := s.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if t == json.Delim('}') {
+			break
+		}
+		key, ok := t.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected token %v", t)
+		}
+
+		if len(key) > 0 && key[0] == '$' {
+			var v any
+			if err := s.dec.Decode(&v); err != nil {
+				return nil, err
+			}
+			if meta == nil {
+				meta = make(map[string]any)
+			}
+			meta[key] = v
+			continue
+		}
+
+		vt, err := s.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if vt != json.Delim('{') {
+			if err := s.skip(vt); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		start := len(s.result)
+		if err := s.member(key, jsonPath, path); err != nil {
+			return nil, err
+		}
+		members = append(members, memberRange{key: key, start: start, end: len(s.result)})
+	}
+
+	if len(members) > 0 {
+		s.order(groupStart, members)
+	}
+
+	if groupType, ok := meta["$type"].(string); ok {
+		for _, tok := range s.result[groupStart:] {
+			if tok.Type == "" {
+				tok.Type = groupType
+			}
+		}
+	}
+	return meta, nil
+}
+
+// member decodes the object stored under key, whose opening brace has
+// been consumed, and appends the token it defines followed by its
+// children's tokens. This mirrors the per-key logic of extractTokens.
+func (s *jsonStream) member(key string, jsonPath []string, path string) error {
+	opts := s.opts
+	isRootToken := common.IsRootToken(key, opts.SchemaVersion, opts.GroupMarkers)
+	inMarkers := slices.Contains(opts.GroupMarkers, key)
+	isMarker := inMarkers && opts.SchemaVersion == schema.Draft
+
+	// Whether a marker is transparent depends on its $value, which may
+	// not have been read yet. Children are only kept for markers that
+	// are transparent or root tokens, so decode them as transparent.
+	childJSON, childPath := buildPaths(jsonPath, path, key, isRootToken || inMarkers, isMarker)
+
+	start := len(s.result)
+	meta, err := s.group(childJSON, childPath)
+	if err != nil {
+		return err
+	}
+
+	dollarValue, hasValue := meta["$value"]
+	dollarRef, hasRef := meta["$ref"]
+	hasRef = hasRef && opts.SchemaVersion != schema.Draft
+	if !hasValue && !hasRef {
+		return nil
+	}
+
+	if !isMarker && !isRootToken {
+		// Plain tokens don't have children
+		clear(s.result[start:])
+		s.result = s.result[:start]
+	}
+
+	isTransparentMarker := inMarkers && !hasValue
+	currentPath, _ := buildPaths(jsonPath, path, key, isTransparentMarker || isRootToken, isMarker)
+	t := s.p.createToken(key, path, meta, currentPath, opts, isRootToken || isMarker, dollarValue, dollarRef, "")
+	s.result = slices.Insert(s.result, start, t)
+	return nil
+}
+
+// order arranges the members of a group that starts at groupStart in
+// key order, keeping only the last of any duplicate keys, as
+// json.Unmarshal into a map would.
+func (s *jsonStream) order(groupStart int, members []memberRange) {
+	last := make(map[string]int, len(members))
+	for i, m := range members {
+		last[m.key] = i
+	}
+	sorted := s.opts.SkipSort || sort.SliceIsSorted(members, func(i, j int) bool {
+		return members[i].key < members[j].key
+	})
+	if sorted && len(last) == len(members) {
+		return
+	}
+
+	kept := make([]memberRange, 0, len(last))
+	for i, m := range members {
+		if last[m.key] == i {
+			kept = append(kept, m)
+		}
+	}
+	if !s.opts.SkipSort {
+		sort.Slice(kept, func(i, j int) bool {
+			return kept[i].key < kept[j].key
+		})
+	}
+
+	ordered := make([]*token.Token, 0, len(s.result)-groupStart)
+	for _, m := range kept {
+		ordered = append(ordered, s.result[m.start:m.end]...)
+	}
+	n := copy(s.result[groupStart:], ordered)
+	clear(s.result[groupStart+n:])
+	s.result = s.result[:groupStart+n]
+}
+
+// skip consumes the remainder of a value whose first token is t.
+func (s *jsonStream) skip(t json.Token) error {
+	depth := 0
+	for {
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+		var err error
+		if t, err = s.dec.Token(); err != nil {
+			return err
+		}
+	}
+}
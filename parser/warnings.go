@@ -0,0 +1,101 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"bennypowers.dev/asimonim/token"
+)
+
+// Warning is a problem found while parsing that does not stop it, such
+// as an unknown $type with Options.StrictTypes.
+type Warning struct {
+	// FilePath is the file the token came from, if parsed with ParseFile.
+	FilePath string
+	// Path is the dot path of the token.
+	Path string
+	// Message describes what's wrong.
+	Message string
+	// Suggestion provides an actionable fix.
+	Suggestion string
+}
+
+// String formats the warning like a validator.ValidationError.
+func (w Warning) String() string {
+	var sb strings.Builder
+	if w.FilePath != "" {
+		sb.WriteString(w.FilePath)
+		sb.WriteString(": ")
+	}
+	sb.WriteString(w.Path)
+	sb.WriteString(": ")
+	sb.WriteString(w.Message)
+	if w.Suggestion != "" {
+		sb.WriteString(" (")
+		sb.WriteString(w.Suggestion)
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// checkTypes reports a warning to onWarning for each token with an
+// unknown $type. Tokens that inherit an unknown type from their group are
+// each reported.
+func checkTypes(tokens []*token.Token, onWarning func(Warning)) {
+	if onWarning == nil {
+		return
+	}
+	for _, tok := range tokens {
+		if tok.Type == "" || token.IsDTCGType(tok.Type) {
+			continue
+		}
+		w := Warning{
+			FilePath: tok.FilePath,
+			Path:     tok.DotPath(),
+			Message:  fmt.Sprintf("unknown $type %q", tok.Type),
+		}
+		if suggestion := suggestType(tok.Type); suggestion != "" {
+			w.Suggestion = fmt.Sprintf("did you mean %q?", suggestion)
+		}
+		onWarning(w)
+	}
+}
+
+// suggestType returns the DTCG type closest to typ, ignoring case, or ""
+// if none is within two edits.
+func suggestType(typ string) string {
+	best, bestDistance := "", 3
+	for _, known := range token.Types {
+		if d := editDistance(strings.ToLower(typ), strings.ToLower(known)); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package parser_test
+
+import (
+	"testing"
+
+	"bennypowers.dev/asimonim/internal/mapfs"
+	"bennypowers.dev/asimonim/parser"
+)
+
+const unknownTypes = `{
+	"color": {
+		"brand": { "$type": "colour", "$value": "#FF6B35" },
+		"accent": { "$type": "color", "$value": "#0066CC" }
+	},
+	"space": {
+		"$type": "Dimension",
+		"small": { "$value": "4px" }
+	},
+	"misc": {
+		"flag": { "$type": "vendor-thing", "$value": "on" },
+		"untyped": { "$value": "plain" }
+	}
+}`
+
+func TestParse_StrictTypes(t *testing.T) {
+	for _, skipPositions := range []bool{false, true} {
+		var warnings []parser.Warning
+		opts := parser.Options{
+			StrictTypes:   true,
+			SkipPositions: skipPositions,
+			OnWarning:     func(w parser.Warning) { warnings = append(warnings, w) },
+		}
+		tokens, err := parser.NewJSONParser().Parse([]byte(unknownTypes), opts)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if len(tokens) != 5 {
+			t.Errorf("Parse() returned %d tokens, want 5", len(tokens))
+		}
+
+		want := map[string]string{
+			"color.brand": `color.brand: unknown $type "colour" (did you mean "color"?)`,
+			"space.small": `space.small: unknown $type "Dimension" (did you mean "dimension"?)`,
+			"misc.flag":   `misc.flag: unknown $type "vendor-thing"`,
+		}
+		if len(warnings) != len(want) {
+			t.Fatalf("warnings = %v, want %d warnings", warnings, len(want))
+		}
+		for _, w := range warnings {
+			if got := w.String(); got != want[w.Path] {
+				t.Errorf("warning = %q, want %q", got, want[w.Path])
+			}
+		}
+	}
+}
+
+func TestParse_StrictTypesOff(t *testing.T) {
+	var warnings []parser.Warning
+	opts := parser.Options{OnWarning: func(w parser.Warning) { warnings = append(warnings, w) }}
+	if _, err := parser.NewJSONParser().Parse([]byte(unknownTypes), opts); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("warnings = %v, want none without StrictTypes", warnings)
+	}
+}
+
+func TestParseFile_StrictTypesFilePath(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/tokens.json", `{"size": {"$type": "dimenson", "$value": "4px"}}`, 0644)
+
+	var warnings []parser.Warning
+	opts := parser.Options{StrictTypes: true, OnWarning: func(w parser.Warning) { warnings = append(warnings, w) }}
+	if _, err := parser.NewJSONParser().ParseFile(mfs, "/tokens.json", opts); err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want 1 warning", warnings)
+	}
+	if warnings[0].FilePath != "/tokens.json" {
+		t.Errorf("FilePath = %q, want /tokens.json", warnings[0].FilePath)
+	}
+}
+
+func TestParse_StrictTypesPerCall(t *testing.T) {
+	// A shared parser reports each call's warnings to that call only
+	p := parser.NewJSONParser()
+	for range 2 {
+		count := 0
+		opts := parser.Options{StrictTypes: true, OnWarning: func(parser.Warning) { count++ }}
+		if _, err := p.Parse([]byte(unknownTypes), opts); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if count != 3 {
+			t.Errorf("got %d warnings, want 3", count)
+		}
+	}
+}
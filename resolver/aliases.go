@@ -7,18 +7,63 @@ license that can be found in the LICENSE file.
 package resolver
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	"bennypowers.dev/asimonim/parser/common"
 	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/token"
 )
 
+// ResolveOptions configures alias resolution.
+type ResolveOptions struct {
+	// Extensions also resolves {token.path} references in $extensions,
+	// storing the result in each token's ResolvedExtensions.
+	Extensions bool
+}
+
 // ResolveAliases resolves all alias references in the token list.
-// Updates ResolvedValue and IsResolved fields on each token.
+// Updates ResolvedValue and IsResolved fields on each token. An alias
+// without its own $type takes the type of the token it resolves to, so
+// formatters treat it like the terminal value, and has TypeFromAlias set.
 func ResolveAliases(tokens []*token.Token, version schema.Version) error {
+	return ResolveAliasesWithOptions(tokens, version, ResolveOptions{})
+}
+
+// ResolveAliasesWithOptions resolves all alias references in the token
+// list, as ResolveAliases does, with the given options.
+func ResolveAliasesWithOptions(tokens []*token.Token, version schema.Version, opts ResolveOptions) error {
+	return resolveAliases(context.Background(), tokens, version, opts)
+}
+
+// ResolveAliasesContext resolves all alias references in the token list,
+// as ResolveAliases does, stopping early with ctx.Err() if ctx is done.
+// Tokens resolved before cancellation keep their resolved values.
+func ResolveAliasesContext(ctx context.Context, tokens []*token.Token, version schema.Version) error {
+	return resolveAliases(ctx, tokens, version, ResolveOptions{})
+}
+
+// cancelCheckInterval is how many tokens are resolved between checks
+// for cancellation.
+const cancelCheckInterval = 256
+
+func resolveAliases(ctx context.Context, tokens []*token.Token, version schema.Version, opts ResolveOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	graph := BuildDependencyGraph(tokens)
 
+	// A token aliasing itself is a cycle too, but a common enough mistake
+	// to be worth naming directly.
+	for _, tok := range tokens {
+		if slices.Contains(graph.Dependencies(tok.Name), tok.Name) {
+			return fmt.Errorf("%w: %s has value %s", schema.ErrSelfReference, tok.DotPath(), tok.Value)
+		}
+	}
+
 	if graph.HasCycle() {
 		cycle := graph.FindCycle()
 		return fmt.Errorf("%w: %v", schema.ErrCircularReference, cycle)
@@ -28,24 +73,44 @@ func ResolveAliases(tokens []*token.Token, version schema.Version) error {
 	if err != nil {
 		return err
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-	tokenByName := make(map[string]*token.Token)
+	idx := newTokenIndex(tokens)
+	tokenByName := make(map[string]*token.Token, len(tokens))
 	for _, tok := range tokens {
 		tokenByName[tok.Name] = tok
 	}
 
-	for _, name := range sortedNames {
+	for i, name := range sortedNames {
+		if i%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
 		tok := tokenByName[name]
 		if tok == nil {
 			continue
 		}
-		resolveToken(tok, tokenByName, version)
+		resolveToken(tok, idx, version)
+	}
+
+	if opts.Extensions {
+		for i, tok := range tokens {
+			if i%cancelCheckInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+			resolveExtensions(tok, idx)
+		}
 	}
 
 	return nil
 }
 
-func resolveToken(tok *token.Token, tokenByName map[string]*token.Token, version schema.Version) {
+func resolveToken(tok *token.Token, idx tokenIndex, version schema.Version) {
 	if tok.IsResolved {
 		return
 	}
@@ -62,7 +127,7 @@ func resolveToken(tok *token.Token, tokenByName map[string]*token.Token, version
 
 	if strings.Contains(tok.Value, "{") {
 		isAlias = true
-		result := resolveCurlyBraceRef(tok.Value, tokenByName)
+		result := resolveCurlyBraceRef(tok.Value, idx)
 		if !result.ok {
 			// Resolution failed - use original value as fallback
 			tok.ResolvedValue = tok.Value
@@ -72,9 +137,10 @@ func resolveToken(tok *token.Token, tokenByName map[string]*token.Token, version
 		}
 		tok.ResolvedValue = result.value
 		tok.ResolutionChain = result.chain
+		inheritType(tok, result.typ)
 	} else if effectiveVersion != schema.Draft && strings.HasPrefix(tok.Value, "#/") {
 		isAlias = true
-		result := resolveJSONPointerRef(tok.Value, tokenByName)
+		result := resolveJSONPointerRef(tok.Value, idx)
 		if !result.ok {
 			// Resolution failed - use original value as fallback
 			tok.ResolvedValue = tok.Value
@@ -84,12 +150,27 @@ func resolveToken(tok *token.Token, tokenByName map[string]*token.Token, version
 		}
 		tok.ResolvedValue = result.value
 		tok.ResolutionChain = result.chain
+		inheritType(tok, result.typ)
+	} else if ref, overrides, ok := token.PartialRef(tok.RawValue); ok && effectiveVersion != schema.Draft {
+		isAlias = true
+		result := resolveJSONPointerRef(ref, idx)
+		base, isObject := result.value.(map[string]any)
+		if !result.ok || !isObject {
+			// Only an object value can take overrides - keep it as written
+			tok.ResolvedValue = tok.RawValue
+			tok.ResolutionChain = nil
+			tok.IsResolved = true
+			return
+		}
+		tok.ResolvedValue = mergeOverrides(base, resolveOverrides(overrides, idx))
+		tok.ResolutionChain = result.chain
+		inheritType(tok, result.typ)
 	}
 
 	if !isAlias {
 		if tok.RawValue != nil {
 			tok.ResolvedValue = tok.RawValue
-		} else {
+		} else if !tok.IsPlaceholder() {
 			tok.ResolvedValue = tok.Value
 		}
 	}
@@ -97,14 +178,63 @@ func resolveToken(tok *token.Token, tokenByName map[string]*token.Token, version
 	tok.IsResolved = true
 }
 
+// mergeOverrides returns a copy of a referenced composite value with the
+// given fields replaced. Overrides replace whole fields, so overriding a
+// shadow's color replaces its color object rather than merging into it.
+func mergeOverrides(base, overrides map[string]any) map[string]any {
+	merged := deepCopyMap(base)
+	for k, v := range overrides {
+		merged[k] = deepCopyAny(v)
+	}
+	return merged
+}
+
+// resolveOverrides returns overrides with each field whose value is
+// itself a reference, such as {"$ref": "#/color/black"}, replaced by the
+// referenced token's resolved value. References that don't resolve are
+// kept as written.
+func resolveOverrides(overrides map[string]any, idx tokenIndex) map[string]any {
+	resolved := make(map[string]any, len(overrides))
+	for k, v := range overrides {
+		resolved[k] = v
+		if ref, ok := overrideRef(v); ok {
+			if result := resolveJSONPointerRef(ref, idx); result.ok {
+				resolved[k] = deepCopyAny(result.value)
+			}
+		}
+	}
+	return resolved
+}
+
+// overrideRef returns the JSON pointer of an override value that aliases
+// a whole token, such as {"$ref": "#/color/black"}.
+func overrideRef(v any) (string, bool) {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+	ref, ok := m["$ref"].(string)
+	return ref, ok
+}
+
+// inheritType gives an untyped alias the type of its referenced token,
+// marking it as not authored so that it isn't written back out.
+func inheritType(tok *token.Token, typ string) {
+	if tok.Type == "" && typ != "" {
+		tok.Type = typ
+		tok.TypeFromAlias = true
+	}
+}
+
 // resolveResult holds the result of resolving a reference.
 type resolveResult struct {
 	value any
+	typ   string
 	chain []string
 	ok    bool
 }
 
-func resolveCurlyBraceRef(value string, tokenByName map[string]*token.Token) resolveResult {
+func resolveCurlyBraceRef(value string, idx tokenIndex) resolveResult {
 	refs := extractCurlyBraceRefs(value)
 	if len(refs) == 0 {
 		return resolveResult{value: value, ok: true}
@@ -117,10 +247,7 @@ func resolveCurlyBraceRef(value string, tokenByName map[string]*token.Token) res
 		return resolveResult{value: value, ok: true}
 	}
 
-	ref := refs[0]
-	tokenName := strings.ReplaceAll(ref, ".", "-")
-
-	refToken := tokenByName[tokenName]
+	refToken := idx.lookup(refs[0])
 	if refToken == nil {
 		// Reference not found - leave unresolved
 		return resolveResult{ok: false}
@@ -135,14 +262,11 @@ func resolveCurlyBraceRef(value string, tokenByName map[string]*token.Token) res
 	chain := []string{refToken.Name}
 	chain = append(chain, refToken.ResolutionChain...)
 
-	return resolveResult{value: refToken.ResolvedValue, chain: chain, ok: true}
+	return resolveResult{value: refToken.ResolvedValue, typ: refToken.Type, chain: chain, ok: true}
 }
 
-func resolveJSONPointerRef(value string, tokenByName map[string]*token.Token) resolveResult {
-	path := strings.TrimPrefix(value, "#/")
-	tokenName := strings.ReplaceAll(path, "/", "-")
-
-	refToken := tokenByName[tokenName]
+func resolveJSONPointerRef(value string, idx tokenIndex) resolveResult {
+	refToken := idx.lookup(common.ConvertJSONPointerToTokenPath(value))
 	if refToken == nil {
 		return resolveResult{ok: false}
 	}
@@ -155,5 +279,5 @@ func resolveJSONPointerRef(value string, tokenByName map[string]*token.Token) re
 	chain := []string{refToken.Name}
 	chain = append(chain, refToken.ResolutionChain...)
 
-	return resolveResult{value: refToken.ResolvedValue, chain: chain, ok: true}
+	return resolveResult{value: refToken.ResolvedValue, typ: refToken.Type, chain: chain, ok: true}
 }
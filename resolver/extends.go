@@ -14,7 +14,6 @@ import (
 
 	"bennypowers.dev/asimonim/schema"
 	"bennypowers.dev/asimonim/token"
-	"gopkg.in/yaml.v3"
 )
 
 // groupExtension represents a group that extends another group.
@@ -28,6 +27,9 @@ type groupExtension struct {
 // ResolveGroupExtensions resolves $extends relationships in DTCG 2025.10 files.
 // It creates copies of inherited tokens with updated paths and names.
 // Child tokens override inherited tokens with the same terminal name.
+// Untyped tokens in an extending group take their $type from the merged
+// group, so a base group's $type applies unless the extending group sets
+// its own.
 //
 // This function should be called AFTER parsing, BEFORE alias resolution.
 // For Draft schema, this is a no-op that returns the tokens unchanged.
@@ -48,14 +50,19 @@ func ResolveGroupExtensions(tokens []*token.Token, data []byte) ([]*token.Token,
 		return tokens, nil
 	}
 
-	// Parse raw data to find $extends relationships
-	var raw map[string]any
-	if err := yaml.Unmarshal(data, &raw); err != nil {
+	// Parse raw data to find $extends relationships. Each document of a
+	// YAML stream and each object of a top-level array is a root, and the
+	// roots together make up one group tree, as they do for the parser.
+	roots, err := schema.DecodeRoots(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse data for extends resolution: %w", err)
 	}
-
-	// Find all groups with $extends
-	extensions := findExtensions(raw, nil)
+	raw := make(map[string]any)
+	var extensions []groupExtension
+	for _, root := range roots {
+		mergeGroups(raw, root)
+		extensions = append(extensions, findExtensions(root, nil)...)
+	}
 	if len(extensions) == 0 {
 		return tokens, nil
 	}
@@ -111,6 +118,8 @@ func ResolveGroupExtensions(tokens []*token.Token, data []byte) ([]*token.Token,
 		}
 	}
 
+	inheritMergedTypes(raw, result, extensions)
+
 	// Sort result for deterministic output
 	sort.Slice(result, func(i, j int) bool {
 		return result[i].Name < result[j].Name
@@ -119,6 +128,84 @@ func ResolveGroupExtensions(tokens []*token.Token, data []byte) ([]*token.Token,
 	return result, nil
 }
 
+// mergeGroups deep-merges the groups of src into dst, with src winning
+// where both define the same member.
+func mergeGroups(dst, src map[string]any) {
+	for key, value := range src {
+		srcGroup, ok := value.(map[string]any)
+		dstGroup, isGroup := dst[key].(map[string]any)
+		if ok && isGroup {
+			mergeGroups(dstGroup, srcGroup)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// inheritMergedTypes sets the $type of tokens in extending groups from the
+// merged group tree. An extending group behaves as the base group with its
+// own members and metadata laid over it, so a token without its own $type
+// takes the nearest group $type, where a group that omits $type inherits
+// the one from the group it extends. The extending group's $type wins over
+// the base group's, and both win over types from outer groups.
+func inheritMergedTypes(raw map[string]any, tokens []*token.Token, extensions []groupExtension) {
+	for _, t := range tokens {
+		if !slices.ContainsFunc(extensions, func(ext groupExtension) bool {
+			return tokenBelongsToGroup(t, ext.path)
+		}) {
+			continue
+		}
+		for i := len(t.Path); i >= 0; i-- {
+			if typ, ok := mergedField(raw, t.Path[:i], "$type").(string); ok {
+				t.Type = typ
+				break
+			}
+		}
+	}
+}
+
+// mergedField returns field from the node at path in the merged group
+// tree, falling back through $extends when the node doesn't declare it.
+func mergedField(raw map[string]any, path []string, field string) any {
+	seen := make(map[string]bool)
+	node := lookupMerged(raw, path, seen)
+	for node != nil {
+		if v, ok := node[field]; ok {
+			return v
+		}
+		ref, _ := node["$extends"].(string)
+		base := parseJSONPointer(ref)
+		if base == nil {
+			return nil
+		}
+		node = lookupMerged(raw, base, seen)
+	}
+	return nil
+}
+
+// lookupMerged returns the raw node at path, looking up members that an
+// extending group doesn't declare in the group it extends. seen guards
+// against $extends loops that the group-level cycle check can't see, such
+// as a group extending one of its own descendants.
+func lookupMerged(raw map[string]any, path []string, seen map[string]bool) map[string]any {
+	node := raw
+	for i, seg := range path {
+		child, ok := node[seg].(map[string]any)
+		if !ok {
+			ref, _ := node["$extends"].(string)
+			base := parseJSONPointer(ref)
+			key := strings.Join(path[:i], "/")
+			if base == nil || seen[key] {
+				return nil
+			}
+			seen[key] = true
+			return lookupMerged(raw, append(slices.Clone(base), path[i:]...), seen)
+		}
+		node = child
+	}
+	return node
+}
+
 // findExtensions recursively finds all groups with $extends.
 func findExtensions(data map[string]any, currentPath []string) []groupExtension {
 	var extensions []groupExtension
@@ -302,10 +389,12 @@ func resolveExtension(ext groupExtension, tokens []*token.Token, terminalNames m
 			Name:               newName,
 			Value:              t.Value,
 			Type:               t.Type,
+			TypeFromAlias:      t.TypeFromAlias,
 			Description:        t.Description,
 			Extensions:         deepCopyMap(t.Extensions),
 			Deprecated:         t.Deprecated,
 			DeprecationMessage: t.DeprecationMessage,
+			Replacement:        t.Replacement,
 			FilePath:           t.FilePath,
 			Prefix:             t.Prefix,
 			Path:               newPath,
@@ -136,6 +136,63 @@ func TestResolveGroupExtensions_Override(t *testing.T) {
 	}
 }
 
+func TestResolveGroupExtensions_GroupType(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/v2025_10/extends-group-type", "/test")
+	data, err := mfs.ReadFile("/test/tokens.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	p := parser.NewJSONParser()
+	tokens, err := p.Parse(data, parser.Options{})
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	result, err := resolver.ResolveGroupExtensions(tokens, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	types := make(map[string]string, len(result))
+	for _, tok := range result {
+		types[tok.Name] = tok.Type
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		// Inherited tokens keep the base group's type
+		{"theme-red", token.TypeColor},
+		{"brand-red", token.TypeColor},
+		// Own tokens of a group that omits $type take the base group's
+		{"theme-green", token.TypeColor},
+		{"brand-green", token.TypeColor},
+		{"brand-blue", token.TypeColor},
+		// A token's own $type wins over the extending group's
+		{"space-ratio", token.TypeNumber},
+		{"space-sm", token.TypeDimension},
+		{"space-md", token.TypeDimension},
+	}
+	for _, tt := range tests {
+		typ, ok := types[tt.name]
+		if !ok {
+			t.Errorf("expected to find %s", tt.name)
+			continue
+		}
+		if typ != tt.want {
+			t.Errorf("%s: expected type %q, got %q", tt.name, tt.want, typ)
+		}
+	}
+
+	for _, tok := range result {
+		if tok.Name == "brand-red" && tok.Description != "Alert red" {
+			t.Errorf("expected brand-red to keep description 'Alert red', got %q", tok.Description)
+		}
+	}
+}
+
 func TestResolveGroupExtensions_Circular(t *testing.T) {
 	mfs := testutil.NewFixtureFS(t, "fixtures/v2025_10/extends-circular", "/test")
 	data, err := mfs.ReadFile("/test/tokens.json")
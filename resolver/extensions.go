@@ -0,0 +1,54 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package resolver
+
+import (
+	"strings"
+
+	"bennypowers.dev/asimonim/token"
+)
+
+// resolveExtensions sets tok.ResolvedExtensions to a copy of its
+// extensions with each {token.path} reference replaced by the referenced
+// token's resolved value. It runs once every token's value is resolved,
+// and substituted values are not searched for further references, so an
+// extension referring back to its own token can't loop. References that
+// don't resolve are kept as written.
+func resolveExtensions(tok *token.Token, idx tokenIndex) {
+	if tok.Extensions == nil {
+		return
+	}
+	tok.ResolvedExtensions = resolveExtensionValue(tok.Extensions, idx).(map[string]any)
+}
+
+// resolveExtensionValue copies v, resolving the references in it.
+func resolveExtensionValue(v any, idx tokenIndex) any {
+	switch val := v.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(val))
+		for k, item := range val {
+			result[k] = resolveExtensionValue(item, idx)
+		}
+		return result
+	case []any:
+		result := make([]any, len(val))
+		for i, item := range val {
+			result[i] = resolveExtensionValue(item, idx)
+		}
+		return result
+	case string:
+		if !strings.Contains(val, "{") {
+			return val
+		}
+		if result := resolveCurlyBraceRef(val, idx); result.ok {
+			return deepCopyAny(result.value)
+		}
+		return val
+	default:
+		return val
+	}
+}
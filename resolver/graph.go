@@ -9,6 +9,8 @@ package resolver
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"bennypowers.dev/asimonim/parser/common"
@@ -35,8 +37,16 @@ func BuildDependencyGraph(tokens []*token.Token) *DependencyGraph {
 		graph.nodes[tok.Name] = true
 	}
 
+	idx := newTokenIndex(tokens)
 	for _, tok := range tokens {
 		deps := extractDependencies(tok)
+		for i, dep := range deps {
+			// Point dependencies at the actual token name, so prefixed
+			// and unprefixed references share a node
+			if target := idx.lookup(dep); target != nil {
+				deps[i] = target.Name
+			}
+		}
 		if len(deps) > 0 {
 			graph.dependencies[tok.Name] = deps
 			for _, dep := range deps {
@@ -63,9 +73,21 @@ func extractDependencies(tok *token.Token) []string {
 
 	// Check for JSON Pointer references ($ref field)
 	if tok.SchemaVersion != schema.Draft && strings.HasPrefix(tok.Value, "#/") {
-		path := strings.TrimPrefix(tok.Value, "#/")
-		tokenName := strings.ReplaceAll(path, "/", "-")
-		deps = append(deps, tokenName)
+		tokenPath := common.ConvertJSONPointerToTokenPath(tok.Value)
+		deps = append(deps, strings.ReplaceAll(tokenPath, ".", "-"))
+	}
+
+	// A $value object with a $ref and overrides depends on its base, and
+	// on any token an override references
+	if ref, overrides, ok := token.PartialRef(tok.RawValue); ok && tok.SchemaVersion != schema.Draft {
+		tokenPath := common.ConvertJSONPointerToTokenPath(ref)
+		deps = append(deps, strings.ReplaceAll(tokenPath, ".", "-"))
+		for _, field := range slices.Sorted(maps.Keys(overrides)) {
+			if ref, ok := overrideRef(overrides[field]); ok {
+				tokenPath := common.ConvertJSONPointerToTokenPath(ref)
+				deps = append(deps, strings.ReplaceAll(tokenPath, ".", "-"))
+			}
+		}
 	}
 
 	return deps
@@ -209,3 +231,54 @@ func (g *DependencyGraph) topologicalSortDFS(node string, visited map[string]boo
 
 	*stack = append(*stack, node)
 }
+
+// FindUnused returns the tokens that cannot be reached by following
+// references from the entry points. An entry point is the dot path of
+// a token or of a group, which includes every token in it. Tokens are
+// returned in input order.
+func FindUnused(tokens []*token.Token, entryPoints []string) []*token.Token {
+	graph := BuildDependencyGraph(tokens)
+
+	entries := make(map[string]bool, len(entryPoints))
+	for _, entry := range entryPoints {
+		entries[entry] = true
+	}
+
+	reached := make(map[string]bool, len(tokens))
+	var queue []string
+	for _, tok := range tokens {
+		if isEntryPoint(tok, entries) && !reached[tok.Name] {
+			reached[tok.Name] = true
+			queue = append(queue, tok.Name)
+		}
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, dep := range graph.Dependencies(name) {
+			if !reached[dep] {
+				reached[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	var unused []*token.Token
+	for _, tok := range tokens {
+		if !reached[tok.Name] {
+			unused = append(unused, tok)
+		}
+	}
+	return unused
+}
+
+// isEntryPoint reports whether the token, or any group containing it,
+// is in entries.
+func isEntryPoint(tok *token.Token, entries map[string]bool) bool {
+	for i := range tok.Path {
+		if entries[strings.Join(tok.Path[:i+1], ".")] {
+			return true
+		}
+	}
+	return false
+}
@@ -7,10 +7,16 @@ license that can be found in the LICENSE file.
 package resolver_test
 
 import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
 
+	"bennypowers.dev/asimonim/parser"
 	"bennypowers.dev/asimonim/resolver"
 	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/testutil"
 	"bennypowers.dev/asimonim/token"
 )
 
@@ -189,6 +195,52 @@ func TestResolveAliases(t *testing.T) {
 	}
 }
 
+func TestResolveAliases_SelfReference(t *testing.T) {
+	data := testutil.LoadFixtureFile(t, "fixtures/draft/self-reference/tokens.json")
+	tokens, err := parser.NewJSONParser().Parse(data, parser.Options{SchemaVersion: schema.Draft})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = resolver.ResolveAliases(tokens, schema.Draft)
+	if !errors.Is(err, schema.ErrSelfReference) {
+		t.Fatalf("expected ErrSelfReference, got %v", err)
+	}
+	if errors.Is(err, schema.ErrCircularReference) {
+		t.Error("expected a self-reference to be reported apart from general cycles")
+	}
+	if !strings.Contains(err.Error(), "color.primary") {
+		t.Errorf("expected error to name the token, got %q", err)
+	}
+}
+
+func TestResolveAliases_SelfReference_JSONPointer(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-primary", Path: []string{"color", "primary"}, Value: "#/color/primary", SchemaVersion: schema.V2025_10},
+	}
+
+	err := resolver.ResolveAliases(tokens, schema.V2025_10)
+	if !errors.Is(err, schema.ErrSelfReference) {
+		t.Fatalf("expected ErrSelfReference, got %v", err)
+	}
+}
+
+func TestResolveAliases_Placeholder(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/draft/placeholders", schema.Draft)
+
+	brand := testutil.TokenByPath(t, tokens, "color.brand")
+	if brand.ResolvedValue != nil || !brand.IsPlaceholder() {
+		t.Errorf("expected color.brand to stay a placeholder, got %v", brand.ResolvedValue)
+	}
+	link := testutil.TokenByPath(t, tokens, "color.link")
+	if !link.IsPlaceholder() {
+		t.Errorf("expected an alias of a placeholder to be a placeholder, got %v", link.ResolvedValue)
+	}
+	if len(link.ResolutionChain) != 1 || link.ResolutionChain[0] != "color-brand" {
+		t.Errorf("expected color.link to resolve through color-brand, got %v", link.ResolutionChain)
+	}
+}
+
 func TestResolveAliases_V2025_10_CurlyRefs(t *testing.T) {
 	// V2025_10 supports both $ref (JSON Pointer) and curly-brace syntax
 	// This tests curly-brace refs in V2025_10 schema
@@ -240,3 +292,317 @@ func TestResolveAliases_V2025_10_CurlyRefs(t *testing.T) {
 		t.Errorf("expected action chain length 2, got %d", len(tokens[2].ResolutionChain))
 	}
 }
+
+func TestResolveAliases_JSONPointerRefs(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/v2025_10/json-pointer-refs", schema.V2025_10)
+	primary := testutil.TokenByPath(t, tokens, "color.primary")
+
+	tests := []struct {
+		path  string
+		chain []string
+	}{
+		{"action.background", []string{"color-primary"}},
+		{"action.hover", []string{"action-background", "color-primary"}},
+		{"action.text", []string{"color-on/off"}},
+		{"action.border", []string{"color-primary"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			tok := testutil.TokenByPath(t, tokens, tt.path)
+			if !tok.IsResolved {
+				t.Fatal("expected token to be resolved")
+			}
+			if !slices.Equal(tok.ResolutionChain, tt.chain) {
+				t.Errorf("ResolutionChain = %v, want %v", tok.ResolutionChain, tt.chain)
+			}
+			if tok.Type != token.TypeColor {
+				t.Errorf("expected type %q from the target, got %q", token.TypeColor, tok.Type)
+			}
+		})
+	}
+
+	hover := testutil.TokenByPath(t, tokens, "action.hover")
+	if m, ok := hover.ResolvedValue.(map[string]any); !ok || m["hex"] != primary.ResolvedValue.(map[string]any)["hex"] {
+		t.Errorf("expected action.hover to resolve to color.primary's value, got %v", hover.ResolvedValue)
+	}
+}
+
+func TestResolveAliases_PartialRefs(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/v2025_10/partial-refs", schema.V2025_10)
+	field := func(tok *token.Token, name string) any {
+		t.Helper()
+		m, ok := tok.ResolvedValue.(map[string]any)
+		if !ok {
+			t.Fatalf("expected %s to resolve to an object, got %v", tok.DotPath(), tok.ResolvedValue)
+		}
+		return m[name]
+	}
+	offsetY := func(tok *token.Token) any {
+		return field(tok, "offsetY").(map[string]any)["value"]
+	}
+	alpha := func(tok *token.Token) any {
+		return field(tok, "color").(map[string]any)["alpha"]
+	}
+
+	base := testutil.TokenByPath(t, tokens, "shadow.base")
+	brand := testutil.TokenByPath(t, tokens, "shadow.brand")
+	raised := testutil.TokenByPath(t, tokens, "shadow.raised")
+
+	t.Run("override replaces a field", func(t *testing.T) {
+		if alpha(brand) != 0.5 || offsetY(brand) != 2.0 {
+			t.Errorf("expected the base shadow with a new color, got %v", brand.ResolvedValue)
+		}
+		if ref := field(brand, "$ref"); ref != nil {
+			t.Errorf("expected $ref to be merged away, got %v", brand.ResolvedValue)
+		}
+		if !slices.Equal(brand.ResolutionChain, []string{"shadow-base"}) {
+			t.Errorf("ResolutionChain = %v, want [shadow-base]", brand.ResolutionChain)
+		}
+	})
+
+	t.Run("overrides chain", func(t *testing.T) {
+		if alpha(raised) != 0.5 || offsetY(raised) != 8.0 || field(raised, "blur").(map[string]any)["value"] != 16.0 {
+			t.Errorf("expected shadow.brand with new offsetY and blur, got %v", raised.ResolvedValue)
+		}
+		if !slices.Equal(raised.ResolutionChain, []string{"shadow-brand", "shadow-base"}) {
+			t.Errorf("ResolutionChain = %v, want [shadow-brand shadow-base]", raised.ResolutionChain)
+		}
+	})
+
+	t.Run("override with a $ref resolves", func(t *testing.T) {
+		dark := testutil.TokenByPath(t, tokens, "shadow.dark")
+		color, ok := field(dark, "color").(map[string]any)
+		if !ok || color["hex"] != "#000000" || color["$ref"] != nil {
+			t.Errorf("expected the color of color.black, got %v", dark.ResolvedValue)
+		}
+		if offsetY(dark) != 2.0 {
+			t.Errorf("expected the base shadow's other fields, got %v", dark.ResolvedValue)
+		}
+	})
+
+	t.Run("base is not modified", func(t *testing.T) {
+		if alpha(base) != 0.2 || offsetY(base) != 2.0 {
+			t.Errorf("expected shadow.base to keep its own fields, got %v", base.ResolvedValue)
+		}
+	})
+
+	t.Run("whole-value $ref still aliases", func(t *testing.T) {
+		alias := testutil.TokenByPath(t, tokens, "shadow.alias")
+		if alpha(alias) != 0.2 || !slices.Equal(alias.ResolutionChain, []string{"shadow-base"}) {
+			t.Errorf("expected shadow.alias to resolve to shadow.base, got %v", alias.ResolvedValue)
+		}
+	})
+
+	t.Run("missing base is kept as written", func(t *testing.T) {
+		missing := testutil.TokenByPath(t, tokens, "border.missing")
+		if field(missing, "$ref") != "#/border/nowhere" || missing.ResolutionChain != nil {
+			t.Errorf("expected the unresolved value as written, got %v", missing.ResolvedValue)
+		}
+	})
+}
+
+func TestResolveAliases_PartialRefCycle(t *testing.T) {
+	tokens, err := parser.NewJSONParser().Parse([]byte(`{
+  "shadow": {
+    "$type": "shadow",
+    "a": { "$value": { "$ref": "#/shadow/b", "blur": { "value": 1, "unit": "px" } } },
+    "b": { "$value": { "$ref": "#/shadow/a", "blur": { "value": 2, "unit": "px" } } }
+  }
+}`), parser.Options{SchemaVersion: schema.V2025_10})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	err = resolver.ResolveAliases(tokens, schema.V2025_10)
+	if !errors.Is(err, schema.ErrCircularReference) {
+		t.Errorf("expected a circular reference error, got %v", err)
+	}
+}
+
+func TestResolveAliases_InheritsTerminalType(t *testing.T) {
+	tokens := testutil.ParseFixtureTokens(t, "fixtures/v2025_10/alias-chain", schema.V2025_10)
+
+	// surface.card -> surface.base -> color.white, with neither alias typed
+	for _, path := range []string{"surface.base", "surface.card"} {
+		tok := testutil.TokenByPath(t, tokens, path)
+		if tok.Type != token.TypeColor || !tok.TypeFromAlias {
+			t.Errorf("%s: expected type %q from the alias, got %q", path, token.TypeColor, tok.Type)
+		}
+		resolved, ok := tok.ResolvedValue.(map[string]any)
+		if !ok {
+			t.Fatalf("%s: expected structured color, got %T", path, tok.ResolvedValue)
+		}
+		if resolved["colorSpace"] != "srgb" {
+			t.Errorf("%s: expected colorSpace srgb, got %v", path, resolved["colorSpace"])
+		}
+	}
+
+	// An explicit $type on an alias is kept
+	typed := []*token.Token{
+		{Name: "size", Type: token.TypeDimension, Value: "4px"},
+		{Name: "gap", Type: token.TypeNumber, Value: "{size}"},
+	}
+	if err := resolver.ResolveAliases(typed, schema.Draft); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if typed[1].Type != token.TypeNumber {
+		t.Errorf("expected alias to keep its own type, got %q", typed[1].Type)
+	}
+}
+
+func TestResolveAliases_PrefixedFiles(t *testing.T) {
+	jsonParser := parser.NewJSONParser()
+	base, err := jsonParser.Parse([]byte(`{
+  "color": { "$type": "color", "primary": { "$value": "#ff0000" } }
+}`), parser.Options{Prefix: "rh"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	theme, err := jsonParser.Parse([]byte(`{
+  "button": {
+    "$type": "color",
+    "bg": { "$value": "{color.primary}" },
+    "border": { "$value": "{rh.color.primary}" }
+  }
+}`), parser.Options{Prefix: "ds"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tokens := append(theme, base...)
+	if err := resolver.ResolveAliases(tokens, schema.Draft); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tok := range theme {
+		if tok.ResolvedValue != "#ff0000" {
+			t.Errorf("%s: expected #ff0000, got %v", tok.Name, tok.ResolvedValue)
+		}
+		if len(tok.ResolutionChain) != 1 || tok.ResolutionChain[0] != "color-primary" {
+			t.Errorf("%s: expected chain [color-primary], got %v", tok.Name, tok.ResolutionChain)
+		}
+	}
+}
+
+func TestResolveAliases_PrefixedName(t *testing.T) {
+	// The alias comes first, so resolution depends on the graph
+	// ordering the prefixed target before it
+	tokens := []*token.Token{
+		{Name: "button-bg", Value: "{color.base}"},
+		{Name: "rh-color-base", Prefix: "rh", Value: "#00ff00"},
+	}
+
+	if err := resolver.ResolveAliases(tokens, schema.Draft); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tokens[0].ResolvedValue != "#00ff00" {
+		t.Errorf("expected #00ff00, got %v", tokens[0].ResolvedValue)
+	}
+}
+
+func TestFindUnused(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-_red", Path: []string{"color", "_red"}, Value: "#f00"},
+		{Name: "color-_blue", Path: []string{"color", "_blue"}, Value: "#00f"},
+		{Name: "color-_stale", Path: []string{"color", "_stale"}, Value: "#777"},
+		{Name: "color-brand", Path: []string{"color", "brand"}, Value: "{color._red}"},
+		{Name: "button-bg", Path: []string{"button", "bg"}, Value: "{color.brand}"},
+		{Name: "link-fg", Path: []string{"link", "fg"}, Value: "{color._blue}"},
+	}
+
+	unused := resolver.FindUnused(tokens, []string{"button", "color.brand"})
+
+	var names []string
+	for _, tok := range unused {
+		names = append(names, tok.Name)
+	}
+	want := []string{"color-_blue", "color-_stale", "link-fg"}
+	if !slices.Equal(names, want) {
+		t.Errorf("FindUnused() = %v, want %v", names, want)
+	}
+}
+
+func TestFindUnused_GroupPrefixIsNotSubstring(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-red", Path: []string{"color", "red"}, Value: "#f00"},
+		{Name: "colors-red", Path: []string{"colors", "red"}, Value: "#f00"},
+	}
+
+	unused := resolver.FindUnused(tokens, []string{"color"})
+	if len(unused) != 1 || unused[0].Name != "colors-red" {
+		t.Errorf("expected only colors-red to be unused, got %v", unused)
+	}
+}
+
+func TestResolveAliasesWithOptions_Extensions(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/draft/extension-references", "/test")
+	parse := func() []*token.Token {
+		tokens, err := parser.NewJSONParser().ParseFile(mfs, "/test/tokens.json", parser.Options{SchemaVersion: schema.Draft})
+		if err != nil {
+			t.Fatalf("failed to parse: %v", err)
+		}
+		return tokens
+	}
+
+	tokens := parse()
+	if err := resolver.ResolveAliasesWithOptions(tokens, schema.Draft, resolver.ResolveOptions{Extensions: true}); err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+
+	surface := testutil.TokenByPath(t, tokens, "color.surface")
+	modes, ok := surface.ResolvedExtensions["com.example.modes"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected resolved modes, got %v", surface.ResolvedExtensions)
+	}
+	if modes["dark"] != "#0066cc" {
+		t.Errorf("dark = %v, want #0066cc", modes["dark"])
+	}
+	// A reference back to the token itself resolves to its own value.
+	if modes["light"] != "#ffffff" {
+		t.Errorf("light = %v, want #ffffff", modes["light"])
+	}
+	contrast, _ := modes["contrast"].([]any)
+	want := []any{"#0066cc", "{color.missing}", "1px solid {color.brand}"}
+	if !slices.Equal(contrast, want) {
+		t.Errorf("contrast = %v, want %v", contrast, want)
+	}
+
+	raw := surface.Extensions["com.example.modes"].(map[string]any)
+	if raw["dark"] != "{color.brand}" {
+		t.Errorf("expected Extensions to keep the reference, got %v", raw["dark"])
+	}
+
+	// Extension references are opt-in.
+	tokens = parse()
+	if err := resolver.ResolveAliases(tokens, schema.Draft); err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if got := testutil.TokenByPath(t, tokens, "color.surface").ResolvedExtensions; got != nil {
+		t.Errorf("expected no resolved extensions by default, got %v", got)
+	}
+}
+
+func TestResolveAliasesContext_Canceled(t *testing.T) {
+	mfs := testutil.NewFixtureFS(t, "fixtures/draft/simple", "/test")
+	tokens, err := parser.NewJSONParser().ParseFile(mfs, "/test/tokens.json", parser.Options{SchemaVersion: schema.Draft})
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if err := resolver.ResolveAliasesContext(ctx, tokens, schema.Draft); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	for _, tok := range tokens {
+		if tok.IsResolved {
+			t.Errorf("expected %s to stay unresolved after cancellation", tok.DotPath())
+		}
+	}
+
+	if err := resolver.ResolveAliasesContext(t.Context(), tokens, schema.Draft); err != nil {
+		t.Fatalf("ResolveAliasesContext() error = %v", err)
+	}
+	if !testutil.TokenByPath(t, tokens, "color.secondary").IsResolved {
+		t.Error("expected color.secondary to be resolved")
+	}
+}
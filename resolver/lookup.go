@@ -0,0 +1,58 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package resolver
+
+import (
+	"strings"
+
+	"bennypowers.dev/asimonim/token"
+)
+
+// tokenIndex looks up reference targets by name, following the same
+// normalization as token.Map.Get: dot paths and dash names are equivalent,
+// leading dashes are ignored, and a token's prefix is optional on either
+// side of the reference.
+type tokenIndex map[string]*token.Token
+
+func newTokenIndex(tokens []*token.Token) tokenIndex {
+	idx := make(tokenIndex, len(tokens))
+	for _, tok := range tokens {
+		idx[tok.Name] = tok
+	}
+	// Normalized aliases never shadow an exact name
+	for _, tok := range tokens {
+		bare := unprefixedName(tok)
+		if _, ok := idx[bare]; !ok {
+			idx[bare] = tok
+		}
+		if tok.Prefix != "" {
+			full := strings.ReplaceAll(tok.Prefix, ".", "-") + "-" + bare
+			if _, ok := idx[full]; !ok {
+				idx[full] = tok
+			}
+		}
+	}
+	return idx
+}
+
+// lookup returns the token a reference name points to, or nil.
+func (idx tokenIndex) lookup(name string) *token.Token {
+	if tok, ok := idx[name]; ok {
+		return tok
+	}
+	return idx[strings.TrimLeft(strings.ReplaceAll(name, ".", "-"), "-")]
+}
+
+// unprefixedName returns the token's name without leading dashes or its
+// own prefix.
+func unprefixedName(tok *token.Token) string {
+	name := strings.TrimLeft(strings.ReplaceAll(tok.Name, ".", "-"), "-")
+	if tok.Prefix != "" {
+		name = strings.TrimPrefix(name, strings.ReplaceAll(tok.Prefix, ".", "-")+"-")
+	}
+	return name
+}
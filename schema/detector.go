@@ -7,8 +7,11 @@ license that can be found in the LICENSE file.
 package schema
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 
+	"github.com/tidwall/jsonc"
 	"gopkg.in/yaml.v3"
 )
 
@@ -25,16 +28,22 @@ type DetectionConfig struct {
 // 3. Duck typing (detect reserved fields/structured formats)
 // 4. Default to draft (backward compatibility)
 func DetectVersion(content []byte, config *DetectionConfig) (Version, error) {
-	var data map[string]any
-	if err := yaml.Unmarshal(content, &data); err != nil {
-		return Unknown, fmt.Errorf("invalid YAML/JSON: %w", err)
+	roots, err := DecodeRoots(content)
+	if err != nil {
+		// JSONC comments aren't valid YAML, so retry without them
+		var jsoncErr error
+		if roots, jsoncErr = DecodeRoots(jsonc.ToJSON(content)); jsoncErr != nil {
+			return Unknown, fmt.Errorf("invalid YAML/JSON: %w", err)
+		}
 	}
 
 	// 1. Check for explicit $schema field
-	if schemaURL, ok := data["$schema"].(string); ok {
-		version, err := FromURL(schemaURL)
-		if err == nil {
-			return version, nil
+	for _, data := range roots {
+		if schemaURL, ok := data["$schema"].(string); ok {
+			version, err := FromURL(schemaURL)
+			if err == nil {
+				return version, nil
+			}
 		}
 	}
 
@@ -44,14 +53,45 @@ func DetectVersion(content []byte, config *DetectionConfig) (Version, error) {
 	}
 
 	// 3. Duck typing - check for unambiguous 2025.10 features
-	if version := duckTypeSchema(data); version != Unknown {
-		return version, nil
+	for _, data := range roots {
+		if version := duckTypeSchema(data); version != Unknown {
+			return version, nil
+		}
 	}
 
 	// 4. Default to draft for backward compatibility
 	return Draft, nil
 }
 
+// DecodeRoots decodes the root objects of token file content: the root
+// of each document in a YAML stream, or each object of a top-level array.
+func DecodeRoots(content []byte) ([]map[string]any, error) {
+	var roots []map[string]any
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err == io.EOF {
+			return roots, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		nodes := []*yaml.Node{doc.Content[0]}
+		if doc.Content[0].Kind == yaml.SequenceNode {
+			nodes = doc.Content[0].Content
+		}
+		for _, node := range nodes {
+			var data map[string]any
+			if err := node.Decode(&data); err != nil {
+				return nil, err
+			}
+			roots = append(roots, data)
+		}
+	}
+}
+
 // duckTypeSchema attempts to detect schema version from content patterns.
 func duckTypeSchema(data map[string]any) Version {
 	if hasFeature(data, "$ref") {
@@ -192,6 +192,14 @@ func TestDetectVersion(t *testing.T) {
 			content:  `{"$schema": "https://www.designtokens.org/schemas/2025.10.json"}`,
 			expected: schema.V2025_10,
 		},
+		{
+			name: "jsonc comments",
+			content: `{
+  // Generated from Figma
+  "$schema": "https://www.designtokens.org/schemas/2025.10.json"
+}`,
+			expected: schema.V2025_10,
+		},
 		{
 			name:    "config default version",
 			content: `{"color": {"$value": "#fff"}}`,
@@ -265,3 +273,43 @@ func TestDetectVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectVersion_MultipleRoots(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    schema.Version
+	}{
+		{
+			name:    "$schema in a later YAML document",
+			content: "color:\n  $type: color\n---\n$schema: https://www.designtokens.org/schemas/2025.10.json\n",
+			want:    schema.V2025_10,
+		},
+		{
+			name:    "$ref in a later YAML document",
+			content: "a:\n  $value: 1\n---\nb:\n  $value:\n    $ref: \"#/a\"\n",
+			want:    schema.V2025_10,
+		},
+		{
+			name:    "top-level array",
+			content: `[{"a": {"$value": 1}}, {"b": {"$value": {"$ref": "#/a"}}}]`,
+			want:    schema.V2025_10,
+		},
+		{
+			name:    "draft top-level array",
+			content: `[{"a": {"$value": 1}}, {"b": {"$value": "{a}"}}]`,
+			want:    schema.Draft,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := schema.DetectVersion([]byte(tt.content), nil)
+			if err != nil {
+				t.Fatalf("DetectVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
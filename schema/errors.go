@@ -28,6 +28,9 @@ var (
 	// ErrCircularReference indicates a circular reference was detected.
 	ErrCircularReference = errors.New("circular reference detected")
 
+	// ErrSelfReference indicates a token references its own path.
+	ErrSelfReference = errors.New("token references itself")
+
 	// ErrUnresolvedReference indicates a reference could not be resolved.
 	ErrUnresolvedReference = errors.New("unresolved token reference")
 )
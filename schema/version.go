@@ -35,6 +35,34 @@ func (v Version) String() string {
 	}
 }
 
+// Versions returns the known schema versions, oldest first.
+func Versions() []Version {
+	return []Version{Draft, V2025_10}
+}
+
+// Features returns short descriptions of what token files written
+// against this version can use, for display to users choosing a version.
+func (v Version) Features() []string {
+	switch v {
+	case Draft:
+		return []string{
+			"{token.path} references",
+			"string colors",
+			"group markers for root tokens",
+		}
+	case V2025_10:
+		return []string{
+			"{token.path} references",
+			"$ref JSON Pointer references",
+			"$extends group inheritance",
+			"$root tokens",
+			"structured colors",
+		}
+	default:
+		return nil
+	}
+}
+
 // URL returns the JSON Schema URL for this version.
 func (v Version) URL() string {
 	switch v {
@@ -107,3 +107,24 @@ func TestFromString(t *testing.T) {
 		})
 	}
 }
+
+func TestVersions_RoundTrip(t *testing.T) {
+	for _, v := range schema.Versions() {
+		t.Run(v.String(), func(t *testing.T) {
+			fromString, err := schema.FromString(v.String())
+			if err != nil || fromString != v {
+				t.Errorf("FromString(%q) = %v, %v; want %v", v.String(), fromString, err, v)
+			}
+			fromURL, err := schema.FromURL(v.URL())
+			if err != nil || fromURL != v {
+				t.Errorf("FromURL(%q) = %v, %v; want %v", v.URL(), fromURL, err, v)
+			}
+			if len(v.Features()) == 0 {
+				t.Error("expected features")
+			}
+		})
+	}
+	if features := schema.Unknown.Features(); features != nil {
+		t.Errorf("Unknown.Features() = %v, want nil", features)
+	}
+}
@@ -6,6 +6,13 @@ license that can be found in the LICENSE file.
 
 package specifier
 
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
 // LocalResolver handles local filesystem paths (non-package specifiers).
 type LocalResolver struct{}
 
@@ -14,11 +21,19 @@ func NewLocalResolver() *LocalResolver {
 	return &LocalResolver{}
 }
 
-// Resolve returns the path unchanged for local files.
+// Resolve returns the path unchanged for local files. file:// URLs, such
+// as LSP document URIs, are converted to OS paths.
 func (r *LocalResolver) Resolve(spec string) (*ResolvedFile, error) {
+	path := spec
+	if IsFileURL(spec) {
+		var err error
+		if path, err = FileURLToPath(spec); err != nil {
+			return nil, err
+		}
+	}
 	return &ResolvedFile{
 		Specifier: spec,
-		Path:      spec,
+		Path:      path,
 		Kind:      KindLocal,
 	}, nil
 }
@@ -27,3 +42,47 @@ func (r *LocalResolver) Resolve(spec string) (*ResolvedFile, error) {
 func (r *LocalResolver) CanResolve(spec string) bool {
 	return !IsPackageSpecifier(spec)
 }
+
+// IsFileURL reports whether spec is a file:// URL.
+func IsFileURL(spec string) bool {
+	return len(spec) >= len("file://") && strings.EqualFold(spec[:len("file://")], "file://")
+}
+
+// FileURLToPath converts a file:// URL to an OS path, decoding
+// percent-escapes. A Windows drive letter keeps its colon, so
+// file:///C:/tokens.json is C:\tokens.json on Windows. A host other than
+// localhost names a UNC share: file://server/share is \\server\share.
+func FileURLToPath(fileURL string) (string, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid file URL %s: %w", fileURL, err)
+	}
+	if !strings.EqualFold(u.Scheme, "file") {
+		return "", fmt.Errorf("not a file URL: %s", fileURL)
+	}
+
+	path := u.Path
+	switch {
+	case isDriveLetter(u.Host):
+		// Nonstandard file://C:/path
+		path = u.Host + path
+	case u.Host != "" && !strings.EqualFold(u.Host, "localhost"):
+		path = "//" + u.Host + path
+	case len(path) >= 3 && path[0] == '/' && isDriveLetter(path[1:3]):
+		// file:///C:/path
+		path = path[1:]
+	}
+	if path == "" {
+		return "", fmt.Errorf("file URL has no path: %s", fileURL)
+	}
+	return filepath.FromSlash(path), nil
+}
+
+// isDriveLetter reports whether s is a Windows drive, e.g. C:.
+func isDriveLetter(s string) bool {
+	if len(s) != 2 || s[1] != ':' {
+		return false
+	}
+	ch := s[0]
+	return (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z')
+}
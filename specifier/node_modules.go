@@ -7,6 +7,7 @@ license that can be found in the LICENSE file.
 package specifier
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -57,10 +58,12 @@ func (r *NodeModulesResolver) Resolve(spec string) (*ResolvedFile, error) {
 		}
 
 		if r.fs.Exists(nodeModulesPath) {
+			packageDir := filepath.Join(nodeModulesBase, parsed.Package)
 			return &ResolvedFile{
 				Specifier: spec,
 				Path:      nodeModulesPath,
 				Kind:      KindNPM,
+				Version:   r.packageVersion(filepath.Dir(nodeModulesPath), packageDir),
 			}, nil
 		}
 
@@ -76,6 +79,27 @@ func (r *NodeModulesResolver) Resolve(spec string) (*ResolvedFile, error) {
 	return nil, fmt.Errorf("package not found: %s (looked in node_modules starting from %s)", parsed.Package, startDir)
 }
 
+// packageVersion returns the version from the nearest package.json at or
+// above dir, without leaving packageDir. Returns "" if none declares one.
+func (r *NodeModulesResolver) packageVersion(dir, packageDir string) string {
+	for isInsideDir(dir, packageDir) {
+		data, err := r.fs.ReadFile(filepath.Join(dir, "package.json"))
+		if err == nil {
+			var pkg struct {
+				Version string `json:"version"`
+			}
+			if json.Unmarshal(data, &pkg) == nil && pkg.Version != "" {
+				return pkg.Version
+			}
+		}
+		if dir == packageDir {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	return ""
+}
+
 // isInsideDir checks if path is inside baseDir (no path traversal escape).
 func isInsideDir(path, baseDir string) bool {
 	rel, err := filepath.Rel(baseDir, path)
@@ -18,6 +18,11 @@ type ResolvedFile struct {
 
 	// Kind indicates the type of specifier (KindNPM, KindJSR, KindLocal).
 	Kind Kind
+
+	// Version is the version of the package the file was resolved from,
+	// read from its package.json. Empty for local files or when the
+	// package does not declare a version.
+	Version string
 }
 
 // Resolver resolves specifiers to filesystem paths.
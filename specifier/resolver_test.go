@@ -7,6 +7,7 @@ license that can be found in the LICENSE file.
 package specifier
 
 import (
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -40,10 +41,73 @@ func TestLocalResolver_Passthrough(t *testing.T) {
 			if rf.Kind != KindLocal {
 				t.Errorf("Kind = %v, want KindLocal", rf.Kind)
 			}
+			if rf.Version != "" {
+				t.Errorf("Version = %q, want empty", rf.Version)
+			}
 		})
 	}
 }
 
+func TestLocalResolver_FileURL(t *testing.T) {
+	resolver := NewLocalResolver()
+
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{"posix", "file:///home/user/tokens.json", "/home/user/tokens.json"},
+		{"escaped", "file:///home/user/my%20tokens.json", "/home/user/my tokens.json"},
+		{"localhost", "file://localhost/home/user/tokens.json", "/home/user/tokens.json"},
+		{"windows drive", "file:///C:/Users/me/tokens.json", filepath.FromSlash("C:/Users/me/tokens.json")},
+		{"encoded drive colon", "file:///c%3A/Users/me/tokens.json", filepath.FromSlash("c:/Users/me/tokens.json")},
+		{"unc share", "file://server/share/tokens.json", filepath.FromSlash("//server/share/tokens.json")},
+		{"uppercase scheme", "FILE:///home/user/tokens.json", "/home/user/tokens.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !resolver.CanResolve(tt.spec) {
+				t.Fatalf("expected CanResolve for %s", tt.spec)
+			}
+			rf, err := resolver.Resolve(tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rf.Path != tt.want {
+				t.Errorf("Path = %q, want %q", rf.Path, tt.want)
+			}
+			if rf.Specifier != tt.spec {
+				t.Errorf("Specifier = %q, want %q", rf.Specifier, tt.spec)
+			}
+			if rf.Kind != KindLocal {
+				t.Errorf("Kind = %v, want KindLocal", rf.Kind)
+			}
+		})
+	}
+
+	if _, err := resolver.Resolve("file://%zz/tokens.json"); err == nil {
+		t.Error("expected error for malformed file URL")
+	}
+}
+
+func TestDefaultResolver_FileURL(t *testing.T) {
+	mfs := mapfs.New()
+	mfs.AddFile("/project/tokens.json", `{}`, 0644)
+
+	resolver, err := NewDefaultResolver(mfs, "/project")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rf, err := resolver.Resolve("file:///project/tokens.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rf.Path != filepath.FromSlash("/project/tokens.json") || rf.Kind != KindLocal {
+		t.Errorf("got Path %q, Kind %v", rf.Path, rf.Kind)
+	}
+}
+
 func TestLocalResolver_CanResolve(t *testing.T) {
 	resolver := NewLocalResolver()
 
@@ -134,6 +198,84 @@ func TestNodeModulesResolver_WalksUpDirectoryTree(t *testing.T) {
 	}
 }
 
+func TestNodeModulesResolver_Version(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		spec  string
+		want  string
+	}{
+		{
+			name: "package root version",
+			files: map[string]string{
+				"/project/node_modules/@rhds/tokens/package.json":          `{"name":"@rhds/tokens","version":"3.1.0"}`,
+				"/project/node_modules/@rhds/tokens/json/rhds.tokens.json": `{}`,
+			},
+			spec: "npm:@rhds/tokens/json/rhds.tokens.json",
+			want: "3.1.0",
+		},
+		{
+			name: "nearest package.json wins",
+			files: map[string]string{
+				"/project/node_modules/tokens/package.json":      `{"version":"2.0.0"}`,
+				"/project/node_modules/tokens/dist/package.json": `{"version":"2.0.0-dist"}`,
+				"/project/node_modules/tokens/dist/tokens.json":  `{}`,
+			},
+			spec: "npm:tokens/dist/tokens.json",
+			want: "2.0.0-dist",
+		},
+		{
+			name: "nested package.json without version",
+			files: map[string]string{
+				"/project/node_modules/tokens/package.json":     `{"version":"2.0.0"}`,
+				"/project/node_modules/tokens/esm/package.json": `{"type":"module"}`,
+				"/project/node_modules/tokens/esm/tokens.json":  `{}`,
+			},
+			spec: "npm:tokens/esm/tokens.json",
+			want: "2.0.0",
+		},
+		{
+			name: "missing package.json",
+			files: map[string]string{
+				"/project/node_modules/tokens/tokens.json": `{}`,
+			},
+			spec: "npm:tokens/tokens.json",
+			want: "",
+		},
+		{
+			name: "malformed package.json",
+			files: map[string]string{
+				"/project/node_modules/tokens/package.json": `{`,
+				"/project/node_modules/tokens/tokens.json":  `{}`,
+			},
+			spec: "npm:tokens/tokens.json",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mfs := mapfs.New()
+			for path, content := range tt.files {
+				mfs.AddFile(path, content, 0644)
+			}
+
+			resolver, err := NewNodeModulesResolver(mfs, "/project")
+			if err != nil {
+				t.Fatalf("failed to create resolver: %v", err)
+			}
+
+			rf, err := resolver.Resolve(tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rf.Version != tt.want {
+				t.Errorf("Version = %q, want %q", rf.Version, tt.want)
+			}
+		})
+	}
+}
+
 func TestNodeModulesResolver_PackageNotFound(t *testing.T) {
 	mfs := mapfs.New()
 	mfs.AddDir("/project", 0755)
@@ -0,0 +1,9 @@
+// Generated by asimonim
+// Do not edit manually
+
+export const borderFocus = {"color":"#0066cc","style":"solid","width":"2px"} as const;
+export const colorShadow = "#00000033" as const;
+export const shadowLayered = [{"blur":"2px","color":"#0000001a","offsetX":"0px","offsetY":"1px","spread":"0px"},{"blur":"16px","color":"#00000026","inset":false,"offsetX":"0px","offsetY":"8px","spread":"-4px"}] as const;
+export const shadowRaised = {"blur":"4px","color":"{color.shadow}","offsetX":"0px","offsetY":"2px","spread":"0px"} as const;
+export const transitionFade = {"delay":"0ms","duration":"200ms","timingFunction":[0.4,0,0.2,1]} as const;
+export const typographyBody = {"fontFamily":["Red Hat Text","sans-serif"],"fontSize":"16px","fontWeight":400,"letterSpacing":"0px","lineHeight":1.5} as const;
@@ -0,0 +1,273 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package token
+
+import (
+	"strconv"
+	"strings"
+
+	"bennypowers.dev/asimonim/parser/common"
+	"bennypowers.dev/asimonim/schema"
+)
+
+// CSSValue returns the token's value as a CSS value string.
+// Unlike DisplayValue, which may fall back to JSON, the result is always
+// usable as a CSS property value: structured colors become CSS color
+// functions, composites become shorthands (box-shadow, border, transition,
+// font, linear-gradient), and unresolved references become var() calls.
+// It returns false when the value has no CSS representation.
+func (t *Token) CSSValue() (string, bool) {
+	var val any
+	if t.IsResolved && t.ResolvedValue != nil {
+		val = t.ResolvedValue
+	} else if t.RawValue != nil {
+		val = t.RawValue
+	} else {
+		val = t.Value
+	}
+
+	if m, ok := val.(map[string]any); ok {
+		if ref, ok := m["$ref"].(string); ok && t.SchemaVersion != schema.Draft {
+			val = ref
+		}
+	}
+
+	s := t.cssValue(val)
+	if s == "" {
+		return "", false
+	}
+	return t.cssReferences(s), true
+}
+
+// cssValue formats val for the token's type, or returns "" if it has no
+// CSS representation.
+func (t *Token) cssValue(val any) string {
+	if s, ok := val.(string); ok {
+		if path, ok := ParseJSONPointerRef(s); ok && t.SchemaVersion != schema.Draft {
+			return t.cssVar(path)
+		}
+		if t.Type == TypeFontFamily {
+			return quoteFontFamily(s)
+		}
+		return s
+	}
+
+	switch t.Type {
+	case TypeColor:
+		return cssColor(val)
+	case TypeDimension, TypeDuration:
+		if n := cssNumber(val); n == "0" {
+			return n
+		}
+		return formatDimension(val)
+	case TypeNumber:
+		return formatNumber(val)
+	case TypeFontWeight:
+		return cssNumber(val)
+	case TypeCubicBezier:
+		return formatCubicBezier(val)
+	case TypeFontFamily:
+		return cssFontFamily(val)
+	case TypeShadow:
+		return cssShadow(val)
+	case TypeBorder:
+		return cssBorder(val)
+	case TypeTransition:
+		return formatTransition(val)
+	case TypeGradient:
+		return cssGradient(val)
+	case TypeTypography:
+		return cssFont(val)
+	case TypeBoolean:
+		if b, ok := val.(bool); ok {
+			return strconv.FormatBool(b)
+		}
+	}
+	return ""
+}
+
+// cssReferences replaces {token.path} references in s with var() calls.
+func (t *Token) cssReferences(s string) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+	return curlyBracePattern.ReplaceAllStringFunc(s, func(match string) string {
+		return t.cssVar(match[1 : len(match)-1])
+	})
+}
+
+// cssVar returns a var() call for the token at path, using this token's prefix.
+func (t *Token) cssVar(path string) string {
+	path = strings.TrimSuffix(path, ".$root")
+	ref := &Token{Name: strings.ReplaceAll(path, ".", "-"), Prefix: t.Prefix}
+	return "var(" + ref.CSSVariableName() + ")"
+}
+
+func cssColor(val any) string {
+	m, ok := val.(map[string]any)
+	if !ok {
+		return ""
+	}
+	// Structured color objects are a v2025.10 feature
+	colorVal, err := common.ParseColorValue(m, schema.V2025_10)
+	if err != nil {
+		return ""
+	}
+	return colorVal.ToCSS()
+}
+
+func cssNumber(val any) string {
+	switch v := val.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return ""
+	}
+}
+
+// quoteFontFamily quotes a single font family name that contains spaces.
+func quoteFontFamily(s string) string {
+	if strings.Contains(s, " ") && !strings.HasPrefix(s, `"`) && !strings.HasPrefix(s, "'") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func cssFontFamily(val any) string {
+	arr, ok := val.([]any)
+	if !ok {
+		return ""
+	}
+	parts := make([]string, 0, len(arr))
+	for _, f := range arr {
+		s, ok := f.(string)
+		if !ok {
+			return ""
+		}
+		parts = append(parts, quoteFontFamily(s))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// cssShadow formats a shadow as a box-shadow value, adding the inset
+// keyword that the display format omits.
+func cssShadow(val any) string {
+	layers, ok := val.([]any)
+	if !ok {
+		layers = []any{val}
+	}
+	if len(layers) == 0 {
+		return ""
+	}
+	shadows := make([]string, 0, len(layers))
+	for _, layer := range layers {
+		m, ok := layer.(map[string]any)
+		if !ok {
+			return ""
+		}
+		shadow := formatSingleShadow(m)
+		if shadow == "" {
+			return ""
+		}
+		if inset, _ := m["inset"].(bool); inset {
+			shadow = "inset " + shadow
+		}
+		shadows = append(shadows, shadow)
+	}
+	return strings.Join(shadows, ", ")
+}
+
+// cssBorder formats a border shorthand. Object stroke styles (dash
+// arrays) have no border-style equivalent.
+func cssBorder(val any) string {
+	m, ok := val.(map[string]any)
+	if !ok {
+		return ""
+	}
+	if _, ok := m["style"].(string); !ok {
+		return ""
+	}
+	return formatBorder(m)
+}
+
+// cssGradient formats gradient stops as a linear-gradient().
+func cssGradient(val any) string {
+	stops, ok := val.([]any)
+	if !ok || len(stops) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(stops))
+	for _, stop := range stops {
+		m, ok := stop.(map[string]any)
+		if !ok {
+			return ""
+		}
+		color := formatColorField(m["color"])
+		if color == "" {
+			return ""
+		}
+		switch pos := m["position"].(type) {
+		case float64:
+			color += " " + strconv.FormatFloat(pos*100, 'f', -1, 64) + "%"
+		case string:
+			color += " " + pos
+		}
+		parts = append(parts, color)
+	}
+	return "linear-gradient(" + strings.Join(parts, ", ") + ")"
+}
+
+// cssFont formats a typography value as a font shorthand. Letter spacing
+// is not part of the shorthand and is dropped.
+func cssFont(val any) string {
+	m, ok := val.(map[string]any)
+	if !ok {
+		return ""
+	}
+	size := formatDimensionField(m["fontSize"])
+	family := ""
+	switch f := m["fontFamily"].(type) {
+	case string:
+		family = quoteFontFamily(f)
+	default:
+		family = cssFontFamily(f)
+	}
+	if size == "" || family == "" {
+		return ""
+	}
+
+	var parts []string
+	if style, ok := m["fontStyle"].(string); ok {
+		parts = append(parts, style)
+	}
+	switch w := m["fontWeight"].(type) {
+	case string:
+		parts = append(parts, w)
+	default:
+		if s := cssNumber(w); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	switch lh := m["lineHeight"].(type) {
+	case string:
+		size += "/" + lh
+	case map[string]any:
+		if s := formatDimension(lh); s != "" {
+			size += "/" + s
+		}
+	default:
+		if s := cssNumber(lh); s != "" {
+			size += "/" + s
+		}
+	}
+	parts = append(parts, size, family)
+	return strings.Join(parts, " ")
+}
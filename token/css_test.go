@@ -0,0 +1,213 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package token_test
+
+import (
+	"testing"
+
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/token"
+)
+
+func TestToken_CSSValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  token.Token
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "string value",
+			token:  token.Token{Type: token.TypeColor, Value: "#ff0000"},
+			want:   "#ff0000",
+			wantOK: true,
+		},
+		{
+			name: "resolved value takes precedence",
+			token: token.Token{
+				Type:          token.TypeColor,
+				Value:         "{color.primary}",
+				ResolvedValue: "#0000ff",
+				IsResolved:    true,
+			},
+			want:   "#0000ff",
+			wantOK: true,
+		},
+		{
+			name:   "ratio number passes through",
+			token:  token.Token{Type: token.TypeNumber, Value: "16/9"},
+			want:   "16/9",
+			wantOK: true,
+		},
+		{
+			name: "percentage number",
+			token: token.Token{
+				Type:     token.TypeNumber,
+				RawValue: map[string]any{"value": 12.5, "unit": "%"},
+			},
+			want:   "12.5%",
+			wantOK: true,
+		},
+		{
+			name: "number with unknown unit",
+			token: token.Token{
+				Type:     token.TypeNumber,
+				RawValue: map[string]any{"value": 2.0, "unit": "x"},
+			},
+			wantOK: false,
+		},
+		{
+			name:   "curly brace reference becomes var()",
+			token:  token.Token{Type: token.TypeColor, Value: "{color.brand.primary}", Prefix: "rh"},
+			want:   "var(--rh-color-brand-primary)",
+			wantOK: true,
+		},
+		{
+			name: "JSON pointer reference becomes var()",
+			token: token.Token{
+				Type:          token.TypeColor,
+				RawValue:      map[string]any{"$ref": "#/color/primary/$root"},
+				SchemaVersion: schema.V2025_10,
+			},
+			want:   "var(--color-primary)",
+			wantOK: true,
+		},
+		{
+			name: "structured color",
+			token: token.Token{
+				Type: token.TypeColor,
+				RawValue: map[string]any{
+					"colorSpace": "oklch",
+					"components": []any{0.7, 0.15, 180.0},
+					"alpha":      0.8,
+				},
+				SchemaVersion: schema.V2025_10,
+			},
+			want:   "oklch(0.7 0.15 180 / 0.8)",
+			wantOK: true,
+		},
+		{
+			name:   "unparseable structured color",
+			token:  token.Token{Type: token.TypeColor, RawValue: map[string]any{"foo": "bar"}},
+			wantOK: false,
+		},
+		{
+			name:   "structured dimension",
+			token:  token.Token{Type: token.TypeDimension, RawValue: map[string]any{"value": 1.5, "unit": "rem"}},
+			want:   "1.5rem",
+			wantOK: true,
+		},
+		{
+			name:   "unitless zero dimension",
+			token:  token.Token{Type: token.TypeDimension, RawValue: 0.0},
+			want:   "0",
+			wantOK: true,
+		},
+		{
+			name:   "dimension without value",
+			token:  token.Token{Type: token.TypeDimension, RawValue: map[string]any{"value": nil, "unit": "px"}},
+			wantOK: false,
+		},
+		{
+			name:   "integer-valued number",
+			token:  token.Token{Type: token.TypeFontWeight, RawValue: 700.0},
+			want:   "700",
+			wantOK: true,
+		},
+		{
+			name:   "font family list",
+			token:  token.Token{Type: token.TypeFontFamily, RawValue: []any{"Red Hat Text", "sans-serif"}},
+			want:   `"Red Hat Text", sans-serif`,
+			wantOK: true,
+		},
+		{
+			name: "inset shadow",
+			token: token.Token{
+				Type: token.TypeShadow,
+				RawValue: map[string]any{
+					"offsetX": "0px", "offsetY": "1px", "blur": "2px",
+					"color": "#000", "inset": true,
+				},
+			},
+			want:   "inset 0px 1px 2px #000",
+			wantOK: true,
+		},
+		{
+			name: "border with reference",
+			token: token.Token{
+				Type:     token.TypeBorder,
+				RawValue: map[string]any{"width": "1px", "style": "solid", "color": "{color.primary}"},
+			},
+			want:   "1px solid var(--color-primary)",
+			wantOK: true,
+		},
+		{
+			name: "border with dash array style",
+			token: token.Token{
+				Type: token.TypeBorder,
+				RawValue: map[string]any{
+					"width": "1px",
+					"style": map[string]any{"dashArray": []any{"2px"}, "lineCap": "round"},
+					"color": "#000",
+				},
+			},
+			wantOK: false,
+		},
+		{
+			name: "gradient",
+			token: token.Token{
+				Type: token.TypeGradient,
+				RawValue: []any{
+					map[string]any{"color": "#fff", "position": 0.0},
+					map[string]any{"color": "#000", "position": 1.0},
+				},
+			},
+			want:   "linear-gradient(#fff 0%, #000 100%)",
+			wantOK: true,
+		},
+		{
+			name: "typography",
+			token: token.Token{
+				Type: token.TypeTypography,
+				RawValue: map[string]any{
+					"fontFamily":    []any{"Red Hat Display", "sans-serif"},
+					"fontSize":      map[string]any{"value": 1.5, "unit": "rem"},
+					"fontWeight":    500.0,
+					"lineHeight":    1.2,
+					"letterSpacing": "0.01em",
+				},
+			},
+			want:   `500 1.5rem/1.2 "Red Hat Display", sans-serif`,
+			wantOK: true,
+		},
+		{
+			name: "typography without font size",
+			token: token.Token{
+				Type:     token.TypeTypography,
+				RawValue: map[string]any{"fontFamily": "Arial"},
+			},
+			wantOK: false,
+		},
+		{
+			name:   "untyped map",
+			token:  token.Token{RawValue: map[string]any{"a": 1.0}},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.token.CSSValue()
+			if ok != tt.wantOK {
+				t.Fatalf("Token.CSSValue() ok = %v, want %v (value %q)", ok, tt.wantOK, got)
+			}
+			if got != tt.want {
+				t.Errorf("Token.CSSValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
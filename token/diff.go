@@ -0,0 +1,115 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package token
+
+import (
+	"sort"
+)
+
+// MapDiff describes the differences between two token maps.
+// Each list is sorted by dot path.
+type MapDiff struct {
+	// Added holds tokens only in the other map.
+	Added []*Token
+
+	// Removed holds tokens only in the receiver.
+	Removed []*Token
+
+	// Changed holds tokens in both maps that aren't Equal.
+	Changed []TokenChange
+}
+
+// IsEmpty reports whether the maps had no differences.
+func (d MapDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// TokenChange describes one token that differs between two maps.
+type TokenChange struct {
+	// Path is the token's dot path.
+	Path string
+
+	// Old and New are the token in the receiver and in the other map.
+	Old, New *Token
+
+	// OldValue and NewValue are the tokens' display values.
+	OldValue, NewValue string
+
+	// OldType and NewType are the tokens' $type.
+	OldType, NewType string
+
+	// OldDeprecated and NewDeprecated are the tokens' deprecation status.
+	OldDeprecated, NewDeprecated bool
+}
+
+// Diff compares m to other, matching tokens by dot path so that maps
+// with different prefixes compare equal. Tokens are compared with
+// Equal, which compares values by DisplayValue, so resolve both maps'
+// aliases first to compare what each token resolves to rather than how
+// it is written.
+func (m *Map) Diff(other *Map) MapDiff {
+	old := m.byPath()
+	next := other.byPath()
+
+	var d MapDiff
+	for path, tok := range old {
+		newTok, ok := next[path]
+		if !ok {
+			d.Removed = append(d.Removed, tok)
+			continue
+		}
+		change := TokenChange{
+			Path:          path,
+			Old:           tok,
+			New:           newTok,
+			OldValue:      tok.DisplayValue(),
+			NewValue:      newTok.DisplayValue(),
+			OldType:       tok.Type,
+			NewType:       newTok.Type,
+			OldDeprecated: tok.Deprecated,
+			NewDeprecated: newTok.Deprecated,
+		}
+		if !tok.Equal(newTok) {
+			d.Changed = append(d.Changed, change)
+		}
+	}
+	for path, tok := range next {
+		if _, ok := old[path]; !ok {
+			d.Added = append(d.Added, tok)
+		}
+	}
+
+	sortByPath(d.Added)
+	sortByPath(d.Removed)
+	sort.Slice(d.Changed, func(i, j int) bool {
+		return d.Changed[i].Path < d.Changed[j].Path
+	})
+	return d
+}
+
+// byPath indexes the map's tokens by dot path, falling back to the
+// token name for tokens without a path.
+func (m *Map) byPath() map[string]*Token {
+	result := make(map[string]*Token, len(m.tokens))
+	for _, tok := range m.tokens {
+		result[diffKey(tok)] = tok
+	}
+	return result
+}
+
+func diffKey(tok *Token) string {
+	if len(tok.Path) > 0 {
+		return tok.DotPath()
+	}
+	return tok.Name
+}
+
+func sortByPath(tokens []*Token) {
+	sort.Slice(tokens, func(i, j int) bool {
+		return diffKey(tokens[i]) < diffKey(tokens[j])
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package token_test
+
+import (
+	"testing"
+
+	"bennypowers.dev/asimonim/token"
+)
+
+func TestMap_Diff(t *testing.T) {
+	old := token.NewMap([]*token.Token{
+		{Name: "color-primary", Path: []string{"color", "primary"}, Type: token.TypeColor, Value: "#ff0000"},
+		{Name: "color-secondary", Path: []string{"color", "secondary"}, Type: token.TypeColor, Value: "#00ff00"},
+		{Name: "space-sm", Path: []string{"space", "sm"}, Type: token.TypeDimension, Value: "4px"},
+		{Name: "space-md", Path: []string{"space", "md"}, Type: token.TypeDimension, Value: "8px"},
+		{Name: "radius", Path: []string{"radius"}, Type: token.TypeDimension, Value: "2px"},
+	}, "")
+	next := token.NewMap([]*token.Token{
+		{Name: "color-primary", Path: []string{"color", "primary"}, Type: token.TypeColor, Value: "#ff0000"},
+		{Name: "color-secondary", Path: []string{"color", "secondary"}, Type: token.TypeColor, Value: "#0000ff"},
+		{Name: "space-sm", Path: []string{"space", "sm"}, Type: token.TypeNumber, Value: "4px"},
+		{Name: "space-md", Path: []string{"space", "md"}, Type: token.TypeDimension, Value: "8px", Deprecated: true},
+		{Name: "space-lg", Path: []string{"space", "lg"}, Type: token.TypeDimension, Value: "16px"},
+	}, "rh")
+
+	d := old.Diff(next)
+
+	if len(d.Added) != 1 || d.Added[0].DotPath() != "space.lg" {
+		t.Errorf("Added = %v, want [space.lg]", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].DotPath() != "radius" {
+		t.Errorf("Removed = %v, want [radius]", d.Removed)
+	}
+
+	want := []token.TokenChange{
+		{Path: "color.secondary", OldValue: "#00ff00", NewValue: "#0000ff", OldType: token.TypeColor, NewType: token.TypeColor},
+		{Path: "space.md", OldValue: "8px", NewValue: "8px", OldType: token.TypeDimension, NewType: token.TypeDimension, NewDeprecated: true},
+		{Path: "space.sm", OldValue: "4px", NewValue: "4px", OldType: token.TypeDimension, NewType: token.TypeNumber},
+	}
+	if len(d.Changed) != len(want) {
+		t.Fatalf("Changed has %d entries, want %d: %+v", len(d.Changed), len(want), d.Changed)
+	}
+	for i, c := range d.Changed {
+		if c.Old == nil || c.New == nil {
+			t.Errorf("Changed[%d] missing tokens: %+v", i, c)
+		}
+		c.Old, c.New = nil, nil
+		if c != want[i] {
+			t.Errorf("Changed[%d] = %+v, want %+v", i, c, want[i])
+		}
+	}
+}
+
+func TestMap_Diff_Identical(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-primary", Path: []string{"color", "primary"}, Type: token.TypeColor, Value: "#ff0000"},
+	}
+	d := token.NewMap(tokens, "").Diff(token.NewMap(tokens, "ds"))
+	if !d.IsEmpty() {
+		t.Errorf("expected no differences, got %+v", d)
+	}
+}
+
+func TestMap_Diff_Metadata(t *testing.T) {
+	old := token.NewMap([]*token.Token{
+		{Name: "color-primary", Path: []string{"color", "primary"}, Type: token.TypeColor, Value: "#ff0000", Line: 3},
+		{Name: "color-old", Path: []string{"color", "old"}, Type: token.TypeColor, Value: "#aa0000", Deprecated: true},
+		{Name: "color-moved", Path: []string{"color", "moved"}, Type: token.TypeColor, Value: "#00ff00", FilePath: "a.json", Line: 4},
+	}, "")
+	next := token.NewMap([]*token.Token{
+		{Name: "color-primary", Path: []string{"color", "primary"}, Type: token.TypeColor, Value: "#ff0000", Description: "Brand red", Line: 3},
+		{Name: "color-old", Path: []string{"color", "old"}, Type: token.TypeColor, Value: "#aa0000", Deprecated: true, DeprecationMessage: "Use color.primary"},
+		{Name: "color-moved", Path: []string{"color", "moved"}, Type: token.TypeColor, Value: "#00ff00", FilePath: "b.json", Line: 9},
+	}, "")
+
+	d := old.Diff(next)
+
+	// Positions don't count, descriptions and deprecation messages do
+	var paths []string
+	for _, c := range d.Changed {
+		paths = append(paths, c.Path)
+	}
+	if len(paths) != 2 || paths[0] != "color.old" || paths[1] != "color.primary" {
+		t.Errorf("Changed = %v, want [color.old color.primary]", paths)
+	}
+}
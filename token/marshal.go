@@ -0,0 +1,147 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"bennypowers.dev/asimonim/schema"
+)
+
+// mapFormat versions the JSON form of a Map, so caches written by another
+// version of asimonim are rejected rather than misread.
+const mapFormat = 1
+
+// mapJSON is the JSON form of a Map.
+type mapJSON struct {
+	Format int                   `json:"format"`
+	Prefix string                `json:"prefix,omitempty"`
+	Tokens map[string]*tokenJSON `json:"tokens"`
+}
+
+// tokenJSON is the JSON form of a Token, including the fields Token's own
+// JSON form leaves out.
+type tokenJSON struct {
+	Name               string         `json:"name"`
+	Value              string         `json:"value,omitempty"`
+	Type               string         `json:"type,omitempty"`
+	TypeFromAlias      bool           `json:"typeFromAlias,omitempty"`
+	Description        string         `json:"description,omitempty"`
+	Extensions         map[string]any `json:"extensions,omitempty"`
+	Deprecated         bool           `json:"deprecated,omitempty"`
+	DeprecationMessage string         `json:"deprecationMessage,omitempty"`
+	Replacement        string         `json:"replacement,omitempty"`
+	FilePath           string         `json:"filePath,omitempty"`
+	Prefix             string         `json:"prefix,omitempty"`
+	Path               []string       `json:"path,omitempty"`
+	DefinitionURI      string         `json:"definitionUri,omitempty"`
+	Line               uint32         `json:"line,omitempty"`
+	Character          uint32         `json:"character,omitempty"`
+	Reference          string         `json:"reference,omitempty"`
+	SchemaVersion      string         `json:"schema,omitempty"`
+	RawValue           any            `json:"rawValue,omitempty"`
+	ResolvedValue      any            `json:"resolvedValue,omitempty"`
+	ResolvedExtensions map[string]any `json:"resolvedExtensions,omitempty"`
+	IsResolved         bool           `json:"isResolved,omitempty"`
+	ResolutionChain    []string       `json:"resolutionChain,omitempty"`
+}
+
+// MarshalJSON encodes the map with the full state of its tokens,
+// including positions and resolution state, so it can be cached and
+// reloaded with UnmarshalJSON instead of parsing and resolving again.
+//
+// Values must be JSON-like, as parsed values are. Numbers are decoded
+// as float64, as when parsing JSON.
+func (m *Map) MarshalJSON() ([]byte, error) {
+	out := mapJSON{
+		Format: mapFormat,
+		Prefix: m.prefix,
+		Tokens: make(map[string]*tokenJSON, len(m.tokens)),
+	}
+	for key, t := range m.tokens {
+		var version string
+		if t.SchemaVersion != schema.Unknown {
+			version = t.SchemaVersion.String()
+		}
+		out.Tokens[key] = &tokenJSON{
+			Name:               t.Name,
+			Value:              t.Value,
+			Type:               t.Type,
+			TypeFromAlias:      t.TypeFromAlias,
+			Description:        t.Description,
+			Extensions:         t.Extensions,
+			Deprecated:         t.Deprecated,
+			DeprecationMessage: t.DeprecationMessage,
+			Replacement:        t.Replacement,
+			FilePath:           t.FilePath,
+			Prefix:             t.Prefix,
+			Path:               t.Path,
+			DefinitionURI:      t.DefinitionURI,
+			Line:               t.Line,
+			Character:          t.Character,
+			Reference:          t.Reference,
+			SchemaVersion:      version,
+			RawValue:           t.RawValue,
+			ResolvedValue:      t.ResolvedValue,
+			ResolvedExtensions: t.ResolvedExtensions,
+			IsResolved:         t.IsResolved,
+			ResolutionChain:    t.ResolutionChain,
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a map written by MarshalJSON, replacing the
+// contents of m.
+func (m *Map) UnmarshalJSON(data []byte) error {
+	var in mapJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.Format != mapFormat {
+		return fmt.Errorf("unsupported token map format %d, want %d", in.Format, mapFormat)
+	}
+
+	tokens := make(map[string]*Token, len(in.Tokens))
+	for key, t := range in.Tokens {
+		if t == nil {
+			return fmt.Errorf("token %s is null", key)
+		}
+		version, err := schema.FromString(t.SchemaVersion)
+		if err != nil {
+			return fmt.Errorf("token %s: %w", key, err)
+		}
+		tokens[key] = &Token{
+			Name:               t.Name,
+			Value:              t.Value,
+			Type:               t.Type,
+			TypeFromAlias:      t.TypeFromAlias,
+			Description:        t.Description,
+			Extensions:         t.Extensions,
+			Deprecated:         t.Deprecated,
+			DeprecationMessage: t.DeprecationMessage,
+			Replacement:        t.Replacement,
+			FilePath:           t.FilePath,
+			Prefix:             t.Prefix,
+			Path:               t.Path,
+			DefinitionURI:      t.DefinitionURI,
+			Line:               t.Line,
+			Character:          t.Character,
+			Reference:          t.Reference,
+			SchemaVersion:      version,
+			RawValue:           t.RawValue,
+			ResolvedValue:      t.ResolvedValue,
+			ResolvedExtensions: t.ResolvedExtensions,
+			IsResolved:         t.IsResolved,
+			ResolutionChain:    t.ResolutionChain,
+		}
+	}
+	m.prefix = in.Prefix
+	m.tokens = tokens
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package token_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/resolver"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/token"
+)
+
+func TestMap_JSONRoundTrip(t *testing.T) {
+	tokens, err := parser.NewJSONParser().Parse([]byte(`{
+		"color": {
+			"$type": "color",
+			"base": { "$value": "#FF6B35", "$description": "Brand orange" },
+			"brand": { "$value": "{color.base}" },
+			"accent": {
+				"$value": "{color.brand}",
+				"$extensions": { "com.example": { "pair": "{color.base}" } }
+			},
+			"old": { "$value": "#000000", "$deprecated": "Use color.brand" }
+		},
+		"shadow": {
+			"card": {
+				"$type": "shadow",
+				"$value": { "color": "{color.brand}", "offsetX": "0px", "offsetY": "1px", "blur": "2px", "spread": "0px" }
+			}
+		},
+		"space": { "unset": { "$type": "dimension", "$value": null } }
+	}`), parser.Options{SchemaVersion: schema.Draft})
+	if err != nil {
+		t.Fatalf("failed to parse tokens: %v", err)
+	}
+	for _, tok := range tokens {
+		tok.FilePath = "/tokens.json"
+		tok.DefinitionURI = "file:///tokens.json"
+	}
+	if err := resolver.ResolveAliasesWithOptions(tokens, schema.Draft, resolver.ResolveOptions{Extensions: true}); err != nil {
+		t.Fatalf("failed to resolve tokens: %v", err)
+	}
+	original := token.NewMap(tokens, "ds")
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	var loaded token.Map
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+
+	if loaded.Len() != original.Len() {
+		t.Fatalf("loaded %d tokens, want %d", loaded.Len(), original.Len())
+	}
+	for _, want := range original.All() {
+		got, ok := loaded.Get(want.CSSVariableName())
+		if !ok {
+			t.Errorf("missing %s after round trip", want.CSSVariableName())
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s after round trip:\n got %+v\nwant %+v", want.DotPath(), got, want)
+		}
+	}
+
+	// Lookups by short name still apply the prefix
+	accent, ok := loaded.Get("color-accent")
+	if !ok {
+		t.Fatal("expected color-accent by short name")
+	}
+	if !reflect.DeepEqual(accent.ResolutionChain, []string{"color-brand", "color-base"}) {
+		t.Errorf("ResolutionChain = %v", accent.ResolutionChain)
+	}
+	if accent.ResolvedValue != "#FF6B35" || accent.Line == 0 {
+		t.Errorf("ResolvedValue = %v, Line = %d", accent.ResolvedValue, accent.Line)
+	}
+}
+
+func TestMap_UnmarshalJSONErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown format": `{"format": 99, "tokens": {}}`,
+		"unknown schema": `{"format": 1, "tokens": {"--a": {"name": "a", "schema": "v1999"}}}`,
+		"null token":     `{"format": 1, "tokens": {"--a": null}}`,
+		"malformed":      `{"format": 1, "tokens": [`,
+	} {
+		var m token.Map
+		if err := json.Unmarshal([]byte(data), &m); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -87,6 +87,29 @@ func IsJSONPointerRef(ref string) bool {
 	return jsonPointerPattern.MatchString(ref)
 }
 
+// PartialRef splits a 2025.10 $value object that references another
+// token and overrides some of its fields, such as
+// {"$ref": "#/shadow/base", "color": "#000000"}, into the JSON pointer
+// and the overriding fields. ok is false for any other value, including
+// a $ref with no overrides, which aliases the whole value.
+func PartialRef(value any) (ref string, overrides map[string]any, ok bool) {
+	m, isMap := value.(map[string]any)
+	if !isMap || len(m) < 2 {
+		return "", nil, false
+	}
+	ref, ok = m["$ref"].(string)
+	if !ok {
+		return "", nil, false
+	}
+	overrides = make(map[string]any, len(m)-1)
+	for k, v := range m {
+		if k != "$ref" {
+			overrides[k] = v
+		}
+	}
+	return ref, overrides, true
+}
+
 // ExtractAllRefs extracts all curly brace references from a string.
 func ExtractAllRefs(value string) []string {
 	matches := curlyBracePattern.FindAllStringSubmatch(value, -1)
@@ -139,3 +139,23 @@ func TestExtractAllRefs(t *testing.T) {
 		})
 	}
 }
+
+func TestPartialRef(t *testing.T) {
+	override := map[string]any{"value": 2.0, "unit": "px"}
+	ref, overrides, ok := token.PartialRef(map[string]any{"$ref": "#/shadow/base", "blur": override})
+	if !ok || ref != "#/shadow/base" || len(overrides) != 1 || overrides["blur"] == nil {
+		t.Errorf("PartialRef() = %q, %v, %v; want #/shadow/base with a blur override", ref, overrides, ok)
+	}
+
+	for _, value := range []any{
+		map[string]any{"$ref": "#/shadow/base"},
+		map[string]any{"blur": override, "color": "#000"},
+		map[string]any{"$ref": 1.0, "blur": override},
+		"#/shadow/base",
+		nil,
+	} {
+		if _, _, ok := token.PartialRef(value); ok {
+			t.Errorf("PartialRef(%v) = true, want false", value)
+		}
+	}
+}
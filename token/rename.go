@@ -0,0 +1,146 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package token
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"bennypowers.dev/asimonim/parser/common"
+)
+
+// Rename moves the token at oldPath to newPath, both dot paths like
+// "color.brand.primary", and rewrites every reference to it across
+// tokens: {old.path} references in values, including those embedded in
+// strings and inside composite values, $ref JSON pointers, references
+// in $extensions, and deprecation replacements. References are rewritten
+// even when the token itself is not among tokens, e.g. when it is
+// defined in another file.
+//
+// It returns the number of tokens changed, counting the renamed token.
+// Changed tokens have their resolution state cleared, as with SetLiteral.
+// It is an error for newPath to name a token that already exists.
+func Rename(tokens []*Token, oldPath, newPath string) (int, error) {
+	if oldPath == "" || newPath == "" {
+		return 0, fmt.Errorf("rename requires both an old and a new path")
+	}
+	if oldPath == newPath {
+		return 0, nil
+	}
+	for _, tok := range tokens {
+		if tok.DotPath() == newPath {
+			return 0, fmt.Errorf("cannot rename %s to %s: %s already exists", oldPath, newPath, newPath)
+		}
+	}
+
+	r := renamer{oldPath: oldPath, newPath: newPath}
+	count := 0
+	for _, tok := range tokens {
+		changed := false
+		if tok.DotPath() == oldPath {
+			tok.Path = strings.Split(newPath, ".")
+			tok.Name = strings.ReplaceAll(newPath, ".", "-")
+			tok.Reference = "{" + newPath + "}"
+			changed = true
+		}
+		if value, ok := r.string(tok.Value); ok {
+			tok.Value = value
+			changed = true
+		}
+		if raw, ok := r.value(tok.RawValue); ok {
+			tok.RawValue = raw
+			changed = true
+		}
+		if ext, ok := r.value(tok.Extensions); ok {
+			tok.Extensions = ext.(map[string]any)
+			changed = true
+		}
+		if tok.Replacement == oldPath {
+			tok.Replacement = newPath
+			changed = true
+		}
+		if changed {
+			tok.clearResolution()
+			tok.ResolvedExtensions = nil
+			count++
+		}
+	}
+	return count, nil
+}
+
+// RenameReferences returns s with its references to the token at
+// oldPath, as {old.path} or a #/old/path JSON pointer, rewritten to
+// newPath, and reports whether any changed.
+func RenameReferences(s, oldPath, newPath string) (string, bool) {
+	return renamer{oldPath: oldPath, newPath: newPath}.string(s)
+}
+
+// renamer rewrites references from oldPath to newPath.
+type renamer struct {
+	oldPath, newPath string
+}
+
+// string rewrites references in s, reporting whether any changed.
+func (r renamer) string(s string) (string, bool) {
+	if path, ok := ParseJSONPointerRef(s); ok {
+		if path == r.oldPath {
+			return common.ConvertTokenPathToJSONPointer(r.newPath), true
+		}
+		return s, false
+	}
+	changed := false
+	out := curlyBracePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref[1:len(ref)-1] != r.oldPath {
+			return ref
+		}
+		changed = true
+		return "{" + r.newPath + "}"
+	})
+	return out, changed
+}
+
+// value rewrites references in a JSON-like value, reporting whether any
+// changed. Changed maps and slices are copied rather than modified, since
+// values may be shared between tokens.
+func (r renamer) value(v any) (any, bool) {
+	switch val := v.(type) {
+	case string:
+		return r.string(val)
+	case map[string]any:
+		var out map[string]any
+		for k, elem := range val {
+			if next, ok := r.value(elem); ok {
+				if out == nil {
+					out = maps.Clone(val)
+				}
+				out[k] = next
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case []any:
+		var out []any
+		for i, elem := range val {
+			if next, ok := r.value(elem); ok {
+				if out == nil {
+					out = slices.Clone(val)
+				}
+				out[i] = next
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	default:
+		return v, false
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package token_test
+
+import (
+	"reflect"
+	"testing"
+
+	"bennypowers.dev/asimonim/parser"
+	"bennypowers.dev/asimonim/resolver"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/testutil"
+	"bennypowers.dev/asimonim/token"
+)
+
+func parseRenameTokens(t *testing.T, content string, version schema.Version) []*token.Token {
+	t.Helper()
+	tokens, err := parser.NewJSONParser().Parse([]byte(content), parser.Options{SchemaVersion: version, SkipPositions: true})
+	if err != nil {
+		t.Fatalf("failed to parse tokens: %v", err)
+	}
+	return tokens
+}
+
+func TestRename_Draft(t *testing.T) {
+	tokens := parseRenameTokens(t, `{
+		"color": {
+			"$type": "color",
+			"brand": { "$value": "#FF6B35" },
+			"accent": { "$value": "{color.brand}" },
+			"old": {
+				"$value": "#000000",
+				"$deprecated": "Use color.brand",
+				"$extensions": { "com.example": { "replacement": "{color.brand}" } }
+			}
+		},
+		"shadow": {
+			"card": {
+				"$type": "shadow",
+				"$value": [
+					{ "color": "{color.brand}", "offsetX": "0px", "offsetY": "1px", "blur": "2px", "spread": "0px" },
+					{ "color": "#00000033", "offsetX": "0px", "offsetY": "4px", "blur": "8px", "spread": "0px" }
+				]
+			}
+		},
+		"border": {
+			"focus": {
+				"$type": "border",
+				"$value": { "color": "{color.brand}", "width": "2px", "style": "solid" }
+			}
+		},
+		"text": {
+			"note": { "$type": "string", "$value": "Uses {color.brand} and {color.brandish}" }
+		}
+	}`, schema.Draft)
+	testutil.TokenByPath(t, tokens, "color.old").Replacement = "color.brand"
+
+	count, err := token.Rename(tokens, "color.brand", "color.primary")
+	if err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	// The token itself, its alias, the deprecated token, the shadow, the border, and the string
+	if count != 6 {
+		t.Errorf("Rename() = %d, want 6", count)
+	}
+
+	renamed := testutil.TokenByPath(t, tokens, "color.primary")
+	if renamed.Name != "color-primary" || renamed.Reference != "{color.primary}" {
+		t.Errorf("renamed token Name = %q, Reference = %q", renamed.Name, renamed.Reference)
+	}
+
+	if got := testutil.TokenByPath(t, tokens, "color.accent").Value; got != "{color.primary}" {
+		t.Errorf("alias Value = %q, want {color.primary}", got)
+	}
+
+	shadow := testutil.TokenByPath(t, tokens, "shadow.card").RawValue.([]any)
+	if got := shadow[0].(map[string]any)["color"]; got != "{color.primary}" {
+		t.Errorf("shadow layer color = %v, want {color.primary}", got)
+	}
+	if got := shadow[1].(map[string]any)["color"]; got != "#00000033" {
+		t.Errorf("unrelated shadow layer color = %v, want #00000033", got)
+	}
+
+	border := testutil.TokenByPath(t, tokens, "border.focus").RawValue.(map[string]any)
+	if got := border["color"]; got != "{color.primary}" {
+		t.Errorf("border color = %v, want {color.primary}", got)
+	}
+
+	// Only the exact path is rewritten, not paths it prefixes
+	if got := testutil.TokenByPath(t, tokens, "text.note").Value; got != "Uses {color.primary} and {color.brandish}" {
+		t.Errorf("embedded references = %q", got)
+	}
+
+	old := testutil.TokenByPath(t, tokens, "color.old")
+	if old.Replacement != "color.primary" {
+		t.Errorf("Replacement = %q, want color.primary", old.Replacement)
+	}
+	want := map[string]any{"com.example": map[string]any{"replacement": "{color.primary}"}}
+	if !reflect.DeepEqual(old.Extensions, want) {
+		t.Errorf("Extensions = %v, want %v", old.Extensions, want)
+	}
+
+	// The renamed set resolves as before
+	if err := resolver.ResolveAliases(tokens, schema.Draft); err != nil {
+		t.Fatalf("ResolveAliases() error = %v", err)
+	}
+	if got := testutil.TokenByPath(t, tokens, "color.accent").ResolvedValue; got != "#FF6B35" {
+		t.Errorf("alias ResolvedValue = %v, want #FF6B35", got)
+	}
+}
+
+func TestRename_V2025(t *testing.T) {
+	tokens := parseRenameTokens(t, `{
+		"color": {
+			"$type": "color",
+			"brand": { "$value": { "colorSpace": "srgb", "components": [1, 0.5, 0] } },
+			"accent": { "$value": { "$ref": "#/color/brand" } }
+		},
+		"border": {
+			"focus": {
+				"$type": "border",
+				"$value": { "color": { "$ref": "#/color/brand" }, "width": { "value": 2, "unit": "px" }, "style": "solid" }
+			}
+		}
+	}`, schema.V2025_10)
+
+	count, err := token.Rename(tokens, "color.brand", "color.primary")
+	if err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Rename() = %d, want 3", count)
+	}
+
+	if got := testutil.TokenByPath(t, tokens, "color.accent").Value; got != "#/color/primary" {
+		t.Errorf("alias Value = %q, want #/color/primary", got)
+	}
+	border := testutil.TokenByPath(t, tokens, "border.focus").RawValue.(map[string]any)
+	if got := border["color"]; !reflect.DeepEqual(got, map[string]any{"$ref": "#/color/primary"}) {
+		t.Errorf("border color = %v, want $ref to #/color/primary", got)
+	}
+
+	if err := resolver.ResolveAliases(tokens, schema.V2025_10); err != nil {
+		t.Fatalf("ResolveAliases() error = %v", err)
+	}
+	accent := testutil.TokenByPath(t, tokens, "color.accent")
+	if _, ok := accent.ResolvedValue.(map[string]any); !ok {
+		t.Errorf("alias ResolvedValue = %v, want the structured color", accent.ResolvedValue)
+	}
+}
+
+func TestRename_DoesNotModifySharedValues(t *testing.T) {
+	shared := map[string]any{"color": "{color.brand}", "width": "1px", "style": "solid"}
+	tokens := []*token.Token{
+		{Name: "color-brand", Path: []string{"color", "brand"}, Value: "#FF6B35", RawValue: "#FF6B35"},
+		{Name: "border-a", Path: []string{"border", "a"}, RawValue: shared},
+	}
+
+	if _, err := token.Rename(tokens, "color.brand", "color.primary"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	if shared["color"] != "{color.brand}" {
+		t.Errorf("shared value was modified: %v", shared)
+	}
+}
+
+func TestRename_Errors(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-brand", Path: []string{"color", "brand"}, Value: "#FF6B35"},
+		{Name: "color-primary", Path: []string{"color", "primary"}, Value: "#000000"},
+	}
+
+	if _, err := token.Rename(tokens, "color.brand", "color.primary"); err == nil {
+		t.Error("expected an error renaming onto an existing token")
+	}
+	if _, err := token.Rename(tokens, "", "color.other"); err == nil {
+		t.Error("expected an error for an empty path")
+	}
+	if count, err := token.Rename(tokens, "color.brand", "color.brand"); err != nil || count != 0 {
+		t.Errorf("Rename() to the same path = %d, %v; want 0, nil", count, err)
+	}
+	if tokens[0].DotPath() != "color.brand" {
+		t.Errorf("failed rename changed the token path to %s", tokens[0].DotPath())
+	}
+}
+
+func TestRenameReferences(t *testing.T) {
+	tests := []struct {
+		in, want string
+		changed  bool
+	}{
+		{"{color.brand}", "{color.primary}", true},
+		{"1px solid {color.brand}", "1px solid {color.primary}", true},
+		{"#/color/brand", "#/color/primary", true},
+		{"{color.brand.light}", "{color.brand.light}", false},
+		{"{color.brandy}", "{color.brandy}", false},
+		{"#FF6B35", "#FF6B35", false},
+	}
+	for _, tt := range tests {
+		got, changed := token.RenameReferences(tt.in, "color.brand", "color.primary")
+		if got != tt.want || changed != tt.changed {
+			t.Errorf("RenameReferences(%q) = %q, %v; want %q, %v", tt.in, got, changed, tt.want, tt.changed)
+		}
+	}
+}
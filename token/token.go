@@ -10,6 +10,9 @@ package token
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
 	"bennypowers.dev/asimonim/parser/common"
@@ -33,8 +36,21 @@ const (
 	TypeShadow      = "shadow"
 	TypeGradient    = "gradient"
 	TypeTypography  = "typography"
+	TypeBoolean     = "boolean"
 )
 
+// Types lists the DTCG token type constants.
+var Types = []string{
+	TypeColor, TypeDimension, TypeFontFamily, TypeFontWeight, TypeDuration,
+	TypeCubicBezier, TypeNumber, TypeString, TypeStrokeStyle, TypeBorder,
+	TypeTransition, TypeShadow, TypeGradient, TypeTypography, TypeBoolean,
+}
+
+// IsDTCGType reports whether typ is one of the DTCG token types.
+func IsDTCGType(typ string) bool {
+	return slices.Contains(Types, typ)
+}
+
 // Token represents a design token following the DTCG specification.
 // See: https://design-tokens.github.io/community-group/format/
 type Token struct {
@@ -47,6 +63,10 @@ type Token struct {
 	// Type specifies the type of token (color, dimension, etc.).
 	Type string `json:"$type,omitempty"`
 
+	// TypeFromAlias is true when Type wasn't authored but taken from the
+	// token an alias resolves to. Serializers leave such types out.
+	TypeFromAlias bool `json:"-"`
+
 	// Description is optional documentation for the token.
 	Description string `json:"$description,omitempty"`
 
@@ -59,6 +79,10 @@ type Token struct {
 	// DeprecationMessage provides context for deprecated tokens.
 	DeprecationMessage string `json:"$deprecationMessage,omitempty"`
 
+	// Replacement is the dot path of the token that supersedes a deprecated
+	// token (e.g., "color.new"), when given.
+	Replacement string `json:"-"`
+
 	// FilePath is the file this token was loaded from.
 	FilePath string `json:"-"`
 
@@ -90,6 +114,11 @@ type Token struct {
 	// ResolvedValue is the value after alias/extends resolution.
 	ResolvedValue any `json:"-"`
 
+	// ResolvedExtensions is a copy of Extensions with token references
+	// replaced by the values they point to. It is only set when
+	// extension references are resolved; see resolver.ResolveOptions.
+	ResolvedExtensions map[string]any `json:"-"`
+
 	// IsResolved indicates if alias resolution has been performed.
 	IsResolved bool `json:"-"`
 
@@ -118,16 +147,85 @@ func NewMap(tokens []*Token, prefix string) *Map {
 		// Apply prefix to token if not already set
 		tok := t
 		if tok.Prefix == "" && prefix != "" {
-			// Create a copy with prefix set
-			copy := *t
-			copy.Prefix = prefix
-			tok = &copy
+			tok = t.WithPrefix(prefix)
 		}
 		m.tokens[tok.CSSVariableName()] = tok
 	}
 	return m
 }
 
+// WithPrefix returns a copy of the token with Prefix set.
+// The copy shares no slices or maps with the original, so either
+// can be modified without affecting the other.
+func (t *Token) WithPrefix(prefix string) *Token {
+	clone := *t
+	clone.Prefix = prefix
+	clone.Path = slices.Clone(t.Path)
+	clone.ResolutionChain = slices.Clone(t.ResolutionChain)
+	if t.Extensions != nil {
+		clone.Extensions = deepCopyValue(t.Extensions).(map[string]any)
+	}
+	if t.ResolvedExtensions != nil {
+		clone.ResolvedExtensions = deepCopyValue(t.ResolvedExtensions).(map[string]any)
+	}
+	clone.RawValue = deepCopyValue(t.RawValue)
+	clone.ResolvedValue = deepCopyValue(t.ResolvedValue)
+	return &clone
+}
+
+// IsPlaceholder reports whether the token has no value, as with
+// "$value": null, leaving a theme to fill it in. Once resolved, aliases
+// of placeholders are placeholders too.
+func (t *Token) IsPlaceholder() bool {
+	if len(t.ResolutionChain) > 0 {
+		return t.ResolvedValue == nil
+	}
+	return t.RawValue == nil && t.Value == "" && t.ResolvedValue == nil
+}
+
+// Equal reports whether t and other mean the same thing: the same $type,
+// display value, description, deprecation, and extensions. Names and
+// positional fields such as FilePath and Line are ignored, so tokens
+// defined in different places compare equal. As with Map.Diff, resolve
+// aliases first to compare what tokens resolve to.
+func (t *Token) Equal(other *Token) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+	if t.Type != other.Type ||
+		t.Description != other.Description ||
+		t.Deprecated != other.Deprecated ||
+		t.DeprecationMessage != other.DeprecationMessage ||
+		t.DisplayValue() != other.DisplayValue() {
+		return false
+	}
+	if len(t.Extensions) == 0 && len(other.Extensions) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(t.Extensions, other.Extensions)
+}
+
+// deepCopyValue copies nested maps and slices of a JSON-like value.
+// Other values are returned as-is.
+func deepCopyValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(val))
+		for k, item := range val {
+			result[k] = deepCopyValue(item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(val))
+		for i, item := range val {
+			result[i] = deepCopyValue(item)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
 // Get returns the Token for the given name, prepending the prefix if needed.
 // Accepts short names (color-primary), full CSS names (--prefix-color-primary),
 // or dot-path names (color.primary).
@@ -151,6 +249,34 @@ func (m *Map) Len() int {
 	return len(m.tokens)
 }
 
+// Names returns every name Get accepts for the tokens in the map, sorted
+// and without duplicates: full CSS variable names, the same names without
+// leading dashes, short names without the prefix, and dot-paths.
+func (m *Map) Names() []string {
+	var names []string
+	for key, tok := range m.tokens {
+		bare := strings.TrimPrefix(key, "--")
+		candidates := []string{key, bare}
+		if m.prefix != "" {
+			candidates = append(candidates, strings.TrimPrefix(bare, m.prefix+"-"))
+		}
+		if dot := tok.DotPath(); dot != "" {
+			candidates = append(candidates, dot)
+			if m.prefix != "" {
+				candidates = append(candidates, m.prefix+"."+dot)
+			}
+		}
+		for _, name := range candidates {
+			// Only keep names that actually lead back to this token
+			if m.normalizeName(name) == key {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
 // normalizeName converts a name to a full CSS variable name.
 func (m *Map) normalizeName(name string) string {
 	// Convert dot-path to dash-separated
@@ -234,11 +360,69 @@ func TypeToCSSSyntax(tokenType string) string {
 		return "<line-style>"
 	case TypeTransition:
 		return "<time> || <easing-function>"
+	case TypeBoolean:
+		return "<custom-ident>"
 	default:
 		return "<custom-ident>" // Fallback for unknown types
 	}
 }
 
+// IsAlias reports whether this token's value refers to another token,
+// either as a {token.path} reference or as a $ref JSON pointer.
+func (t *Token) IsAlias() bool {
+	if IsCurlyBraceRef(t.Value) {
+		return true
+	}
+	if t.SchemaVersion != schema.Draft && IsJSONPointerRef(t.Value) {
+		return true
+	}
+	if m, ok := t.RawValue.(map[string]any); ok {
+		_, hasRef := m["$ref"].(string)
+		return hasRef
+	}
+	return false
+}
+
+// AliasTarget returns the dot path of the token this token's value
+// refers to, such as "color.primary" for "{color.primary}",
+// "#/color/primary", or {"$ref": "#/color/primary"}. It returns false
+// when the token isn't an alias or the reference can't be parsed.
+func (t *Token) AliasTarget() (string, bool) {
+	if path, ok := ParseCurlyBraceRef(t.Value); ok {
+		return path, true
+	}
+	if t.SchemaVersion == schema.Draft {
+		return "", false
+	}
+	ref := t.Value
+	if m, ok := t.RawValue.(map[string]any); ok {
+		if s, ok := m["$ref"].(string); ok {
+			ref = s
+		}
+	}
+	return ParseJSONPointerRef(ref)
+}
+
+// IsStructuredColor reports whether this is a color token whose value is a
+// 2025.10 structured color object (with colorSpace and components).
+// The resolved value is checked when available, so aliases to structured
+// colors also report true once resolved.
+func (t *Token) IsStructuredColor() bool {
+	if t.Type != TypeColor {
+		return false
+	}
+	val := t.RawValue
+	if t.IsResolved && t.ResolvedValue != nil {
+		val = t.ResolvedValue
+	}
+	m, ok := val.(map[string]any)
+	if !ok {
+		return false
+	}
+	_, hasColorSpace := m["colorSpace"].(string)
+	return hasColorSpace
+}
+
 // DisplayValue returns a formatted string for display in hover/UI.
 // It uses ResolvedValue if resolved, otherwise RawValue if set, else Value.
 // The value is formatted based on the token's Type for human readability.
@@ -277,6 +461,10 @@ func (t *Token) formatValue(val any) string {
 		if s := formatDimension(val); s != "" {
 			return s
 		}
+	case TypeNumber:
+		if s := formatNumber(val); s != "" {
+			return s
+		}
 	case TypeDuration:
 		if s := formatDuration(val); s != "" {
 			return s
@@ -301,6 +489,10 @@ func (t *Token) formatValue(val any) string {
 		if s := formatTransition(val); s != "" {
 			return s
 		}
+	case TypeBoolean:
+		if b, ok := val.(bool); ok {
+			return strconv.FormatBool(b)
+		}
 	}
 
 	// Handle maps and arrays with JSON serialization as fallback
@@ -328,12 +520,27 @@ func formatDimension(val any) string {
 	}
 	v, hasValue := m["value"]
 	u, hasUnit := m["unit"].(string)
-	if !hasValue || !hasUnit {
+	if !hasValue || v == nil || !hasUnit {
 		return ""
 	}
 	return fmt.Sprintf("%v%s", v, u)
 }
 
+// formatNumber formats a number value without exponent notation. A
+// percentage written as {"value": 50, "unit": "%"} formats as "50%".
+// Ratios such as "16/9" are strings and never reach here.
+func formatNumber(val any) string {
+	if m, ok := val.(map[string]any); ok {
+		if unit, _ := m["unit"].(string); unit == "%" {
+			if n := cssNumber(m["value"]); n != "" {
+				return n + unit
+			}
+		}
+		return ""
+	}
+	return cssNumber(val)
+}
+
 // formatDuration formats a structured duration value like {"value": 100, "unit": "ms"} to "100ms".
 func formatDuration(val any) string {
 	m, ok := val.(map[string]any)
@@ -7,6 +7,7 @@ license that can be found in the LICENSE file.
 package token_test
 
 import (
+	"slices"
 	"testing"
 
 	"bennypowers.dev/asimonim/schema"
@@ -91,6 +92,149 @@ func TestNewMap(t *testing.T) {
 			t.Errorf("original token was modified, Prefix = %q", tokens[0].Prefix)
 		}
 	})
+
+	t.Run("mutating map tokens does not affect originals", func(t *testing.T) {
+		originals := []*token.Token{{
+			Name:       "color-primary",
+			Path:       []string{"color", "primary"},
+			Extensions: map[string]any{"com.example": map[string]any{"role": "brand"}},
+		}}
+		m := token.NewMap(originals, "my-prefix")
+		tok, ok := m.Get("color-primary")
+		if !ok {
+			t.Fatal("expected to find token")
+		}
+
+		tok.Path[1] = "changed"
+		tok.Extensions["com.example"].(map[string]any)["role"] = "changed"
+
+		if originals[0].Path[1] != "primary" {
+			t.Errorf("original Path was modified: %v", originals[0].Path)
+		}
+		if role := originals[0].Extensions["com.example"].(map[string]any)["role"]; role != "brand" {
+			t.Errorf("original Extensions were modified: role = %v", role)
+		}
+	})
+}
+
+func TestToken_WithPrefix(t *testing.T) {
+	original := &token.Token{
+		Name:            "color-primary",
+		Path:            []string{"color", "primary"},
+		RawValue:        map[string]any{"colorSpace": "srgb", "components": []any{1.0, 0.0, 0.0}},
+		ResolutionChain: []string{"color-base"},
+	}
+
+	clone := original.WithPrefix("rh")
+	if clone == original {
+		t.Fatal("expected a new token")
+	}
+	if clone.Prefix != "rh" || original.Prefix != "" {
+		t.Errorf("Prefix: clone = %q, original = %q", clone.Prefix, original.Prefix)
+	}
+	if got := clone.CSSVariableName(); got != "--rh-color-primary" {
+		t.Errorf("CSSVariableName() = %q, want %q", got, "--rh-color-primary")
+	}
+
+	clone.RawValue.(map[string]any)["components"].([]any)[0] = 0.5
+	clone.ResolutionChain[0] = "changed"
+
+	if c := original.RawValue.(map[string]any)["components"].([]any)[0]; c != 1.0 {
+		t.Errorf("original RawValue was modified: component = %v", c)
+	}
+	if original.ResolutionChain[0] != "color-base" {
+		t.Errorf("original ResolutionChain was modified: %v", original.ResolutionChain)
+	}
+}
+
+func TestToken_IsPlaceholder(t *testing.T) {
+	tests := []struct {
+		name string
+		tok  *token.Token
+		want bool
+	}{
+		{"null value", &token.Token{Type: token.TypeColor}, true},
+		{"string value", &token.Token{Value: "#f00", RawValue: "#f00"}, false},
+		{"empty string value", &token.Token{RawValue: ""}, false},
+		{"structured value", &token.Token{RawValue: map[string]any{"value": 4.0, "unit": "px"}}, false},
+		{"unresolved alias", &token.Token{Value: "{color.brand}", RawValue: "{color.brand}"}, false},
+		{"alias of placeholder", &token.Token{Value: "{color.brand}", RawValue: "{color.brand}",
+			IsResolved: true, ResolutionChain: []string{"color-brand"}}, true},
+		{"alias of value", &token.Token{Value: "{color.brand}", RawValue: "{color.brand}",
+			IsResolved: true, ResolvedValue: "#f00", ResolutionChain: []string{"color-brand"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tok.IsPlaceholder(); got != tt.want {
+				t.Errorf("IsPlaceholder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToken_Equal(t *testing.T) {
+	base := func() *token.Token {
+		return &token.Token{
+			Name:        "color-primary",
+			Path:        []string{"color", "primary"},
+			Type:        token.TypeColor,
+			Value:       "#ff0000",
+			Description: "Brand red",
+			Extensions:  map[string]any{"com.example": map[string]any{"tier": []any{"core"}}},
+			FilePath:    "/tokens/a.json",
+			Line:        3,
+			Character:   4,
+		}
+	}
+
+	moved := base()
+	moved.Name = "brand-red"
+	moved.Path = []string{"brand", "red"}
+	moved.FilePath = "/tokens/b.json"
+	moved.Line = 12
+	moved.Character = 8
+	moved.Prefix = "rh"
+	if !base().Equal(moved) {
+		t.Error("expected tokens differing only in name and position to be equal")
+	}
+
+	resolved := base()
+	resolved.Value = "{color.red}"
+	resolved.RawValue = "{color.red}"
+	resolved.ResolvedValue = "#ff0000"
+	resolved.IsResolved = true
+	if !base().Equal(resolved) {
+		t.Error("expected an alias to equal a token with its resolved value")
+	}
+
+	tests := map[string]func(*token.Token){
+		"type":        func(tok *token.Token) { tok.Type = token.TypeString },
+		"value":       func(tok *token.Token) { tok.Value = "#00ff00" },
+		"description": func(tok *token.Token) { tok.Description = "" },
+		"deprecated":  func(tok *token.Token) { tok.Deprecated = true },
+		"extensions": func(tok *token.Token) {
+			tok.Extensions["com.example"].(map[string]any)["tier"] = []any{"product"}
+		},
+	}
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			other := base()
+			modify(other)
+			if base().Equal(other) {
+				t.Errorf("expected tokens differing in %s to be unequal", name)
+			}
+		})
+	}
+
+	empty := &token.Token{Value: "1", Extensions: map[string]any{}}
+	if !empty.Equal(&token.Token{Value: "1"}) {
+		t.Error("expected empty and nil extensions to be equal")
+	}
+
+	var nilToken *token.Token
+	if !nilToken.Equal(nil) || nilToken.Equal(base()) || base().Equal(nil) {
+		t.Error("expected only nil to equal nil")
+	}
 }
 
 func TestMap_Get(t *testing.T) {
@@ -166,6 +310,45 @@ func TestMap_Get(t *testing.T) {
 	})
 }
 
+func TestMap_Names(t *testing.T) {
+	tokens := []*token.Token{
+		{Name: "color-primary", Path: []string{"color", "primary"}},
+		{Name: "space", Path: []string{"space"}},
+	}
+
+	t.Run("without prefix", func(t *testing.T) {
+		m := token.NewMap(tokens, "")
+		want := []string{
+			"--color-primary", "--space",
+			"color-primary", "color.primary",
+			"space",
+		}
+		if got := m.Names(); !slices.Equal(got, want) {
+			t.Errorf("Names() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("with prefix", func(t *testing.T) {
+		m := token.NewMap(tokens, "rh")
+		want := []string{
+			"--rh-color-primary", "--rh-space",
+			"color-primary", "color.primary",
+			"rh-color-primary", "rh-space",
+			"rh.color.primary", "rh.space",
+			"space",
+		}
+		got := m.Names()
+		if !slices.Equal(got, want) {
+			t.Errorf("Names() = %v, want %v", got, want)
+		}
+		for _, name := range got {
+			if _, ok := m.Get(name); !ok {
+				t.Errorf("Get(%q) found no token", name)
+			}
+		}
+	})
+}
+
 func TestMap_All(t *testing.T) {
 	tokens := []*token.Token{
 		{Name: "a", Value: "1"},
@@ -248,6 +431,7 @@ func TestTypeToCSSSyntax(t *testing.T) {
 		{token.TypeTypography, "<custom-ident>"},
 		{token.TypeStrokeStyle, "<line-style>"},
 		{token.TypeTransition, "<time> || <easing-function>"},
+		{token.TypeBoolean, "<custom-ident>"},
 		{"unknownType", "<custom-ident>"},
 		{"", "<custom-ident>"},
 	}
@@ -412,6 +596,38 @@ func TestToken_DisplayValue(t *testing.T) {
 			},
 			expected: "42.5",
 		},
+		{
+			name: "large number without exponent",
+			token: token.Token{
+				Type:     token.TypeNumber,
+				RawValue: 1500000.0,
+			},
+			expected: "1500000",
+		},
+		{
+			name: "opacity between 0 and 1",
+			token: token.Token{
+				Type:     token.TypeNumber,
+				RawValue: 0.64,
+			},
+			expected: "0.64",
+		},
+		{
+			name: "percentage number",
+			token: token.Token{
+				Type:     token.TypeNumber,
+				RawValue: map[string]any{"value": 50.0, "unit": "%"},
+			},
+			expected: "50%",
+		},
+		{
+			name: "ratio string passes through",
+			token: token.Token{
+				Type:     token.TypeNumber,
+				RawValue: "16/9",
+			},
+			expected: "16/9",
+		},
 		// Dimension tests
 		{
 			name: "structured dimension with rem",
@@ -783,6 +999,22 @@ func TestToken_DisplayValue(t *testing.T) {
 			},
 			expected: `{"value":16}`,
 		},
+		{
+			name: "boolean true",
+			token: token.Token{
+				RawValue: true,
+				Type:     token.TypeBoolean,
+			},
+			expected: "true",
+		},
+		{
+			name: "boolean false",
+			token: token.Token{
+				RawValue: false,
+				Type:     token.TypeBoolean,
+			},
+			expected: "false",
+		},
 	}
 
 	for _, tt := range tests {
@@ -837,3 +1069,136 @@ func TestTokenTypeConstants(t *testing.T) {
 		}
 	}
 }
+
+func TestToken_IsAlias(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    token.Token
+		expected bool
+	}{
+		{
+			name:     "curly brace reference",
+			token:    token.Token{Value: "{color.primary}"},
+			expected: true,
+		},
+		{
+			name:     "json pointer value in 2025.10",
+			token:    token.Token{Value: "#/color/primary", SchemaVersion: schema.V2025_10},
+			expected: true,
+		},
+		{
+			name:     "json pointer-like value in draft",
+			token:    token.Token{Value: "#/color/primary", SchemaVersion: schema.Draft},
+			expected: false,
+		},
+		{
+			name: "$ref object",
+			token: token.Token{
+				SchemaVersion: schema.V2025_10,
+				RawValue:      map[string]any{"$ref": "#/color/primary"},
+			},
+			expected: true,
+		},
+		{
+			name:     "literal color",
+			token:    token.Token{Value: "#ff0000", RawValue: "#ff0000"},
+			expected: false,
+		},
+		{
+			name: "structured color",
+			token: token.Token{
+				RawValue: map[string]any{"colorSpace": "srgb", "components": []any{1.0, 0.0, 0.0}},
+			},
+			expected: false,
+		},
+		{
+			name:     "own reference only",
+			token:    token.Token{Value: "8px", Reference: "{spacing.small}"},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.token.IsAlias(); got != tt.expected {
+				t.Errorf("Token.IsAlias() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToken_AliasTarget(t *testing.T) {
+	tests := []struct {
+		name  string
+		token token.Token
+		want  string
+		ok    bool
+	}{
+		{"curly brace reference", token.Token{Value: "{color.primary}"}, "color.primary", true},
+		{"json pointer value", token.Token{Value: "#/color/primary", SchemaVersion: schema.V2025_10}, "color.primary", true},
+		{"json pointer-like value in draft", token.Token{Value: "#/color/primary", SchemaVersion: schema.Draft}, "", false},
+		{"$ref object", token.Token{SchemaVersion: schema.V2025_10, RawValue: map[string]any{"$ref": "#/color/primary"}}, "color.primary", true},
+		{"literal", token.Token{Value: "#ff0000", RawValue: "#ff0000"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.token.AliasTarget()
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Token.AliasTarget() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestToken_IsStructuredColor(t *testing.T) {
+	structured := map[string]any{"colorSpace": "oklch", "components": []any{0.5, 0.1, 200.0}}
+
+	tests := []struct {
+		name     string
+		token    token.Token
+		expected bool
+	}{
+		{
+			name:     "structured color",
+			token:    token.Token{Type: token.TypeColor, RawValue: structured},
+			expected: true,
+		},
+		{
+			name:     "string color",
+			token:    token.Token{Type: token.TypeColor, Value: "#ff0000", RawValue: "#ff0000"},
+			expected: false,
+		},
+		{
+			name: "resolved alias to structured color",
+			token: token.Token{
+				Type:          token.TypeColor,
+				RawValue:      map[string]any{"$ref": "#/color/base"},
+				ResolvedValue: structured,
+				IsResolved:    true,
+			},
+			expected: true,
+		},
+		{
+			name: "unresolved $ref",
+			token: token.Token{
+				Type:     token.TypeColor,
+				RawValue: map[string]any{"$ref": "#/color/base"},
+			},
+			expected: false,
+		},
+		{
+			name:     "non-color type",
+			token:    token.Token{Type: token.TypeDimension, RawValue: structured},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.token.IsStructuredColor(); got != tt.expected {
+				t.Errorf("Token.IsStructuredColor() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package token
+
+import (
+	"strconv"
+	"strings"
+
+	"bennypowers.dev/asimonim/parser/common"
+	"bennypowers.dev/asimonim/schema"
+)
+
+// SetLiteral replaces the token's value with v, a JSON-like value as it
+// would appear under $value (a string, float64, bool, map[string]any, or
+// []any). Value and RawValue are set the way the parser sets them, and
+// resolution state is cleared, so run the resolver again before reading
+// ResolvedValue.
+func (t *Token) SetLiteral(v any) {
+	t.RawValue = v
+	switch val := v.(type) {
+	case string:
+		t.Value = val
+	case float64:
+		t.Value = strconv.FormatFloat(val, 'f', -1, 64)
+	case int:
+		t.Value = strconv.Itoa(val)
+	default:
+		t.Value = ""
+	}
+	t.clearResolution()
+}
+
+// SetReference makes the token an alias of the token at path, a dot path
+// like "color.brand.primary" (surrounding braces are ignored). The
+// reference is written in the syntax of the token's schema: {color.primary}
+// for the Editor's Draft and a #/color/primary JSON pointer for later
+// schemas. Resolution state is cleared, as with SetLiteral.
+func (t *Token) SetReference(path string) {
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+	if t.SchemaVersion == schema.Draft || t.SchemaVersion == schema.Unknown {
+		t.Value = "{" + path + "}"
+	} else {
+		t.Value = common.ConvertTokenPathToJSONPointer(path)
+	}
+	t.RawValue = t.Value
+	t.clearResolution()
+}
+
+func (t *Token) clearResolution() {
+	t.ResolvedValue = nil
+	t.IsResolved = false
+	t.ResolutionChain = nil
+}
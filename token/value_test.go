@@ -0,0 +1,104 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package token_test
+
+import (
+	"reflect"
+	"testing"
+
+	"bennypowers.dev/asimonim/resolver"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/token"
+)
+
+func TestToken_SetLiteral(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     any
+		wantValue string
+	}{
+		{name: "string", value: "#ff0000", wantValue: "#ff0000"},
+		{name: "number", value: 1.5, wantValue: "1.5"},
+		{name: "structured", value: map[string]any{"value": 4.0, "unit": "px"}, wantValue: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tok := &token.Token{
+				Value:           "{color.old}",
+				RawValue:        "{color.old}",
+				ResolvedValue:   "#000000",
+				IsResolved:      true,
+				ResolutionChain: []string{"color-old"},
+			}
+			tok.SetLiteral(tt.value)
+			if tok.Value != tt.wantValue {
+				t.Errorf("Value = %q, want %q", tok.Value, tt.wantValue)
+			}
+			if !reflect.DeepEqual(tok.RawValue, tt.value) {
+				t.Errorf("RawValue = %v, want %v", tok.RawValue, tt.value)
+			}
+			if tok.IsResolved || tok.ResolvedValue != nil || tok.ResolutionChain != nil {
+				t.Error("expected resolution state to be cleared")
+			}
+			if tok.IsAlias() {
+				t.Error("expected literal token not to be an alias")
+			}
+		})
+	}
+}
+
+func TestToken_SetReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   schema.Version
+		path      string
+		wantValue string
+	}{
+		{name: "draft", version: schema.Draft, path: "color.red", wantValue: "{color.red}"},
+		{name: "draft with braces", version: schema.Draft, path: "{color.red}", wantValue: "{color.red}"},
+		{name: "v2025.10", version: schema.V2025_10, path: "color.red", wantValue: "#/color/red"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			red := &token.Token{
+				Name: "color-red", Path: []string{"color", "red"}, Type: token.TypeColor,
+				Value: "#ff0000", RawValue: "#ff0000", SchemaVersion: tt.version,
+			}
+			primary := &token.Token{
+				Name: "color-primary", Path: []string{"color", "primary"}, Type: token.TypeColor,
+				Value: "#0000ff", RawValue: "#0000ff", SchemaVersion: tt.version,
+			}
+			tokens := []*token.Token{red, primary}
+			if err := resolver.ResolveAliases(tokens, tt.version); err != nil {
+				t.Fatalf("ResolveAliases error: %v", err)
+			}
+
+			primary.SetReference(tt.path)
+			if primary.Value != tt.wantValue || primary.RawValue != tt.wantValue {
+				t.Errorf("Value, RawValue = %q, %v, want %q", primary.Value, primary.RawValue, tt.wantValue)
+			}
+			if primary.IsResolved {
+				t.Error("expected IsResolved to be cleared")
+			}
+			if !primary.IsAlias() {
+				t.Error("expected token to be an alias")
+			}
+
+			if err := resolver.ResolveAliases(tokens, tt.version); err != nil {
+				t.Fatalf("ResolveAliases error: %v", err)
+			}
+			if primary.ResolvedValue != "#ff0000" {
+				t.Errorf("ResolvedValue = %v, want #ff0000", primary.ResolvedValue)
+			}
+			if !reflect.DeepEqual(primary.ResolutionChain, []string{"color-red"}) {
+				t.Errorf("ResolutionChain = %v, want [color-red]", primary.ResolutionChain)
+			}
+		})
+	}
+}
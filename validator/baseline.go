@@ -0,0 +1,43 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package validator
+
+import (
+	"fmt"
+
+	"bennypowers.dev/asimonim/token"
+)
+
+// ValidateBaseline reports breaking changes between a baseline token set,
+// such as the last published release, and the current one: tokens that
+// were removed or renamed, and tokens whose $type changed. New tokens and
+// changed values are allowed.
+func ValidateBaseline(baseline, current []*token.Token) []ValidationError {
+	diff := token.NewMap(baseline, "").Diff(token.NewMap(current, ""))
+
+	var errors []ValidationError
+	for _, tok := range diff.Removed {
+		errors = append(errors, ValidationError{
+			FilePath:   tok.FilePath,
+			Path:       tok.DotPath(),
+			Message:    "token in baseline is missing",
+			Suggestion: "restore the token, or deprecate it for a release before removing it",
+		})
+	}
+	for _, change := range diff.Changed {
+		if change.OldType == change.NewType {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			FilePath:   change.New.FilePath,
+			Path:       change.Path,
+			Message:    fmt.Sprintf("$type changed from %q to %q since baseline", change.OldType, change.NewType),
+			Suggestion: "add a new token with the new type instead",
+		})
+	}
+	return errors
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package validator_test
+
+import (
+	"slices"
+	"testing"
+
+	"bennypowers.dev/asimonim/token"
+	"bennypowers.dev/asimonim/validator"
+)
+
+func TestValidateBaseline(t *testing.T) {
+	baseline := []*token.Token{
+		{Name: "color-primary", Path: []string{"color", "primary"}, Type: token.TypeColor, Value: "#f00"},
+		{Name: "color-old", Path: []string{"color", "old"}, Type: token.TypeColor, Value: "#a00"},
+		{Name: "space-sm", Path: []string{"space", "sm"}, Type: token.TypeDimension, Value: "4px"},
+	}
+	current := []*token.Token{
+		{Name: "color-primary", Path: []string{"color", "primary"}, Type: token.TypeColor, Value: "#00f"},
+		{Name: "space-sm", Path: []string{"space", "sm"}, Type: token.TypeNumber, Value: "4"},
+		{Name: "space-md", Path: []string{"space", "md"}, Type: token.TypeDimension, Value: "8px"},
+	}
+
+	errors := validator.ValidateBaseline(baseline, current)
+	var got []string
+	for _, e := range errors {
+		got = append(got, e.Path)
+	}
+	if want := []string{"color.old", "space.sm"}; !slices.Equal(got, want) {
+		t.Errorf("ValidateBaseline() paths = %v, want %v", got, want)
+	}
+
+	if errors := validator.ValidateBaseline(baseline, baseline); len(errors) != 0 {
+		t.Errorf("expected no errors against itself, got %v", errors)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package validator
+
+import (
+	"slices"
+	"strings"
+
+	"bennypowers.dev/asimonim/token"
+)
+
+// DescriptionOptions configures ValidateDescriptions.
+type DescriptionOptions struct {
+	// Types limits the check to tokens of these $types.
+	// Empty checks tokens of every type.
+	Types []string
+
+	// Groups limits the check to tokens under these dot-separated group
+	// paths (e.g. "color.brand"). Empty checks every token.
+	Groups []string
+
+	// SkipDeprecated exempts deprecated tokens, which often keep only a
+	// deprecation message.
+	SkipDeprecated bool
+}
+
+// ValidateDescriptions reports tokens that have no $description.
+func ValidateDescriptions(tokens []*token.Token, opts DescriptionOptions) []ValidationError {
+	groups := make([][]string, len(opts.Groups))
+	for i, g := range opts.Groups {
+		groups[i] = strings.Split(g, ".")
+	}
+
+	var errors []ValidationError
+	for _, tok := range tokens {
+		if strings.TrimSpace(tok.Description) != "" {
+			continue
+		}
+		if opts.SkipDeprecated && tok.Deprecated {
+			continue
+		}
+		if len(opts.Types) > 0 && !slices.Contains(opts.Types, tok.Type) {
+			continue
+		}
+		if len(groups) > 0 && !slices.ContainsFunc(groups, func(g []string) bool {
+			return len(tok.Path) > len(g) && slices.Equal(tok.Path[:len(g)], g)
+		}) {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			FilePath:   tok.FilePath,
+			Path:       tok.DotPath(),
+			Message:    "missing $description",
+			Suggestion: "describe when to use this token",
+		})
+	}
+	return errors
+}
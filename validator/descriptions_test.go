@@ -0,0 +1,51 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package validator_test
+
+import (
+	"slices"
+	"testing"
+
+	"bennypowers.dev/asimonim/token"
+	"bennypowers.dev/asimonim/validator"
+)
+
+func TestValidateDescriptions(t *testing.T) {
+	tokens := []*token.Token{
+		{Path: []string{"color", "brand", "primary"}, Type: token.TypeColor, Description: "Main brand color"},
+		{Path: []string{"color", "brand", "accent"}, Type: token.TypeColor},
+		{Path: []string{"color", "text"}, Type: token.TypeColor, Description: "  "},
+		{Path: []string{"color", "old"}, Type: token.TypeColor, Deprecated: true},
+		{Path: []string{"space", "sm"}, Type: token.TypeDimension},
+		{Path: []string{"colorful"}, Type: token.TypeColor},
+	}
+
+	tests := []struct {
+		name string
+		opts validator.DescriptionOptions
+		want []string
+	}{
+		{"all tokens", validator.DescriptionOptions{}, []string{"color.brand.accent", "color.text", "color.old", "space.sm", "colorful"}},
+		{"skip deprecated", validator.DescriptionOptions{SkipDeprecated: true}, []string{"color.brand.accent", "color.text", "space.sm", "colorful"}},
+		{"by type", validator.DescriptionOptions{Types: []string{token.TypeDimension}}, []string{"space.sm"}},
+		{"by group", validator.DescriptionOptions{Groups: []string{"color"}}, []string{"color.brand.accent", "color.text", "color.old"}},
+		{"by nested group", validator.DescriptionOptions{Groups: []string{"color.brand"}}, []string{"color.brand.accent"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.ValidateDescriptions(tokens, tt.opts)
+			var got []string
+			for _, e := range errors {
+				got = append(got, e.Path)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ValidateDescriptions() paths = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
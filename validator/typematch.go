@@ -0,0 +1,124 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/mazznoer/csscolorparser"
+
+	"bennypowers.dev/asimonim/parser/common"
+	"bennypowers.dev/asimonim/token"
+)
+
+// ValidateValueTypeMatch reports tokens whose value is invalid for their
+// $type but is clearly a value of another type, such as a dimension token
+// with the value "#fff" or a color token with the value "16px". These are
+// usually copy-paste errors, so each error suggests the likely type.
+//
+// Values that are merely malformed for their type are left to
+// ValidateTypes. Tokens should be alias-resolved first, so an alias of a
+// token with a different type is reported too.
+func ValidateValueTypeMatch(tokens []*token.Token) []ValidationError {
+	var errors []ValidationError
+
+	for _, tok := range tokens {
+		value := typedValue(tok)
+		if value == nil || validForType(tok.Type, value) {
+			continue
+		}
+		likely := inferType(value)
+		if likely == "" || likely == tok.Type {
+			continue
+		}
+		errors = append(errors, ValidationError{
+			FilePath:   tok.FilePath,
+			Path:       tok.DotPath(),
+			Message:    fmt.Sprintf("%s token has a %s value %s", tok.Type, likely, describeValue(value)),
+			Suggestion: fmt.Sprintf("did you mean \"$type\": %q?", likely),
+		})
+	}
+
+	return errors
+}
+
+// validForType reports whether value passes the ValidateTypes check for
+// typ. Types without a value check count as valid, so they are never
+// reported as mismatches.
+func validForType(typ string, value any) bool {
+	var msg string
+	switch typ {
+	case token.TypeColor:
+		msg, _ = checkColor(value)
+	case token.TypeDimension:
+		msg, _ = checkUnitValue(value, lengthUnits, "dimension", "")
+	case token.TypeDuration:
+		msg, _ = checkUnitValue(value, timeUnits, "duration", "")
+	case token.TypeFontWeight:
+		msg, _ = checkFontWeight(value)
+	case token.TypeCubicBezier:
+		msg, _ = checkCubicBezier(value)
+	case token.TypeNumber:
+		if _, ok := toFloat(value); !ok {
+			s, isString := value.(string)
+			if _, unit, ok := common.ParseNumberWithUnit(s); !isString || !ok || unit != "" {
+				msg = "not a number"
+			}
+		}
+	default:
+		return true
+	}
+	return msg == ""
+}
+
+// inferType returns the type a value unambiguously belongs to: color,
+// dimension, or duration. Plain numbers and percentages fit several types
+// and return "".
+func inferType(value any) string {
+	switch v := value.(type) {
+	case map[string]any:
+		if _, ok := v["colorSpace"].(string); ok {
+			return token.TypeColor
+		}
+		if _, ok := toFloat(v["value"]); ok {
+			unit, _ := v["unit"].(string)
+			return unitType(unit)
+		}
+	case string:
+		if _, unit, ok := common.ParseNumberWithUnit(v); ok {
+			return unitType(unit)
+		}
+		if _, err := csscolorparser.Parse(v); err == nil {
+			return token.TypeColor
+		}
+	}
+	return ""
+}
+
+// unitType returns the type whose units include unit.
+func unitType(unit string) string {
+	switch {
+	case slices.Contains(timeUnits, unit):
+		return token.TypeDuration
+	case unit != "%" && slices.Contains(lengthUnits, unit):
+		return token.TypeDimension
+	}
+	return ""
+}
+
+// describeValue formats a value for an error message.
+func describeValue(value any) string {
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	if data, err := json.Marshal(value); err == nil {
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
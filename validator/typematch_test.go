@@ -0,0 +1,63 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/token"
+	"bennypowers.dev/asimonim/validator"
+)
+
+func TestValidateValueTypeMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		tok        token.Token
+		wantLikely string
+	}{
+		{"dimension with hex color", token.Token{Type: token.TypeDimension, RawValue: "#fff"}, token.TypeColor},
+		{"color with px", token.Token{Type: token.TypeColor, RawValue: "16px"}, token.TypeDimension},
+		{"duration with length", token.Token{Type: token.TypeDuration, RawValue: "200px"}, token.TypeDimension},
+		{"dimension with time", token.Token{Type: token.TypeDimension, RawValue: map[string]any{"value": 200.0, "unit": "ms"}}, token.TypeDuration},
+		{"number with dimension", token.Token{Type: token.TypeNumber, RawValue: "1.5rem"}, token.TypeDimension},
+		{"fontWeight with color", token.Token{Type: token.TypeFontWeight, RawValue: "rebeccapurple"}, token.TypeColor},
+		{"dimension with structured color", token.Token{Type: token.TypeDimension, RawValue: map[string]any{"colorSpace": "srgb", "components": []any{1.0, 1.0, 1.0}}}, token.TypeColor},
+		{"resolved alias to a color", token.Token{Type: token.TypeDimension, Value: "{color.white}", ResolvedValue: "#ffffff", IsResolved: true}, token.TypeColor},
+
+		{"valid dimension", token.Token{Type: token.TypeDimension, RawValue: "16px"}, ""},
+		{"valid color", token.Token{Type: token.TypeColor, RawValue: "#fff"}, ""},
+		{"fontWeight keyword that is also a color", token.Token{Type: token.TypeFontWeight, RawValue: "black"}, ""},
+		{"unitless dimension is malformed, not mistyped", token.Token{Type: token.TypeDimension, RawValue: "16"}, ""},
+		{"percentage number", token.Token{Type: token.TypeNumber, RawValue: "50%"}, ""},
+		{"string type is not checked", token.Token{Type: token.TypeString, RawValue: "#fff"}, ""},
+		{"unresolved alias skipped", token.Token{Type: token.TypeDimension, Value: "{color.white}", RawValue: "{color.white}"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tok.Name = "test"
+			tt.tok.Path = []string{"test"}
+			errors := validator.ValidateValueTypeMatch([]*token.Token{&tt.tok})
+			if tt.wantLikely == "" {
+				if len(errors) != 0 {
+					t.Errorf("expected no errors, got %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 {
+				t.Fatalf("expected 1 error, got %d: %v", len(errors), errors)
+			}
+			if !strings.Contains(errors[0].Message, tt.tok.Type+" token has a "+tt.wantLikely+" value") {
+				t.Errorf("error message = %q, want a %s value reported", errors[0].Message, tt.wantLikely)
+			}
+			if want := `"$type": "` + tt.wantLikely + `"`; !strings.Contains(errors[0].Suggestion, want) {
+				t.Errorf("suggestion = %q, want it to contain %s", errors[0].Suggestion, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/mazznoer/csscolorparser"
+
+	"bennypowers.dev/asimonim/parser/common"
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/token"
+)
+
+// fontWeightKeywords are the named font weights allowed by the DTCG spec.
+var fontWeightKeywords = []string{
+	"thin", "hairline",
+	"extra-light", "ultra-light",
+	"light",
+	"normal", "regular", "book",
+	"medium",
+	"semi-bold", "demi-bold",
+	"bold",
+	"extra-bold", "ultra-bold",
+	"black", "heavy",
+	"extra-black", "ultra-black",
+}
+
+// lengthUnits are the units accepted for dimension tokens.
+var lengthUnits = []string{
+	"px", "rem", "em", "%", "ch", "ex", "lh", "rlh",
+	"vw", "vh", "vmin", "vmax", "svh", "lvh", "dvh", "svw", "lvw", "dvw",
+	"cm", "mm", "q", "in", "pt", "pc",
+}
+
+// timeUnits are the units accepted for duration tokens.
+var timeUnits = []string{"ms", "s"}
+
+// cssFunctionPattern matches CSS function values like light-dark(...) or color-mix(...).
+var cssFunctionPattern = regexp.MustCompile(`^[a-zA-Z-]+\(.*\)$`)
+
+// ValidateTypes checks that each token's value is plausible for its $type:
+// - fontWeight is a number in 1–1000 or a DTCG weight keyword
+// - cubicBezier is four numbers with x coordinates in 0–1
+// - duration has a time unit (ms or s)
+// - dimension has a length unit (unitless 0 is allowed)
+// - color parses as a CSS color or a structured color object
+//
+// Tokens should be alias-resolved first; unresolved aliases are skipped.
+func ValidateTypes(tokens []*token.Token) []ValidationError {
+	var errors []ValidationError
+
+	for _, tok := range tokens {
+		value := typedValue(tok)
+		if value == nil {
+			continue
+		}
+
+		var msg, suggestion string
+		switch tok.Type {
+		case token.TypeFontWeight:
+			msg, suggestion = checkFontWeight(value)
+		case token.TypeCubicBezier:
+			msg, suggestion = checkCubicBezier(value)
+		case token.TypeDuration:
+			msg, suggestion = checkUnitValue(value, timeUnits, "duration", "use a time unit like \"200ms\" or \"0.5s\"")
+		case token.TypeDimension:
+			msg, suggestion = checkUnitValue(value, lengthUnits, "dimension", "use a length unit like \"16px\" or \"1rem\"")
+		case token.TypeColor:
+			msg, suggestion = checkColor(value)
+		}
+
+		if msg != "" {
+			errors = append(errors, ValidationError{
+				FilePath:   tok.FilePath,
+				Path:       tok.DotPath(),
+				Message:    msg,
+				Suggestion: suggestion,
+			})
+		}
+	}
+
+	return errors
+}
+
+// typedValue returns the value to check for a token, or nil if the token
+// is an alias that has not been resolved.
+func typedValue(tok *token.Token) any {
+	var value any
+	switch {
+	case tok.IsResolved && tok.ResolvedValue != nil:
+		value = tok.ResolvedValue
+	case tok.IsAlias():
+		return nil
+	case tok.RawValue != nil:
+		value = tok.RawValue
+	case tok.Value != "":
+		value = tok.Value
+	}
+	if isReference(value) {
+		// References to tokens outside the validated set stay unresolved.
+		return nil
+	}
+	return value
+}
+
+// isReference reports whether a value is still a {token.path} or $ref reference.
+func isReference(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return token.IsCurlyBraceRef(v)
+	case map[string]any:
+		_, hasRef := v["$ref"]
+		return hasRef
+	}
+	return false
+}
+
+// checkFontWeight validates a fontWeight value.
+func checkFontWeight(value any) (string, string) {
+	if s, ok := value.(string); ok {
+		if slices.Contains(fontWeightKeywords, s) {
+			return "", ""
+		}
+		if n, unit, ok := common.ParseNumberWithUnit(s); ok && unit == "" {
+			value = n
+		} else {
+			return fmt.Sprintf("fontWeight %q is not a number or weight keyword", s),
+				"use a number from 1 to 1000 or a keyword like \"bold\""
+		}
+	}
+	n, ok := toFloat(value)
+	if !ok {
+		return fmt.Sprintf("fontWeight must be a number, got %T", value), ""
+	}
+	if n < 1 || n > 1000 {
+		return fmt.Sprintf("fontWeight %v is out of range", n), "use a number from 1 to 1000"
+	}
+	return "", ""
+}
+
+// checkCubicBezier validates a cubicBezier value.
+func checkCubicBezier(value any) (string, string) {
+	arr, ok := value.([]any)
+	if !ok || len(arr) != 4 {
+		return "cubicBezier must be an array of 4 numbers", "use [x1, y1, x2, y2]"
+	}
+	for i, v := range arr {
+		n, ok := toFloat(v)
+		if !ok {
+			return fmt.Sprintf("cubicBezier[%d] must be a number, got %T", i, v), ""
+		}
+		if (i == 0 || i == 2) && (n < 0 || n > 1) {
+			return fmt.Sprintf("cubicBezier x coordinate %v is out of range", n), "x1 and x2 must be between 0 and 1"
+		}
+	}
+	return "", ""
+}
+
+// checkUnitValue validates a value that needs a unit, either as a
+// {value, unit} object or a string like "16px".
+func checkUnitValue(value any, units []string, typeName, suggestion string) (string, string) {
+	var unit string
+	var number float64
+	switch v := value.(type) {
+	case map[string]any:
+		n, ok := toFloat(v["value"])
+		if !ok {
+			return fmt.Sprintf("%s value must be a number", typeName), suggestion
+		}
+		u, _ := v["unit"].(string)
+		number, unit = n, u
+	case string:
+		n, u, ok := common.ParseNumberWithUnit(v)
+		if !ok {
+			if cssFunctionPattern.MatchString(v) {
+				return "", ""
+			}
+			return fmt.Sprintf("%s %q is not a number with a unit", typeName, v), suggestion
+		}
+		number, unit = n, u
+	default:
+		n, ok := toFloat(v)
+		if !ok {
+			return fmt.Sprintf("%s has unexpected value type %T", typeName, value), suggestion
+		}
+		number = n
+	}
+
+	if unit == "" {
+		if number == 0 && typeName == "dimension" {
+			return "", ""
+		}
+		return fmt.Sprintf("%s is missing a unit", typeName), suggestion
+	}
+	if !slices.Contains(units, unit) {
+		return fmt.Sprintf("%s has unknown unit %q", typeName, unit), suggestion
+	}
+	return "", ""
+}
+
+// checkColor validates a color value. String colors must parse as CSS colors;
+// structured colors must be well-formed 2025.10 color objects. Whether the
+// color form matches the file's schema is left to ValidateConsistency.
+func checkColor(value any) (string, string) {
+	if s, ok := value.(string); ok {
+		if _, err := csscolorparser.Parse(s); err != nil && !cssFunctionPattern.MatchString(s) {
+			return fmt.Sprintf("color %q does not parse as a CSS color", s), ""
+		}
+		return "", ""
+	}
+	if _, err := common.ParseColorValue(value, schema.V2025_10); err != nil {
+		return fmt.Sprintf("invalid color: %v", err), ""
+	}
+	return "", ""
+}
+
+// toFloat converts JSON or YAML numbers to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
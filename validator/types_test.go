@@ -0,0 +1,81 @@
+/*
+Copyright 2026 Benny Powers. All rights reserved.
+Use of this source code is governed by the GPLv3
+license that can be found in the LICENSE file.
+*/
+
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"bennypowers.dev/asimonim/schema"
+	"bennypowers.dev/asimonim/token"
+	"bennypowers.dev/asimonim/validator"
+)
+
+func TestValidateTypes(t *testing.T) {
+	structuredColor := map[string]any{"colorSpace": "srgb", "components": []any{1.0, 0.0, 0.0}}
+
+	tests := []struct {
+		name    string
+		tok     token.Token
+		wantErr string
+	}{
+		{"fontWeight number", token.Token{Type: token.TypeFontWeight, RawValue: 400.0}, ""},
+		{"fontWeight keyword", token.Token{Type: token.TypeFontWeight, RawValue: "semi-bold"}, ""},
+		{"fontWeight numeric string", token.Token{Type: token.TypeFontWeight, RawValue: "700"}, ""},
+		{"fontWeight zero", token.Token{Type: token.TypeFontWeight, RawValue: 0.0}, "out of range"},
+		{"fontWeight too heavy", token.Token{Type: token.TypeFontWeight, RawValue: 1200.0}, "out of range"},
+		{"fontWeight unknown keyword", token.Token{Type: token.TypeFontWeight, RawValue: "boldest"}, "not a number or weight keyword"},
+		{"cubicBezier valid", token.Token{Type: token.TypeCubicBezier, RawValue: []any{0.25, 0.1, 0.25, 1.0}}, ""},
+		{"cubicBezier y overshoot", token.Token{Type: token.TypeCubicBezier, RawValue: []any{0.5, -0.5, 0.5, 1.5}}, ""},
+		{"cubicBezier x out of range", token.Token{Type: token.TypeCubicBezier, RawValue: []any{1.5, 0.0, 0.5, 1.0}}, "out of range"},
+		{"cubicBezier wrong length", token.Token{Type: token.TypeCubicBezier, RawValue: []any{0.5, 0.0}}, "array of 4 numbers"},
+		{"cubicBezier non-number", token.Token{Type: token.TypeCubicBezier, RawValue: []any{0.5, "a", 0.5, 1.0}}, "must be a number"},
+		{"duration string", token.Token{Type: token.TypeDuration, RawValue: "200ms"}, ""},
+		{"duration object", token.Token{Type: token.TypeDuration, RawValue: map[string]any{"value": 0.5, "unit": "s"}}, ""},
+		{"duration unitless", token.Token{Type: token.TypeDuration, RawValue: "200"}, "missing a unit"},
+		{"duration length unit", token.Token{Type: token.TypeDuration, RawValue: "200px"}, "unknown unit"},
+		{"dimension string", token.Token{Type: token.TypeDimension, RawValue: "1.5rem"}, ""},
+		{"dimension spaced", token.Token{Type: token.TypeDimension, RawValue: "1.5 rem"}, "not a number with a unit"},
+		{"dimension percent", token.Token{Type: token.TypeDimension, RawValue: "50%"}, ""},
+		{"dimension object", token.Token{Type: token.TypeDimension, RawValue: map[string]any{"value": 4.0, "unit": "px"}}, ""},
+		{"dimension zero", token.Token{Type: token.TypeDimension, RawValue: "0"}, ""},
+		{"dimension calc", token.Token{Type: token.TypeDimension, RawValue: "calc(1rem + 2px)"}, ""},
+		{"dimension unitless", token.Token{Type: token.TypeDimension, RawValue: "16"}, "missing a unit"},
+		{"dimension time unit", token.Token{Type: token.TypeDimension, RawValue: map[string]any{"value": 4.0, "unit": "ms"}}, "unknown unit"},
+		{"color hex", token.Token{Type: token.TypeColor, RawValue: "#ff0000", SchemaVersion: schema.Draft}, ""},
+		{"color function", token.Token{Type: token.TypeColor, RawValue: "light-dark(#fff, #000)", SchemaVersion: schema.Draft}, ""},
+		{"color garbage", token.Token{Type: token.TypeColor, RawValue: "#ggg", SchemaVersion: schema.Draft}, "does not parse"},
+		{"color structured", token.Token{Type: token.TypeColor, RawValue: structuredColor, SchemaVersion: schema.V2025_10}, ""},
+		{"color missing colorSpace", token.Token{Type: token.TypeColor, RawValue: map[string]any{"components": []any{1.0}}, SchemaVersion: schema.V2025_10}, "invalid color"},
+		{"unresolved alias skipped", token.Token{Type: token.TypeDimension, Value: "{spacing.base}", RawValue: "{spacing.base}"}, ""},
+		{"resolved alias checked", token.Token{Type: token.TypeDimension, Value: "{spacing.base}", RawValue: "{spacing.base}", ResolvedValue: "16", IsResolved: true}, "missing a unit"},
+		{"untyped ignored", token.Token{RawValue: "anything"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tok.Name = "test"
+			tt.tok.Path = []string{"test"}
+			errors := validator.ValidateTypes([]*token.Token{&tt.tok})
+			if tt.wantErr == "" {
+				if len(errors) != 0 {
+					t.Errorf("expected no errors, got %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 {
+				t.Fatalf("expected 1 error, got %d: %v", len(errors), errors)
+			}
+			if !strings.Contains(errors[0].Message, tt.wantErr) {
+				t.Errorf("error message = %q, want it to contain %q", errors[0].Message, tt.wantErr)
+			}
+			if errors[0].Path != "test" {
+				t.Errorf("error path = %q, want %q", errors[0].Path, "test")
+			}
+		})
+	}
+}
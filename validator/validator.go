@@ -12,7 +12,6 @@ import (
 	"strings"
 
 	"bennypowers.dev/asimonim/schema"
-	"gopkg.in/yaml.v3"
 )
 
 // ValidationError represents a schema consistency error.
@@ -59,8 +58,8 @@ func ValidateConsistency(content []byte, version schema.Version) []ValidationErr
 
 // ValidateConsistencyWithPath validates content and includes file path in errors.
 func ValidateConsistencyWithPath(content []byte, version schema.Version, filePath string) []ValidationError {
-	var data map[string]any
-	if err := yaml.Unmarshal(content, &data); err != nil {
+	roots, err := schema.DecodeRoots(content)
+	if err != nil {
 		return []ValidationError{{
 			FilePath: filePath,
 			Message:  fmt.Sprintf("failed to parse content: %v", err),
@@ -69,11 +68,13 @@ func ValidateConsistencyWithPath(content []byte, version schema.Version, filePat
 
 	var errors []ValidationError
 
-	switch version {
-	case schema.Draft:
-		errors = validateDraft(data, filePath, nil)
-	case schema.V2025_10:
-		errors = validateV2025(data, filePath, nil)
+	for _, data := range roots {
+		switch version {
+		case schema.Draft:
+			errors = append(errors, validateDraft(data, filePath, nil)...)
+		case schema.V2025_10:
+			errors = append(errors, validateV2025(data, filePath, nil)...)
+		}
 	}
 
 	return errors
@@ -247,3 +247,12 @@ func TestValidationError_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConsistency_MultiDocument(t *testing.T) {
+	content := []byte("a:\n  $value: 1\n---\nb:\n  $value:\n    $ref: \"#/a\"\n")
+	errors := validator.ValidateConsistency(content, schema.Draft)
+
+	if len(errors) != 1 || errors[0].Path != "b.$value.$ref" {
+		t.Errorf("expected one $ref error from the second document, got: %v", errors)
+	}
+}